@@ -11,8 +11,10 @@ import (
 	"github.com/lshigami/Ringtails/database"
 	_ "github.com/lshigami/Ringtails/docs" // Swagger docs - auto-generated
 	adminctrl "github.com/lshigami/Ringtails/internal/controller/admin"
+	authctrl "github.com/lshigami/Ringtails/internal/controller/auth"
 	userctrl "github.com/lshigami/Ringtails/internal/controller/user"
 	"github.com/lshigami/Ringtails/internal/logger" // Assuming logger.Init() is global or provide a logger instance
+	"github.com/lshigami/Ringtails/internal/middleware"
 	"github.com/lshigami/Ringtails/internal/model"
 	"github.com/lshigami/Ringtails/internal/repository"
 	"github.com/lshigami/Ringtails/internal/service"
@@ -35,6 +37,10 @@ import (
 // @host localhost:8080
 // @BasePath /api/v1
 // @schemes http https
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the access token returned by /auth/login.
 func main() {
 	// Initialize global logger (if your logger.Init() does this)
 	logger.Init() // Call this early
@@ -53,41 +59,91 @@ func main() {
 			repository.NewQuestionRepository,
 			repository.NewTestAttemptRepository,
 			repository.NewAnswerRepository,
+			repository.NewUserRepository,
+			repository.NewScoringJobRepository,
+			repository.NewBankQuestionRepository,
+			repository.NewPracticeAnswerRepository,
+			repository.NewAnswerScoreHistoryRepository,
+			repository.NewScoreConversionTableRepository,
+			repository.NewPromptTemplateRepository,
+			repository.NewLLMCallLogRepository,
+			repository.NewAnswerDisputeRepository,
+			repository.NewDashboardRepository,
+			repository.NewAnalyticsRepository,
+			repository.NewImageRepository,
 		),
 
 		// Services Layer
 		fx.Provide(
+			service.NewAuthService,
+			service.NewUserManagementService,
 			service.NewAdminTestService,
+			service.NewQuestionBankService,
 			func(testRepo repository.TestRepository, attemptRepo repository.TestAttemptRepository, sc service.ScoreConverterService) service.UserTestService {
 				return service.NewUserTestService(testRepo, attemptRepo, sc)
 			},
-			service.NewGeminiLLMService, // Renamed Gemini service
+			service.NewLLMProvider, // Gemini, OpenAI-compatible or fake, chosen by LLM_PROVIDER
+			service.NewLLMAuditService,
+			service.NewLLMAuditPurger,
+			service.NewLLMService,
 			func(
 				testRepo repository.TestRepository,
 				questionRepo repository.QuestionRepository,
 				testAttemptRepo repository.TestAttemptRepository,
-				answerRepo repository.AnswerRepository,
-				geminiService service.GeminiLLMService,
 				sc service.ScoreConverterService, // Thêm ScoreConverterService
 				db *gorm.DB,
 			) service.TestSubmissionService {
-				return service.NewTestSubmissionService(testRepo, questionRepo, testAttemptRepo, answerRepo, geminiService, sc, db)
+				return service.NewTestSubmissionService(testRepo, questionRepo, testAttemptRepo, sc, db)
 			},
 			service.NewScoreConverterService,
+			service.NewPromptTemplateService,
+			service.NewScoringService,
+			service.NewScoringWorkerPool,
+			service.NewPracticeService,
+			service.NewExamService,
+			service.NewExamDeadlineSweeper,
+			service.NewRescoreService,
+			service.NewReviewService,
+			service.NewDashboardService,
+			service.NewAnalyticsService,
+			service.NewImageStorage, // Local directory or S3-compatible bucket, chosen by STORAGE_BACKEND
+			service.NewImageService,
 		),
 
 		// API Controllers Layer
 		fx.Provide(
+			authctrl.NewAuthController,
 			adminctrl.NewAdminTestController,
+			adminctrl.NewAdminUserController,
+			adminctrl.NewAdminQuestionBankController,
+			adminctrl.NewAdminRescoreController,
+			adminctrl.NewAdminScoreTableController,
+			adminctrl.NewAdminAnalyticsController,
+			adminctrl.NewAdminImageController,
+			adminctrl.NewAdminPromptTemplateController,
+			adminctrl.NewAdminLLMCallController,
+			adminctrl.NewAdminReviewController,
 			// UserTestController needs *gorm.DB for TestSubmissionService's transaction handling
 			func(uts service.UserTestService, tss service.TestSubmissionService, db *gorm.DB) *userctrl.UserTestController {
 				return userctrl.NewUserTestController(uts, tss, db)
 			},
+			userctrl.NewPracticeController,
+			userctrl.NewExamController,
+			userctrl.NewRescoreController,
+			userctrl.NewDisputeController,
+			userctrl.NewDashboardController,
+			userctrl.NewImageController,
 		),
 
 		// Invokers - Functions that are executed by Fx
 		fx.Invoke(RegisterRoutesAndStartServer), // Combined registration and server start
 		fx.Invoke(AutoMigrateDB),
+		fx.Invoke(BootstrapAdmin), // Runs after migrations so the users table exists
+		fx.Invoke(SeedScoreConversionTable),
+		fx.Invoke(SeedPromptTemplates),
+		fx.Invoke(StartScoringWorkers),
+		fx.Invoke(StartExamDeadlineSweeper),
+		fx.Invoke(StartLLMAuditPurger),
 	)
 
 	// Start the application
@@ -148,28 +204,140 @@ func RegisterRoutesAndStartServer(
 	lc fx.Lifecycle,
 	router *gin.Engine,
 	cfg *config.Config,
+	authService service.AuthService,
+	authCtrl *authctrl.AuthController,
 	adminTestCtrl *adminctrl.AdminTestController,
+	adminUserCtrl *adminctrl.AdminUserController,
+	adminQuestionBankCtrl *adminctrl.AdminQuestionBankController,
+	adminRescoreCtrl *adminctrl.AdminRescoreController,
+	adminScoreTableCtrl *adminctrl.AdminScoreTableController,
+	adminAnalyticsCtrl *adminctrl.AdminAnalyticsController,
+	adminImageCtrl *adminctrl.AdminImageController,
+	adminPromptTemplateCtrl *adminctrl.AdminPromptTemplateController,
+	adminLLMCallCtrl *adminctrl.AdminLLMCallController,
+	adminReviewCtrl *adminctrl.AdminReviewController,
 	userTestCtrl *userctrl.UserTestController,
+	practiceCtrl *userctrl.PracticeController,
+	examCtrl *userctrl.ExamController,
+	rescoreCtrl *userctrl.RescoreController,
+	disputeCtrl *userctrl.DisputeController,
+	dashboardCtrl *userctrl.DashboardController,
+	imageCtrl *userctrl.ImageController,
 ) {
-	// Admin Routes (prefixed with /api/v1/admin)
-	adminAPIGroup := router.Group("/api/v1/admin")
+	requireAuth := middleware.RequireAuth(authService)
+	optionalAuth := middleware.OptionalAuth(authService)
+
+	// Auth Routes (prefixed with /api/v1/auth)
+	authAPIGroup := router.Group("/api/v1/auth")
 	{
-		testsAdminGroup := adminAPIGroup.Group("/tests")
+		authAPIGroup.POST("/register", authCtrl.Register)
+		authAPIGroup.POST("/login", authCtrl.Login)
+		authAPIGroup.GET("/me", requireAuth, authCtrl.Me)
+	}
+
+	// Admin Routes (prefixed with /api/v1/admin). Every route requires a token;
+	// content routes are open to teachers and admins, user management to admins only.
+	adminAPIGroup := router.Group("/api/v1/admin", requireAuth)
+	{
+		testsAdminGroup := adminAPIGroup.Group("/tests", middleware.RequireRoles(model.RoleTeacher, model.RoleAdmin))
 		testsAdminGroup.POST("", adminTestCtrl.CreateTest)
-		// Add more admin routes for tests here (e.g., update, delete test)
+		testsAdminGroup.POST("/from-bank", adminTestCtrl.CreateTestFromBank)
+		testsAdminGroup.POST("/import", adminTestCtrl.ImportTests)
+		testsAdminGroup.GET("/export", adminTestCtrl.ExportTests)
+		testsAdminGroup.GET("", adminTestCtrl.ListTests)
+		testsAdminGroup.GET("/:test_id", adminTestCtrl.GetTest)
+		testsAdminGroup.PUT("/:test_id", adminTestCtrl.UpdateTest)
+		testsAdminGroup.DELETE("/:test_id", adminTestCtrl.DeleteTest)
+		testsAdminGroup.PUT("/:test_id/questions/:question_id", adminTestCtrl.UpdateQuestion)
+		testsAdminGroup.GET("/:test_id/questions/:question_id/revisions", adminTestCtrl.ListQuestionRevisions)
+		testsAdminGroup.GET("/:test_id/questions/:question_id/revisions/diff", adminTestCtrl.DiffQuestionRevisions)
+		testsAdminGroup.POST("/:test_id/publish", adminTestCtrl.PublishTest)
+		testsAdminGroup.POST("/:test_id/unpublish", adminTestCtrl.UnpublishTest)
+		testsAdminGroup.POST("/:test_id/restore", adminTestCtrl.RestoreTest)
+		testsAdminGroup.POST("/:test_id/rescore", adminRescoreCtrl.RescoreTest)
+		testsAdminGroup.GET("/:test_id/analytics", adminAnalyticsCtrl.GetTestAnalytics)
+		testsAdminGroup.GET("/:test_id/export", adminTestCtrl.ExportTest)
+
+		scoringAdminGroup := adminAPIGroup.Group("", middleware.RequireRoles(model.RoleTeacher, model.RoleAdmin))
+		scoringAdminGroup.POST("/test-attempts/:attempt_id/rescore", adminRescoreCtrl.RescoreAttempt)
+		scoringAdminGroup.GET("/answers/:answer_id/score-history", adminRescoreCtrl.GetAnswerScoreHistory)
+		scoringAdminGroup.GET("/analytics/tests", adminAnalyticsCtrl.ListTestAnalytics)
+		scoringAdminGroup.GET("/llm-calls", adminLLMCallCtrl.ListLLMCalls)
+		scoringAdminGroup.GET("/llm-calls/:call_id", adminLLMCallCtrl.GetLLMCall)
+
+		// Teacher review of scores: disputes raised by learners, overrides and AI calibration
+		scoringAdminGroup.GET("/disputes", adminReviewCtrl.ListDisputes)
+		scoringAdminGroup.GET("/disputes/:dispute_id", adminReviewCtrl.GetDispute)
+		scoringAdminGroup.POST("/disputes/:dispute_id/resolve", adminReviewCtrl.ResolveDispute)
+		scoringAdminGroup.PUT("/answers/:answer_id/review", adminReviewCtrl.ReviewAnswer)
+		scoringAdminGroup.GET("/reviews/calibration", adminReviewCtrl.GetReviewCalibration)
+
+		imagesAdminGroup := adminAPIGroup.Group("/images", middleware.RequireRoles(model.RoleTeacher, model.RoleAdmin))
+		imagesAdminGroup.POST("", adminImageCtrl.UploadImage)
+		imagesAdminGroup.GET("/broken", adminImageCtrl.BrokenImageReport)
+
+		questionBankAdminGroup := adminAPIGroup.Group("/question-bank", middleware.RequireRoles(model.RoleTeacher, model.RoleAdmin))
+		questionBankAdminGroup.POST("", adminQuestionBankCtrl.CreateBankQuestion)
+		questionBankAdminGroup.GET("", adminQuestionBankCtrl.ListBankQuestions)
+		questionBankAdminGroup.GET("/:question_id", adminQuestionBankCtrl.GetBankQuestion)
+		questionBankAdminGroup.PUT("/:question_id", adminQuestionBankCtrl.UpdateBankQuestion)
+		questionBankAdminGroup.DELETE("/:question_id", adminQuestionBankCtrl.DeleteBankQuestion)
+
+		usersAdminGroup := adminAPIGroup.Group("/users", middleware.RequireRoles(model.RoleAdmin))
+		usersAdminGroup.GET("", adminUserCtrl.ListUsers)
+		usersAdminGroup.PUT("/:user_id/role", adminUserCtrl.UpdateUserRole)
+
+		scoreTablesAdminGroup := adminAPIGroup.Group("/score-tables", middleware.RequireRoles(model.RoleAdmin))
+		scoreTablesAdminGroup.GET("", adminScoreTableCtrl.ListScoreTables)
+		scoreTablesAdminGroup.POST("", adminScoreTableCtrl.UploadScoreTable)
+		scoreTablesAdminGroup.GET("/:version", adminScoreTableCtrl.GetScoreTable)
+		scoreTablesAdminGroup.POST("/:version/activate", adminScoreTableCtrl.ActivateScoreTable)
+		scoreTablesAdminGroup.GET("/:version/convert", adminScoreTableCtrl.PreviewScoreConversion)
+
+		promptTemplatesAdminGroup := adminAPIGroup.Group("/prompt-templates", middleware.RequireRoles(model.RoleAdmin))
+		promptTemplatesAdminGroup.GET("", adminPromptTemplateCtrl.ListPromptTemplates)
+		promptTemplatesAdminGroup.POST("/:question_type", adminPromptTemplateCtrl.CreatePromptTemplate)
+		promptTemplatesAdminGroup.POST("/:question_type/activate", adminPromptTemplateCtrl.ActivatePromptTemplates)
+		promptTemplatesAdminGroup.GET("/:question_type/:version", adminPromptTemplateCtrl.GetPromptTemplate)
+		promptTemplatesAdminGroup.POST("/:question_type/:version/preview", adminPromptTemplateCtrl.PreviewPromptTemplate)
 	}
 
 	// User Routes (prefixed with /api/v1)
 	userAPIGroup := router.Group("/api/v1")
 	{
 		// Test listing and details
-		userAPIGroup.GET("/tests", userTestCtrl.GetAllTests)
+		userAPIGroup.GET("/tests", optionalAuth, userTestCtrl.GetAllTests)
 		userAPIGroup.GET("/tests/:test_id", userTestCtrl.GetTestDetails)
 
-		// Test Attempts
-		userAPIGroup.POST("/tests/:test_id/attempts", userTestCtrl.SubmitTestAttempt)
-		userAPIGroup.GET("/tests/:test_id/my-attempts", userTestCtrl.GetUserTestAttempts) // User ID from query/auth
-		userAPIGroup.GET("/test-attempts/:attempt_id", userTestCtrl.GetSpecificTestAttemptDetails)
+		// Question images uploaded by admins; public so they load in <img> tags
+		userAPIGroup.GET("/images/:hash", imageCtrl.GetImage)
+
+		// Test Attempts (user ID always comes from the access token)
+		userAPIGroup.POST("/tests/:test_id/attempts", requireAuth, userTestCtrl.SubmitTestAttempt)
+		userAPIGroup.GET("/tests/:test_id/my-attempts", requireAuth, userTestCtrl.GetUserTestAttempts)
+		userAPIGroup.GET("/test-attempts/:attempt_id", requireAuth, userTestCtrl.GetSpecificTestAttemptDetails)
+
+		// Attempts answered over time: start (optionally timed), autosave answers, resume, submit
+		userAPIGroup.POST("/tests/:test_id/attempts/start", requireAuth, examCtrl.StartAttempt)
+		userAPIGroup.GET("/tests/:test_id/attempts/in-progress", requireAuth, examCtrl.GetInProgressAttempt)
+		userAPIGroup.PUT("/test-attempts/:attempt_id/answers/:question_id", requireAuth, examCtrl.SaveAnswer)
+		userAPIGroup.POST("/test-attempts/:attempt_id/submit", requireAuth, examCtrl.SubmitAttempt)
+
+		// Retry scoring of failed answers; earlier results stay visible as history
+		userAPIGroup.POST("/test-attempts/:attempt_id/rescore", requireAuth, rescoreCtrl.RescoreAttempt)
+		userAPIGroup.GET("/test-attempts/:attempt_id/answers/:answer_id/history", requireAuth, rescoreCtrl.GetAnswerScoreHistory)
+
+		// Ask a teacher to review a score
+		userAPIGroup.POST("/test-attempts/:attempt_id/answers/:answer_id/dispute", requireAuth, disputeCtrl.DisputeAnswer)
+		userAPIGroup.GET("/disputes", requireAuth, disputeCtrl.GetMyDisputes)
+
+		// Progress across all of the caller's tests
+		userAPIGroup.GET("/dashboard", requireAuth, dashboardCtrl.GetDashboard)
+
+		// Single-question practice, kept apart from test attempts
+		userAPIGroup.POST("/practice", requireAuth, practiceCtrl.SubmitPractice)
+		userAPIGroup.GET("/practice", requireAuth, practiceCtrl.GetPracticeHistory)
+		userAPIGroup.GET("/practice/:practice_id", requireAuth, practiceCtrl.GetPracticeAnswer)
 	}
 
 	// HTTP Server Setup and Lifecycle
@@ -206,12 +374,130 @@ func AutoMigrateDB(db *gorm.DB) error {
 		&model.Question{},
 		&model.TestAttempt{},
 		&model.Answer{},
-		// &model.User{}, // If you add a User model later
+		&model.AnswerCriterionScore{},
+		&model.User{},
+		&model.ScoringJob{},
+		&model.BankQuestion{},
+		&model.QuestionRevision{},
+		&model.PracticeAnswer{},
+		&model.AnswerScoreHistory{},
+		&model.ScoreConversionTable{},
+		&model.Image{},
+		&model.ImageSource{},
+		&model.PromptTemplate{},
+		&model.LLMCallLog{},
+		&model.AnswerDispute{},
 	)
 	if err != nil {
 		log.Error().Err(err).Msg("Database migration failed")
 		return err
 	}
+
+	// Answers scored synchronously before the background pipeline existed have no scoring_status;
+	// derive it from their stored score so they are not mistaken for queued work. Idempotent.
+	if err := db.Exec(`UPDATE answers SET scoring_status = CASE WHEN ai_score IS NULL THEN 'failed' ELSE 'scored' END
+		WHERE scoring_status = 'pending' AND test_attempt_id IN
+		(SELECT id FROM test_attempts WHERE status IN ('completed', 'completed_with_errors', 'error'))`).Error; err != nil {
+		log.Error().Err(err).Msg("Backfilling answer scoring status failed")
+		return err
+	}
+
+	// Questions created before revisions existed get their current content as revision 1, and their
+	// answers are pinned to it (nothing could have been edited yet). Idempotent.
+	if err := db.Exec(`INSERT INTO question_revisions (question_id, revision, title, prompt, type, image_url, given_word1, given_word2, max_score, created_at)
+		SELECT q.id, 1, q.title, q.prompt, q.type, q.image_url, q.given_word1, q.given_word2, q.max_score, q.updated_at FROM questions q
+		WHERE NOT EXISTS (SELECT 1 FROM question_revisions r WHERE r.question_id = q.id)`).Error; err != nil {
+		log.Error().Err(err).Msg("Backfilling question revisions failed")
+		return err
+	}
+	if err := db.Exec(`UPDATE questions SET current_revision_id = r.id FROM question_revisions r
+		WHERE r.question_id = questions.id AND r.revision = 1 AND questions.current_revision_id IS NULL`).Error; err != nil {
+		log.Error().Err(err).Msg("Backfilling current question revisions failed")
+		return err
+	}
+	if err := db.Exec(`UPDATE answers SET question_revision_id = r.id FROM question_revisions r
+		WHERE r.question_id = answers.question_id AND r.revision = 1 AND answers.question_revision_id IS NULL`).Error; err != nil {
+		log.Error().Err(err).Msg("Pinning answers to question revisions failed")
+		return err
+	}
+	// A user has at most one attempt in progress per test. Concurrent starts could create duplicates
+	// before the index existed: the newest is kept in progress and the others expire. Idempotent.
+	if err := db.Exec(`UPDATE test_attempts SET status = 'expired' WHERE status = 'in_progress' AND deleted_at IS NULL
+		AND id NOT IN (SELECT MAX(id) FROM test_attempts WHERE status = 'in_progress' AND deleted_at IS NULL GROUP BY test_id, user_id)`).Error; err != nil {
+		log.Error().Err(err).Msg("Expiring duplicate attempts in progress failed")
+		return err
+	}
+	if err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_test_attempts_in_progress ON test_attempts (user_id, test_id)
+		WHERE status = 'in_progress' AND deleted_at IS NULL`).Error; err != nil {
+		log.Error().Err(err).Msg("Creating the attempt in progress index failed")
+		return err
+	}
 	log.Info().Msg("Database V1 migration completed successfully.")
 	return nil
 }
+
+// BootstrapAdmin creates or promotes the configured admin account if no admin exists yet.
+func BootstrapAdmin(userManagementService service.UserManagementService) error {
+	if err := userManagementService.BootstrapAdmin(); err != nil {
+		log.Error().Err(err).Msg("Admin bootstrap failed")
+		return err
+	}
+	return nil
+}
+
+// SeedScoreConversionTable stores the built-in score conversion table on first start.
+func SeedScoreConversionTable(scoreConverter service.ScoreConverterService) error {
+	if err := scoreConverter.EnsureDefaultTable(); err != nil {
+		log.Error().Err(err).Msg("Score conversion table seeding failed")
+		return err
+	}
+	return nil
+}
+
+// SeedPromptTemplates stores the built-in scoring prompt template of each question type on first start.
+func SeedPromptTemplates(prompts service.PromptTemplateService) error {
+	if err := prompts.EnsureDefaultTemplates(); err != nil {
+		log.Error().Err(err).Msg("Prompt template seeding failed")
+		return err
+	}
+	return nil
+}
+
+// StartScoringWorkers ties the background scoring pool to the application lifecycle.
+func StartScoringWorkers(lc fx.Lifecycle, pool *service.ScoringWorkerPool) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			pool.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return pool.Stop(ctx)
+		},
+	})
+}
+
+// StartExamDeadlineSweeper ties the auto-submit loop for timed exams to the application lifecycle.
+func StartExamDeadlineSweeper(lc fx.Lifecycle, sweeper *service.ExamDeadlineSweeper) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			sweeper.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return sweeper.Stop(ctx)
+		},
+	})
+}
+
+// StartLLMAuditPurger ties the deletion of expired scoring call records to the application lifecycle.
+func StartLLMAuditPurger(lc fx.Lifecycle, purger *service.LLMAuditPurger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			purger.Start()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return purger.Stop(ctx)
+		},
+	})
+}
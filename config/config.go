@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
@@ -8,6 +10,12 @@ import (
 type Config struct {
 	Server       Server
 	Database     Database
+	JWT          JWT
+	Admin        Admin
+	Scoring      Scoring
+	LLM          LLM
+	Exam         Exam
+	Storage      Storage
 	GeminiApiKey string
 }
 
@@ -22,6 +30,77 @@ type Database struct {
 	Name     string
 }
 
+type JWT struct {
+	Secret    string `json:"-"` // Never log the signing key
+	Issuer    string
+	ExpiresIn time.Duration
+}
+
+// Admin holds the credentials used to bootstrap the first admin account on startup.
+type Admin struct {
+	Email    string
+	Password string `json:"-"`
+}
+
+// Scoring configures the background worker pool that scores submitted attempts.
+type Scoring struct {
+	Workers      int           // Number of concurrent scoring workers in this process
+	PollInterval time.Duration // How often an idle worker checks the job table
+	MaxAttempts  int           // Attempts before a job is marked failed
+	RetryDelay   time.Duration // Base delay before a failed job is retried (doubled per attempt)
+	StaleAfter   time.Duration // A running job whose lock has not been refreshed for this long is assumed orphaned and requeued
+}
+
+// LLM selects and configures the provider used to score answers.
+type LLM struct {
+	Provider string // "gemini", "openai" (any OpenAI-compatible chat endpoint, e.g. llama.cpp or Ollama) or "fake"
+	Model    string // Provider-specific model name; empty uses the provider default
+	BaseURL  string // Base URL of the OpenAI-compatible API, e.g. http://localhost:11434/v1
+	APIKey   string `json:"-"` // API key for the OpenAI-compatible endpoint; Gemini uses GEMINI_API_KEY
+
+	MaxRepairAttempts int // Times a response that fails JSON validation is sent back to the model for repair
+
+	CallTimeout      time.Duration // Limit for a single provider call; retries get their own
+	MaxRetries       int           // Retries of a call that failed with a transient error (timeout, 429, 5xx)
+	RetryBaseDelay   time.Duration // Backoff before the first retry, doubled per retry, with jitter
+	RetryMaxDelay    time.Duration // Upper bound for one backoff, also for a provider's Retry-After
+	MaxConcurrency   int           // Provider calls in flight at once, shared by all submissions in this process
+	BreakerThreshold int           // Consecutive failed calls that open the circuit breaker; 0 disables it
+	BreakerCooldown  time.Duration // How long the breaker stays open before a probe call is let through
+
+	AuditEnabled       bool          // Store the prompt, raw response and usage of every scoring call
+	AuditRetention     time.Duration // Age after which stored calls are deleted; 0 keeps them forever
+	AuditPurgeInterval time.Duration // How often calls older than AuditRetention are deleted
+}
+
+// Exam configures timed exam attempts. Parts run back to back, so each part's deadline is
+// the start time plus the durations of all parts up to and including it.
+type Exam struct {
+	Part1Duration time.Duration // Questions 1-5
+	Part2Duration time.Duration // Questions 6-7
+	Part3Duration time.Duration // Question 8
+	LatePolicy    string        // "reject" refuses answers saved after their part's deadline, "flag" stores them marked as late
+	GracePeriod   time.Duration // Allowance for network latency before an answer counts as late
+	SweepInterval time.Duration // How often expired attempts are auto-submitted
+}
+
+// Storage selects where uploaded images are kept.
+type Storage struct {
+	Backend       string // "local" (a directory on this host) or "s3" (any S3-compatible service, e.g. MinIO)
+	LocalDir      string // Root directory of the local backend
+	MaxImageBytes int64  // Largest accepted image, uploaded or downloaded
+
+	CacheMaxBytes int64         // In-memory cache of image data used by scoring; 0 disables it
+	FetchTimeout  time.Duration // Limit for downloading an external question image
+
+	S3Endpoint  string // e.g. https://s3.eu-west-1.amazonaws.com or http://localhost:9000
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string `json:"-"`
+	S3PathStyle bool   // Address the bucket in the URL path (MinIO and most stand-ins) instead of the host name
+}
+
 func NewConfig() (*Config, error) {
 	viper.SetConfigName(".env")
 	viper.SetConfigType("env")
@@ -29,6 +108,39 @@ func NewConfig() (*Config, error) {
 
 	viper.AutomaticEnv()
 
+	viper.SetDefault("JWT_ISSUER", "ringtails")
+	viper.SetDefault("JWT_EXPIRES_IN", "24h")
+	viper.SetDefault("SCORING_WORKERS", 2)
+	viper.SetDefault("SCORING_POLL_INTERVAL", "2s")
+	viper.SetDefault("SCORING_MAX_ATTEMPTS", 3)
+	viper.SetDefault("SCORING_RETRY_DELAY", "30s")
+	viper.SetDefault("SCORING_STALE_AFTER", "10m")
+	viper.SetDefault("LLM_PROVIDER", "gemini")
+	viper.SetDefault("LLM_MAX_REPAIR_ATTEMPTS", 1)
+	viper.SetDefault("LLM_CALL_TIMEOUT", "90s")
+	viper.SetDefault("LLM_MAX_RETRIES", 3)
+	viper.SetDefault("LLM_RETRY_BASE_DELAY", "1s")
+	viper.SetDefault("LLM_RETRY_MAX_DELAY", "30s")
+	viper.SetDefault("LLM_MAX_CONCURRENCY", 4)
+	viper.SetDefault("LLM_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("LLM_BREAKER_COOLDOWN", "1m")
+	viper.SetDefault("LLM_AUDIT_ENABLED", true)
+	viper.SetDefault("LLM_AUDIT_RETENTION", "720h")
+	viper.SetDefault("LLM_AUDIT_PURGE_INTERVAL", "1h")
+	viper.SetDefault("EXAM_PART1_DURATION", "8m")
+	viper.SetDefault("EXAM_PART2_DURATION", "20m")
+	viper.SetDefault("EXAM_PART3_DURATION", "30m")
+	viper.SetDefault("EXAM_LATE_POLICY", "reject")
+	viper.SetDefault("EXAM_GRACE_PERIOD", "30s")
+	viper.SetDefault("EXAM_SWEEP_INTERVAL", "30s")
+	viper.SetDefault("STORAGE_BACKEND", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "./data/images")
+	viper.SetDefault("STORAGE_MAX_IMAGE_BYTES", 5<<20)
+	viper.SetDefault("STORAGE_CACHE_MAX_BYTES", 64<<20)
+	viper.SetDefault("STORAGE_FETCH_TIMEOUT", "15s")
+	viper.SetDefault("STORAGE_S3_REGION", "us-east-1")
+	viper.SetDefault("STORAGE_S3_PATH_STYLE", true)
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Warn().Err(err).Msg("Error reading config file")
 	}
@@ -42,6 +154,54 @@ func NewConfig() (*Config, error) {
 	config.Database.Password = viper.GetString("DATABASE_PASSWORD")
 	config.Database.Name = viper.GetString("DATABASE_NAME")
 
+	config.JWT.Secret = viper.GetString("JWT_SECRET")
+	config.JWT.Issuer = viper.GetString("JWT_ISSUER")
+	config.JWT.ExpiresIn = viper.GetDuration("JWT_EXPIRES_IN")
+
+	config.Admin.Email = viper.GetString("ADMIN_EMAIL")
+	config.Admin.Password = viper.GetString("ADMIN_PASSWORD")
+
+	config.Scoring.Workers = viper.GetInt("SCORING_WORKERS")
+	config.Scoring.PollInterval = viper.GetDuration("SCORING_POLL_INTERVAL")
+	config.Scoring.MaxAttempts = viper.GetInt("SCORING_MAX_ATTEMPTS")
+	config.Scoring.RetryDelay = viper.GetDuration("SCORING_RETRY_DELAY")
+	config.Scoring.StaleAfter = viper.GetDuration("SCORING_STALE_AFTER")
+
+	config.LLM.Provider = viper.GetString("LLM_PROVIDER")
+	config.LLM.Model = viper.GetString("LLM_MODEL")
+	config.LLM.BaseURL = viper.GetString("LLM_BASE_URL")
+	config.LLM.APIKey = viper.GetString("LLM_API_KEY")
+	config.LLM.MaxRepairAttempts = viper.GetInt("LLM_MAX_REPAIR_ATTEMPTS")
+	config.LLM.CallTimeout = viper.GetDuration("LLM_CALL_TIMEOUT")
+	config.LLM.MaxRetries = viper.GetInt("LLM_MAX_RETRIES")
+	config.LLM.RetryBaseDelay = viper.GetDuration("LLM_RETRY_BASE_DELAY")
+	config.LLM.RetryMaxDelay = viper.GetDuration("LLM_RETRY_MAX_DELAY")
+	config.LLM.MaxConcurrency = viper.GetInt("LLM_MAX_CONCURRENCY")
+	config.LLM.BreakerThreshold = viper.GetInt("LLM_BREAKER_THRESHOLD")
+	config.LLM.BreakerCooldown = viper.GetDuration("LLM_BREAKER_COOLDOWN")
+	config.LLM.AuditEnabled = viper.GetBool("LLM_AUDIT_ENABLED")
+	config.LLM.AuditRetention = viper.GetDuration("LLM_AUDIT_RETENTION")
+	config.LLM.AuditPurgeInterval = viper.GetDuration("LLM_AUDIT_PURGE_INTERVAL")
+
+	config.Exam.Part1Duration = viper.GetDuration("EXAM_PART1_DURATION")
+	config.Exam.Part2Duration = viper.GetDuration("EXAM_PART2_DURATION")
+	config.Exam.Part3Duration = viper.GetDuration("EXAM_PART3_DURATION")
+	config.Exam.LatePolicy = viper.GetString("EXAM_LATE_POLICY")
+	config.Exam.GracePeriod = viper.GetDuration("EXAM_GRACE_PERIOD")
+	config.Exam.SweepInterval = viper.GetDuration("EXAM_SWEEP_INTERVAL")
+
+	config.Storage.Backend = viper.GetString("STORAGE_BACKEND")
+	config.Storage.LocalDir = viper.GetString("STORAGE_LOCAL_DIR")
+	config.Storage.MaxImageBytes = viper.GetInt64("STORAGE_MAX_IMAGE_BYTES")
+	config.Storage.CacheMaxBytes = viper.GetInt64("STORAGE_CACHE_MAX_BYTES")
+	config.Storage.FetchTimeout = viper.GetDuration("STORAGE_FETCH_TIMEOUT")
+	config.Storage.S3Endpoint = viper.GetString("STORAGE_S3_ENDPOINT")
+	config.Storage.S3Region = viper.GetString("STORAGE_S3_REGION")
+	config.Storage.S3Bucket = viper.GetString("STORAGE_S3_BUCKET")
+	config.Storage.S3AccessKey = viper.GetString("STORAGE_S3_ACCESS_KEY")
+	config.Storage.S3SecretKey = viper.GetString("STORAGE_S3_SECRET_KEY")
+	config.Storage.S3PathStyle = viper.GetBool("STORAGE_S3_PATH_STYLE")
+
 	config.GeminiApiKey = viper.GetString("GEMINI_API_KEY")
 
 	log.Info().Interface("config", config).Msg("Config loaded")
@@ -24,9 +24,79 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/tests": {
-            "post": {
-                "description": "Admin creates a new test with exactly 8 questions. All questions must be provided.",
+        "/admin/analytics/tests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Aggregates the submitted attempts of every test: attempt and learner counts, attempts by final status, mean and median raw totals and the share of answers whose AI scoring failed. Use format=csv to download the report as a spreadsheet.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Admin - Analytics"
+                ],
+                "summary": "(Admin/Teacher) Analytics for every test",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAnalyticsSummaryDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/answers/{answer_id}/review": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets a teacher's score and feedback on a finished answer, disputed or not. They replace the AI's in the attempt's total and scaled score, which are recomputed, and are kept when the answer is re-scored; the AI result stays on the answer. An open dispute of the answer is resolved as overridden.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,35 +104,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Admin - Tests"
+                    "Admin - Reviews"
                 ],
-                "summary": "(Admin) Create a new complete test",
+                "summary": "(Admin) Override the score of an answer",
                 "parameters": [
                     {
-                        "description": "Test creation data including all questions (must be 8 questions)",
-                        "name": "test_data",
+                        "type": "integer",
+                        "description": "Answer ID",
+                        "name": "answer_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Teacher's score and feedback",
+                        "name": "review",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestCreateDTO"
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerReviewDTO"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Test created successfully",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestResponseDTO"
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data (e.g., not 8 questions, missing fields)",
+                        "description": "Invalid input or score out of range",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Answer not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Attempt is in progress or being scored",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
@@ -70,21 +165,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/test-attempts/{attempt_id}": {
+        "/admin/answers/{answer_id}/score-history": {
             "get": {
-                "description": "Retrieve full details of a single test attempt, including all answers, scores, and feedback.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the results that were replaced by re-scoring, newest first. The current result is on the answer itself.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User - Tests \u0026 Attempts"
+                    "Admin - Re-scoring"
                 ],
-                "summary": "(User) Get details of a specific test attempt",
+                "summary": "(Admin) List earlier scoring results of an answer",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Test Attempt ID",
-                        "name": "attempt_id",
+                        "description": "Answer ID",
+                        "name": "answer_id",
                         "in": "path",
                         "required": true
                     }
@@ -93,23 +193,26 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptDetailDTO"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerScoreHistoryDTO"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid Test Attempt ID format",
+                        "description": "Invalid Answer ID format",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Test Attempt not found",
+                    "401": {
+                        "description": "Authentication required",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Teacher or admin role required",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
@@ -117,21 +220,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/tests": {
+        "/admin/disputes": {
             "get": {
-                "description": "Get a list of tests. If 'user_id' query param is provided, includes attempt status for that user.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists score disputes raised by learners. With status=open (the queue), the oldest come first; otherwise the newest.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User - Tests \u0026 Attempts"
+                    "Admin - Reviews"
                 ],
-                "summary": "(User) List all available tests",
+                "summary": "(Admin) Review queue of disputed scores",
                 "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "upheld",
+                            "overridden"
+                        ],
+                        "type": "string",
+                        "description": "Dispute status",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Optional User ID to check attempt status against",
-                        "name": "user_id",
+                        "description": "Only disputes of attempts at this test",
+                        "name": "test_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "page_size",
                         "in": "query"
                     }
                 ],
@@ -139,14 +270,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestSummaryDTO"
-                            }
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerDisputeListDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid User ID format",
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
@@ -160,21 +300,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/tests/{test_id}": {
+        "/admin/disputes/{dispute_id}": {
             "get": {
-                "description": "Get full details of a test, including all its questions, for a user to start an attempt.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the dispute with the answer, the question as the learner saw it, the AI result and any teacher review.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User - Tests \u0026 Attempts"
+                    "Admin - Reviews"
                 ],
-                "summary": "(User) Get details of a specific test",
+                "summary": "(Admin) Get a disputed answer",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Test ID",
-                        "name": "test_id",
+                        "description": "Dispute ID",
+                        "name": "dispute_id",
                         "in": "path",
                         "required": true
                     }
@@ -183,23 +328,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestResponseDTO"
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerDisputeDetailDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid Test ID format",
+                        "description": "Invalid dispute ID format",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Test not found",
+                    "401": {
+                        "description": "Authentication required",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Dispute not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
@@ -207,9 +358,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tests/{test_id}/attempts": {
+        "/admin/disputes/{dispute_id}/resolve": {
             "post": {
-                "description": "User submits answers for questions in a specific test. AI scoring happens in the background.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Closes an open dispute. With a score, the teacher's score and feedback replace the AI's in the attempt's total and scaled score, which are recomputed; the AI result is kept on the answer. Without a score, the current score is upheld. The note is shown to the learner.",
                 "consumes": [
                     "application/json"
                 ],
@@ -217,48 +373,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User - Tests \u0026 Attempts"
+                    "Admin - Reviews"
                 ],
-                "summary": "(User) Submit answers for an entire test",
+                "summary": "(Admin) Resolve a score dispute",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID of the Test being attempted",
-                        "name": "test_id",
+                        "description": "Dispute ID",
+                        "name": "dispute_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User ID (optional for now) and list of answers",
-                        "name": "submission_data",
+                        "description": "Decision",
+                        "name": "resolution",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptSubmitDTO"
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.DisputeResolveDTO"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Attempt submitted and processing started. Details might be partial until scoring completes.",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptDetailDTO"
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerDisputeDetailDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid input (e.g., bad Test ID, invalid answers format)",
+                        "description": "Invalid input or score out of range",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Test not found",
+                        "description": "Dispute not found",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Error processing submission",
+                    "409": {
+                        "description": "Dispute already resolved, or attempt is being scored",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
@@ -266,28 +434,93 @@ const docTemplate = `{
                 }
             }
         },
-        "/tests/{test_id}/my-attempts": {
-            "get": {
-                "description": "Retrieve a list of summary information for all attempts a user made on a test.",
+        "/admin/images": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores a PNG, JPEG, WebP, GIF, HEIC or HEIF image, detected from its content rather than its name. Images are identified by the SHA-256 of their data: uploading the same image twice returns the stored one. Use the returned url as a question's image_url.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User - Tests \u0026 Attempts"
+                    "Admin - Images"
                 ],
-                "summary": "(User) Get all attempts by a user for a specific test",
+                "summary": "(Admin) Upload an image for a question",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Test ID",
-                        "name": "test_id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ImageDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing file or not a supported image",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Image too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/images/broken": {
+            "get": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "User ID to filter attempts. (Temporary - will be from auth token)",
-                        "name": "user_id",
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the images of test and bank questions that cannot be used: unreachable, not a supported image, undecodable or too large. Each entry lists the questions using the image and whether a copy kept at an earlier check still lets answers be scored. With recheck=true every external image is downloaded again, which can take a while; otherwise the last check is reported and only images never checked are downloaded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Images"
+                ],
+                "summary": "(Admin) Report broken question images",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Download every external image again",
+                        "name": "recheck",
                         "in": "query"
                     }
                 ],
@@ -295,14 +528,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptSummaryDTO"
-                            }
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BrokenImageReportDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format for Test ID or User ID",
+                        "description": "Invalid recheck value",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
                         "schema": {
                             "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
@@ -315,116 +557,5717 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_lshigami_Ringtails_internal_dto.AnswerResponseDTO": {
-            "type": "object",
-            "properties": {
-                "ai_feedback": {
-                    "type": "string"
-                },
-                "ai_score": {
-                    "type": "number"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "question": {
-                    "description": "Contains full question details",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionResponseDTO"
+        },
+        "/admin/llm-calls": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the calls made to the AI provider while scoring, newest first, without their prompt and response. Filter by answer_id or practice_answer_id to see how one answer was scored; a scoring run that repaired the model's output has one call per attempt. Calls are deleted after LLM_AUDIT_RETENTION.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - LLM Calls"
+                ],
+                "summary": "(Admin) List audited scoring calls",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test answer ID",
+                        "name": "answer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Practice answer ID",
+                        "name": "practice_answer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only calls that failed or returned a rejected response",
+                        "name": "errors_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.LLMCallListDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/llm-calls/{call_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns one call made to the AI provider while scoring, with the exact prompt sent, the raw response, the model, latency, token usage and the error, if any. Images attached to the request are counted, not stored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - LLM Calls"
+                ],
+                "summary": "(Admin) Get an audited scoring call",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Call ID",
+                        "name": "call_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.LLMCallDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid call ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Call not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/prompt-templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every stored version of the scoring prompt templates, by question type and newest first. One or two versions of each type are active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Prompt Templates"
+                ],
+                "summary": "(Admin) List scoring prompt templates",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sentence_picture",
+                            "email_response",
+                            "opinion_essay"
+                        ],
+                        "type": "string",
+                        "description": "Filter by question type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptTemplateDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid question type",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/prompt-templates/{question_type}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores the template as the next version of the question type. The body is a Go text/template rendered with the question and the answer (see docs/prompt-templates.md); it must include the UserAnswer field. The precheck findings and the JSON response schema are appended to every prompt and are not part of the template. The version only affects scoring once activated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Prompt Templates"
+                ],
+                "summary": "(Admin) Write a new version of a scoring prompt template",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sentence_picture",
+                            "email_response",
+                            "opinion_essay"
+                        ],
+                        "type": "string",
+                        "description": "Question type",
+                        "name": "question_type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "template_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptTemplateCreateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptTemplateDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid template",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/prompt-templates/{question_type}/activate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the active versions of the question type with one version, or two versions that split the answers between them by weight (weights sum to 100). Each answer records the version it was scored with, so the two can be compared. Answers already scored keep their result.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Prompt Templates"
+                ],
+                "summary": "(Admin) Choose the active scoring prompt templates",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sentence_picture",
+                            "email_response",
+                            "opinion_essay"
+                        ],
+                        "type": "string",
+                        "description": "Question type",
+                        "name": "question_type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Versions to activate and their weights",
+                        "name": "traffic",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptTemplateActivateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptTemplateDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid versions or weights",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/prompt-templates/{question_type}/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Prompt Templates"
+                ],
+                "summary": "(Admin) Get one version of a scoring prompt template",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sentence_picture",
+                            "email_response",
+                            "opinion_essay"
+                        ],
+                        "type": "string",
+                        "description": "Question type",
+                        "name": "question_type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptTemplateDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/prompt-templates/{question_type}/{version}/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shows the full prompt a template version sends to the model for a sample question and answer, including the precheck findings and the response schema. Nothing is sent to the model.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Prompt Templates"
+                ],
+                "summary": "(Admin) Render a scoring prompt template",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sentence_picture",
+                            "email_response",
+                            "opinion_essay"
+                        ],
+                        "type": "string",
+                        "description": "Question type",
+                        "name": "question_type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sample question and answer",
+                        "name": "sample",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptPreviewDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptPreviewResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version format or template error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Template not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/question-bank": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists bank questions, newest edits first. Filters are combined; \"tag\" may be repeated and matches questions having all given tags.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Question Bank"
+                ],
+                "summary": "(Admin) Browse and search the question bank",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sentence_picture",
+                            "email_response",
+                            "opinion_essay"
+                        ],
+                        "type": "string",
+                        "description": "Question type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "easy",
+                            "medium",
+                            "hard"
+                        ],
+                        "type": "string",
+                        "description": "Difficulty",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tag (repeatable)",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text to search for in title and prompt",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BankQuestionListDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a reusable question. Questions of type sentence_picture need an image URL and both given words.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Question Bank"
+                ],
+                "summary": "(Admin) Add a question to the question bank",
+                "parameters": [
+                    {
+                        "description": "Question data",
+                        "name": "question_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BankQuestionCreateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BankQuestionResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/question-bank/{question_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Question Bank"
+                ],
+                "summary": "(Admin) Get a bank question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BankQuestionResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid question ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Bank question not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Edits a bank question and increments its version. Tests built from earlier versions are not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Question Bank"
+                ],
+                "summary": "(Admin) Update a bank question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "question_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BankQuestionUpdateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BankQuestionResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Bank question not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a question from the bank. Tests built from it keep their copies.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Question Bank"
+                ],
+                "summary": "(Admin) Delete a bank question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Bank question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid question ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Bank question not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/calibration": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "For each question type, compares the teacher's and the AI's score of every reviewed answer the AI had scored: means, the average signed and absolute difference, and how many reviews changed the score.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Reviews"
+                ],
+                "summary": "(Admin) Compare teacher and AI scores",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ReviewCalibrationDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/score-tables": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every stored version of the raw-to-scaled score conversion table, newest first. Exactly one is active.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Score Tables"
+                ],
+                "summary": "(Admin) List score conversion tables",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScoreTableDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stores the table as a new version. Points map raw totals (0-28) to scaled scores; they must start at raw 0, with raw scores increasing and scaled scores not decreasing. \"step\" interpolation gives a raw score the scaled score of the first point at or above it, \"linear\" interpolates between the two surrounding points. The table only affects scoring once activated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Score Tables"
+                ],
+                "summary": "(Admin) Upload a score conversion table",
+                "parameters": [
+                    {
+                        "description": "Conversion table",
+                        "name": "table_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScoreTableUploadDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScoreTableDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid table",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/score-tables/{version}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Score Tables"
+                ],
+                "summary": "(Admin) Get one version of the score conversion table",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Table version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScoreTableDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Table not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/score-tables/{version}/activate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes this version the table used for attempts scored from now on. Attempts already scored keep the scaled score and table version recorded on them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Score Tables"
+                ],
+                "summary": "(Admin) Activate a score conversion table",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Table version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScoreTableDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Table not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/score-tables/{version}/convert": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shows the scaled score a table version gives to a raw total, e.g. to check a table before activating it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Score Tables"
+                ],
+                "summary": "(Admin) Convert a raw score with a given table",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Table version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Raw total score",
+                        "name": "raw",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScoreConversionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid version or raw score",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Table not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/test-attempts/{attempt_id}/rescore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues the selected answers (all answers if \"answer_ids\" is empty; only failed ones if \"only_failed\") for AI scoring again. Their current results are kept in the answer's score history and the attempt's total score is recomputed when scoring finishes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Re-scoring"
+                ],
+                "summary": "(Admin) Re-score answers of any attempt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test Attempt ID",
+                        "name": "attempt_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answers to re-score",
+                        "name": "rescore_data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AttemptRescoreDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Answers queued for scoring",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AttemptRescoreResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, or nothing to re-score",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attempt or answer not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Attempt is in progress or already being scored",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists tests including drafts. Soft-deleted tests are included only when include_deleted is true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) List tests",
+                "parameters": [
+                    {
+                        "enum": [
+                            "draft",
+                            "published"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted tests",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestSummaryDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin creates a new test with exactly 8 questions. All questions must be provided. The test is a draft, hidden from learners, unless \"publish\" is true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Create a new complete test",
+                "parameters": [
+                    {
+                        "description": "Test creation data including all questions (must be 8 questions)",
+                        "name": "test_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestCreateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Test created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data (e.g., not 8 questions, missing fields)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Title already used by another test",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exports the listed tests, or every non-deleted test when ids is omitted, in the format read by POST /admin/tests/import.",
+                "produces": [
+                    "application/yaml",
+                    "application/json",
+                    "application/zip"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Export several tests as one content file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated test IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "yaml",
+                            "json",
+                            "zip"
+                        ],
+                        "type": "string",
+                        "description": "File format (default yaml)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestContentFileDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid IDs or format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/from-bank": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a test from eight bank questions. The n-th ID becomes question n, so IDs must follow the layout: 5 sentence_picture, 2 email_response, 1 opinion_essay. Questions are copied with their current bank version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Build a test from the question bank",
+                "parameters": [
+                    {
+                        "description": "Test metadata and bank question IDs in test order",
+                        "name": "test_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestFromBankCreateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or bank questions do not fit the test layout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Title already used by another test",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Imports the tests of a content file (see docs/test-content-format.md): a JSON or YAML document, or a zip bundle holding one such document and the image files it references. Send the file as the multipart field \"file\" or as the raw request body. Every test is checked with the same rules as POST /admin/tests; tests are only created if all of them are valid, in one transaction. With dry_run=true nothing is created and the report only lists the problems found.",
+                "consumes": [
+                    "multipart/form-data",
+                    "application/json",
+                    "application/yaml",
+                    "application/zip"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Import tests from a content file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Content file (.json, .yaml, .yml or .zip)",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the file",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry-run report",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestImportReportDTO"
+                        }
+                    },
+                    "201": {
+                        "description": "Tests imported",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestImportReportDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "File unreadable or tests invalid; nothing was imported",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestImportReportDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gets a test with its questions, whatever its status. Soft-deleted tests are also returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Get a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the title and/or description of a test. Omitted fields are left unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Update test metadata",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "test_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestUpdateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Title already used by another test",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-deletes a test. It disappears for learners, but its attempts are kept and it can be restored.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Delete a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/analytics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds per-question statistics to the test summary: answer counts, scoring failure rate, mean, median and standard deviation of AIScore/MaxScore, the distribution of that fraction in five 20% buckets and the average answer length. Use format=csv to download one row per question.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Admin - Analytics"
+                ],
+                "summary": "(Admin/Teacher) Analytics for one test and its questions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAnalyticsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid test ID or format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exports the test in the format read by POST /admin/tests/import. In a zip bundle, embedded images are written as files next to tests.yaml.",
+                "produces": [
+                    "application/yaml",
+                    "application/json",
+                    "application/zip"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Export a test as a content file",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "yaml",
+                            "json",
+                            "zip"
+                        ],
+                        "type": "string",
+                        "description": "File format (default yaml)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestContentFileDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID or format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/publish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes a draft test visible to learners. The test must have all 8 questions and must not be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Publish a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Test cannot be published",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/questions/{question_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the content of one question and records it as a new revision. Past answers keep showing the revision they answered. Type, order and max score are fixed by the question's position. Send an empty string to clear an optional field.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Update a question of a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "question_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionUpdateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test or question not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/questions/{question_id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every revision of a test question, oldest first. Answers are pinned to the revision the learner saw.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) List the revisions of a question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionRevisionDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test or question not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/questions/{question_id}/revisions/diff": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the changed fields and a line diff of the prompt. Defaults compare the latest revision with the one before it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Compare two revisions of a question",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Older revision number",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Newer revision number",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionRevisionDiffDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or revision number",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test, question or revision not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/rescore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues all finished attempts of the test for AI scoring again, or with \"only_failed\" just their failed answers. Attempts that are in progress or already being scored are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Re-scoring"
+                ],
+                "summary": "(Admin) Re-score every attempt of a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Re-score options",
+                        "name": "rescore_data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestRescoreDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Attempts queued for scoring",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestRescoreResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores a soft-deleted test with its previous status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Restore a deleted test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No deleted test with this ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tests/{test_id}/unpublish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a test back to draft so learners can no longer see or attempt it. Existing attempts are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Tests"
+                ],
+                "summary": "(Admin) Unpublish a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Teacher or admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists all user accounts, optionally filtered by role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Users"
+                ],
+                "summary": "(Admin) List user accounts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "learner",
+                            "teacher",
+                            "admin"
+                        ],
+                        "type": "string",
+                        "description": "Filter by role",
+                        "name": "role",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.UserResponseDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role filter",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{user_id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Grants or revokes the learner, teacher or admin role. Takes effect on the user's next request. The last admin cannot be demoted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin - Users"
+                ],
+                "summary": "(Admin) Change a user's role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "role_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.UserRoleUpdateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.UserResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid User ID or role",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The user is the last admin",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Exchanges email and password for a signed access token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Log in",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.LoginDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AuthResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email or password",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the account that owns the access token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get the current user's profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.UserResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User no longer exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Creates a learner account and returns an access token for it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register a new account",
+                "parameters": [
+                    {
+                        "description": "Registration data",
+                        "name": "account",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.RegisterDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Account created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AuthResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is already registered",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Aggregates the caller's attempts across all tests: scaled score over time, average score by question type and by rubric criterion, the weakest question type, attempt counts and daily streaks (UTC). Practice answers are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Dashboard"
+                ],
+                "summary": "(User) Get the caller's progress dashboard",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.DashboardDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/disputes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the caller's disputes, newest first, with the teacher's decision and note once resolved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) List my score disputes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerDisputeDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{hash}": {
+            "get": {
+                "description": "Serves an image uploaded by an admin. No token is needed, so the URL works in an img tag. The content never changes for a hash, so responses may be cached indefinitely.",
+                "produces": [
+                    "image/png",
+                    "image/jpeg",
+                    "image/webp",
+                    "image/gif"
+                ],
+                "tags": [
+                    "Images"
+                ],
+                "summary": "Get an uploaded question image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SHA-256 of the image data (64 hex characters)",
+                        "name": "hash",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not modified"
+                    },
+                    "400": {
+                        "description": "Invalid hash",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/practice": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the caller's practice answers, newest first, optionally filtered by question type.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Practice"
+                ],
+                "summary": "(User) Get the caller's practice history",
+                "parameters": [
+                    {
+                        "enum": [
+                            "sentence_picture",
+                            "email_response",
+                            "opinion_essay"
+                        ],
+                        "type": "string",
+                        "description": "Question type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PracticeSummaryDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid type filter",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Scores one answer immediately, either to a question of a published test (question_id) or to an ad-hoc prompt of a given type. An ad-hoc image_url must be an uploaded image (/api/v1/images/\u003chash\u003e) or a data: URI. The result is stored in the caller's practice history, separate from test attempts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Practice"
+                ],
+                "summary": "(User) Practice a single question",
+                "parameters": [
+                    {
+                        "description": "Question or ad-hoc prompt, and the answer",
+                        "name": "practice_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PracticeSubmitDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Practice answer stored; scoring_status is \\\"failed\\\" if the AI could not score it",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PracticeAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Question not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/practice/{practice_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a practice answer with its full AI feedback. Only the owner may read it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Practice"
+                ],
+                "summary": "(User) Get one practice answer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Practice answer ID",
+                        "name": "practice_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PracticeAnswerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid practice ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Practice answer belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Practice answer not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/test-attempts/{attempt_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve full details of a single test attempt, including all answers, scores, and feedback. Only the owner of the attempt may read it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) Get details of a specific test attempt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test Attempt ID",
+                        "name": "attempt_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test Attempt ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Attempt belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test Attempt not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/test-attempts/{attempt_id}/answers/{answer_id}/dispute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asks a teacher to review the score of one of the caller's scored (or failed) answers. Each answer can have one open dispute at a time. The teacher either upholds the score or replaces it; a replaced score appears as human_score on the answer and the attempt's total and scaled score are recomputed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) Dispute the score of an answer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test Attempt ID",
+                        "name": "attempt_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Answer ID",
+                        "name": "answer_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the score seems wrong",
+                        "name": "dispute_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerDisputeCreateDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerDisputeDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or answer not scored",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Attempt belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attempt or answer not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Answer already has an open dispute, or attempt is being scored",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/test-attempts/{attempt_id}/answers/{answer_id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the results of one of the caller's answers that were replaced by re-scoring, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) List earlier scoring results of an answer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test Attempt ID",
+                        "name": "attempt_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Answer ID",
+                        "name": "answer_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerScoreHistoryDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Attempt belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attempt or answer not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/test-attempts/{attempt_id}/answers/{question_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates or overwrites the answer to one question (autosave). \"revision\" must be the revision last received for this answer (0 if never saved); a stale revision is rejected with 409 so that an old tab cannot overwrite newer text. Depending on the server's late policy, answers saved after their part's deadline are rejected (403) or stored with \"late\": true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Timed Exams"
+                ],
+                "summary": "(User) Save one answer of an attempt in progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test Attempt ID",
+                        "name": "attempt_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Question ID",
+                        "name": "question_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Answer text",
+                        "name": "answer_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerSaveDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Attempt belongs to another user, or the part's deadline has passed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attempt or question not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Stale revision, or attempt is no longer in progress",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/test-attempts/{attempt_id}/rescore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues the caller's failed answers (all of them if \"answer_ids\" is empty) for AI scoring again. Answers that were scored successfully cannot be re-scored. The total score is recomputed when scoring finishes; poll the attempt for the result.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) Retry scoring of failed answers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test Attempt ID",
+                        "name": "attempt_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Failed answers to re-score",
+                        "name": "rescore_data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AttemptRescoreDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Answers queued for scoring",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AttemptRescoreResultDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, no failed answers, or an answer did not fail",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Attempt belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attempt or answer not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Attempt is in progress or already being scored",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/test-attempts/{attempt_id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends the attempt and queues the saved answers for AI scoring. Attempts still in progress when the last deadline passes are submitted automatically.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Timed Exams"
+                ],
+                "summary": "(User) Submit an attempt in progress",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test Attempt ID",
+                        "name": "attempt_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Attempt queued for scoring",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID, or no answers saved",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Attempt belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Attempt not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Attempt is no longer in progress",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a list of tests. When called with a valid access token, includes the caller's attempt status for each test.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) List all available tests",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestSummaryDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Access token present but invalid",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}": {
+            "get": {
+                "description": "Get full details of a test, including all its questions, for a user to start an attempt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) Get details of a specific test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/attempts": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "User submits answers for questions in a specific test. The attempt is stored with status \"pending\" and AI scoring happens in the background.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) Submit answers for an entire test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the Test being attempted",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "List of answers",
+                        "name": "submission_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptSubmitDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Attempt stored and queued for scoring. Poll /test-attempts/{attempt_id} until status is completed.",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input (e.g., bad Test ID, invalid answers format)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error processing submission",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/attempts/in-progress": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the caller's unsubmitted attempt for the test with every saved answer and its revision, so it can be resumed on any device.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Timed Exams"
+                ],
+                "summary": "(User) Resume the attempt in progress for a test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No attempt in progress",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/attempts/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts an attempt whose answers are saved one by one. Timed attempts (the default) get the deadline of each part (Q1-5, Q6-7, Q8) and are auto-submitted when time runs out. If the caller already has an attempt in progress for this test, that attempt is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Timed Exams"
+                ],
+                "summary": "(User) Start an attempt answered over time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Attempt options",
+                        "name": "start_data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AttemptStartDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Attempt in progress",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptDetailDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Test not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tests/{test_id}/my-attempts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of summary information for all attempts the authenticated user made on a test.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User - Tests \u0026 Attempts"
+                ],
+                "summary": "(User) Get all of the caller's attempts for a specific test",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Test ID",
+                        "name": "test_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestAttemptSummaryDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Test ID format",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_lshigami_Ringtails_internal_dto.AdminTestDetailDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionResponseDTO"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AdminTestSummaryDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "published_at": {
+                    "type": "string"
+                },
+                "question_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerDisputeCreateDTO": {
+            "type": "object",
+            "required": [
+                "comment"
+            ],
+            "properties": {
+                "comment": {
+                    "description": "Why the score seems wrong",
+                    "type": "string",
+                    "maxLength": 2000
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerDisputeDTO": {
+            "type": "object",
+            "properties": {
+                "answer_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disputed_score": {
+                    "description": "Score of the answer when the dispute was raised",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_in_test": {
+                    "type": "integer"
+                },
+                "question_type": {
+                    "type": "string"
+                },
+                "resolution_note": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "reviewed_by_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "\"open\", \"upheld\" or \"overridden\"",
+                    "type": "string"
+                },
+                "test_attempt_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerDisputeDetailDTO": {
+            "type": "object",
+            "properties": {
+                "answer": {
+                    "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerResponseDTO"
+                },
+                "answer_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disputed_score": {
+                    "description": "Score of the answer when the dispute was raised",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "order_in_test": {
+                    "type": "integer"
+                },
+                "question_type": {
+                    "type": "string"
+                },
+                "resolution_note": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "reviewed_by_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "\"open\", \"upheld\" or \"overridden\"",
+                    "type": "string"
+                },
+                "test_attempt_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerDisputeListDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerDisputeDTO"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerErrorDTO": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "correction": {
+                    "type": "string"
+                },
+                "explanation": {
+                    "type": "string"
+                },
+                "original": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerResponseDTO": {
+            "type": "object",
+            "properties": {
+                "ai_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerErrorDTO"
+                    }
+                },
+                "ai_feedback": {
+                    "type": "string"
+                },
+                "ai_revised_answer": {
+                    "description": "Question 8 only",
+                    "type": "string"
+                },
+                "ai_score": {
+                    "type": "number"
+                },
+                "ai_strengths": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ai_uncapped_score": {
+                    "description": "The AI's score when a hard precheck finding capped ai_score",
+                    "type": "number"
+                },
+                "ai_vocabulary": {
+                    "description": "Question 8 only",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.VocabularyItemDTO"
+                    }
+                },
+                "criterion_scores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.CriterionScoreDTO"
+                    }
+                },
+                "human_feedback": {
+                    "type": "string"
+                },
+                "human_score": {
+                    "description": "Set by a teacher's review; counts instead of ai_score",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "late": {
+                    "description": "Saved after its part's deadline",
+                    "type": "boolean"
+                },
+                "model_name": {
+                    "type": "string"
+                },
+                "prechecks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PrecheckFindingDTO"
+                    }
+                },
+                "prompt_version": {
+                    "description": "Prompt template version of the question type used for scoring",
+                    "type": "integer"
+                },
+                "question": {
+                    "description": "Content of the question revision that was answered",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionResponseDTO"
+                        }
+                    ]
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "question_revision_id": {
+                    "type": "integer"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "Send back when saving the answer again",
+                    "type": "integer"
+                },
+                "saved_at": {
+                    "description": "Last time the answer was saved during the attempt",
+                    "type": "string"
+                },
+                "scoring_status": {
+                    "description": "\"pending\", \"scored\", \"failed\", \"deferred\"",
+                    "type": "string"
+                },
+                "user_answer": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerReviewDTO": {
+            "type": "object",
+            "required": [
+                "score"
+            ],
+            "properties": {
+                "feedback": {
+                    "type": "string"
+                },
+                "note": {
+                    "description": "Reply to the learner when an open dispute is resolved by the review",
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerSaveDTO": {
+            "type": "object",
+            "required": [
+                "user_answer"
+            ],
+            "properties": {
+                "revision": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "user_answer": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AnswerScoreHistoryDTO": {
+            "type": "object",
+            "properties": {
+                "ai_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerErrorDTO"
+                    }
+                },
+                "ai_feedback": {
+                    "type": "string"
+                },
+                "ai_revised_answer": {
+                    "type": "string"
+                },
+                "ai_score": {
+                    "type": "number"
+                },
+                "ai_strengths": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ai_uncapped_score": {
+                    "type": "number"
+                },
+                "ai_vocabulary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.VocabularyItemDTO"
+                    }
+                },
+                "answer_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "When the result was replaced",
+                    "type": "string"
+                },
+                "criterion_scores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.CriterionScoreDTO"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "model_name": {
+                    "type": "string"
+                },
+                "prechecks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PrecheckFindingDTO"
+                    }
+                },
+                "prompt_version": {
+                    "type": "integer"
+                },
+                "requested_by_id": {
+                    "type": "integer"
+                },
+                "scored_at": {
+                    "type": "string"
+                },
+                "scoring_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AttemptRescoreDTO": {
+            "type": "object",
+            "properties": {
+                "answer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "only_failed": {
+                    "description": "Admin only; learners are always limited to failed answers",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AttemptRescoreResultDTO": {
+            "type": "object",
+            "properties": {
+                "answer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "attempt_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AttemptStartDTO": {
+            "type": "object",
+            "properties": {
+                "timed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.AuthResponseDTO": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "description": "Always \"Bearer\"",
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.UserResponseDTO"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.BankQuestionCreateDTO": {
+            "type": "object",
+            "required": [
+                "prompt",
+                "title",
+                "type"
+            ],
+            "properties": {
+                "difficulty": {
+                    "description": "Defaults to \"medium\"",
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "sentence_picture",
+                        "email_response",
+                        "opinion_essay"
+                    ]
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.BankQuestionListDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BankQuestionResponseDTO"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.BankQuestionResponseDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "difficulty": {
+                    "type": "string"
+                },
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.BankQuestionUpdateDTO": {
+            "type": "object",
+            "properties": {
+                "difficulty": {
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "prompt": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.BrokenImageDTO": {
+            "type": "object",
+            "properties": {
+                "bank_question_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "has_stored_copy": {
+                    "description": "Scoring still works from the copy kept when the image last passed",
+                    "type": "boolean"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "last_ok_at": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ImageUsageDTO"
+                    }
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.BrokenImageReportDTO": {
+            "type": "object",
+            "properties": {
+                "broken": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.BrokenImageDTO"
+                    }
+                },
+                "checked": {
+                    "description": "Distinct image URLs in use",
+                    "type": "integer"
+                },
+                "rechecked": {
+                    "description": "External images were downloaded again for this report",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.CriterionAverageDTO": {
+            "type": "object",
+            "properties": {
+                "average_percent": {
+                    "type": "number"
+                },
+                "average_score": {
+                    "type": "number"
+                },
+                "criterion": {
+                    "type": "string"
+                },
+                "scores": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.CriterionScoreDTO": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "criterion": {
+                    "type": "string"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.DashboardAttemptCountsDTO": {
+            "type": "object",
+            "properties": {
+                "being_scored": {
+                    "description": "Pending or scoring",
+                    "type": "integer"
+                },
+                "completed": {
+                    "description": "Scored, including attempts where some answers failed",
+                    "type": "integer"
+                },
+                "distinct_tests": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Scoring failed for the whole attempt",
+                    "type": "integer"
+                },
+                "in_progress": {
+                    "type": "integer"
+                },
+                "submitted": {
+                    "description": "Every attempt except those in progress or expired unanswered",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.DashboardDTO": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.DashboardAttemptCountsDTO"
+                },
+                "average_scaled_score": {
+                    "type": "number"
+                },
+                "best_scaled_score": {
+                    "type": "number"
+                },
+                "criteria": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.CriterionAverageDTO"
+                    }
+                },
+                "question_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionTypeAverageDTO"
+                    }
+                },
+                "score_history": {
+                    "description": "Scored attempts, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScoreHistoryPointDTO"
+                    }
+                },
+                "streak": {
+                    "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.StreakDTO"
+                },
+                "weakest_question_type": {
+                    "description": "Lowest average percentage",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionTypeAverageDTO"
+                        }
+                    ]
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.DiffLineDTO": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.DisputeResolveDTO": {
+            "type": "object",
+            "properties": {
+                "feedback": {
+                    "type": "string"
+                },
+                "note": {
+                    "description": "Reply to the learner",
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.FieldChangeDTO": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ImageDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "original_name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ImageUsageDTO": {
+            "type": "object",
+            "properties": {
+                "order_in_test": {
+                    "type": "integer"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "test_title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.LLMCallDTO": {
+            "type": "object",
+            "properties": {
+                "answer_id": {
+                    "type": "integer"
+                },
+                "attempt": {
+                    "description": "1 for the first call, higher for calls repairing a rejected response",
+                    "type": "integer"
+                },
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_count": {
+                    "type": "integer"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "practice_answer_id": {
+                    "type": "integer"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "prompt_version": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "question_type": {
+                    "type": "string"
+                },
+                "raw_response": {
+                    "type": "string"
+                },
+                "retry": {
+                    "description": "0 for the first try, higher for retries after a transient provider error",
+                    "type": "integer"
+                },
+                "total_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.LLMCallListDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.LLMCallSummaryDTO"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.LLMCallSummaryDTO": {
+            "type": "object",
+            "properties": {
+                "answer_id": {
+                    "type": "integer"
+                },
+                "attempt": {
+                    "description": "1 for the first call, higher for calls repairing a rejected response",
+                    "type": "integer"
+                },
+                "completion_tokens": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_count": {
+                    "type": "integer"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "practice_answer_id": {
+                    "type": "integer"
+                },
+                "prompt_tokens": {
+                    "type": "integer"
+                },
+                "prompt_version": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "question_type": {
+                    "type": "string"
+                },
+                "retry": {
+                    "description": "0 for the first try, higher for retries after a transient provider error",
+                    "type": "integer"
+                },
+                "total_tokens": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.LoginDTO": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PartScoreDTO": {
+            "type": "object",
+            "properties": {
+                "answered": {
+                    "description": "Answers submitted in this part",
+                    "type": "integer"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "part": {
+                    "description": "1: Q1-5 sentences, 2: Q6-7 emails, 3: Q8 essay",
+                    "type": "integer"
+                },
+                "questions": {
+                    "description": "Question numbers in the part, e.g. \"1-5\"",
+                    "type": "string"
+                },
+                "raw_score": {
+                    "type": "number"
+                },
+                "scored": {
+                    "description": "Of those, answers scored successfully",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PracticeAnswerDTO": {
+            "type": "object",
+            "properties": {
+                "ai_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerErrorDTO"
+                    }
+                },
+                "ai_feedback": {
+                    "type": "string"
+                },
+                "ai_revised_answer": {
+                    "type": "string"
+                },
+                "ai_score": {
+                    "type": "number"
+                },
+                "ai_strengths": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ai_uncapped_score": {
+                    "description": "The AI's score when a hard precheck finding capped ai_score",
+                    "type": "number"
+                },
+                "ai_vocabulary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.VocabularyItemDTO"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "criterion_scores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.CriterionScoreDTO"
+                    }
+                },
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "model_name": {
+                    "type": "string"
+                },
+                "prechecks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PrecheckFindingDTO"
+                    }
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "prompt_version": {
+                    "description": "Prompt template version of the question type used for scoring",
+                    "type": "integer"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "scoring_status": {
+                    "description": "\"scored\", \"failed\", or \"deferred\" when the AI provider was unavailable",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_answer": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PracticeSubmitDTO": {
+            "type": "object",
+            "required": [
+                "user_answer"
+            ],
+            "properties": {
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "sentence_picture",
+                        "email_response",
+                        "opinion_essay"
+                    ]
+                },
+                "user_answer": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PracticeSummaryDTO": {
+            "type": "object",
+            "properties": {
+                "ai_score": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "scoring_status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PrecheckFindingDTO": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "\"empty\", \"language\", \"sentence_count\", \"given_words\", \"word_count\"",
+                    "type": "string"
+                },
+                "score_cap": {
+                    "type": "number"
+                },
+                "severity": {
+                    "description": "\"hard\" or \"advisory\"",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ProficiencyLevelDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PromptPreviewDTO": {
+            "type": "object",
+            "properties": {
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "order_in_test": {
+                    "description": "0 uses a sample position of the template's type; any other position must hold a question of that type",
+                    "type": "integer"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "user_answer": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PromptPreviewResultDTO": {
+            "type": "object",
+            "properties": {
+                "prompt": {
+                    "type": "string"
+                },
+                "question_type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PromptTemplateActivateDTO": {
+            "type": "object",
+            "required": [
+                "versions"
+            ],
+            "properties": {
+                "versions": {
+                    "type": "array",
+                    "maxItems": 2,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PromptTrafficDTO"
+                    }
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PromptTemplateCreateDTO": {
+            "type": "object",
+            "required": [
+                "body",
+                "name"
+            ],
+            "properties": {
+                "activate": {
+                    "description": "Make it the only active version right away",
+                    "type": "boolean"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PromptTemplateDTO": {
+            "type": "object",
+            "properties": {
+                "activated_at": {
+                    "type": "string"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "question_type": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "\"file\" for the built-in template, \"admin\" for versions written by admins",
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "weight": {
+                    "description": "Percent of answers scored with this version while active",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.PromptTrafficDTO": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "version": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "weight": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.QuestionAnalyticsDTO": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "integer"
+                },
+                "average_chars": {
+                    "type": "number"
+                },
+                "average_words": {
+                    "type": "number"
+                },
+                "distribution": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScoreBucketDTO"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "failure_rate": {
+                    "type": "number"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "mean_fraction": {
+                    "type": "number"
+                },
+                "median_fraction": {
+                    "type": "number"
+                },
+                "order_in_test": {
+                    "type": "integer"
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "scored": {
+                    "type": "integer"
+                },
+                "stddev_fraction": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.QuestionContentDTO": {
+            "type": "object",
+            "properties": {
+                "given_words": {
+                    "description": "Exactly 2 for sentence_picture questions",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "image": {
+                    "description": "http(s) URL, data: URI or path relative to the content file in a zip bundle",
+                    "type": "string"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "order": {
+                    "type": "integer"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.QuestionCreateDTO": {
+            "type": "object",
+            "required": [
+                "max_score",
+                "order_in_test",
+                "prompt",
+                "title",
+                "type"
+            ],
+            "properties": {
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "order_in_test": {
+                    "type": "integer",
+                    "maximum": 8,
+                    "minimum": 1
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "sentence_picture",
+                        "email_response",
+                        "opinion_essay"
                     ]
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.QuestionResponseDTO": {
+            "type": "object",
+            "properties": {
+                "bank_question_id": {
+                    "type": "integer"
+                },
+                "bank_question_version": {
+                    "type": "integer"
+                },
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "order_in_test": {
+                    "type": "integer"
+                },
+                "prompt": {
+                    "type": "string"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.QuestionRevisionDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "edited_by_id": {
+                    "type": "integer"
+                },
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_current": {
+                    "type": "boolean"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "prompt": {
+                    "type": "string"
                 },
                 "question_id": {
                     "type": "integer"
                 },
-                "user_answer": {
+                "revision": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "github_com_lshigami_Ringtails_internal_dto.ErrorResponse": {
+        "github_com_lshigami_Ringtails_internal_dto.QuestionRevisionDiffDTO": {
             "type": "object",
             "properties": {
-                "details": {
+                "changes": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.FieldChangeDTO"
+                    }
+                },
+                "from_revision": {
+                    "type": "integer"
+                },
+                "prompt_diff": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.DiffLineDTO"
+                    }
+                },
+                "question_id": {
+                    "type": "integer"
+                },
+                "to_revision": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.QuestionTypeAverageDTO": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "description": "Scored answers of this type",
+                    "type": "integer"
+                },
+                "average_percent": {
+                    "description": "Average score as a percentage of the max score",
+                    "type": "number"
+                },
+                "average_score": {
+                    "type": "number"
+                },
+                "max_score": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.QuestionUpdateDTO": {
+            "type": "object",
+            "properties": {
+                "given_word1": {
+                    "type": "string"
+                },
+                "given_word2": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "prompt": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "title": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.RegisterDTO": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "bcrypt ignores bytes after 72",
+                    "type": "string",
+                    "maxLength": 72,
+                    "minLength": 8
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ReviewCalibrationDTO": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "description": "Reviews that changed the score",
+                    "type": "integer"
+                },
+                "mean_abs_difference": {
+                    "description": "Average size of the correction",
+                    "type": "number"
+                },
+                "mean_ai_score": {
+                    "type": "number"
+                },
+                "mean_difference": {
+                    "description": "Teacher minus AI; positive when the AI scores too low",
+                    "type": "number"
+                },
+                "mean_human_score": {
+                    "type": "number"
+                },
+                "question_type": {
+                    "type": "string"
+                },
+                "reviewed": {
+                    "description": "Answers with both an AI and a teacher score",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ScoreBucketDTO": {
+            "type": "object",
+            "properties": {
+                "answers": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ScoreConversionDTO": {
+            "type": "object",
+            "properties": {
+                "raw_score": {
+                    "type": "number"
+                },
+                "scaled_score": {
+                    "type": "number"
+                },
+                "table_version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ScoreHistoryPointDTO": {
+            "type": "object",
+            "properties": {
+                "attempt_id": {
+                    "type": "integer"
+                },
+                "scaled_score": {
+                    "type": "number"
+                },
+                "score_table_version": {
+                    "type": "integer"
+                },
+                "submitted_at": {
+                    "type": "string"
+                },
+                "test_id": {
+                    "type": "integer"
+                },
+                "test_title": {
+                    "type": "string"
+                },
+                "total_raw_score": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ScorePointDTO": {
+            "type": "object",
+            "properties": {
+                "raw": {
+                    "type": "number"
+                },
+                "scaled": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ScoreTableDTO": {
+            "type": "object",
+            "properties": {
+                "activated_at": {
+                    "type": "string"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "interpolation": {
+                    "type": "string"
+                },
+                "max_raw_score": {
+                    "type": "number"
+                },
+                "max_scaled_score": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScorePointDTO"
+                    }
+                },
+                "round_to": {
+                    "type": "number"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.ScoreTableUploadDTO": {
+            "type": "object",
+            "required": [
+                "name",
+                "points"
+            ],
+            "properties": {
+                "activate": {
+                    "description": "Make it the active table right away",
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "interpolation": {
+                    "description": "Default \"step\"",
+                    "type": "string",
+                    "enum": [
+                        "step",
+                        "linear"
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "minItems": 2,
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ScorePointDTO"
                     }
                 },
+                "round_to": {
+                    "description": "Default 5; 0 keeps unrounded scores",
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.StreakDTO": {
+            "type": "object",
+            "properties": {
+                "current_days": {
+                    "description": "Still running if the last active day is today or yesterday",
+                    "type": "integer"
+                },
+                "last_active_on": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "longest_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "github_com_lshigami_Ringtails_internal_dto.QuestionCreateDTO": {
+        "github_com_lshigami_Ringtails_internal_dto.TestAnalyticsDTO": {
             "type": "object",
-            "required": [
-                "max_score",
-                "order_in_test",
-                "prompt",
-                "title",
-                "type"
-            ],
             "properties": {
-                "given_word1": {
-                    "type": "string"
+                "answer_failure_rate": {
+                    "description": "Failed / (scored + failed) answers",
+                    "type": "number"
                 },
-                "given_word2": {
-                    "type": "string"
+                "attempts": {
+                    "type": "integer"
                 },
-                "image_url": {
-                    "type": "string"
+                "completed": {
+                    "type": "integer"
                 },
-                "max_score": {
+                "completed_with_errors": {
+                    "type": "integer"
+                },
+                "errored": {
+                    "type": "integer"
+                },
+                "learners": {
+                    "type": "integer"
+                },
+                "mean_raw_score": {
                     "type": "number"
                 },
-                "order_in_test": {
-                    "type": "integer",
-                    "maximum": 8,
-                    "minimum": 1
+                "median_raw_score": {
+                    "type": "number"
                 },
-                "prompt": {
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionAnalyticsDTO"
+                    }
+                },
+                "status": {
                     "type": "string"
                 },
+                "test_id": {
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
-                },
-                "type": {
-                    "type": "string",
-                    "enum": [
-                        "sentence_picture",
-                        "email_response",
-                        "opinion_essay"
-                    ]
                 }
             }
         },
-        "github_com_lshigami_Ringtails_internal_dto.QuestionResponseDTO": {
+        "github_com_lshigami_Ringtails_internal_dto.TestAnalyticsSummaryDTO": {
             "type": "object",
             "properties": {
-                "given_word1": {
-                    "type": "string"
+                "answer_failure_rate": {
+                    "description": "Failed / (scored + failed) answers",
+                    "type": "number"
                 },
-                "given_word2": {
-                    "type": "string"
+                "attempts": {
+                    "type": "integer"
                 },
-                "id": {
+                "completed": {
                     "type": "integer"
                 },
-                "image_url": {
-                    "type": "string"
+                "completed_with_errors": {
+                    "type": "integer"
                 },
-                "max_score": {
-                    "type": "number"
+                "errored": {
+                    "type": "integer"
                 },
-                "order_in_test": {
+                "learners": {
                     "type": "integer"
                 },
-                "prompt": {
+                "mean_raw_score": {
+                    "type": "number"
+                },
+                "median_raw_score": {
+                    "type": "number"
+                },
+                "status": {
                     "type": "string"
                 },
                 "test_id": {
@@ -432,9 +6275,6 @@ const docTemplate = `{
                 },
                 "title": {
                     "type": "string"
-                },
-                "type": {
-                    "type": "string"
                 }
             }
         },
@@ -448,13 +6288,51 @@ const docTemplate = `{
                         "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.AnswerResponseDTO"
                     }
                 },
+                "auto_submitted": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "part1_deadline_at": {
+                    "description": "Deadline for Q1-5",
+                    "type": "string"
+                },
+                "part2_deadline_at": {
+                    "description": "Deadline for Q6-7",
+                    "type": "string"
+                },
+                "part3_deadline_at": {
+                    "description": "Deadline for Q8 and end of the exam",
+                    "type": "string"
+                },
+                "part_scores": {
+                    "description": "Once scored",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PartScoreDTO"
+                    }
+                },
+                "proficiency_level": {
+                    "description": "Estimated from ScaledScore",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ProficiencyLevelDTO"
+                        }
+                    ]
+                },
                 "scaled_score": {
                     "description": "Điểm đã quy đổi",
                     "type": "number"
                 },
+                "score_table_version": {
+                    "description": "Conversion table that produced ScaledScore",
+                    "type": "integer"
+                },
+                "started_at": {
+                    "description": "Set for every started attempt, timed or not; nil when answers were submitted without starting",
+                    "type": "string"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -487,10 +6365,6 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.UserAnswerDTO"
                     }
-                },
-                "user_id": {
-                    "description": "Temporary, for non-auth user identification",
-                    "type": "integer"
                 }
             }
         },
@@ -500,10 +6374,23 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "part_scores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.PartScoreDTO"
+                    }
+                },
+                "proficiency_level": {
+                    "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.ProficiencyLevelDTO"
+                },
                 "scaled_score": {
                     "description": "Điểm đã quy đổi",
                     "type": "number"
                 },
+                "score_table_version": {
+                    "description": "Conversion table that produced ScaledScore",
+                    "type": "integer"
+                },
                 "status": {
                     "type": "string"
                 },
@@ -522,6 +6409,40 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_lshigami_Ringtails_internal_dto.TestContentDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "publish": {
+                    "type": "boolean"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.QuestionContentDTO"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.TestContentFileDTO": {
+            "type": "object",
+            "properties": {
+                "format_version": {
+                    "type": "integer"
+                },
+                "tests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestContentDTO"
+                    }
+                }
+            }
+        },
         "github_com_lshigami_Ringtails_internal_dto.TestCreateDTO": {
             "type": "object",
             "required": [
@@ -532,6 +6453,9 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "publish": {
+                    "type": "boolean"
+                },
                 "questions": {
                     "type": "array",
                     "maxItems": 8,
@@ -545,6 +6469,109 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_lshigami_Ringtails_internal_dto.TestFromBankCreateDTO": {
+            "type": "object",
+            "required": [
+                "bank_question_ids",
+                "title"
+            ],
+            "properties": {
+                "bank_question_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "publish": {
+                    "type": "boolean"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.TestImportItemDTO": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "index": {
+                    "description": "Position of the test in the file, from 0",
+                    "type": "integer"
+                },
+                "test_id": {
+                    "description": "Set once the test is created",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.TestImportReportDTO": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "description": "Problems with the file itself",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "tests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_lshigami_Ringtails_internal_dto.TestImportItemDTO"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.TestRescoreDTO": {
+            "type": "object",
+            "properties": {
+                "only_failed": {
+                    "description": "Only attempts with failed answers, and only those answers",
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.TestRescoreResultDTO": {
+            "type": "object",
+            "properties": {
+                "answers_queued": {
+                    "type": "integer"
+                },
+                "attempts_queued": {
+                    "type": "integer"
+                },
+                "attempts_skipped": {
+                    "description": "Still in progress or already being scored",
+                    "type": "integer"
+                },
+                "test_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_lshigami_Ringtails_internal_dto.TestResponseDTO": {
             "type": "object",
             "properties": {
@@ -602,6 +6629,18 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_lshigami_Ringtails_internal_dto.TestUpdateDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
         "github_com_lshigami_Ringtails_internal_dto.UserAnswerDTO": {
             "type": "object",
             "required": [
@@ -616,6 +6655,61 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.UserResponseDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.UserRoleUpdateDTO": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "learner",
+                        "teacher",
+                        "admin"
+                    ]
+                }
+            }
+        },
+        "github_com_lshigami_Ringtails_internal_dto.VocabularyItemDTO": {
+            "type": "object",
+            "properties": {
+                "meaning": {
+                    "type": "string"
+                },
+                "term": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Type \"Bearer\" followed by a space and the access token returned by /auth/login.",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
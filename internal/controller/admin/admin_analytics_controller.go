@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminAnalyticsController struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAdminAnalyticsController(analyticsService service.AnalyticsService) *AdminAnalyticsController {
+	return &AdminAnalyticsController{analyticsService: analyticsService}
+}
+
+// ListTestAnalytics godoc
+// @Summary (Admin/Teacher) Analytics for every test
+// @Description Aggregates the submitted attempts of every test: attempt and learner counts, attempts by final status, mean and median raw totals and the share of answers whose AI scoring failed. Use format=csv to download the report as a spreadsheet.
+// @Tags Admin - Analytics
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param format query string false "Response format" Enums(json, csv)
+// @Success 200 {array} dto.TestAnalyticsSummaryDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/analytics/tests [get]
+func (c *AdminAnalyticsController) ListTestAnalytics(ctx *gin.Context) {
+	asCSV, ok := parseAnalyticsFormat(ctx)
+	if !ok {
+		return
+	}
+	summaries, err := c.analyticsService.ListTestAnalytics()
+	if err != nil {
+		respondAnalyticsError(ctx, "Admin ListTestAnalytics", err)
+		return
+	}
+	if asCSV {
+		writeAnalyticsCSV(ctx, fmt.Sprintf("test-analytics-%s.csv", time.Now().Format("20060102")), func(ctx *gin.Context) error {
+			return service.WriteTestAnalyticsCSV(ctx.Writer, summaries)
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, summaries)
+}
+
+// GetTestAnalytics godoc
+// @Summary (Admin/Teacher) Analytics for one test and its questions
+// @Description Adds per-question statistics to the test summary: answer counts, scoring failure rate, mean, median and standard deviation of AIScore/MaxScore, the distribution of that fraction in five 20% buckets and the average answer length. Use format=csv to download one row per question.
+// @Tags Admin - Analytics
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Param format query string false "Response format" Enums(json, csv)
+// @Success 200 {object} dto.TestAnalyticsDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid test ID or format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/tests/{test_id}/analytics [get]
+func (c *AdminAnalyticsController) GetTestAnalytics(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	asCSV, ok := parseAnalyticsFormat(ctx)
+	if !ok {
+		return
+	}
+	report, err := c.analyticsService.GetTestAnalytics(testID)
+	if err != nil {
+		respondAnalyticsError(ctx, "Admin GetTestAnalytics", err)
+		return
+	}
+	if asCSV {
+		writeAnalyticsCSV(ctx, fmt.Sprintf("test-%d-question-analytics.csv", testID), func(ctx *gin.Context) error {
+			return service.WriteQuestionAnalyticsCSV(ctx.Writer, report)
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// parseAnalyticsFormat reports whether CSV output was requested.
+func parseAnalyticsFormat(ctx *gin.Context) (bool, bool) {
+	switch ctx.DefaultQuery("format", "json") {
+	case "json":
+		return false, true
+	case "csv":
+		return true, true
+	default:
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "format must be 'json' or 'csv'"})
+		return false, false
+	}
+}
+
+func writeAnalyticsCSV(ctx *gin.Context, filename string, write func(ctx *gin.Context) error) {
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
+	if err := write(ctx); err != nil {
+		// Headers are already sent; the client gets a truncated file.
+		log.Error().Err(err).Str("filename", filename).Msg("Admin analytics: Failed to write CSV")
+	}
+}
+
+func respondAnalyticsError(ctx *gin.Context, operation string, err error) {
+	if errors.Is(err, service.ErrTestNotFound) {
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	log.Error().Err(err).Msg(operation + ": Service error")
+	ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to build analytics", Details: []string{err.Error()}})
+}
@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminImageController struct {
+	imageService service.ImageService
+}
+
+func NewAdminImageController(imageService service.ImageService) *AdminImageController {
+	return &AdminImageController{imageService: imageService}
+}
+
+// UploadImage godoc
+// @Summary (Admin) Upload an image for a question
+// @Description Stores a PNG, JPEG, WebP, GIF, HEIC or HEIF image, detected from its content rather than its name. Images are identified by the SHA-256 of their data: uploading the same image twice returns the stored one. Use the returned url as a question's image_url.
+// @Tags Admin - Images
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Image file"
+// @Success 201 {object} dto.ImageDTO
+// @Failure 400 {object} dto.ErrorResponse "Missing file or not a supported image"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 413 {object} dto.ErrorResponse "Image too large"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/images [post]
+func (c *AdminImageController) UploadImage(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxUploadBytes)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		respondUploadReadError(ctx, err, "Multipart field 'file' is required")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Cannot open the uploaded file", Details: []string{err.Error()}})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondUploadReadError(ctx, err, "Cannot read the uploaded file")
+		return
+	}
+
+	image, err := c.imageService.Upload(ctx.Request.Context(), &userID, fileHeader.Filename, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrImageTooLarge):
+			ctx.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, service.ErrInvalidImage):
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		default:
+			log.Error().Err(err).Msg("Admin UploadImage: Service error")
+			ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to store the image", Details: []string{err.Error()}})
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, image)
+}
+
+// BrokenImageReport godoc
+// @Summary (Admin) Report broken question images
+// @Description Lists the images of test and bank questions that cannot be used: unreachable, not a supported image, undecodable or too large. Each entry lists the questions using the image and whether a copy kept at an earlier check still lets answers be scored. With recheck=true every external image is downloaded again, which can take a while; otherwise the last check is reported and only images never checked are downloaded.
+// @Tags Admin - Images
+// @Produce json
+// @Security BearerAuth
+// @Param recheck query bool false "Download every external image again"
+// @Success 200 {object} dto.BrokenImageReportDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid recheck value"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/images/broken [get]
+func (c *AdminImageController) BrokenImageReport(ctx *gin.Context) {
+	recheck := false
+	if raw := ctx.Query("recheck"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid recheck value"})
+			return
+		}
+		recheck = parsed
+	}
+
+	report, err := c.imageService.BrokenImageReport(ctx.Request.Context(), recheck)
+	if err != nil {
+		log.Error().Err(err).Msg("Admin BrokenImageReport: Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to check question images", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
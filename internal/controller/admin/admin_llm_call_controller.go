@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminLLMCallController struct {
+	llmAuditService service.LLMAuditService
+}
+
+func NewAdminLLMCallController(llmAuditService service.LLMAuditService) *AdminLLMCallController {
+	return &AdminLLMCallController{llmAuditService: llmAuditService}
+}
+
+// ListLLMCalls godoc
+// @Summary (Admin) List audited scoring calls
+// @Description Lists the calls made to the AI provider while scoring, newest first, without their prompt and response. Filter by answer_id or practice_answer_id to see how one answer was scored; a scoring run that repaired the model's output has one call per attempt. Calls are deleted after LLM_AUDIT_RETENTION.
+// @Tags Admin - LLM Calls
+// @Produce json
+// @Security BearerAuth
+// @Param answer_id query int false "Test answer ID"
+// @Param practice_answer_id query int false "Practice answer ID"
+// @Param errors_only query bool false "Only calls that failed or returned a rejected response"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} dto.LLMCallListDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid filter"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/llm-calls [get]
+func (c *AdminLLMCallController) ListLLMCalls(ctx *gin.Context) {
+	var query service.LLMCallQuery
+	var err error
+	if query.AnswerID, err = optionalIDQuery(ctx, "answer_id"); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid answer_id value"})
+		return
+	}
+	if query.PracticeAnswerID, err = optionalIDQuery(ctx, "practice_answer_id"); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid practice_answer_id value"})
+		return
+	}
+	if raw := ctx.Query("errors_only"); raw != "" {
+		if query.ErrorsOnly, err = strconv.ParseBool(raw); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid errors_only value"})
+			return
+		}
+	}
+	if query.Page, err = optionalIntQuery(ctx, "page"); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid page value"})
+		return
+	}
+	if query.PageSize, err = optionalIntQuery(ctx, "page_size"); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid page_size value"})
+		return
+	}
+
+	calls, err := c.llmAuditService.ListCalls(query)
+	if err != nil {
+		respondLLMCallError(ctx, "Admin ListLLMCalls", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, calls)
+}
+
+// GetLLMCall godoc
+// @Summary (Admin) Get an audited scoring call
+// @Description Returns one call made to the AI provider while scoring, with the exact prompt sent, the raw response, the model, latency, token usage and the error, if any. Images attached to the request are counted, not stored.
+// @Tags Admin - LLM Calls
+// @Produce json
+// @Security BearerAuth
+// @Param call_id path int true "Call ID"
+// @Success 200 {object} dto.LLMCallDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid call ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Call not found"
+// @Router /admin/llm-calls/{call_id} [get]
+func (c *AdminLLMCallController) GetLLMCall(ctx *gin.Context) {
+	callID, err := strconv.ParseUint(ctx.Param("call_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid call ID format"})
+		return
+	}
+	call, err := c.llmAuditService.GetCall(uint(callID))
+	if err != nil {
+		respondLLMCallError(ctx, "Admin GetLLMCall", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, call)
+}
+
+// optionalIDQuery reads an ID from the query string, returning 0 when it is absent.
+func optionalIDQuery(ctx *gin.Context, name string) (uint, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	return uint(id), err
+}
+
+func respondLLMCallError(ctx *gin.Context, operation string, err error) {
+	if errors.Is(err, service.ErrLLMCallNotFound) {
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	log.Error().Err(err).Msg(operation + ": Service error")
+	ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to load scoring calls", Details: []string{err.Error()}})
+}
@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminPromptTemplateController struct {
+	promptTemplateService service.PromptTemplateService
+}
+
+func NewAdminPromptTemplateController(promptTemplateService service.PromptTemplateService) *AdminPromptTemplateController {
+	return &AdminPromptTemplateController{promptTemplateService: promptTemplateService}
+}
+
+// ListPromptTemplates godoc
+// @Summary (Admin) List scoring prompt templates
+// @Description Lists every stored version of the scoring prompt templates, by question type and newest first. One or two versions of each type are active.
+// @Tags Admin - Prompt Templates
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Filter by question type" Enums(sentence_picture, email_response, opinion_essay)
+// @Success 200 {array} dto.PromptTemplateDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid question type"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/prompt-templates [get]
+func (c *AdminPromptTemplateController) ListPromptTemplates(ctx *gin.Context) {
+	templates, err := c.promptTemplateService.ListTemplates(ctx.Query("type"))
+	if err != nil {
+		respondPromptTemplateError(ctx, "Admin ListPromptTemplates", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, templates)
+}
+
+// GetPromptTemplate godoc
+// @Summary (Admin) Get one version of a scoring prompt template
+// @Tags Admin - Prompt Templates
+// @Produce json
+// @Security BearerAuth
+// @Param question_type path string true "Question type" Enums(sentence_picture, email_response, opinion_essay)
+// @Param version path int true "Template version"
+// @Success 200 {object} dto.PromptTemplateDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid version format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Template not found"
+// @Router /admin/prompt-templates/{question_type}/{version} [get]
+func (c *AdminPromptTemplateController) GetPromptTemplate(ctx *gin.Context) {
+	version, ok := parsePromptTemplateVersion(ctx)
+	if !ok {
+		return
+	}
+	template, err := c.promptTemplateService.GetTemplate(ctx.Param("question_type"), version)
+	if err != nil {
+		respondPromptTemplateError(ctx, "Admin GetPromptTemplate", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, template)
+}
+
+// CreatePromptTemplate godoc
+// @Summary (Admin) Write a new version of a scoring prompt template
+// @Description Stores the template as the next version of the question type. The body is a Go text/template rendered with the question and the answer (see docs/prompt-templates.md); it must include the UserAnswer field. The precheck findings and the JSON response schema are appended to every prompt and are not part of the template. The version only affects scoring once activated.
+// @Tags Admin - Prompt Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param question_type path string true "Question type" Enums(sentence_picture, email_response, opinion_essay)
+// @Param template_data body dto.PromptTemplateCreateDTO true "Template"
+// @Success 201 {object} dto.PromptTemplateDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid template"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Router /admin/prompt-templates/{question_type} [post]
+func (c *AdminPromptTemplateController) CreatePromptTemplate(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	var req dto.PromptTemplateCreateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin CreatePromptTemplate: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	template, err := c.promptTemplateService.CreateTemplate(userID, ctx.Param("question_type"), req)
+	if err != nil {
+		respondPromptTemplateError(ctx, "Admin CreatePromptTemplate", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, template)
+}
+
+// ActivatePromptTemplates godoc
+// @Summary (Admin) Choose the active scoring prompt templates
+// @Description Replaces the active versions of the question type with one version, or two versions that split the answers between them by weight (weights sum to 100). Each answer records the version it was scored with, so the two can be compared. Answers already scored keep their result.
+// @Tags Admin - Prompt Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param question_type path string true "Question type" Enums(sentence_picture, email_response, opinion_essay)
+// @Param traffic body dto.PromptTemplateActivateDTO true "Versions to activate and their weights"
+// @Success 200 {array} dto.PromptTemplateDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid versions or weights"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Template not found"
+// @Router /admin/prompt-templates/{question_type}/activate [post]
+func (c *AdminPromptTemplateController) ActivatePromptTemplates(ctx *gin.Context) {
+	var req dto.PromptTemplateActivateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin ActivatePromptTemplates: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+	templates, err := c.promptTemplateService.ActivateTemplates(ctx.Param("question_type"), req)
+	if err != nil {
+		respondPromptTemplateError(ctx, "Admin ActivatePromptTemplates", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, templates)
+}
+
+// PreviewPromptTemplate godoc
+// @Summary (Admin) Render a scoring prompt template
+// @Description Shows the full prompt a template version sends to the model for a sample question and answer, including the precheck findings and the response schema. Nothing is sent to the model.
+// @Tags Admin - Prompt Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param question_type path string true "Question type" Enums(sentence_picture, email_response, opinion_essay)
+// @Param version path int true "Template version"
+// @Param sample body dto.PromptPreviewDTO true "Sample question and answer"
+// @Success 200 {object} dto.PromptPreviewResultDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid version format or template error"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Template not found"
+// @Router /admin/prompt-templates/{question_type}/{version}/preview [post]
+func (c *AdminPromptTemplateController) PreviewPromptTemplate(ctx *gin.Context) {
+	version, ok := parsePromptTemplateVersion(ctx)
+	if !ok {
+		return
+	}
+	var req dto.PromptPreviewDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+	preview, err := c.promptTemplateService.PreviewTemplate(ctx.Param("question_type"), version, req)
+	if err != nil {
+		respondPromptTemplateError(ctx, "Admin PreviewPromptTemplate", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, preview)
+}
+
+func parsePromptTemplateVersion(ctx *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version <= 0 {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid template version format"})
+		return 0, false
+	}
+	return version, true
+}
+
+// respondPromptTemplateError maps PromptTemplateService errors to HTTP responses.
+func respondPromptTemplateError(ctx *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, service.ErrPromptTemplateNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidPromptTemplate):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+	default:
+		log.Error().Err(err).Msg(operation + ": Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to process the prompt template", Details: []string{err.Error()}})
+	}
+}
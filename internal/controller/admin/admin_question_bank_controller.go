@@ -0,0 +1,215 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminQuestionBankController struct {
+	questionBankService service.QuestionBankService
+}
+
+func NewAdminQuestionBankController(questionBankService service.QuestionBankService) *AdminQuestionBankController {
+	return &AdminQuestionBankController{questionBankService: questionBankService}
+}
+
+// CreateBankQuestion godoc
+// @Summary (Admin) Add a question to the question bank
+// @Description Creates a reusable question. Questions of type sentence_picture need an image URL and both given words.
+// @Tags Admin - Question Bank
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param question_data body dto.BankQuestionCreateDTO true "Question data"
+// @Success 201 {object} dto.BankQuestionResponseDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/question-bank [post]
+func (c *AdminQuestionBankController) CreateBankQuestion(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+
+	var req dto.BankQuestionCreateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin CreateBankQuestion: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	question, err := c.questionBankService.CreateQuestion(userID, req)
+	if err != nil {
+		log.Warn().Err(err).Msg("Admin CreateBankQuestion: Service error")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Failed to create question", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusCreated, question)
+}
+
+// ListBankQuestions godoc
+// @Summary (Admin) Browse and search the question bank
+// @Description Lists bank questions, newest edits first. Filters are combined; "tag" may be repeated and matches questions having all given tags.
+// @Tags Admin - Question Bank
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Question type" Enums(sentence_picture, email_response, opinion_essay)
+// @Param difficulty query string false "Difficulty" Enums(easy, medium, hard)
+// @Param tag query []string false "Tag (repeatable)" collectionFormat(multi)
+// @Param q query string false "Text to search for in title and prompt"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} dto.BankQuestionListDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid filter"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Router /admin/question-bank [get]
+func (c *AdminQuestionBankController) ListBankQuestions(ctx *gin.Context) {
+	query := service.BankQuestionQuery{
+		Type:       ctx.Query("type"),
+		Difficulty: ctx.Query("difficulty"),
+		Search:     ctx.Query("q"),
+	}
+	for _, tag := range ctx.QueryArray("tag") {
+		query.Tags = append(query.Tags, strings.Split(tag, ",")...)
+	}
+	var err error
+	if query.Page, err = optionalIntQuery(ctx, "page"); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid page value"})
+		return
+	}
+	if query.PageSize, err = optionalIntQuery(ctx, "page_size"); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid page_size value"})
+		return
+	}
+
+	questions, err := c.questionBankService.ListQuestions(query)
+	if err != nil {
+		log.Warn().Err(err).Msg("Admin ListBankQuestions: Service error")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Failed to search question bank", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusOK, questions)
+}
+
+// GetBankQuestion godoc
+// @Summary (Admin) Get a bank question
+// @Tags Admin - Question Bank
+// @Produce json
+// @Security BearerAuth
+// @Param question_id path int true "Bank question ID"
+// @Success 200 {object} dto.BankQuestionResponseDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid question ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Bank question not found"
+// @Router /admin/question-bank/{question_id} [get]
+func (c *AdminQuestionBankController) GetBankQuestion(ctx *gin.Context) {
+	questionID, ok := parseBankQuestionID(ctx)
+	if !ok {
+		return
+	}
+	question, err := c.questionBankService.GetQuestion(questionID)
+	if err != nil {
+		respondBankQuestionError(ctx, "Admin GetBankQuestion", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, question)
+}
+
+// UpdateBankQuestion godoc
+// @Summary (Admin) Update a bank question
+// @Description Edits a bank question and increments its version. Tests built from earlier versions are not changed.
+// @Tags Admin - Question Bank
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param question_id path int true "Bank question ID"
+// @Param question_data body dto.BankQuestionUpdateDTO true "Fields to update"
+// @Success 200 {object} dto.BankQuestionResponseDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Bank question not found"
+// @Router /admin/question-bank/{question_id} [put]
+func (c *AdminQuestionBankController) UpdateBankQuestion(ctx *gin.Context) {
+	questionID, ok := parseBankQuestionID(ctx)
+	if !ok {
+		return
+	}
+	var req dto.BankQuestionUpdateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin UpdateBankQuestion: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+	question, err := c.questionBankService.UpdateQuestion(questionID, req)
+	if err != nil {
+		respondBankQuestionError(ctx, "Admin UpdateBankQuestion", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, question)
+}
+
+// DeleteBankQuestion godoc
+// @Summary (Admin) Delete a bank question
+// @Description Removes a question from the bank. Tests built from it keep their copies.
+// @Tags Admin - Question Bank
+// @Produce json
+// @Security BearerAuth
+// @Param question_id path int true "Bank question ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse "Invalid question ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Bank question not found"
+// @Router /admin/question-bank/{question_id} [delete]
+func (c *AdminQuestionBankController) DeleteBankQuestion(ctx *gin.Context) {
+	questionID, ok := parseBankQuestionID(ctx)
+	if !ok {
+		return
+	}
+	if err := c.questionBankService.DeleteQuestion(questionID); err != nil {
+		respondBankQuestionError(ctx, "Admin DeleteBankQuestion", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{Message: "Bank question deleted"})
+}
+
+func parseBankQuestionID(ctx *gin.Context) (uint, bool) {
+	questionID, err := strconv.ParseUint(ctx.Param("question_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid question ID format"})
+		return 0, false
+	}
+	return uint(questionID), true
+}
+
+func respondBankQuestionError(ctx *gin.Context, operation string, err error) {
+	if errors.Is(err, service.ErrBankQuestionNotFound) {
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	log.Warn().Err(err).Msg(operation + ": Service error")
+	ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+}
+
+// optionalIntQuery parses an integer query parameter, returning 0 when it is absent.
+func optionalIntQuery(ctx *gin.Context, name string) (int, error) {
+	raw := ctx.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(raw)
+}
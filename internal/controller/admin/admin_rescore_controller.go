@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminRescoreController struct {
+	rescoreService service.RescoreService
+}
+
+func NewAdminRescoreController(rescoreService service.RescoreService) *AdminRescoreController {
+	return &AdminRescoreController{rescoreService: rescoreService}
+}
+
+// RescoreAttempt godoc
+// @Summary (Admin) Re-score answers of any attempt
+// @Description Queues the selected answers (all answers if "answer_ids" is empty; only failed ones if "only_failed") for AI scoring again. Their current results are kept in the answer's score history and the attempt's total score is recomputed when scoring finishes.
+// @Tags Admin - Re-scoring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param attempt_id path int true "Test Attempt ID"
+// @Param rescore_data body dto.AttemptRescoreDTO false "Answers to re-score"
+// @Success 202 {object} dto.AttemptRescoreResultDTO "Answers queued for scoring"
+// @Failure 400 {object} dto.ErrorResponse "Invalid input, or nothing to re-score"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Attempt or answer not found"
+// @Failure 409 {object} dto.ErrorResponse "Attempt is in progress or already being scored"
+// @Router /admin/test-attempts/{attempt_id}/rescore [post]
+func (c *AdminRescoreController) RescoreAttempt(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	attemptID, err := strconv.ParseUint(ctx.Param("attempt_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test Attempt ID format"})
+		return
+	}
+	var req dto.AttemptRescoreDTO
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+			return
+		}
+	}
+
+	result, err := c.rescoreService.RescoreAttempt(uint(attemptID), userID, req)
+	if err != nil {
+		respondRescoreError(ctx, "Admin RescoreAttempt", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, result)
+}
+
+// RescoreTest godoc
+// @Summary (Admin) Re-score every attempt of a test
+// @Description Queues all finished attempts of the test for AI scoring again, or with "only_failed" just their failed answers. Attempts that are in progress or already being scored are skipped.
+// @Tags Admin - Re-scoring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Param rescore_data body dto.TestRescoreDTO false "Re-score options"
+// @Success 202 {object} dto.TestRescoreResultDTO "Attempts queued for scoring"
+// @Failure 400 {object} dto.ErrorResponse "Invalid input"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Router /admin/tests/{test_id}/rescore [post]
+func (c *AdminRescoreController) RescoreTest(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	var req dto.TestRescoreDTO
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+			return
+		}
+	}
+
+	result, err := c.rescoreService.RescoreTest(testID, userID, req)
+	if err != nil {
+		respondRescoreError(ctx, "Admin RescoreTest", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, result)
+}
+
+// GetAnswerScoreHistory godoc
+// @Summary (Admin) List earlier scoring results of an answer
+// @Description Returns the results that were replaced by re-scoring, newest first. The current result is on the answer itself.
+// @Tags Admin - Re-scoring
+// @Produce json
+// @Security BearerAuth
+// @Param answer_id path int true "Answer ID"
+// @Success 200 {array} dto.AnswerScoreHistoryDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid Answer ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Router /admin/answers/{answer_id}/score-history [get]
+func (c *AdminRescoreController) GetAnswerScoreHistory(ctx *gin.Context) {
+	answerID, err := strconv.ParseUint(ctx.Param("answer_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Answer ID format"})
+		return
+	}
+	history, err := c.rescoreService.GetAnswerHistory(uint(answerID))
+	if err != nil {
+		respondRescoreError(ctx, "Admin GetAnswerScoreHistory", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, history)
+}
+
+// respondRescoreError maps RescoreService errors to HTTP responses.
+func respondRescoreError(ctx *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTestNotFound), errors.Is(err, service.ErrAttemptNotFound), errors.Is(err, service.ErrAnswerNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAttemptBusy):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrNothingToRescore):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+	default:
+		log.Error().Err(err).Msg(operation + ": Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to re-score", Details: []string{err.Error()}})
+	}
+}
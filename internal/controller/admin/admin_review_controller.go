@@ -0,0 +1,213 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminReviewController struct {
+	reviewService service.ReviewService
+}
+
+func NewAdminReviewController(reviewService service.ReviewService) *AdminReviewController {
+	return &AdminReviewController{reviewService: reviewService}
+}
+
+// ListDisputes godoc
+// @Summary (Admin) Review queue of disputed scores
+// @Description Lists score disputes raised by learners. With status=open (the queue), the oldest come first; otherwise the newest.
+// @Tags Admin - Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Dispute status" Enums(open, upheld, overridden)
+// @Param test_id query int false "Only disputes of attempts at this test"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} dto.AnswerDisputeListDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid filter"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/disputes [get]
+func (c *AdminReviewController) ListDisputes(ctx *gin.Context) {
+	query := service.DisputeQuery{Status: ctx.Query("status")}
+	testID, err := optionalIDQuery(ctx, "test_id")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid test_id value"})
+		return
+	}
+	query.TestID = testID
+	if query.Page, err = optionalIntQuery(ctx, "page"); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid page value"})
+		return
+	}
+	if query.PageSize, err = optionalIntQuery(ctx, "page_size"); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid page_size value"})
+		return
+	}
+
+	disputes, err := c.reviewService.ListDisputes(query)
+	if err != nil {
+		respondReviewError(ctx, "Admin ListDisputes", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, disputes)
+}
+
+// GetDispute godoc
+// @Summary (Admin) Get a disputed answer
+// @Description Returns the dispute with the answer, the question as the learner saw it, the AI result and any teacher review.
+// @Tags Admin - Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param dispute_id path int true "Dispute ID"
+// @Success 200 {object} dto.AnswerDisputeDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid dispute ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Dispute not found"
+// @Router /admin/disputes/{dispute_id} [get]
+func (c *AdminReviewController) GetDispute(ctx *gin.Context) {
+	disputeID, ok := parseDisputeID(ctx)
+	if !ok {
+		return
+	}
+	dispute, err := c.reviewService.GetDispute(disputeID)
+	if err != nil {
+		respondReviewError(ctx, "Admin GetDispute", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dispute)
+}
+
+// ResolveDispute godoc
+// @Summary (Admin) Resolve a score dispute
+// @Description Closes an open dispute. With a score, the teacher's score and feedback replace the AI's in the attempt's total and scaled score, which are recomputed; the AI result is kept on the answer. Without a score, the current score is upheld. The note is shown to the learner.
+// @Tags Admin - Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dispute_id path int true "Dispute ID"
+// @Param resolution body dto.DisputeResolveDTO true "Decision"
+// @Success 200 {object} dto.AnswerDisputeDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input or score out of range"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Dispute not found"
+// @Failure 409 {object} dto.ErrorResponse "Dispute already resolved, or attempt is being scored"
+// @Router /admin/disputes/{dispute_id}/resolve [post]
+func (c *AdminReviewController) ResolveDispute(ctx *gin.Context) {
+	reviewerID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	disputeID, ok := parseDisputeID(ctx)
+	if !ok {
+		return
+	}
+	var req dto.DisputeResolveDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	dispute, err := c.reviewService.ResolveDispute(disputeID, reviewerID, req)
+	if err != nil {
+		respondReviewError(ctx, "Admin ResolveDispute", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dispute)
+}
+
+// ReviewAnswer godoc
+// @Summary (Admin) Override the score of an answer
+// @Description Sets a teacher's score and feedback on a finished answer, disputed or not. They replace the AI's in the attempt's total and scaled score, which are recomputed, and are kept when the answer is re-scored; the AI result stays on the answer. An open dispute of the answer is resolved as overridden.
+// @Tags Admin - Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param answer_id path int true "Answer ID"
+// @Param review body dto.AnswerReviewDTO true "Teacher's score and feedback"
+// @Success 200 {object} dto.AnswerResponseDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input or score out of range"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Answer not found"
+// @Failure 409 {object} dto.ErrorResponse "Attempt is in progress or being scored"
+// @Router /admin/answers/{answer_id}/review [put]
+func (c *AdminReviewController) ReviewAnswer(ctx *gin.Context) {
+	reviewerID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	answerID, err := strconv.ParseUint(ctx.Param("answer_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Answer ID format"})
+		return
+	}
+	var req dto.AnswerReviewDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	answer, err := c.reviewService.ReviewAnswer(uint(answerID), reviewerID, req)
+	if err != nil {
+		respondReviewError(ctx, "Admin ReviewAnswer", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, answer)
+}
+
+// GetReviewCalibration godoc
+// @Summary (Admin) Compare teacher and AI scores
+// @Description For each question type, compares the teacher's and the AI's score of every reviewed answer the AI had scored: means, the average signed and absolute difference, and how many reviews changed the score.
+// @Tags Admin - Reviews
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.ReviewCalibrationDTO
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/reviews/calibration [get]
+func (c *AdminReviewController) GetReviewCalibration(ctx *gin.Context) {
+	calibration, err := c.reviewService.Calibration()
+	if err != nil {
+		respondReviewError(ctx, "Admin GetReviewCalibration", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, calibration)
+}
+
+func parseDisputeID(ctx *gin.Context) (uint, bool) {
+	disputeID, err := strconv.ParseUint(ctx.Param("dispute_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid dispute ID format"})
+		return 0, false
+	}
+	return uint(disputeID), true
+}
+
+// respondReviewError maps ReviewService errors to HTTP responses.
+func respondReviewError(ctx *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, service.ErrDisputeNotFound), errors.Is(err, service.ErrAnswerNotFound), errors.Is(err, service.ErrAttemptNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrDisputeClosed), errors.Is(err, service.ErrAttemptBusy):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidReviewScore), errors.Is(err, service.ErrInvalidDispute):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+	default:
+		log.Error().Err(err).Msg(operation + ": Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to process the review", Details: []string{err.Error()}})
+	}
+}
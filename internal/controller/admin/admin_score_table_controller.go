@@ -0,0 +1,180 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminScoreTableController struct {
+	scoreConverter service.ScoreConverterService
+}
+
+func NewAdminScoreTableController(scoreConverter service.ScoreConverterService) *AdminScoreTableController {
+	return &AdminScoreTableController{scoreConverter: scoreConverter}
+}
+
+// ListScoreTables godoc
+// @Summary (Admin) List score conversion tables
+// @Description Lists every stored version of the raw-to-scaled score conversion table, newest first. Exactly one is active.
+// @Tags Admin - Score Tables
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.ScoreTableDTO
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/score-tables [get]
+func (c *AdminScoreTableController) ListScoreTables(ctx *gin.Context) {
+	tables, err := c.scoreConverter.ListTables()
+	if err != nil {
+		respondScoreTableError(ctx, "Admin ListScoreTables", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tables)
+}
+
+// GetScoreTable godoc
+// @Summary (Admin) Get one version of the score conversion table
+// @Tags Admin - Score Tables
+// @Produce json
+// @Security BearerAuth
+// @Param version path int true "Table version"
+// @Success 200 {object} dto.ScoreTableDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid version format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Table not found"
+// @Router /admin/score-tables/{version} [get]
+func (c *AdminScoreTableController) GetScoreTable(ctx *gin.Context) {
+	version, ok := parseScoreTableVersion(ctx)
+	if !ok {
+		return
+	}
+	table, err := c.scoreConverter.GetTable(version)
+	if err != nil {
+		respondScoreTableError(ctx, "Admin GetScoreTable", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, table)
+}
+
+// UploadScoreTable godoc
+// @Summary (Admin) Upload a score conversion table
+// @Description Stores the table as a new version. Points map raw totals (0-28) to scaled scores; they must start at raw 0, with raw scores increasing and scaled scores not decreasing. "step" interpolation gives a raw score the scaled score of the first point at or above it, "linear" interpolates between the two surrounding points. The table only affects scoring once activated.
+// @Tags Admin - Score Tables
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param table_data body dto.ScoreTableUploadDTO true "Conversion table"
+// @Success 201 {object} dto.ScoreTableDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid table"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Router /admin/score-tables [post]
+func (c *AdminScoreTableController) UploadScoreTable(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	var req dto.ScoreTableUploadDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin UploadScoreTable: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	table, err := c.scoreConverter.UploadTable(userID, req)
+	if err != nil {
+		respondScoreTableError(ctx, "Admin UploadScoreTable", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, table)
+}
+
+// ActivateScoreTable godoc
+// @Summary (Admin) Activate a score conversion table
+// @Description Makes this version the table used for attempts scored from now on. Attempts already scored keep the scaled score and table version recorded on them.
+// @Tags Admin - Score Tables
+// @Produce json
+// @Security BearerAuth
+// @Param version path int true "Table version"
+// @Success 200 {object} dto.ScoreTableDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid version format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Table not found"
+// @Router /admin/score-tables/{version}/activate [post]
+func (c *AdminScoreTableController) ActivateScoreTable(ctx *gin.Context) {
+	version, ok := parseScoreTableVersion(ctx)
+	if !ok {
+		return
+	}
+	table, err := c.scoreConverter.ActivateTable(version)
+	if err != nil {
+		respondScoreTableError(ctx, "Admin ActivateScoreTable", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, table)
+}
+
+// PreviewScoreConversion godoc
+// @Summary (Admin) Convert a raw score with a given table
+// @Description Shows the scaled score a table version gives to a raw total, e.g. to check a table before activating it.
+// @Tags Admin - Score Tables
+// @Produce json
+// @Security BearerAuth
+// @Param version path int true "Table version"
+// @Param raw query number true "Raw total score"
+// @Success 200 {object} dto.ScoreConversionDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid version or raw score"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Table not found"
+// @Router /admin/score-tables/{version}/convert [get]
+func (c *AdminScoreTableController) PreviewScoreConversion(ctx *gin.Context) {
+	version, ok := parseScoreTableVersion(ctx)
+	if !ok {
+		return
+	}
+	raw, err := strconv.ParseFloat(ctx.Query("raw"), 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Query parameter raw must be a number"})
+		return
+	}
+	result, err := c.scoreConverter.PreviewConversion(version, raw)
+	if err != nil {
+		respondScoreTableError(ctx, "Admin PreviewScoreConversion", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func parseScoreTableVersion(ctx *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || version <= 0 {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid table version format"})
+		return 0, false
+	}
+	return version, true
+}
+
+// respondScoreTableError maps ScoreConverterService errors to HTTP responses.
+func respondScoreTableError(ctx *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, service.ErrScoreTableNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidScoreTable):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+	default:
+		log.Error().Err(err).Msg(operation + ": Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to process the score table", Details: []string{err.Error()}})
+	}
+}
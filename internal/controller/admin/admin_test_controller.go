@@ -1,10 +1,17 @@
 package admin
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lshigami/Ringtails/internal/dto" // Corrected DTO path
+	"github.com/lshigami/Ringtails/internal/middleware"
 	"github.com/lshigami/Ringtails/internal/service"
 	"github.com/rs/zerolog/log"
 )
@@ -19,13 +26,17 @@ func NewAdminTestController(adminTestService service.AdminTestService) *AdminTes
 
 // CreateTest godoc
 // @Summary (Admin) Create a new complete test
-// @Description Admin creates a new test with exactly 8 questions. All questions must be provided.
+// @Description Admin creates a new test with exactly 8 questions. All questions must be provided. The test is a draft, hidden from learners, unless "publish" is true.
 // @Tags Admin - Tests
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param test_data body dto.TestCreateDTO true "Test creation data including all questions (must be 8 questions)"
-// @Success 201 {object} dto.TestResponseDTO "Test created successfully"
+// @Success 201 {object} dto.AdminTestDetailDTO "Test created successfully"
 // @Failure 400 {object} dto.ErrorResponse "Invalid input data (e.g., not 8 questions, missing fields)"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 409 {object} dto.ErrorResponse "Title already used by another test"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
 // @Router /admin/tests [post]
 func (c *AdminTestController) CreateTest(ctx *gin.Context) {
@@ -38,8 +49,12 @@ func (c *AdminTestController) CreateTest(ctx *gin.Context) {
 		return
 	}
 
-	testResp, err := c.adminTestService.CreateTest(req)
+	testResp, err := c.adminTestService.CreateTest(ctx.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, service.ErrTestTitleTaken) {
+			ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
 		log.Error().Err(err).Interface("requestPayload", req).Msg("Admin CreateTest: Service error")
 		// Determine if it's a client error (e.g. validation from service) or server error
 		// For now, treating most service errors as potential client input issues or internal logic.
@@ -49,3 +64,559 @@ func (c *AdminTestController) CreateTest(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusCreated, testResp)
 }
+
+// CreateTestFromBank godoc
+// @Summary (Admin) Build a test from the question bank
+// @Description Creates a test from eight bank questions. The n-th ID becomes question n, so IDs must follow the layout: 5 sentence_picture, 2 email_response, 1 opinion_essay. Questions are copied with their current bank version.
+// @Tags Admin - Tests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param test_data body dto.TestFromBankCreateDTO true "Test metadata and bank question IDs in test order"
+// @Success 201 {object} dto.AdminTestDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input or bank questions do not fit the test layout"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 409 {object} dto.ErrorResponse "Title already used by another test"
+// @Router /admin/tests/from-bank [post]
+func (c *AdminTestController) CreateTestFromBank(ctx *gin.Context) {
+	var req dto.TestFromBankCreateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin CreateTestFromBank: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+	test, err := c.adminTestService.CreateTestFromBank(ctx.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrBankQuestionNotFound) {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		respondTestError(ctx, "Admin CreateTestFromBank", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, test)
+}
+
+// ListTests godoc
+// @Summary (Admin) List tests
+// @Description Lists tests including drafts. Soft-deleted tests are included only when include_deleted is true.
+// @Tags Admin - Tests
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status" Enums(draft, published)
+// @Param include_deleted query bool false "Include soft-deleted tests"
+// @Success 200 {array} dto.AdminTestSummaryDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid filter"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/tests [get]
+func (c *AdminTestController) ListTests(ctx *gin.Context) {
+	includeDeleted := false
+	if raw := ctx.Query("include_deleted"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid include_deleted value"})
+			return
+		}
+		includeDeleted = parsed
+	}
+
+	tests, err := c.adminTestService.ListTests(ctx.Query("status"), includeDeleted)
+	if err != nil {
+		log.Warn().Err(err).Msg("Admin ListTests: Service error")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Failed to list tests", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusOK, tests)
+}
+
+// GetTest godoc
+// @Summary (Admin) Get a test
+// @Description Gets a test with its questions, whatever its status. Soft-deleted tests are also returned.
+// @Tags Admin - Tests
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Success 200 {object} dto.AdminTestDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid Test ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Router /admin/tests/{test_id} [get]
+func (c *AdminTestController) GetTest(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	test, err := c.adminTestService.GetTest(testID)
+	if err != nil {
+		respondTestError(ctx, "Admin GetTest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, test)
+}
+
+// UpdateTest godoc
+// @Summary (Admin) Update test metadata
+// @Description Updates the title and/or description of a test. Omitted fields are left unchanged.
+// @Tags Admin - Tests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Param test_data body dto.TestUpdateDTO true "Fields to update"
+// @Success 200 {object} dto.AdminTestDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Failure 409 {object} dto.ErrorResponse "Title already used by another test"
+// @Router /admin/tests/{test_id} [put]
+func (c *AdminTestController) UpdateTest(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	var req dto.TestUpdateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin UpdateTest: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+	test, err := c.adminTestService.UpdateTest(testID, req)
+	if err != nil {
+		respondTestError(ctx, "Admin UpdateTest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, test)
+}
+
+// UpdateQuestion godoc
+// @Summary (Admin) Update a question of a test
+// @Description Updates the content of one question and records it as a new revision. Past answers keep showing the revision they answered. Type, order and max score are fixed by the question's position. Send an empty string to clear an optional field.
+// @Tags Admin - Tests
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Param question_id path int true "Question ID"
+// @Param question_data body dto.QuestionUpdateDTO true "Fields to update"
+// @Success 200 {object} dto.AdminTestDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test or question not found"
+// @Router /admin/tests/{test_id}/questions/{question_id} [put]
+func (c *AdminTestController) UpdateQuestion(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	questionID, ok := parseQuestionID(ctx)
+	if !ok {
+		return
+	}
+	var req dto.QuestionUpdateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin UpdateQuestion: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+	test, err := c.adminTestService.UpdateQuestion(ctx.Request.Context(), testID, questionID, userID, req)
+	if err != nil {
+		respondTestError(ctx, "Admin UpdateQuestion", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, test)
+}
+
+// ListQuestionRevisions godoc
+// @Summary (Admin) List the revisions of a question
+// @Description Lists every revision of a test question, oldest first. Answers are pinned to the revision the learner saw.
+// @Tags Admin - Tests
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Param question_id path int true "Question ID"
+// @Success 200 {array} dto.QuestionRevisionDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test or question not found"
+// @Router /admin/tests/{test_id}/questions/{question_id}/revisions [get]
+func (c *AdminTestController) ListQuestionRevisions(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	questionID, ok := parseQuestionID(ctx)
+	if !ok {
+		return
+	}
+	revisions, err := c.adminTestService.ListQuestionRevisions(testID, questionID)
+	if err != nil {
+		respondTestError(ctx, "Admin ListQuestionRevisions", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, revisions)
+}
+
+// DiffQuestionRevisions godoc
+// @Summary (Admin) Compare two revisions of a question
+// @Description Returns the changed fields and a line diff of the prompt. Defaults compare the latest revision with the one before it.
+// @Tags Admin - Tests
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Param question_id path int true "Question ID"
+// @Param from query int false "Older revision number"
+// @Param to query int false "Newer revision number"
+// @Success 200 {object} dto.QuestionRevisionDiffDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid ID or revision number"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test, question or revision not found"
+// @Router /admin/tests/{test_id}/questions/{question_id}/revisions/diff [get]
+func (c *AdminTestController) DiffQuestionRevisions(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	questionID, ok := parseQuestionID(ctx)
+	if !ok {
+		return
+	}
+	from, err := optionalIntQuery(ctx, "from")
+	if err != nil || from < 0 {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid from revision"})
+		return
+	}
+	to, err := optionalIntQuery(ctx, "to")
+	if err != nil || to < 0 {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid to revision"})
+		return
+	}
+	diff, err := c.adminTestService.DiffQuestionRevisions(testID, questionID, from, to)
+	if err != nil {
+		respondTestError(ctx, "Admin DiffQuestionRevisions", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// PublishTest godoc
+// @Summary (Admin) Publish a test
+// @Description Makes a draft test visible to learners. The test must have all 8 questions and must not be deleted.
+// @Tags Admin - Tests
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Success 200 {object} dto.AdminTestDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Test cannot be published"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Router /admin/tests/{test_id}/publish [post]
+func (c *AdminTestController) PublishTest(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	test, err := c.adminTestService.PublishTest(testID)
+	if err != nil {
+		respondTestError(ctx, "Admin PublishTest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, test)
+}
+
+// UnpublishTest godoc
+// @Summary (Admin) Unpublish a test
+// @Description Moves a test back to draft so learners can no longer see or attempt it. Existing attempts are kept.
+// @Tags Admin - Tests
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Success 200 {object} dto.AdminTestDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid Test ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Router /admin/tests/{test_id}/unpublish [post]
+func (c *AdminTestController) UnpublishTest(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	test, err := c.adminTestService.UnpublishTest(testID)
+	if err != nil {
+		respondTestError(ctx, "Admin UnpublishTest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, test)
+}
+
+// DeleteTest godoc
+// @Summary (Admin) Delete a test
+// @Description Soft-deletes a test. It disappears for learners, but its attempts are kept and it can be restored.
+// @Tags Admin - Tests
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse "Invalid Test ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Router /admin/tests/{test_id} [delete]
+func (c *AdminTestController) DeleteTest(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	if err := c.adminTestService.DeleteTest(testID); err != nil {
+		respondTestError(ctx, "Admin DeleteTest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.SuccessResponse{Message: "Test deleted"})
+}
+
+// RestoreTest godoc
+// @Summary (Admin) Restore a deleted test
+// @Description Restores a soft-deleted test with its previous status.
+// @Tags Admin - Tests
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Success 200 {object} dto.AdminTestDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid Test ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "No deleted test with this ID"
+// @Router /admin/tests/{test_id}/restore [post]
+func (c *AdminTestController) RestoreTest(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	test, err := c.adminTestService.RestoreTest(testID)
+	if err != nil {
+		respondTestError(ctx, "Admin RestoreTest", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, test)
+}
+
+// ImportTests godoc
+// @Summary (Admin) Import tests from a content file
+// @Description Imports the tests of a content file (see docs/test-content-format.md): a JSON or YAML document, or a zip bundle holding one such document and the image files it references. Send the file as the multipart field "file" or as the raw request body. Every test is checked with the same rules as POST /admin/tests; tests are only created if all of them are valid, in one transaction. With dry_run=true nothing is created and the report only lists the problems found.
+// @Tags Admin - Tests
+// @Accept multipart/form-data
+// @Accept json
+// @Accept application/yaml
+// @Accept application/zip
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file false "Content file (.json, .yaml, .yml or .zip)"
+// @Param dry_run query bool false "Only validate the file"
+// @Success 200 {object} dto.TestImportReportDTO "Dry-run report"
+// @Success 201 {object} dto.TestImportReportDTO "Tests imported"
+// @Failure 400 {object} dto.TestImportReportDTO "File unreadable or tests invalid; nothing was imported"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 413 {object} dto.ErrorResponse "File too large"
+// @Router /admin/tests/import [post]
+func (c *AdminTestController) ImportTests(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "dry_run must be true or false"})
+		return
+	}
+	data, ok := readImportFile(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := c.adminTestService.ImportTests(ctx.Request.Context(), data, dryRun)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTestContent) {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		log.Error().Err(err).Msg("Admin ImportTests: Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to import tests", Details: []string{err.Error()}})
+		return
+	}
+	switch {
+	case dryRun:
+		ctx.JSON(http.StatusOK, report)
+	case !report.Valid:
+		ctx.JSON(http.StatusBadRequest, report)
+	default:
+		ctx.JSON(http.StatusCreated, report)
+	}
+}
+
+// ExportTest godoc
+// @Summary (Admin) Export a test as a content file
+// @Description Exports the test in the format read by POST /admin/tests/import. In a zip bundle, embedded images are written as files next to tests.yaml.
+// @Tags Admin - Tests
+// @Produce application/yaml
+// @Produce json
+// @Produce application/zip
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Param format query string false "File format (default yaml)" Enums(yaml, json, zip)
+// @Success 200 {object} dto.TestContentFileDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid Test ID or format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Router /admin/tests/{test_id}/export [get]
+func (c *AdminTestController) ExportTest(ctx *gin.Context) {
+	testID, ok := parseTestID(ctx)
+	if !ok {
+		return
+	}
+	c.exportTests(ctx, []uint{testID}, fmt.Sprintf("test-%d", testID))
+}
+
+// ExportTests godoc
+// @Summary (Admin) Export several tests as one content file
+// @Description Exports the listed tests, or every non-deleted test when ids is omitted, in the format read by POST /admin/tests/import.
+// @Tags Admin - Tests
+// @Produce application/yaml
+// @Produce json
+// @Produce application/zip
+// @Security BearerAuth
+// @Param ids query string false "Comma-separated test IDs"
+// @Param format query string false "File format (default yaml)" Enums(yaml, json, zip)
+// @Success 200 {object} dto.TestContentFileDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid IDs or format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Teacher or admin role required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Router /admin/tests/export [get]
+func (c *AdminTestController) ExportTests(ctx *gin.Context) {
+	var testIDs []uint
+	if raw := strings.TrimSpace(ctx.Query("ids")); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: fmt.Sprintf("Invalid Test ID %q in ids", part)})
+				return
+			}
+			testIDs = append(testIDs, uint(id))
+		}
+	}
+	c.exportTests(ctx, testIDs, "tests")
+}
+
+func (c *AdminTestController) exportTests(ctx *gin.Context, testIDs []uint, filename string) {
+	format := ctx.DefaultQuery("format", service.TestContentFormatYAML)
+	contentType, ok := testContentTypes[format]
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "format must be 'yaml', 'json' or 'zip'"})
+		return
+	}
+	file, err := c.adminTestService.ExportTests(ctx.Request.Context(), testIDs)
+	if err != nil {
+		respondTestError(ctx, "Admin ExportTests", err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := service.EncodeTestContent(&buf, file, format); err != nil {
+		log.Error().Err(err).Msg("Admin ExportTests: Failed to encode tests")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to export tests", Details: []string{err.Error()}})
+		return
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+var testContentTypes = map[string]string{
+	service.TestContentFormatYAML: "application/yaml; charset=utf-8",
+	service.TestContentFormatJSON: "application/json; charset=utf-8",
+	service.TestContentFormatZip:  "application/zip",
+}
+
+// maxUploadBytes bounds the request body of file uploads.
+const maxUploadBytes = 32 << 20
+
+// readImportFile returns the uploaded content file, taken from the multipart field "file" or the raw body.
+func readImportFile(ctx *gin.Context) ([]byte, bool) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxUploadBytes)
+	var reader io.Reader = ctx.Request.Body
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			respondUploadReadError(ctx, err, "Multipart field 'file' is required")
+			return nil, false
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Cannot open the uploaded file", Details: []string{err.Error()}})
+			return nil, false
+		}
+		defer file.Close()
+		reader = file
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		respondUploadReadError(ctx, err, "Cannot read the uploaded file")
+		return nil, false
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "The uploaded file is empty"})
+		return nil, false
+	}
+	return data, true
+}
+
+func respondUploadReadError(ctx *gin.Context, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		ctx.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Message: fmt.Sprintf("The file is larger than %d MB", maxUploadBytes>>20)})
+		return
+	}
+	ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: message, Details: []string{err.Error()}})
+}
+
+func parseTestID(ctx *gin.Context) (uint, bool) {
+	testID, err := strconv.ParseUint(ctx.Param("test_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test ID format"})
+		return 0, false
+	}
+	return uint(testID), true
+}
+
+func parseQuestionID(ctx *gin.Context) (uint, bool) {
+	questionID, err := strconv.ParseUint(ctx.Param("question_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Question ID format"})
+		return 0, false
+	}
+	return uint(questionID), true
+}
+
+// respondTestError maps AdminTestService errors to HTTP responses.
+func respondTestError(ctx *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTestNotFound), errors.Is(err, service.ErrQuestionNotFound), errors.Is(err, service.ErrRevisionNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrTestTitleTaken):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+	default:
+		log.Warn().Err(err).Msg(operation + ": Service error")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AdminUserController struct {
+	userManagementService service.UserManagementService
+}
+
+func NewAdminUserController(userManagementService service.UserManagementService) *AdminUserController {
+	return &AdminUserController{userManagementService: userManagementService}
+}
+
+// ListUsers godoc
+// @Summary (Admin) List user accounts
+// @Description Lists all user accounts, optionally filtered by role.
+// @Tags Admin - Users
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Filter by role" Enums(learner, teacher, admin)
+// @Success 200 {array} dto.UserResponseDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid role filter"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/users [get]
+func (c *AdminUserController) ListUsers(ctx *gin.Context) {
+	users, err := c.userManagementService.ListUsers(ctx.Query("role"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRole) {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		log.Error().Err(err).Msg("Admin ListUsers: Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to list users", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusOK, users)
+}
+
+// UpdateUserRole godoc
+// @Summary (Admin) Change a user's role
+// @Description Grants or revokes the learner, teacher or admin role. Takes effect on the user's next request. The last admin cannot be demoted.
+// @Tags Admin - Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "User ID"
+// @Param role_data body dto.UserRoleUpdateDTO true "New role"
+// @Success 200 {object} dto.UserResponseDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid User ID or role"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Admin role required"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Failure 409 {object} dto.ErrorResponse "The user is the last admin"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /admin/users/{user_id}/role [put]
+func (c *AdminUserController) UpdateUserRole(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("user_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid User ID format"})
+		return
+	}
+
+	var req dto.UserRoleUpdateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Admin UpdateUserRole: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	user, err := c.userManagementService.UpdateUserRole(uint(userID), req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRole):
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, service.ErrUserNotFound):
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, service.ErrLastAdmin):
+			ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+		default:
+			log.Error().Err(err).Uint64("userID", userID).Msg("Admin UpdateUserRole: Service error")
+			ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to update role", Details: []string{err.Error()}})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, user)
+}
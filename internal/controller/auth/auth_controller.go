@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type AuthController struct {
+	authService service.AuthService
+}
+
+func NewAuthController(authService service.AuthService) *AuthController {
+	return &AuthController{authService: authService}
+}
+
+// Register godoc
+// @Summary Register a new account
+// @Description Creates a learner account and returns an access token for it.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param account body dto.RegisterDTO true "Registration data"
+// @Success 201 {object} dto.AuthResponseDTO "Account created"
+// @Failure 400 {object} dto.ErrorResponse "Invalid input data"
+// @Failure 409 {object} dto.ErrorResponse "Email is already registered"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /auth/register [post]
+func (c *AuthController) Register(ctx *gin.Context) {
+	var req dto.RegisterDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Auth Register: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	resp, err := c.authService.Register(req)
+	if err != nil {
+		if errors.Is(err, service.ErrEmailAlreadyRegistered) {
+			ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		log.Error().Err(err).Msg("Auth Register: Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to register account", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusCreated, resp)
+}
+
+// Login godoc
+// @Summary Log in
+// @Description Exchanges email and password for a signed access token.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param credentials body dto.LoginDTO true "Login credentials"
+// @Success 200 {object} dto.AuthResponseDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input data"
+// @Failure 401 {object} dto.ErrorResponse "Invalid email or password"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /auth/login [post]
+func (c *AuthController) Login(ctx *gin.Context) {
+	var req dto.LoginDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("Auth Login: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	resp, err := c.authService.Login(req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		log.Error().Err(err).Msg("Auth Login: Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to log in", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// Me godoc
+// @Summary Get the current user's profile
+// @Description Returns the account that owns the access token.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.UserResponseDTO
+// @Failure 401 {object} dto.ErrorResponse "Missing or invalid token"
+// @Failure 404 {object} dto.ErrorResponse "User no longer exists"
+// @Router /auth/me [get]
+func (c *AuthController) Me(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+
+	profile, err := c.authService.GetProfile(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		log.Error().Err(err).Uint("userID", userID).Msg("Auth Me: Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to load profile", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusOK, profile)
+}
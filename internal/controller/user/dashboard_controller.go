@@ -0,0 +1,44 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type DashboardController struct {
+	dashboardService service.DashboardService
+}
+
+func NewDashboardController(dashboardService service.DashboardService) *DashboardController {
+	return &DashboardController{dashboardService: dashboardService}
+}
+
+// GetDashboard godoc
+// @Summary (User) Get the caller's progress dashboard
+// @Description Aggregates the caller's attempts across all tests: scaled score over time, average score by question type and by rubric criterion, the weakest question type, attempt counts and daily streaks (UTC). Practice answers are not included.
+// @Tags User - Dashboard
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.DashboardDTO
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /dashboard [get]
+func (c *DashboardController) GetDashboard(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	dashboard, err := c.dashboardService.GetDashboard(userID)
+	if err != nil {
+		log.Error().Err(err).Uint("userID", userID).Msg("User GetDashboard: Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to build dashboard", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusOK, dashboard)
+}
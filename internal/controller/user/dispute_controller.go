@@ -0,0 +1,108 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type DisputeController struct {
+	reviewService service.ReviewService
+}
+
+func NewDisputeController(reviewService service.ReviewService) *DisputeController {
+	return &DisputeController{reviewService: reviewService}
+}
+
+// DisputeAnswer godoc
+// @Summary (User) Dispute the score of an answer
+// @Description Asks a teacher to review the score of one of the caller's scored (or failed) answers. Each answer can have one open dispute at a time. The teacher either upholds the score or replaces it; a replaced score appears as human_score on the answer and the attempt's total and scaled score are recomputed.
+// @Tags User - Tests & Attempts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param attempt_id path int true "Test Attempt ID"
+// @Param answer_id path int true "Answer ID"
+// @Param dispute_data body dto.AnswerDisputeCreateDTO true "Why the score seems wrong"
+// @Success 201 {object} dto.AnswerDisputeDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input or answer not scored"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Attempt belongs to another user"
+// @Failure 404 {object} dto.ErrorResponse "Attempt or answer not found"
+// @Failure 409 {object} dto.ErrorResponse "Answer already has an open dispute, or attempt is being scored"
+// @Router /test-attempts/{attempt_id}/answers/{answer_id}/dispute [post]
+func (c *DisputeController) DisputeAnswer(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	attemptID, err := strconv.ParseUint(ctx.Param("attempt_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test Attempt ID format"})
+		return
+	}
+	answerID, err := strconv.ParseUint(ctx.Param("answer_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Answer ID format"})
+		return
+	}
+	var req dto.AnswerDisputeCreateDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	dispute, err := c.reviewService.DisputeAnswer(uint(attemptID), uint(answerID), userID, req)
+	if err != nil {
+		respondDisputeError(ctx, "User DisputeAnswer", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, dispute)
+}
+
+// GetMyDisputes godoc
+// @Summary (User) List my score disputes
+// @Description Lists the caller's disputes, newest first, with the teacher's decision and note once resolved.
+// @Tags User - Tests & Attempts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.AnswerDisputeDTO
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /disputes [get]
+func (c *DisputeController) GetMyDisputes(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	disputes, err := c.reviewService.ListOwnDisputes(userID)
+	if err != nil {
+		respondDisputeError(ctx, "User GetMyDisputes", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, disputes)
+}
+
+func respondDisputeError(ctx *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, service.ErrAttemptNotFound), errors.Is(err, service.ErrAnswerNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAttemptAccessDenied):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAttemptBusy), errors.Is(err, service.ErrDisputeExists):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAnswerNotDisputable), errors.Is(err, service.ErrInvalidDispute):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+	default:
+		log.Error().Err(err).Msg(operation + ": Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to process the dispute", Details: []string{err.Error()}})
+	}
+}
@@ -0,0 +1,199 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type ExamController struct {
+	examService service.ExamService
+}
+
+func NewExamController(examService service.ExamService) *ExamController {
+	return &ExamController{examService: examService}
+}
+
+// StartAttempt godoc
+// @Summary (User) Start an attempt answered over time
+// @Description Starts an attempt whose answers are saved one by one. Timed attempts (the default) get the deadline of each part (Q1-5, Q6-7, Q8) and are auto-submitted when time runs out. If the caller already has an attempt in progress for this test, that attempt is returned.
+// @Tags User - Timed Exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Param start_data body dto.AttemptStartDTO false "Attempt options"
+// @Success 201 {object} dto.TestAttemptDetailDTO "Attempt in progress"
+// @Failure 400 {object} dto.ErrorResponse "Invalid Test ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 404 {object} dto.ErrorResponse "Test not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /tests/{test_id}/attempts/start [post]
+func (c *ExamController) StartAttempt(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	testID, err := strconv.ParseUint(ctx.Param("test_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test ID format"})
+		return
+	}
+
+	timed := true
+	if ctx.Request.ContentLength > 0 {
+		var req dto.AttemptStartDTO
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+			return
+		}
+		if req.Timed != nil {
+			timed = *req.Timed
+		}
+	}
+
+	attempt, err := c.examService.StartAttempt(uint(testID), userID, timed)
+	if err != nil {
+		respondExamError(ctx, "User StartAttempt", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, attempt)
+}
+
+// GetInProgressAttempt godoc
+// @Summary (User) Resume the attempt in progress for a test
+// @Description Returns the caller's unsubmitted attempt for the test with every saved answer and its revision, so it can be resumed on any device.
+// @Tags User - Timed Exams
+// @Produce json
+// @Security BearerAuth
+// @Param test_id path int true "Test ID"
+// @Success 200 {object} dto.TestAttemptDetailDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid Test ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 404 {object} dto.ErrorResponse "No attempt in progress"
+// @Router /tests/{test_id}/attempts/in-progress [get]
+func (c *ExamController) GetInProgressAttempt(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	testID, err := strconv.ParseUint(ctx.Param("test_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test ID format"})
+		return
+	}
+
+	attempt, err := c.examService.GetInProgressAttempt(uint(testID), userID)
+	if err != nil {
+		respondExamError(ctx, "User GetInProgressAttempt", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, attempt)
+}
+
+// SaveAnswer godoc
+// @Summary (User) Save one answer of an attempt in progress
+// @Description Creates or overwrites the answer to one question (autosave). "revision" must be the revision last received for this answer (0 if never saved); a stale revision is rejected with 409 so that an old tab cannot overwrite newer text. Depending on the server's late policy, answers saved after their part's deadline are rejected (403) or stored with "late": true.
+// @Tags User - Timed Exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param attempt_id path int true "Test Attempt ID"
+// @Param question_id path int true "Question ID"
+// @Param answer_data body dto.AnswerSaveDTO true "Answer text"
+// @Success 200 {object} dto.AnswerResponseDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid input"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Attempt belongs to another user, or the part's deadline has passed"
+// @Failure 404 {object} dto.ErrorResponse "Attempt or question not found"
+// @Failure 409 {object} dto.ErrorResponse "Stale revision, or attempt is no longer in progress"
+// @Router /test-attempts/{attempt_id}/answers/{question_id} [put]
+func (c *ExamController) SaveAnswer(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	attemptID, err := strconv.ParseUint(ctx.Param("attempt_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test Attempt ID format"})
+		return
+	}
+	questionID, err := strconv.ParseUint(ctx.Param("question_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Question ID format"})
+		return
+	}
+	var req dto.AnswerSaveDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("User SaveAnswer: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	answer, err := c.examService.SaveAnswer(uint(attemptID), uint(questionID), userID, req)
+	if err != nil {
+		respondExamError(ctx, "User SaveAnswer", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, answer)
+}
+
+// SubmitAttempt godoc
+// @Summary (User) Submit an attempt in progress
+// @Description Ends the attempt and queues the saved answers for AI scoring. Attempts still in progress when the last deadline passes are submitted automatically.
+// @Tags User - Timed Exams
+// @Produce json
+// @Security BearerAuth
+// @Param attempt_id path int true "Test Attempt ID"
+// @Success 202 {object} dto.TestAttemptDetailDTO "Attempt queued for scoring"
+// @Failure 400 {object} dto.ErrorResponse "Invalid ID, or no answers saved"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Attempt belongs to another user"
+// @Failure 404 {object} dto.ErrorResponse "Attempt not found"
+// @Failure 409 {object} dto.ErrorResponse "Attempt is no longer in progress"
+// @Router /test-attempts/{attempt_id}/submit [post]
+func (c *ExamController) SubmitAttempt(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	attemptID, err := strconv.ParseUint(ctx.Param("attempt_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test Attempt ID format"})
+		return
+	}
+
+	attempt, err := c.examService.SubmitAttempt(uint(attemptID), userID)
+	if err != nil {
+		respondExamError(ctx, "User SubmitAttempt", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, attempt)
+}
+
+// respondExamError maps ExamService errors to HTTP responses.
+func respondExamError(ctx *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTestNotFound), errors.Is(err, service.ErrAttemptNotFound), errors.Is(err, service.ErrQuestionNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAttemptAccessDenied), errors.Is(err, service.ErrPartDeadlinePassed):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAttemptNotInProgress), errors.Is(err, service.ErrStaleAnswer):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrNoAnswersSaved):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+	default:
+		log.Error().Err(err).Msg(operation + ": Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to process the attempt", Details: []string{err.Error()}})
+	}
+}
@@ -0,0 +1,63 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type ImageController struct {
+	imageService service.ImageService
+}
+
+func NewImageController(imageService service.ImageService) *ImageController {
+	return &ImageController{imageService: imageService}
+}
+
+// GetImage godoc
+// @Summary Get an uploaded question image
+// @Description Serves an image uploaded by an admin. No token is needed, so the URL works in an img tag. The content never changes for a hash, so responses may be cached indefinitely.
+// @Tags Images
+// @Produce image/png
+// @Produce image/jpeg
+// @Produce image/webp
+// @Produce image/gif
+// @Param hash path string true "SHA-256 of the image data (64 hex characters)"
+// @Success 200 {file} binary
+// @Success 304 "Not modified"
+// @Failure 400 {object} dto.ErrorResponse "Invalid hash"
+// @Failure 404 {object} dto.ErrorResponse "Image not found"
+// @Router /images/{hash} [get]
+func (c *ImageController) GetImage(ctx *gin.Context) {
+	hash := ctx.Param("hash")
+	if !service.IsImageHash(hash) {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid image hash"})
+		return
+	}
+	etag := `"` + hash + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	image, err := c.imageService.Get(ctx.Request.Context(), hash)
+	if err != nil {
+		if errors.Is(err, service.ErrImageNotFound) {
+			ctx.Header("Cache-Control", "no-store")
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		log.Error().Err(err).Str("hash", hash).Msg("GetImage: Service error")
+		ctx.Header("Cache-Control", "no-store")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to read the image"})
+		return
+	}
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Data(http.StatusOK, image.MimeType, image.Data)
+}
@@ -0,0 +1,131 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type PracticeController struct {
+	practiceService service.PracticeService
+}
+
+func NewPracticeController(practiceService service.PracticeService) *PracticeController {
+	return &PracticeController{practiceService: practiceService}
+}
+
+// SubmitPractice godoc
+// @Summary (User) Practice a single question
+// @Description Scores one answer immediately, either to a question of a published test (question_id) or to an ad-hoc prompt of a given type. An ad-hoc image_url must be an uploaded image (/api/v1/images/<hash>) or a data: URI. The result is stored in the caller's practice history, separate from test attempts.
+// @Tags User - Practice
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param practice_data body dto.PracticeSubmitDTO true "Question or ad-hoc prompt, and the answer"
+// @Success 201 {object} dto.PracticeAnswerDTO "Practice answer stored; scoring_status is \"failed\" if the AI could not score it"
+// @Failure 400 {object} dto.ErrorResponse "Invalid input"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 404 {object} dto.ErrorResponse "Question not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Router /practice [post]
+func (c *PracticeController) SubmitPractice(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+
+	var req dto.PracticeSubmitDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Warn().Err(err).Msg("User SubmitPractice: Failed to bind JSON")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+		return
+	}
+
+	practice, err := c.practiceService.SubmitPractice(ctx.Request.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrQuestionNotFound) {
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		log.Warn().Err(err).Uint("userID", userID).Msg("User SubmitPractice: Service error")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Failed to submit practice answer", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusCreated, practice)
+}
+
+// GetPracticeHistory godoc
+// @Summary (User) Get the caller's practice history
+// @Description Lists the caller's practice answers, newest first, optionally filtered by question type.
+// @Tags User - Practice
+// @Produce json
+// @Security BearerAuth
+// @Param type query string false "Question type" Enums(sentence_picture, email_response, opinion_essay)
+// @Success 200 {array} dto.PracticeSummaryDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid type filter"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Router /practice [get]
+func (c *PracticeController) GetPracticeHistory(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+
+	history, err := c.practiceService.ListPracticeHistory(userID, ctx.Query("type"))
+	if err != nil {
+		log.Warn().Err(err).Uint("userID", userID).Msg("User GetPracticeHistory: Service error")
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Failed to retrieve practice history", Details: []string{err.Error()}})
+		return
+	}
+	ctx.JSON(http.StatusOK, history)
+}
+
+// GetPracticeAnswer godoc
+// @Summary (User) Get one practice answer
+// @Description Returns a practice answer with its full AI feedback. Only the owner may read it.
+// @Tags User - Practice
+// @Produce json
+// @Security BearerAuth
+// @Param practice_id path int true "Practice answer ID"
+// @Success 200 {object} dto.PracticeAnswerDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid practice ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Practice answer belongs to another user"
+// @Failure 404 {object} dto.ErrorResponse "Practice answer not found"
+// @Router /practice/{practice_id} [get]
+func (c *PracticeController) GetPracticeAnswer(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+
+	practiceID, err := strconv.ParseUint(ctx.Param("practice_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid practice ID format"})
+		return
+	}
+
+	practice, err := c.practiceService.GetPracticeAnswer(uint(practiceID), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPracticeAccessDenied):
+			ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, service.ErrPracticeNotFound):
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+		default:
+			log.Error().Err(err).Uint64("practiceID", practiceID).Msg("User GetPracticeAnswer: Service error")
+			ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to retrieve practice answer", Details: []string{err.Error()}})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, practice)
+}
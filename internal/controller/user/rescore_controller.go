@@ -0,0 +1,120 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/middleware"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type RescoreController struct {
+	rescoreService service.RescoreService
+}
+
+func NewRescoreController(rescoreService service.RescoreService) *RescoreController {
+	return &RescoreController{rescoreService: rescoreService}
+}
+
+// RescoreAttempt godoc
+// @Summary (User) Retry scoring of failed answers
+// @Description Queues the caller's failed answers (all of them if "answer_ids" is empty) for AI scoring again. Answers that were scored successfully cannot be re-scored. The total score is recomputed when scoring finishes; poll the attempt for the result.
+// @Tags User - Tests & Attempts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param attempt_id path int true "Test Attempt ID"
+// @Param rescore_data body dto.AttemptRescoreDTO false "Failed answers to re-score"
+// @Success 202 {object} dto.AttemptRescoreResultDTO "Answers queued for scoring"
+// @Failure 400 {object} dto.ErrorResponse "Invalid input, no failed answers, or an answer did not fail"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Attempt belongs to another user"
+// @Failure 404 {object} dto.ErrorResponse "Attempt or answer not found"
+// @Failure 409 {object} dto.ErrorResponse "Attempt is in progress or already being scored"
+// @Router /test-attempts/{attempt_id}/rescore [post]
+func (c *RescoreController) RescoreAttempt(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	attemptID, err := strconv.ParseUint(ctx.Param("attempt_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test Attempt ID format"})
+		return
+	}
+	var req dto.AttemptRescoreDTO
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid request body", Details: []string{err.Error()}})
+			return
+		}
+	}
+
+	result, err := c.rescoreService.RescoreOwnAttempt(uint(attemptID), userID, req.AnswerIDs)
+	if err != nil {
+		respondRescoreError(ctx, "User RescoreAttempt", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, result)
+}
+
+// GetAnswerScoreHistory godoc
+// @Summary (User) List earlier scoring results of an answer
+// @Description Returns the results of one of the caller's answers that were replaced by re-scoring, newest first.
+// @Tags User - Tests & Attempts
+// @Produce json
+// @Security BearerAuth
+// @Param attempt_id path int true "Test Attempt ID"
+// @Param answer_id path int true "Answer ID"
+// @Success 200 {array} dto.AnswerScoreHistoryDTO
+// @Failure 400 {object} dto.ErrorResponse "Invalid ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Attempt belongs to another user"
+// @Failure 404 {object} dto.ErrorResponse "Attempt or answer not found"
+// @Router /test-attempts/{attempt_id}/answers/{answer_id}/history [get]
+func (c *RescoreController) GetAnswerScoreHistory(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+	attemptID, err := strconv.ParseUint(ctx.Param("attempt_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Test Attempt ID format"})
+		return
+	}
+	answerID, err := strconv.ParseUint(ctx.Param("answer_id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid Answer ID format"})
+		return
+	}
+
+	history, err := c.rescoreService.GetOwnAnswerHistory(uint(attemptID), uint(answerID), userID)
+	if err != nil {
+		respondRescoreError(ctx, "User GetAnswerScoreHistory", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, history)
+}
+
+// respondRescoreError maps RescoreService errors to HTTP responses.
+func respondRescoreError(ctx *gin.Context, operation string, err error) {
+	switch {
+	case errors.Is(err, service.ErrAttemptNotFound), errors.Is(err, service.ErrAnswerNotFound):
+		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAttemptAccessDenied):
+		ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrAttemptBusy):
+		ctx.JSON(http.StatusConflict, dto.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, service.ErrNothingToRescore), errors.Is(err, service.ErrAnswerNotFailed):
+		ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+	default:
+		log.Error().Err(err).Msg(operation + ": Service error")
+		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to re-score", Details: []string{err.Error()}})
+	}
+}
@@ -1,11 +1,13 @@
 package user
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lshigami/Ringtails/internal/dto" // Corrected DTO path
+	"github.com/lshigami/Ringtails/internal/middleware"
 	"github.com/lshigami/Ringtails/internal/service"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm" // For injecting DB into controller if service needs it directly (less ideal)
@@ -27,24 +29,17 @@ func NewUserTestController(uts service.UserTestService, tss service.TestSubmissi
 
 // GetAllTests godoc
 // @Summary (User) List all available tests
-// @Description Get a list of tests. If 'user_id' query param is provided, includes attempt status for that user.
+// @Description Get a list of tests. When called with a valid access token, includes the caller's attempt status for each test.
 // @Tags User - Tests & Attempts
 // @Produce json
-// @Param user_id query int false "Optional User ID to check attempt status against"
+// @Security BearerAuth
 // @Success 200 {array} dto.TestSummaryDTO
-// @Failure 400 {object} dto.ErrorResponse "Invalid User ID format"
+// @Failure 401 {object} dto.ErrorResponse "Access token present but invalid"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
 // @Router /tests [get]
 func (c *UserTestController) GetAllTests(ctx *gin.Context) {
 	var userID *uint
-	userIDQueryStr := ctx.Query("user_id")
-	if userIDQueryStr != "" {
-		val, err := strconv.ParseUint(userIDQueryStr, 10, 32)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid User ID format in query"})
-			return
-		}
-		uID := uint(val)
+	if uID, ok := middleware.CurrentUserID(ctx); ok {
 		userID = &uID
 		log.Info().Uint("userID", *userID).Msg("User GetAllTests: Fetching tests with attempt status for user.")
 	} else {
@@ -90,18 +85,26 @@ func (c *UserTestController) GetTestDetails(ctx *gin.Context) {
 
 // SubmitTestAttempt godoc
 // @Summary (User) Submit answers for an entire test
-// @Description User submits answers for questions in a specific test. AI scoring happens in the background.
+// @Description User submits answers for questions in a specific test. The attempt is stored with status "pending" and AI scoring happens in the background.
 // @Tags User - Tests & Attempts
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param test_id path int true "ID of the Test being attempted"
-// @Param submission_data body dto.TestAttemptSubmitDTO true "User ID (optional for now) and list of answers"
-// @Success 200 {object} dto.TestAttemptDetailDTO "Attempt submitted and processing started. Details might be partial until scoring completes."
+// @Param submission_data body dto.TestAttemptSubmitDTO true "List of answers"
+// @Success 202 {object} dto.TestAttemptDetailDTO "Attempt stored and queued for scoring. Poll /test-attempts/{attempt_id} until status is completed."
 // @Failure 400 {object} dto.ErrorResponse "Invalid input (e.g., bad Test ID, invalid answers format)"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
 // @Failure 404 {object} dto.ErrorResponse "Test not found"
 // @Failure 500 {object} dto.ErrorResponse "Error processing submission"
 // @Router /tests/{test_id}/attempts [post]
 func (c *UserTestController) SubmitTestAttempt(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+
 	testIDStr := ctx.Param("test_id")
 	testID, err := strconv.ParseUint(testIDStr, 10, 32)
 	if err != nil {
@@ -120,33 +123,43 @@ func (c *UserTestController) SubmitTestAttempt(ctx *gin.Context) {
 		return
 	}
 
-	log.Info().Uint64("testID", testID).Interface("userID", req.UserID).Int("answerCount", len(req.Answers)).Msg("Received request to submit test attempt")
+	log.Info().Uint64("testID", testID).Uint("userID", userID).Int("answerCount", len(req.Answers)).Msg("Received request to submit test attempt")
 
 	// Pass the main DB instance from controller to service method for transaction management
-	attemptDetail, err := c.testSubmissionService.SubmitTest(uint(testID), req)
+	attemptDetail, err := c.testSubmissionService.SubmitTest(uint(testID), userID, req)
 	if err != nil {
 		log.Error().Err(err).Uint64("testID", testID).Msg("User SubmitTestAttempt: Service error")
+		if errors.Is(err, service.ErrTestNotFound) {
+			ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
 		// Differentiate errors: e.g., test not found vs. internal server error
 		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to submit test attempt", Details: []string{err.Error()}})
 		return
 	}
-	// HTTP 202 Accepted could also be suitable if processing is truly async and client needs to poll
-	// But for now, 200 OK with the created (or being processed) attempt details is fine.
-	ctx.JSON(http.StatusOK, attemptDetail)
+	// Scoring runs in the background worker pool; the client polls the attempt until it is completed.
+	ctx.JSON(http.StatusAccepted, attemptDetail)
 }
 
 // GetUserTestAttempts godoc
-// @Summary (User) Get all attempts by a user for a specific test
-// @Description Retrieve a list of summary information for all attempts a user made on a test.
+// @Summary (User) Get all of the caller's attempts for a specific test
+// @Description Retrieve a list of summary information for all attempts the authenticated user made on a test.
 // @Tags User - Tests & Attempts
 // @Produce json
+// @Security BearerAuth
 // @Param test_id path int true "Test ID"
-// @Param user_id query int false "User ID to filter attempts. (Temporary - will be from auth token)"
 // @Success 200 {array} dto.TestAttemptSummaryDTO
-// @Failure 400 {object} dto.ErrorResponse "Invalid ID format for Test ID or User ID"
+// @Failure 400 {object} dto.ErrorResponse "Invalid Test ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
 // @Router /tests/{test_id}/my-attempts [get]
 func (c *UserTestController) GetUserTestAttempts(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+
 	testIDStr := ctx.Param("test_id")
 	testID, err := strconv.ParseUint(testIDStr, 10, 32)
 	if err != nil {
@@ -154,25 +167,9 @@ func (c *UserTestController) GetUserTestAttempts(ctx *gin.Context) {
 		return
 	}
 
-	var userID *uint
-	userIDQueryStr := ctx.Query("user_id")
-	if userIDQueryStr != "" {
-		val, parseErr := strconv.ParseUint(userIDQueryStr, 10, 32)
-		if parseErr != nil {
-			ctx.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "Invalid User ID format in query"})
-			return
-		}
-		uID := uint(val)
-		userID = &uID
-	} else {
-		// If no user_id, service might fetch for an anonymous/default user, or all if allowed.
-		// For "my-attempts", user context is usually implicit via auth.
-		log.Info().Uint64("testID", testID).Msg("GetUserTestAttempts: No specific user_id query param, service will handle.")
-	}
-
 	attempts, err := c.testSubmissionService.GetUserAttemptsForTest(uint(testID), userID)
 	if err != nil {
-		log.Error().Err(err).Uint64("testID", testID).Interface("userID", userID).Msg("User GetUserTestAttempts: Service error")
+		log.Error().Err(err).Uint64("testID", testID).Uint("userID", userID).Msg("User GetUserTestAttempts: Service error")
 		ctx.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to retrieve user attempts for test", Details: []string{err.Error()}})
 		return
 	}
@@ -181,16 +178,25 @@ func (c *UserTestController) GetUserTestAttempts(ctx *gin.Context) {
 
 // GetSpecificTestAttemptDetails godoc
 // @Summary (User) Get details of a specific test attempt
-// @Description Retrieve full details of a single test attempt, including all answers, scores, and feedback.
+// @Description Retrieve full details of a single test attempt, including all answers, scores, and feedback. Only the owner of the attempt may read it.
 // @Tags User - Tests & Attempts
 // @Produce json
+// @Security BearerAuth
 // @Param attempt_id path int true "Test Attempt ID"
 // @Success 200 {object} dto.TestAttemptDetailDTO
 // @Failure 400 {object} dto.ErrorResponse "Invalid Test Attempt ID format"
+// @Failure 401 {object} dto.ErrorResponse "Authentication required"
+// @Failure 403 {object} dto.ErrorResponse "Attempt belongs to another user"
 // @Failure 404 {object} dto.ErrorResponse "Test Attempt not found"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
 // @Router /test-attempts/{attempt_id} [get]
 func (c *UserTestController) GetSpecificTestAttemptDetails(ctx *gin.Context) {
+	userID, ok := middleware.CurrentUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+		return
+	}
+
 	attemptIDStr := ctx.Param("attempt_id")
 	attemptID, err := strconv.ParseUint(attemptIDStr, 10, 32)
 	if err != nil {
@@ -198,8 +204,12 @@ func (c *UserTestController) GetSpecificTestAttemptDetails(ctx *gin.Context) {
 		return
 	}
 
-	attemptDetails, err := c.testSubmissionService.GetTestAttemptDetails(uint(attemptID))
+	attemptDetails, err := c.testSubmissionService.GetTestAttemptDetails(uint(attemptID), userID)
 	if err != nil {
+		if errors.Is(err, service.ErrAttemptAccessDenied) {
+			ctx.JSON(http.StatusForbidden, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
 		log.Warn().Err(err).Uint64("attemptID", attemptID).Msg("User GetSpecificTestAttemptDetails: Attempt not found or service error")
 		ctx.JSON(http.StatusNotFound, dto.ErrorResponse{Message: err.Error()}) // Assuming service returns descriptive not found error
 		return
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // QuestionCreateDTO is used within TestCreateDTO for admin test creation.
 type QuestionCreateDTO struct {
 	Title       string  `json:"title" binding:"required"`
@@ -13,8 +15,87 @@ type QuestionCreateDTO struct {
 }
 
 // TestCreateDTO is for admin to create a new test with all its questions.
+// Tests are created as drafts unless Publish is set.
 type TestCreateDTO struct {
 	Title       string              `json:"title" binding:"required"`
 	Description string              `json:"description,omitempty"`
+	Publish     bool                `json:"publish"`
 	Questions   []QuestionCreateDTO `json:"questions" binding:"required,min=8,max=8,dive"`
 }
+
+// TestUpdateDTO edits test metadata. Omitted fields are left unchanged.
+type TestUpdateDTO struct {
+	Title       *string `json:"title" binding:"omitempty,min=1"`
+	Description *string `json:"description"`
+}
+
+// QuestionUpdateDTO edits the content of one question. Type, order and max score are fixed by
+// the question's position in the test and cannot be changed. Omitted fields are left unchanged.
+type QuestionUpdateDTO struct {
+	Title      *string `json:"title" binding:"omitempty,min=1"`
+	Prompt     *string `json:"prompt" binding:"omitempty,min=1"`
+	ImageURL   *string `json:"image_url"`
+	GivenWord1 *string `json:"given_word1"`
+	GivenWord2 *string `json:"given_word2"`
+}
+
+// AdminTestSummaryDTO lists tests for admins, including drafts and soft-deleted tests.
+type AdminTestSummaryDTO struct {
+	ID            uint       `json:"id"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description,omitempty"`
+	Status        string     `json:"status"`
+	QuestionCount int        `json:"question_count"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+}
+
+// AdminTestDetailDTO is the admin view of a test with its lifecycle state.
+type AdminTestDetailDTO struct {
+	TestResponseDTO
+	Status      string     `json:"status"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// QuestionRevisionDTO is one immutable revision of a test question.
+type QuestionRevisionDTO struct {
+	ID         uint      `json:"id"`
+	QuestionID uint      `json:"question_id"`
+	Revision   int       `json:"revision"`
+	Title      string    `json:"title"`
+	Prompt     string    `json:"prompt"`
+	Type       string    `json:"type"`
+	ImageURL   *string   `json:"image_url,omitempty"`
+	GivenWord1 *string   `json:"given_word1,omitempty"`
+	GivenWord2 *string   `json:"given_word2,omitempty"`
+	MaxScore   float64   `json:"max_score"`
+	EditedByID *uint     `json:"edited_by_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
+// FieldChangeDTO is a field whose value differs between two revisions. Nil means the field was empty.
+type FieldChangeDTO struct {
+	Field string  `json:"field"`
+	From  *string `json:"from"`
+	To    *string `json:"to"`
+}
+
+// DiffLineDTO is one line of a line diff. Op is "=" (unchanged), "-" (removed) or "+" (added).
+type DiffLineDTO struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// QuestionRevisionDiffDTO compares two revisions of the same question.
+type QuestionRevisionDiffDTO struct {
+	QuestionID   uint             `json:"question_id"`
+	FromRevision int              `json:"from_revision"`
+	ToRevision   int              `json:"to_revision"`
+	Changes      []FieldChangeDTO `json:"changes"`
+	PromptDiff   []DiffLineDTO    `json:"prompt_diff"`
+}
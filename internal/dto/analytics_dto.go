@@ -0,0 +1,49 @@
+package dto
+
+// TestAnalyticsSummaryDTO shows how one test performs across all learners. Only submitted
+// attempts count; score fractions are scores divided by the max score (0-1).
+type TestAnalyticsSummaryDTO struct {
+	TestID              uint     `json:"test_id"`
+	Title               string   `json:"title"`
+	Status              string   `json:"status"`
+	Attempts            int      `json:"attempts"`
+	Learners            int      `json:"learners"`
+	Completed           int      `json:"completed"`
+	CompletedWithErrors int      `json:"completed_with_errors"`
+	Errored             int      `json:"errored"`
+	MeanRawScore        *float64 `json:"mean_raw_score,omitempty"`
+	MedianRawScore      *float64 `json:"median_raw_score,omitempty"`
+	AnswerFailureRate   *float64 `json:"answer_failure_rate,omitempty"` // Failed / (scored + failed) answers
+}
+
+// ScoreBucketDTO counts the scored answers whose score fraction is in [From, To); the last bucket includes 1.
+type ScoreBucketDTO struct {
+	From    float64 `json:"from"`
+	To      float64 `json:"to"`
+	Answers int     `json:"answers"`
+}
+
+// QuestionAnalyticsDTO shows how one question performs. A low mean points to a prompt that is too hard;
+// a high standard deviation or failure rate to one the AI scores erratically.
+type QuestionAnalyticsDTO struct {
+	QuestionID     uint             `json:"question_id"`
+	OrderInTest    int              `json:"order_in_test"`
+	Type           string           `json:"type"`
+	Title          string           `json:"title"`
+	MaxScore       float64          `json:"max_score"`
+	Answers        int              `json:"answers"`
+	Scored         int              `json:"scored"`
+	Failed         int              `json:"failed"`
+	FailureRate    *float64         `json:"failure_rate,omitempty"`
+	MeanFraction   *float64         `json:"mean_fraction,omitempty"`
+	MedianFraction *float64         `json:"median_fraction,omitempty"`
+	StdDevFraction *float64         `json:"stddev_fraction,omitempty"`
+	Distribution   []ScoreBucketDTO `json:"distribution"`
+	AverageWords   *float64         `json:"average_words,omitempty"`
+	AverageChars   *float64         `json:"average_chars,omitempty"`
+}
+
+type TestAnalyticsDTO struct {
+	TestAnalyticsSummaryDTO
+	Questions []QuestionAnalyticsDTO `json:"questions"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// RegisterDTO is the request body for creating a new learner account.
+type RegisterDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8,max=72"` // bcrypt ignores bytes after 72
+	FullName string `json:"full_name"`
+}
+
+// LoginDTO is the request body for exchanging credentials for an access token.
+type LoginDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// UserResponseDTO is the public view of a user account.
+type UserResponseDTO struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	FullName  string    `json:"full_name,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserRoleUpdateDTO is used by admins to change a user's role.
+type UserRoleUpdateDTO struct {
+	Role string `json:"role" binding:"required,oneof=learner teacher admin"`
+}
+
+// AuthResponseDTO is returned after a successful registration or login.
+type AuthResponseDTO struct {
+	AccessToken string          `json:"access_token"`
+	TokenType   string          `json:"token_type"` // Always "Bearer"
+	ExpiresAt   time.Time       `json:"expires_at"`
+	User        UserResponseDTO `json:"user"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// DashboardDTO summarizes a learner's progress across all tests.
+type DashboardDTO struct {
+	Attempts            DashboardAttemptCountsDTO `json:"attempts"`
+	AverageScaledScore  *float64                  `json:"average_scaled_score,omitempty"`
+	BestScaledScore     *float64                  `json:"best_scaled_score,omitempty"`
+	ScoreHistory        []ScoreHistoryPointDTO    `json:"score_history"` // Scored attempts, oldest first
+	QuestionTypes       []QuestionTypeAverageDTO  `json:"question_types"`
+	Criteria            []CriterionAverageDTO     `json:"criteria"`
+	WeakestQuestionType *QuestionTypeAverageDTO   `json:"weakest_question_type,omitempty"` // Lowest average percentage
+	Streak              StreakDTO                 `json:"streak"`
+}
+
+type DashboardAttemptCountsDTO struct {
+	Submitted     int `json:"submitted"` // Every attempt except those in progress or expired unanswered
+	Completed     int `json:"completed"` // Scored, including attempts where some answers failed
+	InProgress    int `json:"in_progress"`
+	BeingScored   int `json:"being_scored"` // Pending or scoring
+	Failed        int `json:"failed"`       // Scoring failed for the whole attempt
+	DistinctTests int `json:"distinct_tests"`
+}
+
+type ScoreHistoryPointDTO struct {
+	AttemptID         uint      `json:"attempt_id"`
+	TestID            uint      `json:"test_id"`
+	TestTitle         string    `json:"test_title"`
+	SubmittedAt       time.Time `json:"submitted_at"`
+	TotalRawScore     *float64  `json:"total_raw_score,omitempty"`
+	ScaledScore       *float64  `json:"scaled_score,omitempty"`
+	ScoreTableVersion *int      `json:"score_table_version,omitempty"`
+}
+
+type QuestionTypeAverageDTO struct {
+	Type           string  `json:"type"`
+	Answers        int     `json:"answers"` // Scored answers of this type
+	AverageScore   float64 `json:"average_score"`
+	MaxScore       float64 `json:"max_score"`
+	AveragePercent float64 `json:"average_percent"` // Average score as a percentage of the max score
+}
+
+type CriterionAverageDTO struct {
+	Criterion      string  `json:"criterion"`
+	Scores         int     `json:"scores"`
+	AverageScore   float64 `json:"average_score"`
+	AveragePercent float64 `json:"average_percent"`
+}
+
+// StreakDTO counts consecutive days (UTC) with at least one submitted attempt.
+type StreakDTO struct {
+	CurrentDays  int    `json:"current_days"` // Still running if the last active day is today or yesterday
+	LongestDays  int    `json:"longest_days"`
+	LastActiveOn string `json:"last_active_on,omitempty"` // YYYY-MM-DD
+}
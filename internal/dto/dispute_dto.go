@@ -0,0 +1,66 @@
+package dto
+
+import "time"
+
+// AnswerDisputeCreateDTO is a learner's objection to the score of one of their answers.
+type AnswerDisputeCreateDTO struct {
+	Comment string `json:"comment" binding:"required,max=2000"` // Why the score seems wrong
+}
+
+// AnswerDisputeDTO is a dispute as shown in the review queue and to the learner who raised it.
+type AnswerDisputeDTO struct {
+	ID             uint       `json:"id"`
+	AnswerID       uint       `json:"answer_id"`
+	TestAttemptID  uint       `json:"test_attempt_id"`
+	UserID         uint       `json:"user_id"`
+	QuestionType   string     `json:"question_type,omitempty"`
+	OrderInTest    int        `json:"order_in_test,omitempty"`
+	Comment        string     `json:"comment"`
+	Status         string     `json:"status"`                   // "open", "upheld" or "overridden"
+	DisputedScore  *float64   `json:"disputed_score,omitempty"` // Score of the answer when the dispute was raised
+	ReviewedByID   *uint      `json:"reviewed_by_id,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// AnswerDisputeDetailDTO is a dispute with the disputed answer, its AI result and any teacher review.
+type AnswerDisputeDetailDTO struct {
+	AnswerDisputeDTO
+	Answer AnswerResponseDTO `json:"answer"`
+}
+
+// AnswerDisputeListDTO is one page of the review queue.
+type AnswerDisputeListDTO struct {
+	Items    []AnswerDisputeDTO `json:"items"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+}
+
+// AnswerReviewDTO is a teacher's score and feedback for an answer. They replace the AI's in the
+// attempt's totals; the AI result stays on the answer.
+type AnswerReviewDTO struct {
+	Score    *float64 `json:"score" binding:"required,min=0"`
+	Feedback string   `json:"feedback"`
+	Note     string   `json:"note"` // Reply to the learner when an open dispute is resolved by the review
+}
+
+// DisputeResolveDTO closes a dispute. With a score, the teacher's score and feedback replace the
+// AI's; without one, the current score is upheld.
+type DisputeResolveDTO struct {
+	Score    *float64 `json:"score" binding:"omitempty,min=0"`
+	Feedback string   `json:"feedback"`
+	Note     string   `json:"note"` // Reply to the learner
+}
+
+// ReviewCalibrationDTO compares teacher and AI scores of the reviewed answers of one question type.
+type ReviewCalibrationDTO struct {
+	QuestionType      string  `json:"question_type"`
+	Reviewed          int64   `json:"reviewed"` // Answers with both an AI and a teacher score
+	MeanAIScore       float64 `json:"mean_ai_score"`
+	MeanHumanScore    float64 `json:"mean_human_score"`
+	MeanDifference    float64 `json:"mean_difference"`     // Teacher minus AI; positive when the AI scores too low
+	MeanAbsDifference float64 `json:"mean_abs_difference"` // Average size of the correction
+	Changed           int64   `json:"changed"`             // Reviews that changed the score
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// ImageDTO describes an uploaded image. URL is the value to use as a question's image_url.
+type ImageDTO struct {
+	Hash         string    `json:"hash"`
+	URL          string    `json:"url"`
+	MimeType     string    `json:"mime_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	OriginalName string    `json:"original_name,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ImageUsageDTO is a test question that shows an image.
+type ImageUsageDTO struct {
+	TestID      uint   `json:"test_id"`
+	TestTitle   string `json:"test_title"`
+	QuestionID  uint   `json:"question_id"`
+	OrderInTest int    `json:"order_in_test"`
+}
+
+// BrokenImageDTO is an image that failed its last check, with the questions that show it.
+type BrokenImageDTO struct {
+	ImageURL        string          `json:"image_url"`
+	Error           string          `json:"error"`
+	CheckedAt       *time.Time      `json:"checked_at,omitempty"`
+	LastOKAt        *time.Time      `json:"last_ok_at,omitempty"`
+	HasStoredCopy   bool            `json:"has_stored_copy"` // Scoring still works from the copy kept when the image last passed
+	Questions       []ImageUsageDTO `json:"questions"`
+	BankQuestionIDs []uint          `json:"bank_question_ids,omitempty"`
+}
+
+// BrokenImageReportDTO lists the question images that are broken.
+type BrokenImageReportDTO struct {
+	Rechecked bool             `json:"rechecked"` // External images were downloaded again for this report
+	Checked   int              `json:"checked"`   // Distinct image URLs in use
+	Broken    []BrokenImageDTO `json:"broken"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+// LLMCallSummaryDTO is an audited scoring call without its prompt and response.
+type LLMCallSummaryDTO struct {
+	ID               uint      `json:"id"`
+	AnswerID         *uint     `json:"answer_id,omitempty"`
+	PracticeAnswerID *uint     `json:"practice_answer_id,omitempty"`
+	QuestionType     string    `json:"question_type"`
+	Attempt          int       `json:"attempt"` // 1 for the first call, higher for calls repairing a rejected response
+	Retry            int       `json:"retry"`   // 0 for the first try, higher for retries after a transient provider error
+	Provider         string    `json:"provider"`
+	Model            string    `json:"model"`
+	PromptVersion    *int      `json:"prompt_version,omitempty"`
+	ImageCount       int       `json:"image_count"`
+	Error            string    `json:"error,omitempty"`
+	LatencyMs        int64     `json:"latency_ms"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// LLMCallDTO is an audited scoring call with the exact prompt sent and the raw response received.
+type LLMCallDTO struct {
+	LLMCallSummaryDTO
+	Prompt      string `json:"prompt"`
+	RawResponse string `json:"raw_response,omitempty"`
+}
+
+// LLMCallListDTO is one page of audited scoring calls.
+type LLMCallListDTO struct {
+	Items    []LLMCallSummaryDTO `json:"items"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
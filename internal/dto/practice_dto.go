@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+// PracticeSubmitDTO is one practice answer. Either QuestionID refers to a question of a published
+// test, or Type and Prompt describe an ad-hoc task (sentence_picture also needs the image and both words).
+// The image of an ad-hoc task must be an uploaded image (/api/v1/images/<hash>) or a data: URI.
+type PracticeSubmitDTO struct {
+	QuestionID *uint   `json:"question_id"`
+	Type       string  `json:"type" binding:"omitempty,oneof=sentence_picture email_response opinion_essay"`
+	Title      string  `json:"title"`
+	Prompt     string  `json:"prompt"`
+	ImageURL   *string `json:"image_url"`
+	GivenWord1 *string `json:"given_word1"`
+	GivenWord2 *string `json:"given_word2"`
+	UserAnswer string  `json:"user_answer" binding:"required"`
+}
+
+// PracticeAnswerDTO is a scored practice answer with the task it was written for.
+type PracticeAnswerDTO struct {
+	ID              uint                 `json:"id"`
+	QuestionID      *uint                `json:"question_id,omitempty"`
+	Type            string               `json:"type"`
+	Title           string               `json:"title,omitempty"`
+	Prompt          string               `json:"prompt"`
+	ImageURL        *string              `json:"image_url,omitempty"`
+	GivenWord1      *string              `json:"given_word1,omitempty"`
+	GivenWord2      *string              `json:"given_word2,omitempty"`
+	MaxScore        float64              `json:"max_score"`
+	UserAnswer      string               `json:"user_answer"`
+	AIFeedback      string               `json:"ai_feedback,omitempty"`
+	AIScore         *float64             `json:"ai_score,omitempty"`
+	AIStrengths     []string             `json:"ai_strengths,omitempty"`
+	AIErrors        []AnswerErrorDTO     `json:"ai_errors,omitempty"`
+	AIRevisedAnswer string               `json:"ai_revised_answer,omitempty"`
+	AIVocabulary    []VocabularyItemDTO  `json:"ai_vocabulary,omitempty"`
+	CriterionScores []CriterionScoreDTO  `json:"criterion_scores,omitempty"`
+	Prechecks       []PrecheckFindingDTO `json:"prechecks,omitempty"`
+	AIUncappedScore *float64             `json:"ai_uncapped_score,omitempty"` // The AI's score when a hard precheck finding capped ai_score
+	PromptVersion   *int                 `json:"prompt_version,omitempty"`    // Prompt template version of the question type used for scoring
+	ModelName       string               `json:"model_name,omitempty"`
+	ScoringStatus   string               `json:"scoring_status"` // "scored", "failed", or "deferred" when the AI provider was unavailable
+	CreatedAt       time.Time            `json:"created_at"`
+}
+
+// PracticeSummaryDTO is one entry of a learner's practice history.
+type PracticeSummaryDTO struct {
+	ID            uint      `json:"id"`
+	QuestionID    *uint     `json:"question_id,omitempty"`
+	Type          string    `json:"type"`
+	Title         string    `json:"title,omitempty"`
+	AIScore       *float64  `json:"ai_score,omitempty"`
+	MaxScore      float64   `json:"max_score"`
+	ScoringStatus string    `json:"scoring_status"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "time"
+
+// PromptTemplateCreateDTO is a new version of the prompt template of a question type.
+// Body is a Go text/template; the fields it can use are listed in docs/prompt-templates.md.
+type PromptTemplateCreateDTO struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Body        string `json:"body" binding:"required"`
+	Activate    bool   `json:"activate"` // Make it the only active version right away
+}
+
+type PromptTemplateDTO struct {
+	QuestionType string     `json:"question_type"`
+	Version      int        `json:"version"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	Body         string     `json:"body"`
+	Active       bool       `json:"active"`
+	Weight       int        `json:"weight"` // Percent of answers scored with this version while active
+	Source       string     `json:"source"` // "file" for the built-in template, "admin" for versions written by admins
+	CreatedByID  *uint      `json:"created_by_id,omitempty"`
+	ActivatedAt  *time.Time `json:"activated_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// PromptTrafficDTO is the share of answers scored with one template version.
+type PromptTrafficDTO struct {
+	Version int `json:"version" binding:"required,min=1"`
+	Weight  int `json:"weight" binding:"min=1,max=100"`
+}
+
+// PromptTemplateActivateDTO lists the versions to activate for a question type, replacing the
+// active ones. With two versions, answers are split between them by weight; weights must sum to 100.
+type PromptTemplateActivateDTO struct {
+	Versions []PromptTrafficDTO `json:"versions" binding:"required,min=1,max=2,dive"`
+}
+
+// PromptPreviewDTO is a sample question and answer to render a template version with.
+type PromptPreviewDTO struct {
+	Prompt      string `json:"prompt"`
+	GivenWord1  string `json:"given_word1"`
+	GivenWord2  string `json:"given_word2"`
+	OrderInTest int    `json:"order_in_test"` // 0 uses a sample position of the template's type; any other position must hold a question of that type
+	UserAnswer  string `json:"user_answer"`
+}
+
+// PromptPreviewResultDTO is the full prompt a template version produces, as sent to the model.
+type PromptPreviewResultDTO struct {
+	QuestionType string `json:"question_type"`
+	Version      int    `json:"version"`
+	Prompt       string `json:"prompt"`
+}
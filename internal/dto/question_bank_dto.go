@@ -0,0 +1,61 @@
+package dto
+
+import "time"
+
+// BankQuestionCreateDTO adds a reusable question to the question bank.
+type BankQuestionCreateDTO struct {
+	Title      string   `json:"title" binding:"required"`
+	Prompt     string   `json:"prompt" binding:"required"`
+	Type       string   `json:"type" binding:"required,oneof=sentence_picture email_response opinion_essay"`
+	ImageURL   *string  `json:"image_url"`
+	GivenWord1 *string  `json:"given_word1"`
+	GivenWord2 *string  `json:"given_word2"`
+	Difficulty string   `json:"difficulty" binding:"omitempty,oneof=easy medium hard"` // Defaults to "medium"
+	Tags       []string `json:"tags"`
+}
+
+// BankQuestionUpdateDTO edits a bank question. Omitted fields are left unchanged; the type cannot change.
+type BankQuestionUpdateDTO struct {
+	Title      *string   `json:"title" binding:"omitempty,min=1"`
+	Prompt     *string   `json:"prompt" binding:"omitempty,min=1"`
+	ImageURL   *string   `json:"image_url"`
+	GivenWord1 *string   `json:"given_word1"`
+	GivenWord2 *string   `json:"given_word2"`
+	Difficulty *string   `json:"difficulty" binding:"omitempty,oneof=easy medium hard"`
+	Tags       *[]string `json:"tags"`
+}
+
+// BankQuestionResponseDTO is one question of the question bank.
+type BankQuestionResponseDTO struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Prompt      string    `json:"prompt"`
+	Type        string    `json:"type"`
+	ImageURL    *string   `json:"image_url,omitempty"`
+	GivenWord1  *string   `json:"given_word1,omitempty"`
+	GivenWord2  *string   `json:"given_word2,omitempty"`
+	Difficulty  string    `json:"difficulty"`
+	Tags        []string  `json:"tags"`
+	Version     int       `json:"version"`
+	CreatedByID *uint     `json:"created_by_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// BankQuestionListDTO is one page of question bank search results.
+type BankQuestionListDTO struct {
+	Items    []BankQuestionResponseDTO `json:"items"`
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"page_size"`
+}
+
+// TestFromBankCreateDTO builds a test from eight bank questions. BankQuestionIDs[i] becomes
+// question i+1, so the list must follow the test layout: 5 sentence_picture, 2 email_response,
+// then 1 opinion_essay.
+type TestFromBankCreateDTO struct {
+	Title           string `json:"title" binding:"required"`
+	Description     string `json:"description,omitempty"`
+	Publish         bool   `json:"publish"`
+	BankQuestionIDs []uint `json:"bank_question_ids" binding:"required,len=8,dive,gt=0"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+// AttemptRescoreDTO selects the answers of an attempt to score again.
+// Learners may only re-score failed answers; an empty AnswerIDs selects all of them.
+type AttemptRescoreDTO struct {
+	AnswerIDs  []uint `json:"answer_ids"`
+	OnlyFailed bool   `json:"only_failed"` // Admin only; learners are always limited to failed answers
+}
+
+// AttemptRescoreResultDTO reports the answers of an attempt that were queued for scoring.
+// Poll the attempt until its status leaves "pending"/"scoring" to see the new scores.
+type AttemptRescoreResultDTO struct {
+	AttemptID uint   `json:"attempt_id"`
+	AnswerIDs []uint `json:"answer_ids"`
+	Status    string `json:"status"`
+}
+
+// TestRescoreDTO re-scores every finished attempt of a test.
+type TestRescoreDTO struct {
+	OnlyFailed bool `json:"only_failed"` // Only attempts with failed answers, and only those answers
+}
+
+// TestRescoreResultDTO reports how much work a test-wide re-score queued.
+type TestRescoreResultDTO struct {
+	TestID          uint `json:"test_id"`
+	AttemptsQueued  int  `json:"attempts_queued"`
+	AnswersQueued   int  `json:"answers_queued"`
+	AttemptsSkipped int  `json:"attempts_skipped"` // Still in progress or already being scored
+}
+
+// AnswerScoreHistoryDTO is an earlier scoring result that was replaced by a re-score.
+type AnswerScoreHistoryDTO struct {
+	ID              uint                 `json:"id"`
+	AnswerID        uint                 `json:"answer_id"`
+	AIScore         *float64             `json:"ai_score,omitempty"`
+	AIFeedback      string               `json:"ai_feedback,omitempty"`
+	AIStrengths     []string             `json:"ai_strengths,omitempty"`
+	AIErrors        []AnswerErrorDTO     `json:"ai_errors,omitempty"`
+	AIRevisedAnswer string               `json:"ai_revised_answer,omitempty"`
+	AIVocabulary    []VocabularyItemDTO  `json:"ai_vocabulary,omitempty"`
+	CriterionScores []CriterionScoreDTO  `json:"criterion_scores,omitempty"`
+	AIUncappedScore *float64             `json:"ai_uncapped_score,omitempty"`
+	Prechecks       []PrecheckFindingDTO `json:"prechecks,omitempty"`
+	PromptVersion   *int                 `json:"prompt_version,omitempty"`
+	ModelName       string               `json:"model_name,omitempty"`
+	ScoringStatus   string               `json:"scoring_status"`
+	ScoredAt        time.Time            `json:"scored_at"`
+	RequestedByID   *uint                `json:"requested_by_id,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"` // When the result was replaced
+}
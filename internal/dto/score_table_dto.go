@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+type ScorePointDTO struct {
+	Raw    float64 `json:"raw"`
+	Scaled float64 `json:"scaled"`
+}
+
+// ScoreTableUploadDTO is a score conversion table. It has the same format as the built-in
+// table file (internal/service/scoretables), so a file can be edited and uploaded as is.
+// Points must start at raw 0, with raw scores increasing and scaled scores not decreasing.
+type ScoreTableUploadDTO struct {
+	Name          string          `json:"name" binding:"required"`
+	Description   string          `json:"description"`
+	Interpolation string          `json:"interpolation" binding:"omitempty,oneof=step linear"` // Default "step"
+	RoundTo       *float64        `json:"round_to"`                                            // Default 5; 0 keeps unrounded scores
+	Points        []ScorePointDTO `json:"points" binding:"required,min=2"`
+	Activate      bool            `json:"activate"` // Make it the active table right away
+}
+
+type ScoreTableDTO struct {
+	Version        int             `json:"version"`
+	Name           string          `json:"name"`
+	Description    string          `json:"description,omitempty"`
+	Interpolation  string          `json:"interpolation"`
+	RoundTo        float64         `json:"round_to"`
+	MaxRawScore    float64         `json:"max_raw_score"`
+	MaxScaledScore float64         `json:"max_scaled_score"`
+	Points         []ScorePointDTO `json:"points"`
+	Active         bool            `json:"active"`
+	Source         string          `json:"source"`
+	CreatedByID    *uint           `json:"created_by_id,omitempty"`
+	ActivatedAt    *time.Time      `json:"activated_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// ScoreConversionDTO is the result of converting one raw score with a given table.
+type ScoreConversionDTO struct {
+	RawScore     float64 `json:"raw_score"`
+	ScaledScore  float64 `json:"scaled_score"`
+	TableVersion int     `json:"table_version"`
+}
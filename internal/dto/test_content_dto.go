@@ -0,0 +1,46 @@
+package dto
+
+// TestContentFileDTO is the portable file format for tests, read and written as JSON or YAML.
+// See docs/test-content-format.md.
+type TestContentFileDTO struct {
+	FormatVersion int              `json:"format_version" yaml:"format_version"`
+	Tests         []TestContentDTO `json:"tests" yaml:"tests"`
+}
+
+// TestContentDTO is one test in a content file.
+type TestContentDTO struct {
+	Title       string               `json:"title" yaml:"title"`
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Publish     bool                 `json:"publish,omitempty" yaml:"publish,omitempty"`
+	Questions   []QuestionContentDTO `json:"questions" yaml:"questions"`
+}
+
+// QuestionContentDTO is one question of a test in a content file. Type and MaxScore may be omitted;
+// they are then taken from the question's position in the test.
+type QuestionContentDTO struct {
+	Order      int      `json:"order" yaml:"order"`
+	Type       string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Title      string   `json:"title" yaml:"title"`
+	Prompt     string   `json:"prompt" yaml:"prompt"`
+	Image      string   `json:"image,omitempty" yaml:"image,omitempty"`             // http(s) URL, data: URI or path relative to the content file in a zip bundle
+	GivenWords []string `json:"given_words,omitempty" yaml:"given_words,omitempty"` // Exactly 2 for sentence_picture questions
+	MaxScore   float64  `json:"max_score,omitempty" yaml:"max_score,omitempty"`
+}
+
+// TestImportItemDTO is the validation result for one test of an imported file.
+type TestImportItemDTO struct {
+	Index  int      `json:"index"` // Position of the test in the file, from 0
+	Title  string   `json:"title"`
+	Valid  bool     `json:"valid"`
+	Errors []string `json:"errors,omitempty"`
+	TestID *uint    `json:"test_id,omitempty"` // Set once the test is created
+}
+
+// TestImportReportDTO reports on an import. Tests are only created when every test in the file is valid.
+type TestImportReportDTO struct {
+	DryRun   bool                `json:"dry_run"`
+	Valid    bool                `json:"valid"`
+	Imported int                 `json:"imported"`
+	Errors   []string            `json:"errors,omitempty"` // Problems with the file itself
+	Tests    []TestImportItemDTO `json:"tests"`
+}
@@ -4,16 +4,18 @@ import "time"
 
 // QuestionResponseDTO is used for displaying question details to users.
 type QuestionResponseDTO struct {
-	ID          uint    `json:"id"`
-	TestID      uint    `json:"test_id"`
-	Title       string  `json:"title"`
-	Prompt      string  `json:"prompt"`
-	Type        string  `json:"type"`
-	OrderInTest int     `json:"order_in_test"`
-	ImageURL    *string `json:"image_url,omitempty"`
-	GivenWord1  *string `json:"given_word1,omitempty"`
-	GivenWord2  *string `json:"given_word2,omitempty"`
-	MaxScore    float64 `json:"max_score"`
+	ID                  uint    `json:"id"`
+	TestID              uint    `json:"test_id"`
+	Title               string  `json:"title"`
+	Prompt              string  `json:"prompt"`
+	Type                string  `json:"type"`
+	OrderInTest         int     `json:"order_in_test"`
+	ImageURL            *string `json:"image_url,omitempty"`
+	GivenWord1          *string `json:"given_word1,omitempty"`
+	GivenWord2          *string `json:"given_word2,omitempty"`
+	MaxScore            float64 `json:"max_score"`
+	BankQuestionID      *uint   `json:"bank_question_id,omitempty"`
+	BankQuestionVersion *int    `json:"bank_question_version,omitempty"`
 }
 
 // TestResponseDTO is used for displaying full test details to users.
@@ -40,6 +42,18 @@ type TestSummaryDTO struct {
 
 // --- DTOs for Test Attempts (User submitting and viewing attempts) ---
 
+// AttemptStartDTO starts an attempt that is answered over time. Timed defaults to true.
+type AttemptStartDTO struct {
+	Timed *bool `json:"timed"`
+}
+
+// AnswerSaveDTO saves the answer to one question of an in-progress attempt. An empty string clears it.
+// Revision is the revision of the answer the client last received, 0 if it has never been saved.
+type AnswerSaveDTO struct {
+	UserAnswer *string `json:"user_answer" binding:"required"`
+	Revision   int     `json:"revision" binding:"min=0"`
+}
+
 // UserAnswerDTO represents a user's answer to a single question within a test submission.
 type UserAnswerDTO struct {
 	QuestionID uint   `json:"question_id" binding:"required"`
@@ -47,41 +61,118 @@ type UserAnswerDTO struct {
 }
 
 // TestAttemptSubmitDTO is the request DTO for a user submitting all answers for a test.
+// The submitting user is taken from the access token, never from the body.
 type TestAttemptSubmitDTO struct {
-	UserID  *uint           `json:"user_id"` // Temporary, for non-auth user identification
 	Answers []UserAnswerDTO `json:"answers" binding:"required,dive"`
 }
 
+// AnswerErrorDTO is one mistake found by the AI scorer, with its correction.
+type AnswerErrorDTO struct {
+	Original    string `json:"original"`
+	Correction  string `json:"correction"`
+	Explanation string `json:"explanation"`
+	Category    string `json:"category,omitempty"`
+}
+
+// VocabularyItemDTO is a suggested word or phrase with its Vietnamese meaning.
+type VocabularyItemDTO struct {
+	Term    string `json:"term"`
+	Meaning string `json:"meaning"`
+}
+
+// CriterionScoreDTO is the AI score for one rubric criterion of an answer.
+type CriterionScoreDTO struct {
+	Criterion string  `json:"criterion"`
+	Score     float64 `json:"score"`
+	MaxScore  float64 `json:"max_score"`
+	Comment   string  `json:"comment,omitempty"`
+}
+
+// PrecheckFindingDTO is a requirement of the task that the rule-based checks found missed.
+// Hard findings cap the answer's score at ScoreCap.
+type PrecheckFindingDTO struct {
+	Rule     string   `json:"rule"`     // "empty", "language", "sentence_count", "given_words", "word_count"
+	Severity string   `json:"severity"` // "hard" or "advisory"
+	Message  string   `json:"message"`
+	ScoreCap *float64 `json:"score_cap,omitempty"`
+}
+
 // AnswerResponseDTO is used for displaying individual answer details within a test attempt.
+// AIFeedback is a Markdown rendering of the structured AI fields for simple clients.
 type AnswerResponseDTO struct {
-	ID         uint                `json:"id"`
-	QuestionID uint                `json:"question_id"`
-	Question   QuestionResponseDTO `json:"question,omitempty"` // Contains full question details
-	UserAnswer string              `json:"user_answer"`
-	AIFeedback string              `json:"ai_feedback,omitempty"`
-	AIScore    *float64            `json:"ai_score,omitempty"`
+	ID                 uint                 `json:"id"`
+	QuestionID         uint                 `json:"question_id"`
+	Question           QuestionResponseDTO  `json:"question,omitempty"` // Content of the question revision that was answered
+	QuestionRevisionID *uint                `json:"question_revision_id,omitempty"`
+	UserAnswer         string               `json:"user_answer"`
+	AIFeedback         string               `json:"ai_feedback,omitempty"`
+	AIScore            *float64             `json:"ai_score,omitempty"`
+	AIStrengths        []string             `json:"ai_strengths,omitempty"`
+	AIErrors           []AnswerErrorDTO     `json:"ai_errors,omitempty"`
+	AIRevisedAnswer    string               `json:"ai_revised_answer,omitempty"` // Question 8 only
+	AIVocabulary       []VocabularyItemDTO  `json:"ai_vocabulary,omitempty"`     // Question 8 only
+	ScoringStatus      string               `json:"scoring_status"`              // "pending", "scored", "failed", "deferred"
+	CriterionScores    []CriterionScoreDTO  `json:"criterion_scores,omitempty"`
+	Prechecks          []PrecheckFindingDTO `json:"prechecks,omitempty"`
+	AIUncappedScore    *float64             `json:"ai_uncapped_score,omitempty"` // The AI's score when a hard precheck finding capped ai_score
+	PromptVersion      *int                 `json:"prompt_version,omitempty"`    // Prompt template version of the question type used for scoring
+	ModelName          string               `json:"model_name,omitempty"`
+	HumanScore         *float64             `json:"human_score,omitempty"` // Set by a teacher's review; counts instead of ai_score
+	HumanFeedback      string               `json:"human_feedback,omitempty"`
+	ReviewedAt         *time.Time           `json:"reviewed_at,omitempty"`
+	Revision           int                  `json:"revision"`           // Send back when saving the answer again
+	SavedAt            *time.Time           `json:"saved_at,omitempty"` // Last time the answer was saved during the attempt
+	Late               bool                 `json:"late,omitempty"`     // Saved after its part's deadline
+}
+
+// PartScoreDTO is the subtotal of one part of the test. Only scored answers count towards RawScore.
+type PartScoreDTO struct {
+	Part      int     `json:"part"` // 1: Q1-5 sentences, 2: Q6-7 emails, 3: Q8 essay
+	Name      string  `json:"name"`
+	Questions string  `json:"questions"` // Question numbers in the part, e.g. "1-5"
+	RawScore  float64 `json:"raw_score"`
+	MaxScore  float64 `json:"max_score"`
+	Answered  int     `json:"answered"` // Answers submitted in this part
+	Scored    int     `json:"scored"`   // Of those, answers scored successfully
+}
+
+// ProficiencyLevelDTO is the ETS TOEIC Writing proficiency level (1-9) estimated from the scaled score.
+type ProficiencyLevelDTO struct {
+	Level       int    `json:"level"`
+	Description string `json:"description"`
 }
 
 // TestAttemptDetailDTO is for displaying the full details of a specific test attempt.
 type TestAttemptDetailDTO struct {
-	ID            uint                `json:"id"`
-	TestID        uint                `json:"test_id"`
-	TestTitle     string              `json:"test_title,omitempty"`
-	UserID        *uint               `json:"user_id,omitempty"`
-	SubmittedAt   time.Time           `json:"submitted_at"`
-	TotalRawScore *float64            `json:"total_raw_score,omitempty"` // Điểm thô
-	ScaledScore   *float64            `json:"scaled_score,omitempty"`    // Điểm đã quy đổi
-	Status        string              `json:"status"`
-	Answers       []AnswerResponseDTO `json:"answers,omitempty"` // List of answers with their details
+	ID                uint                 `json:"id"`
+	TestID            uint                 `json:"test_id"`
+	TestTitle         string               `json:"test_title,omitempty"`
+	UserID            *uint                `json:"user_id,omitempty"`
+	SubmittedAt       time.Time            `json:"submitted_at"`
+	TotalRawScore     *float64             `json:"total_raw_score,omitempty"`     // Điểm thô
+	ScaledScore       *float64             `json:"scaled_score,omitempty"`        // Điểm đã quy đổi
+	ScoreTableVersion *int                 `json:"score_table_version,omitempty"` // Conversion table that produced ScaledScore
+	PartScores        []PartScoreDTO       `json:"part_scores,omitempty"`         // Once scored
+	ProficiencyLevel  *ProficiencyLevelDTO `json:"proficiency_level,omitempty"`   // Estimated from ScaledScore
+	Status            string               `json:"status"`
+	StartedAt         *time.Time           `json:"started_at,omitempty"`        // Set for every started attempt, timed or not; nil when answers were submitted without starting
+	Part1DeadlineAt   *time.Time           `json:"part1_deadline_at,omitempty"` // Deadline for Q1-5
+	Part2DeadlineAt   *time.Time           `json:"part2_deadline_at,omitempty"` // Deadline for Q6-7
+	Part3DeadlineAt   *time.Time           `json:"part3_deadline_at,omitempty"` // Deadline for Q8 and end of the exam
+	AutoSubmitted     bool                 `json:"auto_submitted,omitempty"`
+	Answers           []AnswerResponseDTO  `json:"answers,omitempty"` // List of answers with their details
 }
 
 // TestAttemptSummaryDTO is for listing a user's attempts for a particular test.
 type TestAttemptSummaryDTO struct {
-	ID            uint      `json:"id"`
-	TestID        uint      `json:"test_id"`
-	UserID        *uint     `json:"user_id,omitempty"`
-	SubmittedAt   time.Time `json:"submitted_at"`
-	TotalRawScore *float64  `json:"total_raw_score,omitempty"` // Điểm thô
-	ScaledScore   *float64  `json:"scaled_score,omitempty"`    // Điểm đã quy đổi
-	Status        string    `json:"status"`
+	ID                uint                 `json:"id"`
+	TestID            uint                 `json:"test_id"`
+	UserID            *uint                `json:"user_id,omitempty"`
+	SubmittedAt       time.Time            `json:"submitted_at"`
+	TotalRawScore     *float64             `json:"total_raw_score,omitempty"`     // Điểm thô
+	ScaledScore       *float64             `json:"scaled_score,omitempty"`        // Điểm đã quy đổi
+	ScoreTableVersion *int                 `json:"score_table_version,omitempty"` // Conversion table that produced ScaledScore
+	PartScores        []PartScoreDTO       `json:"part_scores,omitempty"`
+	ProficiencyLevel  *ProficiencyLevelDTO `json:"proficiency_level,omitempty"`
+	Status            string               `json:"status"`
 }
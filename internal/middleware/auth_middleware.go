@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ContextUserIDKey    = "auth_user_id"
+	ContextUserEmailKey = "auth_user_email"
+	ContextUserRoleKey  = "auth_user_role"
+)
+
+// RequireAuth rejects requests that do not carry a valid "Authorization: Bearer <token>" header.
+func RequireAuth(authService service.AuthService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenString, ok := bearerToken(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Missing or malformed Authorization header"})
+			return
+		}
+		claims, err := authService.Authenticate(tokenString)
+		if err != nil {
+			if !errors.Is(err, service.ErrInvalidToken) {
+				log.Error().Err(err).Str("path", ctx.FullPath()).Msg("RequireAuth: Failed to load user")
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to authenticate"})
+				return
+			}
+			log.Warn().Err(err).Str("path", ctx.FullPath()).Msg("RequireAuth: Rejected access token")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		setClaims(ctx, claims)
+		ctx.Next()
+	}
+}
+
+// OptionalAuth identifies the user when a valid token is present but lets anonymous requests through.
+// A token that is present but invalid is still rejected so clients notice expired sessions.
+func OptionalAuth(authService service.AuthService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.Next()
+			return
+		}
+		tokenString, ok := bearerToken(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Malformed Authorization header"})
+			return
+		}
+		claims, err := authService.Authenticate(tokenString)
+		if err != nil {
+			if !errors.Is(err, service.ErrInvalidToken) {
+				log.Error().Err(err).Str("path", ctx.FullPath()).Msg("OptionalAuth: Failed to load user")
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Message: "Failed to authenticate"})
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		setClaims(ctx, claims)
+		ctx.Next()
+	}
+}
+
+// CurrentUserID returns the authenticated user's ID set by RequireAuth or OptionalAuth.
+func CurrentUserID(ctx *gin.Context) (uint, bool) {
+	val, exists := ctx.Get(ContextUserIDKey)
+	if !exists {
+		return 0, false
+	}
+	userID, ok := val.(uint)
+	return userID, ok && userID != 0
+}
+
+func bearerToken(ctx *gin.Context) (string, bool) {
+	header := ctx.GetHeader("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func setClaims(ctx *gin.Context, claims *service.AuthClaims) {
+	ctx.Set(ContextUserIDKey, claims.UserID)
+	ctx.Set(ContextUserEmailKey, claims.Email)
+	ctx.Set(ContextUserRoleKey, claims.Role)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/rs/zerolog/log"
+)
+
+// RequireRoles only lets through users whose role is one of roles. It must run after RequireAuth.
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		allowed[r] = true
+	}
+	return func(ctx *gin.Context) {
+		role, ok := CurrentUserRole(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Message: "Authentication required"})
+			return
+		}
+		if !allowed[role] {
+			userID, _ := CurrentUserID(ctx)
+			log.Warn().Uint("userID", userID).Str("role", role).Str("path", ctx.FullPath()).Msg("RequireRoles: Access denied")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Message: "You do not have permission to access this resource"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// CurrentUserRole returns the authenticated user's role set by RequireAuth or OptionalAuth.
+func CurrentUserRole(ctx *gin.Context) (string, bool) {
+	val, exists := ctx.Get(ContextUserRoleKey)
+	if !exists {
+		return "", false
+	}
+	role, ok := val.(string)
+	return role, ok && role != ""
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/service"
+)
+
+// stubAuthService accepts the tokens it knows and rejects the others.
+type stubAuthService struct {
+	service.AuthService
+	claims map[string]*service.AuthClaims
+}
+
+func (s stubAuthService) Authenticate(tokenString string) (*service.AuthClaims, error) {
+	if claims, ok := s.claims[tokenString]; ok {
+		return claims, nil
+	}
+	return nil, service.ErrInvalidToken
+}
+
+func TestRequireRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := stubAuthService{claims: map[string]*service.AuthClaims{
+		"learner": {UserID: 1, Role: model.RoleLearner},
+		"teacher": {UserID: 2, Role: model.RoleTeacher},
+		"admin":   {UserID: 3, Role: model.RoleAdmin},
+		"no-role": {UserID: 4},
+	}}
+	router := gin.New()
+	admin := router.Group("/admin", RequireAuth(auth))
+	admin.GET("/tests", RequireRoles(model.RoleTeacher, model.RoleAdmin), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	admin.GET("/users", RequireRoles(model.RoleAdmin), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	router.GET("/unguarded", RequireRoles(model.RoleAdmin), func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	tests := []struct {
+		path  string
+		token string
+		want  int
+	}{
+		{path: "/admin/tests", token: "", want: http.StatusUnauthorized},
+		{path: "/admin/tests", token: "forged", want: http.StatusUnauthorized},
+		{path: "/admin/tests", token: "learner", want: http.StatusForbidden},
+		{path: "/admin/tests", token: "no-role", want: http.StatusUnauthorized},
+		{path: "/admin/tests", token: "teacher", want: http.StatusOK},
+		{path: "/admin/tests", token: "admin", want: http.StatusOK},
+		{path: "/admin/users", token: "teacher", want: http.StatusForbidden},
+		{path: "/admin/users", token: "admin", want: http.StatusOK},
+		{path: "/unguarded", token: "admin", want: http.StatusUnauthorized}, // Without RequireAuth nobody is identified
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("GET %s as %q: status = %d, want %d", tt.path, tt.token, rec.Code, tt.want)
+		}
+	}
+}
@@ -6,15 +6,84 @@ import (
 	"gorm.io/gorm"
 )
 
+// AnswerError is one specific mistake identified by the AI scorer.
+type AnswerError struct {
+	Original    string `json:"original"`
+	Correction  string `json:"correction"`
+	Explanation string `json:"explanation"`
+	Category    string `json:"category,omitempty"` // "grammar", "vocabulary", "coherence", "task"
+}
+
+// VocabularyItem is a suggested word or phrase with its Vietnamese meaning.
+type VocabularyItem struct {
+	Term    string `json:"term"`
+	Meaning string `json:"meaning"`
+}
+
+// PrecheckFinding is a requirement of the task that a rule-based check found missed before AI scoring.
+// Hard findings cap the score at ScoreCap; advisory findings are only reported and given to the AI.
+type PrecheckFinding struct {
+	Rule     string   `json:"rule"`     // "empty", "language", "sentence_count", "given_words", "word_count"
+	Severity string   `json:"severity"` // "hard" or "advisory"
+	Message  string   `json:"message"`
+	ScoreCap *float64 `json:"score_cap,omitempty"` // Hard findings only
+}
+
+const (
+	PrecheckHard     = "hard"
+	PrecheckAdvisory = "advisory"
+)
+
 type Answer struct {
-	ID            uint           `gorm:"primarykey" json:"id"`
-	TestAttemptID uint           `json:"test_attempt_id" gorm:"not null;index"`
-	QuestionID    uint           `json:"question_id" gorm:"not null;index"`
-	Question      Question       `json:"question,omitempty" gorm:"foreignKey:QuestionID"`
-	UserAnswer    string         `json:"user_answer" gorm:"type:text;not null"`
-	AIFeedback    string         `json:"ai_feedback,omitempty" gorm:"type:text"`
-	AIScore       *float64       `json:"ai_score,omitempty"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 uint                   `gorm:"primarykey" json:"id"`
+	TestAttemptID      uint                   `json:"test_attempt_id" gorm:"not null;index"`
+	QuestionID         uint                   `json:"question_id" gorm:"not null;index"`
+	Question           Question               `json:"question,omitempty" gorm:"foreignKey:QuestionID"`
+	QuestionRevisionID *uint                  `json:"question_revision_id,omitempty" gorm:"index"` // The revision shown to the learner; Question may have been edited since
+	QuestionRevision   *QuestionRevision      `json:"question_revision,omitempty" gorm:"foreignKey:QuestionRevisionID"`
+	UserAnswer         string                 `json:"user_answer" gorm:"type:text;not null"`
+	AIFeedback         string                 `json:"ai_feedback,omitempty" gorm:"type:text"` // Markdown rendering of the structured fields below, or the error text when scoring failed
+	AIScore            *float64               `json:"ai_score,omitempty"`
+	AIStrengths        []string               `json:"ai_strengths,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIErrors           []AnswerError          `json:"ai_errors,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIRevisedAnswer    string                 `json:"ai_revised_answer,omitempty" gorm:"type:text"`
+	AIVocabulary       []VocabularyItem       `json:"ai_vocabulary,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIUncappedScore    *float64               `json:"ai_uncapped_score,omitempty"`                           // The AI's score when a precheck finding capped AIScore below it
+	Prechecks          []PrecheckFinding      `json:"prechecks,omitempty" gorm:"serializer:json;type:jsonb"` // Rule-based findings of the last scoring run
+	PromptVersion      *int                   `json:"prompt_version,omitempty"`                              // Version of the prompt template of the question type used to score the answer
+	ModelName          string                 `json:"model_name,omitempty"`                                  // Model that scored the answer
+	ScoringStatus      string                 `json:"scoring_status" gorm:"not null;default:'pending'"`      // "pending", "scored", "failed", "deferred"
+	HumanScore         *float64               `json:"human_score,omitempty"`                                 // Teacher's score; replaces AIScore in totals, which is kept for calibration
+	HumanFeedback      string                 `json:"human_feedback,omitempty" gorm:"type:text"`
+	ReviewedByID       *uint                  `json:"reviewed_by_id,omitempty"` // Teacher who set HumanScore
+	ReviewedAt         *time.Time             `json:"reviewed_at,omitempty"`
+	CriterionScores    []AnswerCriterionScore `json:"criterion_scores,omitempty" gorm:"foreignKey:AnswerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Revision           int                    `json:"revision" gorm:"not null;default:0"` // Incremented on every save of an in-progress answer
+	SavedAt            *time.Time             `json:"saved_at,omitempty"`                 // Last save during an in-progress attempt
+	Late               bool                   `json:"late" gorm:"not null;default:false"` // Saved after its part's deadline (EXAM_LATE_POLICY=flag)
+	CreatedAt          time.Time              `json:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt         `gorm:"index" json:"-"`
+}
+
+// QuestionAsAnswered returns the question with the content of the revision the learner answered.
+// It falls back to the current content when the revision was not loaded.
+func (a *Answer) QuestionAsAnswered() Question {
+	q := a.Question
+	if a.QuestionRevision != nil {
+		a.QuestionRevision.ApplyTo(&q)
+	}
+	return q
+}
+
+// FinalScore is the score the answer adds to its attempt: the teacher's once it was reviewed,
+// otherwise the AI's once scored. It is nil while the answer has no score.
+func (a *Answer) FinalScore() *float64 {
+	if a.HumanScore != nil {
+		return a.HumanScore
+	}
+	if a.ScoringStatus == "scored" {
+		return a.AIScore
+	}
+	return nil
 }
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// AnswerCriterionScore is the AI score for one rubric criterion (e.g. "Grammar") of an Answer.
+type AnswerCriterionScore struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	AnswerID  uint      `json:"answer_id" gorm:"not null;index"`
+	Criterion string    `json:"criterion" gorm:"not null;index"` // Canonical rubric name, see service.rubricCriteria
+	Score     float64   `json:"score" gorm:"not null"`
+	MaxScore  float64   `json:"max_score" gorm:"not null"`
+	Comment   string    `json:"comment,omitempty" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CriterionResult is a criterion score stored inline as JSON, for records that are only ever
+// read as a whole (practice answers, archived scoring results).
+type CriterionResult struct {
+	Criterion string  `json:"criterion"`
+	Score     float64 `json:"score"`
+	MaxScore  float64 `json:"max_score"`
+	Comment   string  `json:"comment,omitempty"`
+}
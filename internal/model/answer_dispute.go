@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	DisputeOpen       = "open"       // Waiting in the teacher review queue
+	DisputeUpheld     = "upheld"     // Reviewed; the score stands
+	DisputeOverridden = "overridden" // Reviewed; a teacher replaced the score
+)
+
+// AnswerDispute is a learner's objection to the score of one of their answers. Teachers work
+// through open disputes in a review queue and either uphold the score or override it on the Answer.
+type AnswerDispute struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	AnswerID       uint       `json:"answer_id" gorm:"not null;index"`
+	Answer         Answer     `json:"answer,omitempty" gorm:"foreignKey:AnswerID"`
+	TestAttemptID  uint       `json:"test_attempt_id" gorm:"not null;index"`
+	UserID         uint       `json:"user_id" gorm:"not null;index"` // Learner who raised it
+	Comment        string     `json:"comment" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"not null;default:'open';index"` // DisputeOpen, DisputeUpheld or DisputeOverridden
+	DisputedScore  *float64   `json:"disputed_score,omitempty"`                    // Score of the answer when the dispute was raised
+	ReviewedByID   *uint      `json:"reviewed_by_id,omitempty"`
+	ResolutionNote string     `json:"resolution_note,omitempty" gorm:"type:text"` // Teacher's reply to the learner
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// AnswerScoreHistory is a scoring result of an Answer that was replaced by re-scoring.
+// The current result always lives on the Answer itself; this table only holds earlier ones.
+type AnswerScoreHistory struct {
+	ID              uint              `gorm:"primarykey" json:"id"`
+	AnswerID        uint              `json:"answer_id" gorm:"not null;index"`
+	AIScore         *float64          `json:"ai_score,omitempty"`
+	AIFeedback      string            `json:"ai_feedback,omitempty" gorm:"type:text"`
+	AIStrengths     []string          `json:"ai_strengths,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIErrors        []AnswerError     `json:"ai_errors,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIRevisedAnswer string            `json:"ai_revised_answer,omitempty" gorm:"type:text"`
+	AIVocabulary    []VocabularyItem  `json:"ai_vocabulary,omitempty" gorm:"serializer:json;type:jsonb"`
+	CriterionScores []CriterionResult `json:"criterion_scores,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIUncappedScore *float64          `json:"ai_uncapped_score,omitempty"`
+	Prechecks       []PrecheckFinding `json:"prechecks,omitempty" gorm:"serializer:json;type:jsonb"`
+	PromptVersion   *int              `json:"prompt_version,omitempty"`
+	ModelName       string            `json:"model_name,omitempty"`
+	ScoringStatus   string            `json:"scoring_status" gorm:"not null"` // Status of the replaced result: "scored" or "failed"
+	ScoredAt        time.Time         `json:"scored_at"`                      // When the replaced result was produced
+	RequestedByID   *uint             `json:"requested_by_id,omitempty"`      // User who asked for the re-score
+	CreatedAt       time.Time         `json:"created_at"`                     // When the result was replaced
+}
+
+// NewAnswerScoreHistory archives the current scoring result of an answer.
+// The answer's CriterionScores must be loaded.
+func NewAnswerScoreHistory(answer *Answer, requestedByID *uint) AnswerScoreHistory {
+	criteria := make([]CriterionResult, 0, len(answer.CriterionScores))
+	for _, c := range answer.CriterionScores {
+		criteria = append(criteria, CriterionResult{Criterion: c.Criterion, Score: c.Score, MaxScore: c.MaxScore, Comment: c.Comment})
+	}
+	return AnswerScoreHistory{
+		AnswerID:        answer.ID,
+		AIScore:         answer.AIScore,
+		AIFeedback:      answer.AIFeedback,
+		AIStrengths:     answer.AIStrengths,
+		AIErrors:        answer.AIErrors,
+		AIRevisedAnswer: answer.AIRevisedAnswer,
+		AIVocabulary:    answer.AIVocabulary,
+		CriterionScores: criteria,
+		AIUncappedScore: answer.AIUncappedScore,
+		Prechecks:       answer.Prechecks,
+		PromptVersion:   answer.PromptVersion,
+		ModelName:       answer.ModelName,
+		ScoringStatus:   answer.ScoringStatus,
+		ScoredAt:        answer.UpdatedAt,
+		RequestedByID:   requestedByID,
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DifficultyEasy   = "easy"
+	DifficultyMedium = "medium"
+	DifficultyHard   = "hard"
+)
+
+// BankQuestion is a reusable question that is not tied to a test. Building a test copies the
+// bank question into a Question row, so later edits to the bank never change past attempts.
+type BankQuestion struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	Title       string         `json:"title" gorm:"not null"`
+	Prompt      string         `json:"prompt" gorm:"type:text;not null"`
+	Type        string         `json:"type" gorm:"not null;index"` // "sentence_picture", "email_response", "opinion_essay"
+	ImageURL    *string        `json:"image_url,omitempty"`
+	GivenWord1  *string        `json:"given_word1,omitempty"`
+	GivenWord2  *string        `json:"given_word2,omitempty"`
+	Difficulty  string         `json:"difficulty" gorm:"not null;default:'medium';index"` // "easy", "medium", "hard"
+	Tags        []string       `json:"tags" gorm:"serializer:json;type:jsonb"`            // Lower-cased, de-duplicated
+	Version     int            `json:"version" gorm:"not null;default:1"`                 // Incremented on every edit
+	CreatedByID *uint          `json:"created_by_id,omitempty" gorm:"index"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func IsValidDifficulty(difficulty string) bool {
+	switch difficulty {
+	case DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return true
+	}
+	return false
+}
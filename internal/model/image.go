@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Image is an uploaded question image. Images are content-addressed: the SHA-256 of the data is
+// both the key in storage and the identifier in URLs, so identical uploads are stored once.
+type Image struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	Hash         string    `json:"hash" gorm:"size:64;not null;uniqueIndex"` // Hex SHA-256 of the data
+	MimeType     string    `json:"mime_type" gorm:"not null"`
+	Size         int64     `json:"size" gorm:"not null"`
+	Width        int       `json:"width"` // 0 for formats whose size is not read (HEIC/HEIF)
+	Height       int       `json:"height"`
+	Backend      string    `json:"backend" gorm:"not null"` // Storage backend that holds the data, "local" or "s3"
+	OriginalName string    `json:"original_name,omitempty"` // File name of an upload, or the URL an external image was copied from
+	UploadedByID *uint     `json:"uploaded_by_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+const (
+	ImageSourceOK     = "ok"
+	ImageSourceBroken = "broken"
+)
+
+// ImageSource records the last check of an external image URL used by a question. A copy of the
+// last image that passed is kept in storage under ImageHash, so scoring does not depend on the
+// external host staying up.
+type ImageSource struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	URL       string     `json:"url" gorm:"type:text;not null;uniqueIndex"`
+	Status    string     `json:"status" gorm:"not null"`              // ImageSourceOK or ImageSourceBroken
+	Error     string     `json:"error,omitempty"`                     // Why the last check failed
+	ImageHash *string    `json:"image_hash,omitempty" gorm:"size:64"` // Kept when a later check fails
+	CheckedAt time.Time  `json:"checked_at"`
+	LastOKAt  *time.Time `json:"last_ok_at,omitempty"`
+}
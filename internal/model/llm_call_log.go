@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// LLMCallLog is the audit record of one request to the AI provider made while scoring an answer:
+// what was sent, what came back and what it cost. A scoring run that repairs the model's output
+// makes several calls, numbered by Attempt; a call retried after a transient provider error has a
+// record per try, numbered by Retry. Records are deleted after LLM_AUDIT_RETENTION.
+type LLMCallLog struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	AnswerID         *uint     `json:"answer_id,omitempty" gorm:"index"`          // Set when a test answer was scored
+	PracticeAnswerID *uint     `json:"practice_answer_id,omitempty" gorm:"index"` // Set when a practice answer was scored
+	QuestionType     string    `json:"question_type" gorm:"not null"`
+	Attempt          int       `json:"attempt" gorm:"not null"`         // 1 for the first call, higher for repair calls
+	Retry            int       `json:"retry" gorm:"not null;default:0"` // 0 for the first try, higher for retries of the same call
+	Provider         string    `json:"provider" gorm:"not null"`
+	Model            string    `json:"model"`
+	PromptVersion    *int      `json:"prompt_version,omitempty"`
+	Prompt           string    `json:"prompt" gorm:"type:text"`
+	ImageCount       int       `json:"image_count"`
+	RawResponse      string    `json:"raw_response,omitempty" gorm:"type:text"`
+	Error            string    `json:"error,omitempty" gorm:"type:text"` // Provider error, or why the response was rejected
+	LatencyMs        int64     `json:"latency_ms"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	TotalTokens      int       `json:"total_tokens"`
+	CreatedAt        time.Time `json:"created_at" gorm:"index"`
+}
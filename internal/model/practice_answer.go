@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PracticeAnswer is a single answer written outside of a full test, either to an existing
+// question or to an ad-hoc prompt. The task content is copied so the history never changes.
+type PracticeAnswer struct {
+	ID                 uint              `gorm:"primarykey" json:"id"`
+	UserID             uint              `json:"user_id" gorm:"not null;index"`
+	QuestionID         *uint             `json:"question_id,omitempty" gorm:"index"` // Nil for ad-hoc prompts
+	QuestionRevisionID *uint             `json:"question_revision_id,omitempty"`
+	Type               string            `json:"type" gorm:"not null;index"` // "sentence_picture", "email_response", "opinion_essay"
+	Title              string            `json:"title"`
+	Prompt             string            `json:"prompt" gorm:"type:text;not null"`
+	ImageURL           *string           `json:"image_url,omitempty"`
+	GivenWord1         *string           `json:"given_word1,omitempty"`
+	GivenWord2         *string           `json:"given_word2,omitempty"`
+	MaxScore           float64           `json:"max_score"`
+	UserAnswer         string            `json:"user_answer" gorm:"type:text;not null"`
+	AIFeedback         string            `json:"ai_feedback,omitempty" gorm:"type:text"`
+	AIScore            *float64          `json:"ai_score,omitempty"`
+	AIStrengths        []string          `json:"ai_strengths,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIErrors           []AnswerError     `json:"ai_errors,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIRevisedAnswer    string            `json:"ai_revised_answer,omitempty" gorm:"type:text"`
+	AIVocabulary       []VocabularyItem  `json:"ai_vocabulary,omitempty" gorm:"serializer:json;type:jsonb"`
+	CriterionScores    []CriterionResult `json:"criterion_scores,omitempty" gorm:"serializer:json;type:jsonb"`
+	AIUncappedScore    *float64          `json:"ai_uncapped_score,omitempty"`
+	Prechecks          []PrecheckFinding `json:"prechecks,omitempty" gorm:"serializer:json;type:jsonb"`
+	PromptVersion      *int              `json:"prompt_version,omitempty"`
+	ModelName          string            `json:"model_name,omitempty"`
+	ScoringStatus      string            `json:"scoring_status" gorm:"not null;default:'pending'"` // "pending", "scored", "failed", "deferred"
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt    `gorm:"index" json:"-"`
+}
+
+// Question builds the question the practice answer was written for, as passed to the scorer.
+func (p *PracticeAnswer) Question() Question {
+	return Question{
+		Title:      p.Title,
+		Prompt:     p.Prompt,
+		Type:       p.Type,
+		ImageURL:   p.ImageURL,
+		GivenWord1: p.GivenWord1,
+		GivenWord2: p.GivenWord2,
+		MaxScore:   p.MaxScore,
+	}
+}
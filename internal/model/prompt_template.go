@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	PromptTemplateSourceFile  = "file"  // Built-in template stored on first start
+	PromptTemplateSourceAdmin = "admin" // Written by an admin
+)
+
+// PromptTemplate is a versioned Go text/template that renders the task part of the scoring prompt
+// for one question type. Templates are immutable once stored: an edit is stored as a new version.
+// One or two versions of a type are active at a time; with two, answers are split between them
+// by Weight (percent), so the wording of a new version can be compared against the current one.
+type PromptTemplate struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	QuestionType string     `json:"question_type" gorm:"not null;uniqueIndex:idx_prompt_template_type_version"`
+	Version      int        `json:"version" gorm:"not null;uniqueIndex:idx_prompt_template_type_version"`
+	Name         string     `json:"name" gorm:"not null"`
+	Description  string     `json:"description,omitempty" gorm:"type:text"`
+	Body         string     `json:"body" gorm:"type:text;not null"`
+	Active       bool       `json:"active" gorm:"not null;default:false;index"`
+	Weight       int        `json:"weight" gorm:"not null;default:0"` // Share of answers, in percent, while active
+	Source       string     `json:"source" gorm:"not null"`           // PromptTemplateSourceFile or PromptTemplateSourceAdmin
+	CreatedByID  *uint      `json:"created_by_id,omitempty"`
+	ActivatedAt  *time.Time `json:"activated_at,omitempty"` // Last activation
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// QuestionRevision is an immutable snapshot of a question's content. A new revision is written
+// every time the question is edited, and each Answer points at the revision the learner saw.
+type QuestionRevision struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	QuestionID uint      `json:"question_id" gorm:"not null;uniqueIndex:idx_question_revision"`
+	Revision   int       `json:"revision" gorm:"not null;uniqueIndex:idx_question_revision"` // 1 for the content the question was created with
+	Title      string    `json:"title" gorm:"not null"`
+	Prompt     string    `json:"prompt" gorm:"type:text;not null"`
+	Type       string    `json:"type" gorm:"not null"`
+	ImageURL   *string   `json:"image_url,omitempty"`
+	GivenWord1 *string   `json:"given_word1,omitempty"`
+	GivenWord2 *string   `json:"given_word2,omitempty"`
+	MaxScore   float64   `json:"max_score"`
+	EditedByID *uint     `json:"edited_by_id,omitempty"` // Nil for the original content and for backfilled revisions
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewQuestionRevision snapshots the current content of q.
+func NewQuestionRevision(q *Question, revision int, editedByID *uint) QuestionRevision {
+	return QuestionRevision{
+		QuestionID: q.ID,
+		Revision:   revision,
+		Title:      q.Title,
+		Prompt:     q.Prompt,
+		Type:       q.Type,
+		ImageURL:   q.ImageURL,
+		GivenWord1: q.GivenWord1,
+		GivenWord2: q.GivenWord2,
+		MaxScore:   q.MaxScore,
+		EditedByID: editedByID,
+	}
+}
+
+// ApplyTo overwrites the content fields of q with this revision's content.
+func (r *QuestionRevision) ApplyTo(q *Question) {
+	q.Title = r.Title
+	q.Prompt = r.Prompt
+	q.Type = r.Type
+	q.ImageURL = r.ImageURL
+	q.GivenWord1 = r.GivenWord1
+	q.GivenWord2 = r.GivenWord2
+	q.MaxScore = r.MaxScore
+}
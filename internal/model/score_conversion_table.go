@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	ScoreInterpolationStep   = "step"   // A raw score gets the scaled score of the first point at or above it
+	ScoreInterpolationLinear = "linear" // A raw score between two points is interpolated linearly between them
+)
+
+// IsValidScoreInterpolation reports whether mode is a supported interpolation mode.
+func IsValidScoreInterpolation(mode string) bool {
+	return mode == ScoreInterpolationStep || mode == ScoreInterpolationLinear
+}
+
+// ScorePoint maps one raw score to a scaled score.
+type ScorePoint struct {
+	Raw    float64 `json:"raw"`
+	Scaled float64 `json:"scaled"`
+}
+
+// ScoreConversionTable converts a raw total (0-28) to the TOEIC Writing scale (0-200).
+// Tables are immutable once stored: a change is uploaded as a new version and activated,
+// so attempts keep pointing at the version that produced their scaled score.
+type ScoreConversionTable struct {
+	ID             uint         `gorm:"primarykey" json:"id"`
+	Version        int          `json:"version" gorm:"not null;uniqueIndex"`
+	Name           string       `json:"name" gorm:"not null"`
+	Description    string       `json:"description,omitempty" gorm:"type:text"`
+	Interpolation  string       `json:"interpolation" gorm:"not null;default:'step'"` // ScoreInterpolationStep or ScoreInterpolationLinear
+	RoundTo        float64      `json:"round_to" gorm:"not null;default:0"`           // Scaled scores are rounded to a multiple of this; 0 keeps them as computed
+	MaxRawScore    float64      `json:"max_raw_score" gorm:"not null"`                // Raw score of the last point
+	MaxScaledScore float64      `json:"max_scaled_score" gorm:"not null"`             // Scaled score of the last point
+	Points         []ScorePoint `json:"points" gorm:"serializer:json;type:jsonb;not null"`
+	Active         bool         `json:"active" gorm:"not null;default:false;index"` // Exactly one table is active at a time
+	Source         string       `json:"source" gorm:"not null"`                     // "file" for the built-in default, "upload" for admin uploads
+	CreatedByID    *uint        `json:"created_by_id,omitempty"`
+	ActivatedAt    *time.Time   `json:"activated_at,omitempty"` // Last activation
+	CreatedAt      time.Time    `json:"created_at"`
+}
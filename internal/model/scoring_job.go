@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	ScoringJobQueued  = "queued"
+	ScoringJobRunning = "running"
+	ScoringJobDone    = "done"
+	ScoringJobFailed  = "failed"
+)
+
+// ScoringJob is a durable unit of background work: score the answers of one TestAttempt.
+// Jobs live in Postgres so a restart never loses a submission that is still waiting for the LLM.
+type ScoringJob struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	TestAttemptID uint       `json:"test_attempt_id" gorm:"not null;index"`
+	Status        string     `json:"status" gorm:"not null;default:'queued';index"` // "queued", "running", "done", "failed"
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error,omitempty" gorm:"type:text"`
+	RunAfter      time.Time  `json:"run_after" gorm:"not null;index"`
+	LockedAt      *time.Time `json:"locked_at,omitempty"`
+	LockedBy      string     `json:"locked_by,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -6,10 +6,17 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	TestStatusDraft     = "draft"
+	TestStatusPublished = "published"
+)
+
 type Test struct {
 	ID          uint           `gorm:"primarykey" json:"id"`
 	Title       string         `json:"title" gorm:"not null;uniqueIndex"`
 	Description string         `json:"description,omitempty"`
+	Status      string         `json:"status" gorm:"not null;default:'published';index"` // "draft", "published". The default only applies to rows created before drafts existed
+	PublishedAt *time.Time     `json:"published_at,omitempty"`
 	Questions   []Question     `json:"questions" gorm:"foreignKey:TestID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -7,15 +7,22 @@ import (
 )
 
 type TestAttempt struct {
-	ID          uint           `gorm:"primarykey" json:"id"`
-	TestID      uint           `json:"test_id" gorm:"not null;index"`
-	Test        Test           `json:"test,omitempty" gorm:"foreignKey:TestID"`
-	UserID      *uint          `json:"user_id,omitempty" gorm:"index"`
-	SubmittedAt time.Time      `json:"submitted_at" gorm:"autoCreateTime"`
-	TotalScore  *float64       `json:"total_score,omitempty"`
-	Status      string         `json:"status" gorm:"default:'pending'"` // "pending", "scoring", "completed", "error", "completed_with_errors"
-	Answers     []Answer       `json:"answers,omitempty" gorm:"foreignKey:TestAttemptID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint           `gorm:"primarykey" json:"id"`
+	TestID            uint           `json:"test_id" gorm:"not null;index"`
+	Test              Test           `json:"test,omitempty" gorm:"foreignKey:TestID"`
+	UserID            *uint          `json:"user_id,omitempty" gorm:"index"`     // At most one "in_progress" attempt per user and test (unique index created in AutoMigrateDB)
+	SubmittedAt       time.Time      `json:"submitted_at" gorm:"autoCreateTime"` // Set again on submit for attempts that were started first
+	TotalScore        *float64       `json:"total_score,omitempty"`
+	ScaledScore       *float64       `json:"scaled_score,omitempty"`          // TotalScore converted when scoring finished
+	ScoreTableVersion *int           `json:"score_table_version,omitempty"`   // ScoreConversionTable version that produced ScaledScore
+	Status            string         `json:"status" gorm:"default:'pending'"` // "in_progress", "expired", "pending", "scoring", "completed", "error", "completed_with_errors"
+	StartedAt         *time.Time     `json:"started_at,omitempty"`            // Set for every started attempt, timed or not; nil when answers were submitted without starting. Only the part deadlines below are timed-only
+	Part1DeadlineAt   *time.Time     `json:"part1_deadline_at,omitempty"`
+	Part2DeadlineAt   *time.Time     `json:"part2_deadline_at,omitempty"`
+	Part3DeadlineAt   *time.Time     `json:"part3_deadline_at,omitempty" gorm:"index"` // Also the end of the exam
+	AutoSubmitted     bool           `json:"auto_submitted" gorm:"not null;default:false"`
+	Answers           []Answer       `json:"answers,omitempty" gorm:"foreignKey:TestAttemptID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
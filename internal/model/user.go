@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleLearner = "learner"
+	RoleTeacher = "teacher"
+	RoleAdmin   = "admin"
+)
+
+// IsValidRole reports whether role is one of the known user roles.
+func IsValidRole(role string) bool {
+	return role == RoleLearner || role == RoleTeacher || role == RoleAdmin
+}
+
+type User struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	Email        string         `json:"email" gorm:"not null;uniqueIndex"`
+	PasswordHash string         `json:"-" gorm:"not null"`
+	FullName     string         `json:"full_name,omitempty"`
+	Role         string         `json:"role" gorm:"not null;default:'learner';index"` // "learner", "teacher", "admin"
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
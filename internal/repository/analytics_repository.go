@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// submittedAttemptSQL limits analytics to attempts that were handed in; drafts of attempts in
+// progress and attempts that expired without answers say nothing about the content.
+const submittedAttemptSQL = "t.status NOT IN ('in_progress', 'expired') AND t.deleted_at IS NULL"
+
+// TestAnalyticsRow aggregates the submitted attempts of one test.
+type TestAnalyticsRow struct {
+	TestID              uint
+	Title               string
+	Status              string
+	Attempts            int
+	Learners            int
+	Completed           int
+	CompletedWithErrors int
+	Errored             int
+	MeanRawScore        *float64
+	MedianRawScore      *float64
+	ScoredAnswers       int
+	FailedAnswers       int
+}
+
+// QuestionAnalyticsRow aggregates the submitted answers to one question. Fractions are AIScore divided by
+// the max score of the revision that was answered.
+type QuestionAnalyticsRow struct {
+	QuestionID     uint
+	Answers        int
+	Scored         int
+	Failed         int
+	MeanFraction   *float64
+	MedianFraction *float64
+	StdDevFraction *float64
+	AverageChars   *float64
+	AverageWords   *float64
+}
+
+// QuestionScoreBucketRow counts the scored answers of a question whose fraction falls in a bucket.
+type QuestionScoreBucketRow struct {
+	QuestionID uint
+	Bucket     int
+	Answers    int
+}
+
+// AnalyticsRepository aggregates how tests and questions perform across all learners.
+type AnalyticsRepository interface {
+	// TestSummaries aggregates every test, including deleted ones, that has at least one submitted attempt.
+	// A non-nil testID limits the result to that test.
+	TestSummaries(testID *uint) ([]TestAnalyticsRow, error)
+	QuestionStats(testID uint) ([]QuestionAnalyticsRow, error)
+	// QuestionScoreBuckets counts the scored answers of each question in equal-width buckets of the score fraction.
+	QuestionScoreBuckets(testID uint, buckets int) ([]QuestionScoreBucketRow, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+func (r *analyticsRepository) TestSummaries(testID *uint) ([]TestAnalyticsRow, error) {
+	filter, args := submittedAttemptSQL, []interface{}{}
+	if testID != nil {
+		filter, args = submittedAttemptSQL+" AND t.test_id = ?", []interface{}{*testID, *testID}
+	}
+
+	var rows []TestAnalyticsRow
+	err := r.db.Raw(`
+		WITH attempt_stats AS (
+			SELECT t.test_id,
+				COUNT(*) AS attempts,
+				COUNT(DISTINCT t.user_id) AS learners,
+				COUNT(*) FILTER (WHERE t.status = 'completed') AS completed,
+				COUNT(*) FILTER (WHERE t.status = 'completed_with_errors') AS completed_with_errors,
+				COUNT(*) FILTER (WHERE t.status = 'error') AS errored,
+				AVG(t.total_score) AS mean_raw_score,
+				PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY t.total_score) AS median_raw_score
+			FROM test_attempts t
+			WHERE `+filter+`
+			GROUP BY t.test_id
+		), answer_stats AS (
+			SELECT t.test_id,
+				COUNT(*) FILTER (WHERE a.scoring_status = 'scored') AS scored_answers,
+				COUNT(*) FILTER (WHERE a.scoring_status = 'failed') AS failed_answers
+			FROM answers a
+			JOIN test_attempts t ON t.id = a.test_attempt_id
+			WHERE `+filter+` AND a.deleted_at IS NULL
+			GROUP BY t.test_id
+		)
+		SELECT tests.id AS test_id, tests.title, tests.status,
+			s.attempts, s.learners, s.completed, s.completed_with_errors, s.errored, s.mean_raw_score, s.median_raw_score,
+			COALESCE(a.scored_answers, 0) AS scored_answers, COALESCE(a.failed_answers, 0) AS failed_answers
+		FROM attempt_stats s
+		JOIN tests ON tests.id = s.test_id
+		LEFT JOIN answer_stats a ON a.test_id = s.test_id
+		ORDER BY tests.id`, args...).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *analyticsRepository) QuestionStats(testID uint) ([]QuestionAnalyticsRow, error) {
+	var rows []QuestionAnalyticsRow
+	err := r.db.Raw(`
+		SELECT a.question_id,
+			COUNT(*) AS answers,
+			COUNT(*) FILTER (WHERE a.scoring_status = 'scored') AS scored,
+			COUNT(*) FILTER (WHERE a.scoring_status = 'failed') AS failed,
+			AVG(a.ai_score / NULLIF(`+answeredMaxScoreColumn+`, 0)) FILTER (WHERE a.scoring_status = 'scored') AS mean_fraction,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY a.ai_score / NULLIF(`+answeredMaxScoreColumn+`, 0)) FILTER (WHERE a.scoring_status = 'scored') AS median_fraction,
+			STDDEV_POP(a.ai_score / NULLIF(`+answeredMaxScoreColumn+`, 0)) FILTER (WHERE a.scoring_status = 'scored') AS std_dev_fraction,
+			AVG(CHAR_LENGTH(BTRIM(a.user_answer))) AS average_chars,
+			AVG(COALESCE(ARRAY_LENGTH(REGEXP_SPLIT_TO_ARRAY(NULLIF(BTRIM(a.user_answer), ''), '\s+'), 1), 0)) AS average_words
+		FROM answers a
+		JOIN test_attempts t ON t.id = a.test_attempt_id
+		JOIN questions q ON q.id = a.question_id
+		LEFT JOIN question_revisions qr ON qr.id = a.question_revision_id
+		WHERE q.test_id = ? AND a.deleted_at IS NULL AND `+submittedAttemptSQL+`
+		GROUP BY a.question_id`, testID).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *analyticsRepository) QuestionScoreBuckets(testID uint, buckets int) ([]QuestionScoreBucketRow, error) {
+	var rows []QuestionScoreBucketRow
+	err := r.db.Raw(`
+		SELECT a.question_id,
+			LEAST(FLOOR(a.ai_score / `+answeredMaxScoreColumn+` * ?)::int, ? - 1) AS bucket,
+			COUNT(*) AS answers
+		FROM answers a
+		JOIN test_attempts t ON t.id = a.test_attempt_id
+		JOIN questions q ON q.id = a.question_id
+		LEFT JOIN question_revisions qr ON qr.id = a.question_revision_id
+		WHERE q.test_id = ? AND `+answeredMaxScoreColumn+` > 0 AND a.deleted_at IS NULL AND a.scoring_status = 'scored' AND a.ai_score IS NOT NULL
+			AND `+submittedAttemptSQL+`
+		GROUP BY 1, 2`, buckets, buckets, testID).
+		Scan(&rows).Error
+	return rows, err
+}
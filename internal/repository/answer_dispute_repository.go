@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+)
+
+// AnswerDisputeFilter narrows FindAll. Zero values mean "no filter".
+type AnswerDisputeFilter struct {
+	Status string
+	TestID uint
+	UserID uint
+	Limit  int
+	Offset int
+}
+
+// ReviewCalibrationRow aggregates the reviewed answers of one question type.
+type ReviewCalibrationRow struct {
+	QuestionType      string
+	Reviewed          int64
+	MeanAIScore       float64
+	MeanHumanScore    float64
+	MeanDifference    float64
+	MeanAbsDifference float64
+	Changed           int64
+}
+
+// AnswerDisputeRepository reads disputes. They are created and resolved by ReviewService in
+// transactions that also lock the attempt, so they are written there.
+type AnswerDisputeRepository interface {
+	FindByID(id uint) (*model.AnswerDispute, error)
+	// FindAll returns one page of matching disputes with their answer's question. Open disputes are
+	// listed oldest first, as a queue; others newest first.
+	FindAll(filter AnswerDisputeFilter) ([]model.AnswerDispute, int64, error)
+	CalibrationByQuestionType() ([]ReviewCalibrationRow, error)
+}
+
+type answerDisputeRepository struct {
+	db *gorm.DB
+}
+
+func NewAnswerDisputeRepository(db *gorm.DB) AnswerDisputeRepository {
+	return &answerDisputeRepository{db: db}
+}
+
+func (r *answerDisputeRepository) FindByID(id uint) (*model.AnswerDispute, error) {
+	var dispute model.AnswerDispute
+	err := r.db.
+		Preload("Answer.Question").
+		Preload("Answer.QuestionRevision").
+		Preload("Answer.CriterionScores", func(db *gorm.DB) *gorm.DB {
+			return db.Order("answer_criterion_scores.id ASC")
+		}).
+		First(&dispute, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *answerDisputeRepository) FindAll(filter AnswerDisputeFilter) ([]model.AnswerDispute, int64, error) {
+	query := r.db.Model(&model.AnswerDispute{})
+	if filter.Status != "" {
+		query = query.Where("answer_disputes.status = ?", filter.Status)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("answer_disputes.user_id = ?", filter.UserID)
+	}
+	if filter.TestID != 0 {
+		query = query.Joins("JOIN test_attempts ON test_attempts.id = answer_disputes.test_attempt_id").
+			Where("test_attempts.test_id = ?", filter.TestID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var disputes []model.AnswerDispute
+	if filter.Status == model.DisputeOpen {
+		query = query.Order("answer_disputes.created_at ASC")
+	} else {
+		query = query.Order("answer_disputes.created_at DESC")
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+	err := query.Preload("Answer.Question").Find(&disputes).Error
+	return disputes, total, err
+}
+
+// CalibrationByQuestionType compares the teacher and AI scores of every answer a teacher reviewed
+// while it had an AI score, disputed or not.
+func (r *answerDisputeRepository) CalibrationByQuestionType() ([]ReviewCalibrationRow, error) {
+	var rows []ReviewCalibrationRow
+	err := r.db.Table("answers AS a").
+		Select("q.type AS question_type, COUNT(*) AS reviewed, AVG(a.ai_score) AS mean_ai_score, "+
+			"AVG(a.human_score) AS mean_human_score, AVG(a.human_score - a.ai_score) AS mean_difference, "+
+			"AVG(ABS(a.human_score - a.ai_score)) AS mean_abs_difference, "+
+			"COUNT(*) FILTER (WHERE a.human_score <> a.ai_score) AS changed").
+		Joins("JOIN questions AS q ON q.id = a.question_id").
+		Where("a.deleted_at IS NULL AND a.human_score IS NOT NULL AND a.ai_score IS NOT NULL AND a.scoring_status = ?", "scored").
+		Group("q.type").
+		Order("q.type").
+		Scan(&rows).Error
+	return rows, err
+}
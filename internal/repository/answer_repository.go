@@ -1,13 +1,30 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/lshigami/Ringtails/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrStaleRevision is returned by SaveDraft when the answer was saved by someone else in the meantime.
+var ErrStaleRevision = errors.New("answer has been modified since the given revision")
+
+// ErrAttemptNotInProgress is returned by SaveDraft when the attempt was submitted or expired before the save.
+var ErrAttemptNotInProgress = errors.New("test attempt is not in progress")
+
 type AnswerRepository interface {
 	Update(answer *model.Answer) error
-	// FindByTestAttemptIDAndQuestionID(testAttemptID uint, questionID uint) (*model.Answer, error) // Might be useful
+	SaveScoringResult(answer *model.Answer) error
+	FindByID(id uint) (*model.Answer, error)
+	FindByTestAttemptIDAndQuestionID(testAttemptID uint, questionID uint) (*model.Answer, error)
+	// SaveDraft creates or overwrites the answer to one question of an in-progress attempt, but only if
+	// the stored revision equals expectedRevision (0 when no answer exists yet). On success the answer
+	// holds the stored row with its incremented revision; otherwise ErrStaleRevision is returned.
+	// ErrAttemptNotInProgress is returned if the attempt is no longer in progress.
+	SaveDraft(answer *model.Answer, expectedRevision int) error
 }
 
 type answerRepository struct {
@@ -19,13 +36,85 @@ func NewAnswerRepository(db *gorm.DB) AnswerRepository {
 }
 
 func (r *answerRepository) Update(answer *model.Answer) error {
-	// Using Save to update all fields, including AIScore and AIFeedback
-	return r.db.Save(answer).Error
+	// Using Save to update all fields, including AIScore and AIFeedback.
+	// Associations are omitted so a preloaded Question is never written back.
+	return r.db.Omit(clause.Associations).Save(answer).Error
+}
+
+// SaveScoringResult stores the answer's AI fields and replaces its criterion scores in one transaction,
+// so a re-scored answer never shows a mix of old and new criterion scores.
+func (r *answerRepository) SaveScoringResult(answer *model.Answer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(answer).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("answer_id = ?", answer.ID).Delete(&model.AnswerCriterionScore{}).Error; err != nil {
+			return err
+		}
+		if len(answer.CriterionScores) == 0 {
+			return nil
+		}
+		for i := range answer.CriterionScores {
+			answer.CriterionScores[i].ID = 0
+			answer.CriterionScores[i].AnswerID = answer.ID
+		}
+		return tx.Create(&answer.CriterionScores).Error
+	})
+}
+
+func (r *answerRepository) FindByID(id uint) (*model.Answer, error) {
+	var answer model.Answer
+	if err := r.db.First(&answer, id).Error; err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+func (r *answerRepository) FindByTestAttemptIDAndQuestionID(testAttemptID uint, questionID uint) (*model.Answer, error) {
+	var answer model.Answer
+	err := r.db.Where("test_attempt_id = ? AND question_id = ?", testAttemptID, questionID).First(&answer).Error
+	return &answer, err
 }
 
-// Example of a more specific find method if needed
-// func (r *answerRepository) FindByTestAttemptIDAndQuestionID(testAttemptID uint, questionID uint) (*model.Answer, error) {
-// 	var answer model.Answer
-// 	err := r.db.Where("test_attempt_id = ? AND question_id = ?", testAttemptID, questionID).First(&answer).Error
-// 	return &answer, err
-// }
+func (r *answerRepository) SaveDraft(answer *model.Answer, expectedRevision int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Saves of the same attempt are serialized on the attempt row, so two devices creating
+		// the first revision of an answer at the same time cannot both succeed. Submitting locks the
+		// same row first, so a save that waited for a submit sees the new status and is refused.
+		var attempt model.TestAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&attempt, answer.TestAttemptID).Error; err != nil {
+			return err
+		}
+		if attempt.Status != "in_progress" {
+			return fmt.Errorf("%w (status %q)", ErrAttemptNotInProgress, attempt.Status)
+		}
+
+		var stored model.Answer
+		err := tx.Where("test_attempt_id = ? AND question_id = ?", answer.TestAttemptID, answer.QuestionID).First(&stored).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if expectedRevision != 0 {
+				return ErrStaleRevision
+			}
+			answer.ID = 0
+			answer.Revision = 1
+			return tx.Omit(clause.Associations).Create(answer).Error
+		case err != nil:
+			return err
+		case stored.Revision != expectedRevision:
+			*answer = stored
+			return ErrStaleRevision
+		}
+
+		stored.UserAnswer = answer.UserAnswer
+		stored.QuestionRevisionID = answer.QuestionRevisionID
+		stored.SavedAt = answer.SavedAt
+		stored.Late = stored.Late || answer.Late
+		stored.Revision++
+		if err := tx.Omit(clause.Associations).Save(&stored).Error; err != nil {
+			return err
+		}
+		*answer = stored
+		return nil
+	})
+}
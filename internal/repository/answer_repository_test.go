@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/testdb"
+)
+
+func TestSaveDraft(t *testing.T) {
+	db := testdb.Open(t, &model.TestAttempt{}, &model.Answer{})
+	repo := NewAnswerRepository(db)
+	attempt := model.TestAttempt{TestID: 1, Status: "in_progress"}
+	if err := db.Create(&attempt).Error; err != nil {
+		t.Fatal(err)
+	}
+	save := func(text string, expectedRevision int) (*model.Answer, error) {
+		answer := &model.Answer{TestAttemptID: attempt.ID, QuestionID: 7, UserAnswer: text, ScoringStatus: "pending"}
+		return answer, repo.SaveDraft(answer, expectedRevision)
+	}
+
+	if _, err := save("first", 1); !errors.Is(err, ErrStaleRevision) {
+		t.Fatalf("first save expecting revision 1: error = %v, want ErrStaleRevision", err)
+	}
+	first, err := save("first", 0)
+	if err != nil || first.Revision != 1 {
+		t.Fatalf("first save = revision %d, %v; want revision 1", first.Revision, err)
+	}
+	if _, err := save("from another device", 0); !errors.Is(err, ErrStaleRevision) {
+		t.Fatalf("second create of the same answer: error = %v, want ErrStaleRevision", err)
+	}
+	second, err := save("second", 1)
+	if err != nil || second.Revision != 2 || second.ID != first.ID {
+		t.Fatalf("save on revision 1 = answer %d revision %d, %v; want answer %d revision 2", second.ID, second.Revision, err, first.ID)
+	}
+	stale, err := save("stale tab", 1)
+	if !errors.Is(err, ErrStaleRevision) {
+		t.Fatalf("save on an outdated revision: error = %v, want ErrStaleRevision", err)
+	}
+	if stale.UserAnswer != "second" || stale.Revision != 2 {
+		t.Errorf("a refused save returns %q at revision %d, want the stored %q at revision 2", stale.UserAnswer, stale.Revision, "second")
+	}
+
+	for _, status := range []string{"pending", "expired"} {
+		if err := db.Model(&attempt).Update("status", status).Error; err != nil {
+			t.Fatal(err)
+		}
+		if _, err := save("after the end", 2); !errors.Is(err, ErrAttemptNotInProgress) {
+			t.Errorf("save on a %s attempt: error = %v, want ErrAttemptNotInProgress", status, err)
+		}
+	}
+	stored, err := repo.FindByTestAttemptIDAndQuestionID(attempt.ID, 7)
+	if err != nil || stored.UserAnswer != "second" || stored.Revision != 2 {
+		t.Fatalf("stored answer = %+v, %v; want %q at revision 2", stored, err, "second")
+	}
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+)
+
+// AnswerScoreHistoryRepository reads archived scoring results. They are written by the
+// re-score transaction in RescoreService, together with the reset of the answers.
+type AnswerScoreHistoryRepository interface {
+	FindByAnswerID(answerID uint) ([]model.AnswerScoreHistory, error)
+}
+
+type answerScoreHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewAnswerScoreHistoryRepository(db *gorm.DB) AnswerScoreHistoryRepository {
+	return &answerScoreHistoryRepository{db: db}
+}
+
+// FindByAnswerID lists the replaced results of an answer, newest first.
+func (r *answerScoreHistoryRepository) FindByAnswerID(answerID uint) ([]model.AnswerScoreHistory, error) {
+	var history []model.AnswerScoreHistory
+	err := r.db.Where("answer_id = ?", answerID).Order("created_at DESC").Find(&history).Error
+	return history, err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+)
+
+// BankQuestionFilter narrows FindAll. Zero values mean "no filter".
+type BankQuestionFilter struct {
+	Type       string
+	Difficulty string
+	Tags       []string // Questions must have all of these tags
+	Search     string   // Case-insensitive match on title or prompt
+	Limit      int
+	Offset     int
+}
+
+type BankQuestionRepository interface {
+	Create(question *model.BankQuestion) error
+	FindByID(id uint) (*model.BankQuestion, error)
+	FindByIDs(ids []uint) ([]model.BankQuestion, error)
+	FindAll(filter BankQuestionFilter) ([]model.BankQuestion, int64, error)
+	Update(question *model.BankQuestion) error
+	Delete(id uint) error
+}
+
+type bankQuestionRepository struct {
+	db *gorm.DB
+}
+
+func NewBankQuestionRepository(db *gorm.DB) BankQuestionRepository {
+	return &bankQuestionRepository{db: db}
+}
+
+func (r *bankQuestionRepository) Create(question *model.BankQuestion) error {
+	return r.db.Create(question).Error
+}
+
+func (r *bankQuestionRepository) FindByID(id uint) (*model.BankQuestion, error) {
+	var question model.BankQuestion
+	err := r.db.First(&question, id).Error
+	return &question, err
+}
+
+func (r *bankQuestionRepository) FindByIDs(ids []uint) ([]model.BankQuestion, error) {
+	var questions []model.BankQuestion
+	err := r.db.Where("id IN ?", ids).Find(&questions).Error
+	return questions, err
+}
+
+// FindAll returns one page of matching questions together with the total number of matches.
+func (r *bankQuestionRepository) FindAll(filter BankQuestionFilter) ([]model.BankQuestion, int64, error) {
+	query := r.db.Model(&model.BankQuestion{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Difficulty != "" {
+		query = query.Where("difficulty = ?", filter.Difficulty)
+	}
+	if len(filter.Tags) > 0 {
+		tags, err := json.Marshal(filter.Tags)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("tags @> ?::jsonb", string(tags))
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := "%" + escapeLikePattern(search) + "%"
+		query = query.Where(`title ILIKE ? ESCAPE '\' OR prompt ILIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var questions []model.BankQuestion
+	query = query.Order("updated_at DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+	err := query.Find(&questions).Error
+	return questions, total, err
+}
+
+func (r *bankQuestionRepository) Update(question *model.BankQuestion) error {
+	return r.db.Save(question).Error
+}
+
+// Delete soft-deletes the question. Tests built from it keep their own copies.
+func (r *bankQuestionRepository) Delete(id uint) error {
+	result := r.db.Delete(&model.BankQuestion{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself, so user input matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLikePattern makes value safe to embed in a LIKE pattern that declares ESCAPE '\'.
+func escapeLikePattern(value string) string {
+	return likeEscaper.Replace(value)
+}
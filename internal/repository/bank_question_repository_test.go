@@ -0,0 +1,22 @@
+package repository
+
+import "testing"
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "email", want: "email"},
+		{value: "100%", want: `100\%`},
+		{value: "given_word", want: `given\_word`},
+		{value: `C:\temp`, want: `C:\\temp`},
+		{value: `%_\`, want: `\%\_\\`},
+		{value: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := escapeLikePattern(tt.value); got != tt.want {
+			t.Errorf("escapeLikePattern(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// answeredTypeColumn and answeredMaxScoreColumn read a question as it was answered, from the answer's
+// revision joined as qr, falling back to the current question q for answers without one.
+const (
+	answeredTypeColumn     = "COALESCE(qr.type, q.type)"
+	answeredMaxScoreColumn = "COALESCE(qr.max_score, q.max_score)"
+)
+
+// finishedAttemptStatuses are the attempts that have been scored and count towards progress.
+var finishedAttemptStatuses = []string{"completed", "completed_with_errors"}
+
+type AttemptScoreRow struct {
+	AttemptID         uint
+	TestID            uint
+	TestTitle         string
+	SubmittedAt       time.Time
+	TotalScore        *float64
+	ScaledScore       *float64
+	ScoreTableVersion *int
+}
+
+type AttemptStatusCount struct {
+	Status string
+	Count  int
+}
+
+type QuestionTypeAverageRow struct {
+	Type           string
+	Answers        int
+	AverageScore   float64
+	AverageMax     float64
+	AveragePercent float64
+}
+
+type CriterionAverageRow struct {
+	Criterion      string
+	Scores         int
+	AverageScore   float64
+	AveragePercent float64
+}
+
+// ActivityStreakRow is a run of consecutive days (UTC) with at least one submitted attempt.
+type ActivityStreakRow struct {
+	StartDay time.Time
+	EndDay   time.Time
+	Days     int
+}
+
+// DashboardRepository aggregates one learner's history across all tests.
+type DashboardRepository interface {
+	ScoreTimeline(userID uint) ([]AttemptScoreRow, error)
+	CountAttemptsByStatus(userID uint) ([]AttemptStatusCount, error)
+	CountDistinctTests(userID uint) (int64, error)
+	AverageByQuestionType(userID uint) ([]QuestionTypeAverageRow, error)
+	AverageByCriterion(userID uint) ([]CriterionAverageRow, error)
+	ActivityStreaks(userID uint) ([]ActivityStreakRow, error)
+}
+
+type dashboardRepository struct {
+	db *gorm.DB
+}
+
+func NewDashboardRepository(db *gorm.DB) DashboardRepository {
+	return &dashboardRepository{db: db}
+}
+
+// ScoreTimeline lists the user's scored attempts, oldest first.
+func (r *dashboardRepository) ScoreTimeline(userID uint) ([]AttemptScoreRow, error) {
+	var rows []AttemptScoreRow
+	err := r.db.Table("test_attempts AS t").
+		Select("t.id AS attempt_id, t.test_id, tests.title AS test_title, t.submitted_at, t.total_score, t.scaled_score, t.score_table_version").
+		Joins("JOIN tests ON tests.id = t.test_id").
+		Where("t.user_id = ? AND t.status IN ? AND t.total_score IS NOT NULL AND t.deleted_at IS NULL", userID, finishedAttemptStatuses).
+		Order("t.submitted_at ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *dashboardRepository) CountAttemptsByStatus(userID uint) ([]AttemptStatusCount, error) {
+	var rows []AttemptStatusCount
+	err := r.db.Table("test_attempts").
+		Select("status, COUNT(*) AS count").
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Group("status").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *dashboardRepository) CountDistinctTests(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Table("test_attempts").
+		Where("user_id = ? AND status IN ? AND deleted_at IS NULL", userID, finishedAttemptStatuses).
+		Distinct("test_id").
+		Count(&count).Error
+	return count, err
+}
+
+// AverageByQuestionType averages the final scores of the user's test answers per question type.
+// AveragePercent normalizes by each question's max score, so types with different scales compare.
+// Type and max score come from the revision that was answered, since a question may have been edited since.
+func (r *dashboardRepository) AverageByQuestionType(userID uint) ([]QuestionTypeAverageRow, error) {
+	var rows []QuestionTypeAverageRow
+	err := r.db.Table("answers AS a").
+		Select(answeredTypeColumn+" AS type, COUNT(*) AS answers, AVG("+finalScoreSQL+") AS average_score, AVG("+answeredMaxScoreColumn+") AS average_max, "+
+			"100 * AVG(("+finalScoreSQL+") / NULLIF("+answeredMaxScoreColumn+", 0)) AS average_percent").
+		Joins("JOIN test_attempts AS t ON t.id = a.test_attempt_id").
+		Joins("JOIN questions AS q ON q.id = a.question_id").
+		Joins("LEFT JOIN question_revisions AS qr ON qr.id = a.question_revision_id").
+		Where("t.user_id = ? AND t.deleted_at IS NULL AND a.deleted_at IS NULL AND ("+finalScoreSQL+") IS NOT NULL", userID).
+		Group(answeredTypeColumn).
+		Order(answeredTypeColumn).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *dashboardRepository) AverageByCriterion(userID uint) ([]CriterionAverageRow, error) {
+	var rows []CriterionAverageRow
+	err := r.db.Table("answer_criterion_scores AS c").
+		Select("c.criterion, COUNT(*) AS scores, AVG(c.score) AS average_score, 100 * AVG(c.score / NULLIF(c.max_score, 0)) AS average_percent").
+		Joins("JOIN answers AS a ON a.id = c.answer_id").
+		Joins("JOIN test_attempts AS t ON t.id = a.test_attempt_id").
+		Where("t.user_id = ? AND t.deleted_at IS NULL AND a.deleted_at IS NULL AND a.scoring_status = ?", userID, "scored").
+		Group("c.criterion").
+		Order("c.criterion").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// ActivityStreaks groups the days with a submission into runs of consecutive days, most recent first.
+// Consecutive days minus their row number give the same date, which identifies the run.
+func (r *dashboardRepository) ActivityStreaks(userID uint) ([]ActivityStreakRow, error) {
+	var rows []ActivityStreakRow
+	err := r.db.Raw(`
+		WITH days AS (
+			SELECT DISTINCT (submitted_at AT TIME ZONE 'UTC')::date AS day
+			FROM test_attempts
+			WHERE user_id = ? AND status NOT IN ('in_progress', 'expired') AND deleted_at IS NULL
+		), runs AS (
+			SELECT day, day - (ROW_NUMBER() OVER (ORDER BY day))::int AS run
+			FROM days
+		)
+		SELECT MIN(day) AS start_day, MAX(day) AS end_day, COUNT(*) AS days
+		FROM runs
+		GROUP BY run
+		ORDER BY end_day DESC`, userID).
+		Scan(&rows).Error
+	return rows, err
+}
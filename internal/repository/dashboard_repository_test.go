@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/testdb"
+)
+
+func TestAverageByQuestionTypeUsesAnsweredRevision(t *testing.T) {
+	db := testdb.Open(t, &model.Test{}, &model.Question{}, &model.QuestionRevision{}, &model.TestAttempt{}, &model.Answer{})
+	userID := uint(3)
+	// The question was an email worth 4 when answered and was later turned into an essay worth 5.
+	question := model.Question{TestID: 1, Title: "Q", Prompt: "Write.", Type: "opinion_essay", OrderInTest: 6, MaxScore: 5}
+	if err := db.Create(&question).Error; err != nil {
+		t.Fatal(err)
+	}
+	answered := model.QuestionRevision{QuestionID: question.ID, Revision: 1, Title: "Q", Prompt: "Write.", Type: "email_response", MaxScore: 4}
+	if err := db.Create(&answered).Error; err != nil {
+		t.Fatal(err)
+	}
+	score := 3.0
+	attempt := model.TestAttempt{TestID: 1, UserID: &userID, Status: "completed", Answers: []model.Answer{
+		{QuestionID: question.ID, QuestionRevisionID: &answered.ID, UserAnswer: "Dear ...", ScoringStatus: "scored", AIScore: &score},
+		{QuestionID: question.ID, UserAnswer: "Dear ...", ScoringStatus: "scored", AIScore: &score}, // No revision: current content
+	}}
+	if err := db.Create(&attempt).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := NewDashboardRepository(db).AverageByQuestionType(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]QuestionTypeAverageRow{
+		"email_response": {Type: "email_response", Answers: 1, AverageScore: 3, AverageMax: 4, AveragePercent: 75},
+		"opinion_essay":  {Type: "opinion_essay", Answers: 1, AverageScore: 3, AverageMax: 5, AveragePercent: 60},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v, want %+v", rows, want)
+	}
+	for _, row := range rows {
+		if row != want[row.Type] {
+			t.Errorf("row = %+v, want %+v", row, want[row.Type])
+		}
+	}
+}
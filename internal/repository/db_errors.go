@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// pgUniqueViolation is the Postgres SQLSTATE of a unique constraint or index violation.
+const pgUniqueViolation = "23505"
+
+// IsUniqueViolation reports whether err was caused by a unique index, e.g. when two requests
+// pass the same existence check and then insert the same key.
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgUniqueViolation
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// versionInsertAttempts bounds retryOnUniqueViolation. Versions are assigned as MAX(version)+1,
+// so a retry is only needed when another insert took the same number in between.
+const versionInsertAttempts = 3
+
+// retryOnUniqueViolation runs fn, which must be a complete transaction, again when it fails on a
+// unique index. Postgres aborts a transaction on the first error, so the retry cannot happen inside it.
+func retryOnUniqueViolation(attempts int, fn func() error) error {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); !IsUniqueViolation(err) {
+			return err
+		}
+	}
+	return err
+}
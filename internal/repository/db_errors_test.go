@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestRetryOnUniqueViolation(t *testing.T) {
+	errOther := errors.New("connection reset")
+	tests := []struct {
+		name      string
+		errs      []error // Result of each call; calls past the end succeed
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "first try", wantCalls: 1},
+		{name: "retried after a duplicate", errs: []error{gorm.ErrDuplicatedKey}, wantCalls: 2},
+		{name: "wrapped duplicate", errs: []error{fmt.Errorf("insert: %w", gorm.ErrDuplicatedKey)}, wantCalls: 2},
+		{name: "other errors are not retried", errs: []error{errOther}, wantCalls: 1, wantErr: errOther},
+		{name: "gives up", errs: []error{gorm.ErrDuplicatedKey, gorm.ErrDuplicatedKey, gorm.ErrDuplicatedKey}, wantCalls: 3, wantErr: gorm.ErrDuplicatedKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retryOnUniqueViolation(3, func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if calls != tt.wantCalls || !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("calls = %d, error = %v; want %d calls and %v", calls, err, tt.wantCalls, tt.wantErr)
+			}
+		})
+	}
+}
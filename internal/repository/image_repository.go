@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImageUsage is a question or bank question that shows an image.
+type ImageUsage struct {
+	ImageURL       string
+	TestID         *uint // Set for test questions
+	TestTitle      string
+	QuestionID     *uint
+	OrderInTest    int
+	BankQuestionID *uint // Set for bank questions
+}
+
+type ImageRepository interface {
+	// Create stores the image record unless one with the same hash exists; the stored record is loaded into image.
+	Create(image *model.Image) error
+	FindByHash(hash string) (*model.Image, error)
+
+	FindSourceByURL(url string) (*model.ImageSource, error)
+	FindSourcesByURLs(urls []string) ([]model.ImageSource, error)
+	RecordSourceOK(url string, hash string, checkedAt time.Time) error
+	// RecordSourceBroken marks the URL broken, keeping the hash of the last copy that passed.
+	RecordSourceBroken(url string, reason string, checkedAt time.Time) error
+	// FindImageUsages lists the images of the questions of non-deleted tests and of the question bank.
+	FindImageUsages() ([]ImageUsage, error)
+	// IsQuestionImage reports whether a test question, one of its revisions or a bank question,
+	// deleted or not, shows the image at url.
+	IsQuestionImage(url string) (bool, error)
+}
+
+type imageRepository struct {
+	db *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepository{db: db}
+}
+
+func (r *imageRepository) Create(image *model.Image) error {
+	if err := r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "hash"}}, DoNothing: true}).Create(image).Error; err != nil {
+		return err
+	}
+	return r.db.Where("hash = ?", image.Hash).First(image).Error
+}
+
+func (r *imageRepository) FindByHash(hash string) (*model.Image, error) {
+	var image model.Image
+	err := r.db.Where("hash = ?", hash).First(&image).Error
+	return &image, err
+}
+
+func (r *imageRepository) FindSourceByURL(url string) (*model.ImageSource, error) {
+	var source model.ImageSource
+	err := r.db.Where("url = ?", url).First(&source).Error
+	return &source, err
+}
+
+func (r *imageRepository) FindSourcesByURLs(urls []string) ([]model.ImageSource, error) {
+	var sources []model.ImageSource
+	if len(urls) == 0 {
+		return sources, nil
+	}
+	err := r.db.Where("url IN ?", urls).Find(&sources).Error
+	return sources, err
+}
+
+func (r *imageRepository) RecordSourceOK(url string, hash string, checkedAt time.Time) error {
+	source := model.ImageSource{URL: url, Status: model.ImageSourceOK, ImageHash: &hash, CheckedAt: checkedAt, LastOKAt: &checkedAt}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "error", "image_hash", "checked_at", "last_ok_at"}),
+	}).Create(&source).Error
+}
+
+func (r *imageRepository) RecordSourceBroken(url string, reason string, checkedAt time.Time) error {
+	source := model.ImageSource{URL: url, Status: model.ImageSourceBroken, Error: reason, CheckedAt: checkedAt}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "error", "checked_at"}),
+	}).Create(&source).Error
+}
+
+func (r *imageRepository) FindImageUsages() ([]ImageUsage, error) {
+	var usages []ImageUsage
+	err := r.db.Raw(`
+		SELECT q.image_url, t.id AS test_id, t.title AS test_title, q.id AS question_id, q.order_in_test, NULL AS bank_question_id
+		FROM questions q JOIN tests t ON t.id = q.test_id
+		WHERE q.image_url IS NOT NULL AND q.image_url <> '' AND q.deleted_at IS NULL AND t.deleted_at IS NULL
+		UNION ALL
+		SELECT b.image_url, NULL, '', NULL, 0, b.id
+		FROM bank_questions b
+		WHERE b.image_url IS NOT NULL AND b.image_url <> '' AND b.deleted_at IS NULL
+		ORDER BY image_url, test_id, order_in_test, bank_question_id`).Scan(&usages).Error
+	return usages, err
+}
+
+func (r *imageRepository) IsQuestionImage(url string) (bool, error) {
+	var used bool
+	err := r.db.Raw(`SELECT EXISTS (SELECT 1 FROM questions WHERE image_url = ?)
+		OR EXISTS (SELECT 1 FROM question_revisions WHERE image_url = ?)
+		OR EXISTS (SELECT 1 FROM bank_questions WHERE image_url = ?)`, url, url, url).Scan(&used).Error
+	return used, err
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+)
+
+// LLMCallLogFilter narrows FindAll. Zero values mean "no filter".
+type LLMCallLogFilter struct {
+	AnswerID         uint
+	PracticeAnswerID uint
+	ErrorsOnly       bool
+	Limit            int
+	Offset           int
+}
+
+type LLMCallLogRepository interface {
+	Create(call *model.LLMCallLog) error
+	FindAll(filter LLMCallLogFilter) ([]model.LLMCallLog, int64, error)
+	FindByID(id uint) (*model.LLMCallLog, error)
+	// DeleteCreatedBefore deletes the calls made before cutoff and returns how many were deleted.
+	DeleteCreatedBefore(cutoff time.Time) (int64, error)
+}
+
+type llmCallLogRepository struct {
+	db *gorm.DB
+}
+
+func NewLLMCallLogRepository(db *gorm.DB) LLMCallLogRepository {
+	return &llmCallLogRepository{db: db}
+}
+
+func (r *llmCallLogRepository) Create(call *model.LLMCallLog) error {
+	return r.db.Create(call).Error
+}
+
+// FindAll returns one page of matching calls, newest first, together with the total number of matches.
+// The prompt and raw response are not loaded; FindByID returns them.
+func (r *llmCallLogRepository) FindAll(filter LLMCallLogFilter) ([]model.LLMCallLog, int64, error) {
+	query := r.db.Model(&model.LLMCallLog{})
+	if filter.AnswerID != 0 {
+		query = query.Where("answer_id = ?", filter.AnswerID)
+	}
+	if filter.PracticeAnswerID != 0 {
+		query = query.Where("practice_answer_id = ?", filter.PracticeAnswerID)
+	}
+	if filter.ErrorsOnly {
+		query = query.Where("error <> ''")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var calls []model.LLMCallLog
+	query = query.Omit("prompt", "raw_response").Order("created_at DESC, id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+	err := query.Find(&calls).Error
+	return calls, total, err
+}
+
+func (r *llmCallLogRepository) FindByID(id uint) (*model.LLMCallLog, error) {
+	var call model.LLMCallLog
+	if err := r.db.First(&call, id).Error; err != nil {
+		return nil, err
+	}
+	return &call, nil
+}
+
+func (r *llmCallLogRepository) DeleteCreatedBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&model.LLMCallLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+)
+
+type PracticeAnswerRepository interface {
+	Create(answer *model.PracticeAnswer) error
+	Update(answer *model.PracticeAnswer) error
+	FindByID(id uint) (*model.PracticeAnswer, error)
+	// FindAllByUser lists a user's practice answers, newest first, optionally filtered by question type.
+	FindAllByUser(userID uint, questionType string) ([]model.PracticeAnswer, error)
+}
+
+type practiceAnswerRepository struct {
+	db *gorm.DB
+}
+
+func NewPracticeAnswerRepository(db *gorm.DB) PracticeAnswerRepository {
+	return &practiceAnswerRepository{db: db}
+}
+
+func (r *practiceAnswerRepository) Create(answer *model.PracticeAnswer) error {
+	return r.db.Create(answer).Error
+}
+
+func (r *practiceAnswerRepository) Update(answer *model.PracticeAnswer) error {
+	return r.db.Save(answer).Error
+}
+
+func (r *practiceAnswerRepository) FindByID(id uint) (*model.PracticeAnswer, error) {
+	var answer model.PracticeAnswer
+	err := r.db.First(&answer, id).Error
+	return &answer, err
+}
+
+func (r *practiceAnswerRepository) FindAllByUser(userID uint, questionType string) ([]model.PracticeAnswer, error) {
+	var answers []model.PracticeAnswer
+	query := r.db.Where("user_id = ?", userID)
+	if questionType != "" {
+		query = query.Where("type = ?", questionType)
+	}
+	err := query.Order("created_at DESC").Find(&answers).Error
+	return answers, err
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+)
+
+type PromptTemplateRepository interface {
+	// Create stores the template as the next version of its question type and, if activate is set,
+	// makes it the only active version of that type.
+	Create(template *model.PromptTemplate, activate bool) error
+	FindAll(questionType string) ([]model.PromptTemplate, error)
+	FindByVersion(questionType string, version int) (*model.PromptTemplate, error)
+	FindActive(questionType string) ([]model.PromptTemplate, error)
+	// SetTraffic makes the given versions, with their weights, the only active versions of the question type.
+	SetTraffic(questionType string, weights map[int]int) error
+	Count(questionType string) (int64, error)
+}
+
+type promptTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewPromptTemplateRepository(db *gorm.DB) PromptTemplateRepository {
+	return &promptTemplateRepository{db: db}
+}
+
+// Create retries when a concurrent upload took the same version number, as ScoreConversionTableRepository does.
+func (r *promptTemplateRepository) Create(template *model.PromptTemplate, activate bool) error {
+	return retryOnUniqueViolation(versionInsertAttempts, func() error {
+		return r.createNextVersion(template, activate)
+	})
+}
+
+func (r *promptTemplateRepository) createNextVersion(template *model.PromptTemplate, activate bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&model.PromptTemplate{}).Where("question_type = ?", template.QuestionType).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		template.ID = 0 // Left over from an attempt that was rolled back
+		template.Version = latest + 1
+		template.Active = false
+		template.Weight = 0
+		if err := tx.Create(template).Error; err != nil {
+			return err
+		}
+		if !activate {
+			return nil
+		}
+		if err := setTrafficIn(tx, template.QuestionType, map[int]int{template.Version: 100}); err != nil {
+			return err
+		}
+		now := time.Now()
+		template.Active = true
+		template.Weight = 100
+		template.ActivatedAt = &now
+		return nil
+	})
+}
+
+func (r *promptTemplateRepository) FindAll(questionType string) ([]model.PromptTemplate, error) {
+	var templates []model.PromptTemplate
+	query := r.db.Order("question_type, version DESC")
+	if questionType != "" {
+		query = query.Where("question_type = ?", questionType)
+	}
+	err := query.Find(&templates).Error
+	return templates, err
+}
+
+func (r *promptTemplateRepository) FindByVersion(questionType string, version int) (*model.PromptTemplate, error) {
+	var template model.PromptTemplate
+	err := r.db.Where("question_type = ? AND version = ?", questionType, version).First(&template).Error
+	return &template, err
+}
+
+func (r *promptTemplateRepository) FindActive(questionType string) ([]model.PromptTemplate, error) {
+	var templates []model.PromptTemplate
+	err := r.db.Where("question_type = ? AND active = ?", questionType, true).Order("version").Find(&templates).Error
+	return templates, err
+}
+
+// SetTraffic returns gorm.ErrRecordNotFound if one of the versions does not exist.
+func (r *promptTemplateRepository) SetTraffic(questionType string, weights map[int]int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return setTrafficIn(tx, questionType, weights)
+	})
+}
+
+func (r *promptTemplateRepository) Count(questionType string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.PromptTemplate{}).Where("question_type = ?", questionType).Count(&count).Error
+	return count, err
+}
+
+func setTrafficIn(tx *gorm.DB, questionType string, weights map[int]int) error {
+	if err := tx.Model(&model.PromptTemplate{}).Where("question_type = ? AND active = ?", questionType, true).
+		Updates(map[string]interface{}{"active": false, "weight": 0}).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for version, weight := range weights {
+		result := tx.Model(&model.PromptTemplate{}).Where("question_type = ? AND version = ?", questionType, version).
+			Updates(map[string]interface{}{"active": true, "weight": weight, "activated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+	}
+	return nil
+}
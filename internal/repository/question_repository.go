@@ -8,9 +8,12 @@ import (
 type QuestionRepository interface {
 	FindByID(id uint) (*model.Question, error)
 	FindByTestID(testID uint) ([]model.Question, error)
-	// Create, Update, Delete for individual Questions are generally handled
-	// via the TestRepository due to the strong association (cascade on delete).
-	// If standalone question management is needed, add methods here.
+	// UpdateWithRevision saves the question and records its new content as the next revision.
+	UpdateWithRevision(question *model.Question, editedByID *uint) (*model.QuestionRevision, error)
+	FindRevisions(questionID uint) ([]model.QuestionRevision, error)
+	FindRevision(questionID uint, revision int) (*model.QuestionRevision, error)
+	// Create and Delete for individual Questions are handled via the TestRepository
+	// because a test always has exactly 8 questions.
 }
 
 type questionRepository struct {
@@ -32,3 +35,35 @@ func (r *questionRepository) FindByTestID(testID uint) ([]model.Question, error)
 	err := r.db.Where("test_id = ?", testID).Order("order_in_test ASC").Find(&questions).Error
 	return questions, err
 }
+
+func (r *questionRepository) UpdateWithRevision(question *model.Question, editedByID *uint) (*model.QuestionRevision, error) {
+	var revision model.QuestionRevision
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&model.QuestionRevision{}).
+			Where("question_id = ?", question.ID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		revision = model.NewQuestionRevision(question, latest+1, editedByID)
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		question.CurrentRevisionID = &revision.ID
+		return tx.Save(question).Error
+	})
+	return &revision, err
+}
+
+func (r *questionRepository) FindRevisions(questionID uint) ([]model.QuestionRevision, error) {
+	var revisions []model.QuestionRevision
+	err := r.db.Where("question_id = ?", questionID).Order("revision ASC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *questionRepository) FindRevision(questionID uint, revision int) (*model.QuestionRevision, error) {
+	var rev model.QuestionRevision
+	err := r.db.Where("question_id = ? AND revision = ?", questionID, revision).First(&rev).Error
+	return &rev, err
+}
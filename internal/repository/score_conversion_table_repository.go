@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+)
+
+type ScoreConversionTableRepository interface {
+	// Create stores the table as the next version and, if activate is set, makes it the active table.
+	Create(table *model.ScoreConversionTable, activate bool) error
+	FindAll() ([]model.ScoreConversionTable, error)
+	FindByVersion(version int) (*model.ScoreConversionTable, error)
+	FindActive() (*model.ScoreConversionTable, error)
+	Activate(version int) error
+	Count() (int64, error)
+}
+
+type scoreConversionTableRepository struct {
+	db *gorm.DB
+}
+
+func NewScoreConversionTableRepository(db *gorm.DB) ScoreConversionTableRepository {
+	return &scoreConversionTableRepository{db: db}
+}
+
+// Create numbers the table after the highest stored version. Two concurrent uploads can read the same
+// maximum; the unique index on version rejects the second insert, which is then retried with a new number.
+func (r *scoreConversionTableRepository) Create(table *model.ScoreConversionTable, activate bool) error {
+	return retryOnUniqueViolation(versionInsertAttempts, func() error {
+		return r.createNextVersion(table, activate)
+	})
+}
+
+func (r *scoreConversionTableRepository) createNextVersion(table *model.ScoreConversionTable, activate bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&model.ScoreConversionTable{}).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		table.ID = 0 // Left over from an attempt that was rolled back
+		table.Version = latest + 1
+		table.Active = false
+		if err := tx.Create(table).Error; err != nil {
+			return err
+		}
+		if !activate {
+			return nil
+		}
+		if err := activateIn(tx, table.Version); err != nil {
+			return err
+		}
+		now := time.Now()
+		table.Active = true
+		table.ActivatedAt = &now
+		return nil
+	})
+}
+
+func (r *scoreConversionTableRepository) FindAll() ([]model.ScoreConversionTable, error) {
+	var tables []model.ScoreConversionTable
+	err := r.db.Order("version DESC").Find(&tables).Error
+	return tables, err
+}
+
+func (r *scoreConversionTableRepository) FindByVersion(version int) (*model.ScoreConversionTable, error) {
+	var table model.ScoreConversionTable
+	err := r.db.Where("version = ?", version).First(&table).Error
+	return &table, err
+}
+
+func (r *scoreConversionTableRepository) FindActive() (*model.ScoreConversionTable, error) {
+	var table model.ScoreConversionTable
+	err := r.db.Where("active = ?", true).Order("activated_at DESC").First(&table).Error
+	return &table, err
+}
+
+// Activate makes the given version the only active table. Returns gorm.ErrRecordNotFound for an unknown version.
+func (r *scoreConversionTableRepository) Activate(version int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return activateIn(tx, version)
+	})
+}
+
+func (r *scoreConversionTableRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ScoreConversionTable{}).Count(&count).Error
+	return count, err
+}
+
+func activateIn(tx *gorm.DB, version int) error {
+	if err := tx.Model(&model.ScoreConversionTable{}).Where("active = ? AND version <> ?", true, version).Update("active", false).Error; err != nil {
+		return err
+	}
+	result := tx.Model(&model.ScoreConversionTable{}).Where("version = ?", version).Updates(map[string]interface{}{
+		"active":       true,
+		"activated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
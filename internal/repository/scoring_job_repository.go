@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrLeaseLost is returned when a worker updates a job it no longer holds,
+// typically because the reaper requeued it and another worker claimed it.
+var ErrLeaseLost = errors.New("scoring job is no longer held by this worker")
+
+type ScoringJobRepository interface {
+	ClaimNext(workerID string) (*model.ScoringJob, error)
+	Heartbeat(id uint, workerID string) error
+	MarkDone(id uint, workerID string) error
+	MarkRetry(id uint, workerID string, lastError string, runAfter time.Time) error
+	MarkFailed(id uint, workerID string, lastError string) error
+	Defer(id uint, workerID string, reason string, runAfter time.Time) error
+	RequeueStale(lockedBefore time.Time) (int64, error)
+}
+
+type scoringJobRepository struct {
+	db *gorm.DB
+}
+
+func NewScoringJobRepository(db *gorm.DB) ScoringJobRepository {
+	return &scoringJobRepository{db: db}
+}
+
+// ClaimNext atomically picks the oldest runnable job and marks it running.
+// SKIP LOCKED lets several workers (or several app instances) poll the same table without blocking each other.
+// Returns nil, nil when there is nothing to do.
+func (r *scoringJobRepository) ClaimNext(workerID string) (*model.ScoringJob, error) {
+	var job model.ScoringJob
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_after <= ?", model.ScoringJobQueued, time.Now()).
+			Order("run_after ASC, id ASC").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		job.Status = model.ScoringJobRunning
+		job.Attempts++
+		job.LockedAt = &now
+		job.LockedBy = workerID
+		return tx.Save(&job).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Heartbeat refreshes locked_at so the reaper does not mistake a long-running job for an orphaned one.
+func (r *scoringJobRepository) Heartbeat(id uint, workerID string) error {
+	return r.updateHeld(id, workerID, map[string]interface{}{
+		"locked_at": time.Now(),
+	})
+}
+
+func (r *scoringJobRepository) MarkDone(id uint, workerID string) error {
+	now := time.Now()
+	return r.updateHeld(id, workerID, map[string]interface{}{
+		"status":       model.ScoringJobDone,
+		"last_error":   "",
+		"locked_at":    nil,
+		"locked_by":    "",
+		"completed_at": &now,
+	})
+}
+
+// MarkRetry puts a job back in the queue to be picked up again no earlier than runAfter.
+func (r *scoringJobRepository) MarkRetry(id uint, workerID string, lastError string, runAfter time.Time) error {
+	return r.updateHeld(id, workerID, map[string]interface{}{
+		"status":     model.ScoringJobQueued,
+		"last_error": lastError,
+		"run_after":  runAfter,
+		"locked_at":  nil,
+		"locked_by":  "",
+	})
+}
+
+// Defer puts a job back in the queue without counting the run against its attempts,
+// for runs that could not make progress because the AI provider is down.
+func (r *scoringJobRepository) Defer(id uint, workerID string, reason string, runAfter time.Time) error {
+	return r.updateHeld(id, workerID, map[string]interface{}{
+		"status":     model.ScoringJobQueued,
+		"attempts":   gorm.Expr("GREATEST(attempts - 1, 0)"),
+		"last_error": reason,
+		"run_after":  runAfter,
+		"locked_at":  nil,
+		"locked_by":  "",
+	})
+}
+
+func (r *scoringJobRepository) MarkFailed(id uint, workerID string, lastError string) error {
+	now := time.Now()
+	return r.updateHeld(id, workerID, map[string]interface{}{
+		"status":       model.ScoringJobFailed,
+		"last_error":   lastError,
+		"locked_at":    nil,
+		"locked_by":    "",
+		"completed_at": &now,
+	})
+}
+
+// updateHeld applies updates only while workerID still holds the running job.
+func (r *scoringJobRepository) updateHeld(id uint, workerID string, updates map[string]interface{}) error {
+	result := r.db.Model(&model.ScoringJob{}).
+		Where("id = ? AND status = ? AND locked_by = ?", id, model.ScoringJobRunning, workerID).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// RequeueStale returns jobs whose worker died mid-run (e.g. the process was killed) to the queue.
+func (r *scoringJobRepository) RequeueStale(lockedBefore time.Time) (int64, error) {
+	result := r.db.Model(&model.ScoringJob{}).
+		Where("status = ? AND locked_at < ?", model.ScoringJobRunning, lockedBefore).
+		Updates(map[string]interface{}{
+			"status":    model.ScoringJobQueued,
+			"run_after": time.Now(),
+			"locked_at": nil,
+			"locked_by": "",
+		})
+	return result.RowsAffected, result.Error
+}
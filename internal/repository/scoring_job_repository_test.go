@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/testdb"
+)
+
+func TestScoringJobLease(t *testing.T) {
+	db := testdb.Open(t, &model.ScoringJob{})
+	repo := NewScoringJobRepository(db)
+	job := model.ScoringJob{TestAttemptID: 1, Status: model.ScoringJobQueued, RunAfter: time.Now().Add(-time.Second)}
+	if err := db.Create(&job).Error; err != nil {
+		t.Fatal(err)
+	}
+	load := func() model.ScoringJob {
+		t.Helper()
+		var stored model.ScoringJob
+		if err := db.First(&stored, job.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		return stored
+	}
+
+	claimed, err := repo.ClaimNext("worker-a")
+	if err != nil || claimed == nil || claimed.ID != job.ID || claimed.Attempts != 1 {
+		t.Fatalf("ClaimNext = %+v, %v; want the queued job on its first attempt", claimed, err)
+	}
+	if next, err := repo.ClaimNext("worker-b"); err != nil || next != nil {
+		t.Fatalf("a running job was claimed again: %+v, %v", next, err)
+	}
+	if err := repo.Heartbeat(job.ID, "worker-a"); err != nil {
+		t.Fatalf("heartbeat of the holder: %v", err)
+	}
+	if err := repo.Heartbeat(job.ID, "worker-b"); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("heartbeat of another worker: error = %v, want ErrLeaseLost", err)
+	}
+
+	// The lease is fresh, so the reaper leaves the job alone.
+	if n, err := repo.RequeueStale(time.Now().Add(-time.Minute)); err != nil || n != 0 {
+		t.Fatalf("RequeueStale of a fresh lease = %d, %v; want 0", n, err)
+	}
+	// Once the heartbeats stop for longer than the stale limit, the job goes back to the queue.
+	if n, err := repo.RequeueStale(time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Fatalf("RequeueStale of an expired lease = %d, %v; want 1", n, err)
+	}
+	if stored := load(); stored.Status != model.ScoringJobQueued || stored.LockedBy != "" || stored.LockedAt != nil {
+		t.Fatalf("requeued job = status %q, locked by %q at %v", stored.Status, stored.LockedBy, stored.LockedAt)
+	}
+
+	// The first worker is still running and must not overwrite the queued job or the second worker's run.
+	if err := repo.MarkDone(job.ID, "worker-a"); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("MarkDone after the lease was lost: error = %v, want ErrLeaseLost", err)
+	}
+	if reclaimed, err := repo.ClaimNext("worker-b"); err != nil || reclaimed == nil || reclaimed.Attempts != 2 {
+		t.Fatalf("ClaimNext after requeue = %+v, %v; want the job on its second attempt", reclaimed, err)
+	}
+	stale := []struct {
+		name   string
+		update func() error
+	}{
+		{name: "Heartbeat", update: func() error { return repo.Heartbeat(job.ID, "worker-a") }},
+		{name: "MarkDone", update: func() error { return repo.MarkDone(job.ID, "worker-a") }},
+		{name: "MarkRetry", update: func() error { return repo.MarkRetry(job.ID, "worker-a", "boom", time.Now()) }},
+		{name: "MarkFailed", update: func() error { return repo.MarkFailed(job.ID, "worker-a", "boom") }},
+	}
+	for _, tt := range stale {
+		if err := tt.update(); !errors.Is(err, ErrLeaseLost) {
+			t.Errorf("%s by the former holder: error = %v, want ErrLeaseLost", tt.name, err)
+		}
+	}
+	if stored := load(); stored.Status != model.ScoringJobRunning || stored.LockedBy != "worker-b" {
+		t.Fatalf("the former holder changed the job: status %q, locked by %q", stored.Status, stored.LockedBy)
+	}
+
+	if err := repo.MarkDone(job.ID, "worker-b"); err != nil {
+		t.Fatalf("MarkDone by the holder: %v", err)
+	}
+	if stored := load(); stored.Status != model.ScoringJobDone || stored.CompletedAt == nil || stored.LockedBy != "" {
+		t.Fatalf("finished job = status %q, completed at %v, locked by %q", stored.Status, stored.CompletedAt, stored.LockedBy)
+	}
+	if err := repo.MarkFailed(job.ID, "worker-b", "late error"); !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("MarkFailed of a finished job: error = %v, want ErrLeaseLost", err)
+	}
+}
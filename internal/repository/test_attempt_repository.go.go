@@ -1,10 +1,27 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/lshigami/Ringtails/internal/model"
 	"gorm.io/gorm"
 )
 
+// AttemptPartScore is the subtotal of one part of an attempt, aggregated in the database.
+type AttemptPartScore struct {
+	TestAttemptID uint
+	Part          int
+	RawScore      float64
+	Answered      int
+	Scored        int
+}
+
+// testPartSQL maps questions.order_in_test to the part number, like service.testPartOf.
+const testPartSQL = "CASE WHEN q.order_in_test <= 5 THEN 1 WHEN q.order_in_test <= 7 THEN 2 ELSE 3 END"
+
+// finalScoreSQL is the score an answer "a" adds to its attempt, like model.Answer.FinalScore.
+const finalScoreSQL = "CASE WHEN a.human_score IS NOT NULL THEN a.human_score WHEN a.scoring_status = 'scored' THEN a.ai_score END"
+
 type TestAttemptRepository interface {
 	Create(attempt *model.TestAttempt) error
 	Update(attempt *model.TestAttempt) error
@@ -12,6 +29,13 @@ type TestAttemptRepository interface {
 	FindByIDWithDetails(id uint) (*model.TestAttempt, error)
 	FindAllByTestAndUser(testID uint, userID *uint) ([]model.TestAttempt, error)
 	FindLatestByTestAndUser(testID uint, userID uint) (*model.TestAttempt, error)
+	UpdateStatus(id uint, status string) error
+	UpdateScoresAndStatus(id uint, totalScore *float64, scaledScore *float64, scoreTableVersion *int, status string) error
+	FindInProgressByTestAndUser(testID uint, userID uint) (*model.TestAttempt, error)
+	// FindExpiredInProgress lists timed attempts still in progress whose last deadline is before deadline.
+	FindExpiredInProgress(deadline time.Time, limit int) ([]model.TestAttempt, error)
+	FindIDsByTestAndStatuses(testID uint, statuses []string) ([]uint, error)
+	SumScoresByPart(attemptIDs []uint) ([]AttemptPartScore, error)
 }
 
 type testAttemptRepository struct {
@@ -44,8 +68,15 @@ func (r *testAttemptRepository) FindByID(id uint) (*model.TestAttempt, error) {
 func (r *testAttemptRepository) FindByIDWithDetails(id uint) (*model.TestAttempt, error) {
 	var attempt model.TestAttempt
 	err := r.db.
-		Preload("Test").             // Preload the Test details
+		Preload("Test"). // Preload the Test details
+		Preload("Test.Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("questions.order_in_test ASC")
+		}).
 		Preload("Answers.Question"). // Preload Answers and their associated Questions
+		Preload("Answers.QuestionRevision").
+		Preload("Answers.CriterionScores", func(db *gorm.DB) *gorm.DB {
+			return db.Order("answer_criterion_scores.id ASC") // Keep the rubric order the model returned
+		}).
 		First(&attempt, id).Error
 	return &attempt, err
 }
@@ -86,3 +117,67 @@ func (r *testAttemptRepository) FindLatestByTestAndUser(testID uint, userID uint
 	}
 	return &attempt, nil
 }
+
+// UpdateStatus changes only the status column, leaving answers and scores untouched.
+func (r *testAttemptRepository) UpdateStatus(id uint, status string) error {
+	return r.db.Model(&model.TestAttempt{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func (r *testAttemptRepository) UpdateScoresAndStatus(id uint, totalScore *float64, scaledScore *float64, scoreTableVersion *int, status string) error {
+	return r.db.Model(&model.TestAttempt{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"total_score":         totalScore,
+		"scaled_score":        scaledScore,
+		"score_table_version": scoreTableVersion,
+		"status":              status,
+	}).Error
+}
+
+// FindInProgressByTestAndUser returns the user's in-progress attempt for the test, or nil if there is none.
+func (r *testAttemptRepository) FindInProgressByTestAndUser(testID uint, userID uint) (*model.TestAttempt, error) {
+	var attempt model.TestAttempt
+	err := r.db.Where("test_id = ? AND user_id = ? AND status = ?", testID, userID, "in_progress").
+		Order("created_at DESC").
+		First(&attempt).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *testAttemptRepository) FindExpiredInProgress(deadline time.Time, limit int) ([]model.TestAttempt, error) {
+	var attempts []model.TestAttempt
+	err := r.db.Where("status = ? AND part3_deadline_at IS NOT NULL AND part3_deadline_at < ?", "in_progress", deadline).
+		Order("part3_deadline_at ASC").
+		Limit(limit).
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *testAttemptRepository) FindIDsByTestAndStatuses(testID uint, statuses []string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.TestAttempt{}).
+		Where("test_id = ? AND status IN ?", testID, statuses).
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// SumScoresByPart returns one row per attempt and part that has answers. Only answers with a final score add to RawScore.
+func (r *testAttemptRepository) SumScoresByPart(attemptIDs []uint) ([]AttemptPartScore, error) {
+	var rows []AttemptPartScore
+	if len(attemptIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.Table("answers AS a").
+		Select("a.test_attempt_id, "+testPartSQL+" AS part, "+
+			"COALESCE(SUM("+finalScoreSQL+"), 0) AS raw_score, "+
+			"COUNT(*) AS answered, COUNT("+finalScoreSQL+") AS scored").
+		Joins("JOIN questions AS q ON q.id = a.question_id").
+		Where("a.test_attempt_id IN ? AND a.deleted_at IS NULL", attemptIDs).
+		Group("a.test_attempt_id, part").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -1,20 +1,37 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/lshigami/Ringtails/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// TestListFilter narrows FindAllWithQuestionCount. The zero value lists every non-deleted test.
+type TestListFilter struct {
+	Status         string // "draft" or "published"; empty means any status
+	IncludeDeleted bool   // Include soft-deleted tests (admin views only)
+}
+
+// TestWithQuestionCount is a Test row together with the number of its (non-deleted) questions.
+type TestWithQuestionCount struct {
+	model.Test
+	QuestionCount int
+}
+
 type TestRepository interface {
 	Create(test *model.Test) error
+	CreateAll(tests []*model.Test) error
 	FindByID(id uint) (*model.Test, error)
 	FindByIDWithQuestions(id uint) (*model.Test, error)
-	FindAllWithQuestionCount() ([]struct {
-		model.Test
-		QuestionCount int
-	}, error)
-	// Update(test *model.Test) error // Add if admin needs to update test metadata
-	// Delete(id uint) error         // Add if admin needs to delete tests
+	FindByIDUnscopedWithQuestions(id uint) (*model.Test, error)
+	FindAllWithQuestionCount(filter TestListFilter) ([]TestWithQuestionCount, error)
+	ExistsByTitle(title string, excludeID uint) (bool, error)
+	Update(test *model.Test) error
+	UpdateStatus(id uint, status string, publishedAt *time.Time) error
+	Delete(id uint) error
+	Restore(id uint) error
 }
 
 type testRepository struct {
@@ -26,9 +43,42 @@ func NewTestRepository(db *gorm.DB) TestRepository {
 }
 
 func (r *testRepository) Create(test *model.Test) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return createTestIn(tx, test)
+	})
+}
+
+// CreateAll creates the tests in one transaction: either all of them are stored or none is.
+func (r *testRepository) CreateAll(tests []*model.Test) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, test := range tests {
+			if err := createTestIn(tx, test); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func createTestIn(tx *gorm.DB, test *model.Test) error {
 	// GORM's Create with associations will handle creating questions if test.Questions is populated
 	// and Question model has TestID foreign key, and Test model has Questions []Question `gorm:"foreignKey:TestID"`
-	return r.db.Create(test).Error
+	if err := tx.Create(test).Error; err != nil {
+		return err
+	}
+	// Every question starts with revision 1 so answers can always be pinned to a revision.
+	for i := range test.Questions {
+		q := &test.Questions[i]
+		revision := model.NewQuestionRevision(q, 1, nil)
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		q.CurrentRevisionID = &revision.ID
+		if err := tx.Model(q).Update("current_revision_id", revision.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *testRepository) FindByID(id uint) (*model.Test, error) {
@@ -45,18 +95,74 @@ func (r *testRepository) FindByIDWithQuestions(id uint) (*model.Test, error) {
 	return &test, err
 }
 
-func (r *testRepository) FindAllWithQuestionCount() ([]struct {
-	model.Test
-	QuestionCount int
-}, error) {
-	var results []struct {
-		model.Test
-		QuestionCount int
-	}
-	err := r.db.Model(&model.Test{}).
+// FindByIDUnscopedWithQuestions also finds soft-deleted tests, for admin views and restore.
+func (r *testRepository) FindByIDUnscopedWithQuestions(id uint) (*model.Test, error) {
+	var test model.Test
+	err := r.db.Unscoped().Preload("Questions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("questions.order_in_test ASC")
+	}).First(&test, id).Error
+	return &test, err
+}
+
+func (r *testRepository) FindAllWithQuestionCount(filter TestListFilter) ([]TestWithQuestionCount, error) {
+	var results []TestWithQuestionCount
+	query := r.db.Model(&model.Test{}).
 		Select("tests.*, (SELECT COUNT(*) FROM questions WHERE questions.test_id = tests.id AND questions.deleted_at IS NULL) as question_count").
-		Order("tests.created_at DESC").
-		Where("tests.deleted_at IS NULL"). // Only select non-deleted tests
-		Scan(&results).Error
+		Order("tests.created_at DESC")
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	} else {
+		query = query.Where("tests.deleted_at IS NULL") // Only select non-deleted tests
+	}
+	if filter.Status != "" {
+		query = query.Where("tests.status = ?", filter.Status)
+	}
+	err := query.Scan(&results).Error
 	return results, err
 }
+
+// ExistsByTitle checks the unique title index, which also covers soft-deleted tests.
+func (r *testRepository) ExistsByTitle(title string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&model.Test{}).
+		Where("title = ? AND id <> ?", title, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Update saves the test's own columns; questions are managed through QuestionRepository.
+func (r *testRepository) Update(test *model.Test) error {
+	return r.db.Omit(clause.Associations).Save(test).Error
+}
+
+func (r *testRepository) UpdateStatus(id uint, status string, publishedAt *time.Time) error {
+	return r.db.Model(&model.Test{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       status,
+		"published_at": publishedAt,
+	}).Error
+}
+
+// Delete soft-deletes the test. Its questions are kept so past attempts still render.
+func (r *testRepository) Delete(id uint) error {
+	result := r.db.Delete(&model.Test{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *testRepository) Restore(id uint) error {
+	result := r.db.Unscoped().Model(&model.Test{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
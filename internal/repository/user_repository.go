@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrLastAdmin is returned by UpdateRole when the change would leave no admin.
+var ErrLastAdmin = errors.New("the last admin cannot lose the admin role")
+
+type UserRepository interface {
+	Create(user *model.User) error
+	FindByID(id uint) (*model.User, error)
+	FindByEmail(email string) (*model.User, error)
+	FindAll(role string) ([]model.User, error)
+	CountByRole(role string) (int64, error)
+	UpdateRole(id uint, role string) error
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) Create(user *model.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *userRepository) FindByID(id uint) (*model.User, error) {
+	var user model.User
+	err := r.db.First(&user, id).Error
+	return &user, err
+}
+
+func (r *userRepository) FindByEmail(email string) (*model.User, error) {
+	var user model.User
+	// Emails are stored lower-cased by the auth service, normalise the lookup the same way
+	err := r.db.Where("email = ?", strings.ToLower(strings.TrimSpace(email))).First(&user).Error
+	return &user, err
+}
+
+// FindAll lists users, optionally filtered by role when role is non-empty.
+func (r *userRepository) FindAll(role string) ([]model.User, error) {
+	var users []model.User
+	query := r.db.Order("created_at ASC")
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	err := query.Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) CountByRole(role string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.User{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// UpdateRole changes the user's role. It returns ErrLastAdmin instead of demoting the only admin;
+// the admins are locked while checking, so two concurrent demotions cannot both pass.
+func (r *userRepository) UpdateRole(id uint, role string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if role != model.RoleAdmin {
+			var adminIDs []uint
+			err := tx.Model(&model.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("role = ?", model.RoleAdmin).Pluck("id", &adminIDs).Error
+			if err != nil {
+				return err
+			}
+			if len(adminIDs) == 1 && adminIDs[0] == id {
+				return ErrLastAdmin
+			}
+		}
+		result := tx.Model(&model.User{}).Where("id = ?", id).Update("role", role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
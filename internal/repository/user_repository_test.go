@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/testdb"
+	"gorm.io/gorm"
+)
+
+func TestUpdateRoleKeepsLastAdmin(t *testing.T) {
+	repo := NewUserRepository(testdb.Open(t, &model.User{}))
+	create := func(email, role string) uint {
+		t.Helper()
+		user := &model.User{Email: email, PasswordHash: "x", Role: role}
+		if err := repo.Create(user); err != nil {
+			t.Fatal(err)
+		}
+		return user.ID
+	}
+	first := create("first@example.com", model.RoleAdmin)
+	second := create("second@example.com", model.RoleAdmin)
+	learner := create("learner@example.com", model.RoleLearner)
+
+	if err := repo.UpdateRole(first, model.RoleAdmin); err != nil {
+		t.Fatalf("keeping an admin an admin: %v", err)
+	}
+	if err := repo.UpdateRole(first, model.RoleTeacher); err != nil {
+		t.Fatalf("demoting one of two admins: %v", err)
+	}
+	if err := repo.UpdateRole(second, model.RoleLearner); !errors.Is(err, ErrLastAdmin) {
+		t.Fatalf("demoting the last admin: error = %v, want ErrLastAdmin", err)
+	}
+	if user, _ := repo.FindByID(second); user.Role != model.RoleAdmin {
+		t.Fatalf("the last admin's role is %q after a refused demotion", user.Role)
+	}
+	if err := repo.UpdateRole(learner, model.RoleTeacher); err != nil {
+		t.Fatalf("changing a non-admin while one admin is left: %v", err)
+	}
+	if err := repo.UpdateRole(learner, model.RoleAdmin); err != nil {
+		t.Fatalf("promoting a second admin: %v", err)
+	}
+	if err := repo.UpdateRole(second, model.RoleLearner); err != nil {
+		t.Fatalf("demoting an admin once another exists: %v", err)
+	}
+	if err := repo.UpdateRole(999, model.RoleLearner); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("unknown user: error = %v, want gorm.ErrRecordNotFound", err)
+	}
+}
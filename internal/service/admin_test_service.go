@@ -1,7 +1,11 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jinzhu/copier"
 	"github.com/lshigami/Ringtails/internal/dto"
@@ -11,22 +15,95 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrTestTitleTaken     = errors.New("a test with this title already exists")
+	ErrQuestionNotFound   = errors.New("question not found")
+	ErrRevisionNotFound   = errors.New("question revision not found")
+	ErrTestNotPublishable = errors.New("test cannot be published")
+)
+
+// AdminTestService manages the full lifecycle of tests for teachers and admins:
+// drafts are invisible to learners until published, and deleted tests can be restored.
 type AdminTestService interface {
-	CreateTest(req dto.TestCreateDTO) (*dto.TestResponseDTO, error)
+	CreateTest(ctx context.Context, req dto.TestCreateDTO) (*dto.AdminTestDetailDTO, error)
+	CreateTestFromBank(ctx context.Context, req dto.TestFromBankCreateDTO) (*dto.AdminTestDetailDTO, error)
+	ListTests(status string, includeDeleted bool) ([]dto.AdminTestSummaryDTO, error)
+	GetTest(testID uint) (*dto.AdminTestDetailDTO, error)
+	UpdateTest(testID uint, req dto.TestUpdateDTO) (*dto.AdminTestDetailDTO, error)
+	UpdateQuestion(ctx context.Context, testID uint, questionID uint, editedByID uint, req dto.QuestionUpdateDTO) (*dto.AdminTestDetailDTO, error)
+	ListQuestionRevisions(testID uint, questionID uint) ([]dto.QuestionRevisionDTO, error)
+	DiffQuestionRevisions(testID uint, questionID uint, fromRevision int, toRevision int) (*dto.QuestionRevisionDiffDTO, error)
+	PublishTest(testID uint) (*dto.AdminTestDetailDTO, error)
+	UnpublishTest(testID uint) (*dto.AdminTestDetailDTO, error)
+	DeleteTest(testID uint) error
+	RestoreTest(testID uint) (*dto.AdminTestDetailDTO, error)
+	ImportTests(ctx context.Context, data []byte, dryRun bool) (*dto.TestImportReportDTO, error)
+	ExportTests(ctx context.Context, testIDs []uint) (*dto.TestContentFileDTO, error)
 }
 
 type adminTestService struct {
-	testRepo repository.TestRepository
-	db       *gorm.DB
+	testRepo     repository.TestRepository
+	questionRepo repository.QuestionRepository
+	bankRepo     repository.BankQuestionRepository
+	images       ImageService
+	db           *gorm.DB
+}
+
+func NewAdminTestService(
+	testRepo repository.TestRepository,
+	questionRepo repository.QuestionRepository,
+	bankRepo repository.BankQuestionRepository,
+	images ImageService,
+	db *gorm.DB,
+) AdminTestService {
+	return &adminTestService{testRepo: testRepo, questionRepo: questionRepo, bankRepo: bankRepo, images: images, db: db}
 }
 
-func NewAdminTestService(testRepo repository.TestRepository, db *gorm.DB) AdminTestService {
-	return &adminTestService{testRepo: testRepo, db: db}
+// testQuestionLayout returns the question type and max score the TOEIC Writing test requires at a position.
+func testQuestionLayout(orderInTest int) (questionType string, maxScore float64, ok bool) {
+	switch {
+	case orderInTest >= 1 && orderInTest <= 5:
+		return "sentence_picture", 3.0, true
+	case orderInTest >= 6 && orderInTest <= 7:
+		return "email_response", 4.0, true
+	case orderInTest == 8:
+		return "opinion_essay", 5.0, true
+	}
+	return "", 0, false
 }
 
-func (s *adminTestService) CreateTest(req dto.TestCreateDTO) (*dto.TestResponseDTO, error) {
+// validateQuestionContent checks the content a question needs whatever its position: a title and a prompt,
+// and for sentence_picture an image and both given words. Blank values count as missing.
+func validateQuestionContent(orderInTest int, questionType string, title string, prompt string, imageURL, givenWord1, givenWord2 *string) error {
+	if strings.TrimSpace(title) == "" || strings.TrimSpace(prompt) == "" {
+		return fmt.Errorf("question %d requires a non-empty title and prompt", orderInTest)
+	}
+	blank := func(value *string) bool { return value == nil || strings.TrimSpace(*value) == "" }
+	if questionType == "sentence_picture" && (blank(imageURL) || blank(givenWord1) || blank(givenWord2)) {
+		return fmt.Errorf("question '%s' (Order: %d) of type 'sentence_picture' requires ImageURL, GivenWord1, and GivenWord2 to be non-empty", title, orderInTest)
+	}
+	return nil
+}
+
+// maxScoreForType returns the max score of a question type, which does not depend on its position.
+func maxScoreForType(questionType string) (float64, bool) {
+	for order := 1; order <= 8; order++ {
+		if layoutType, maxScore, _ := testQuestionLayout(order); layoutType == questionType {
+			return maxScore, true
+		}
+	}
+	return 0, false
+}
+
+// validateTestCreate checks a test against the fixed TOEIC Writing layout and returns its questions.
+// Every problem found is returned, in question order, so import reports can list them all at once.
+func validateTestCreate(req dto.TestCreateDTO) ([]model.Question, []error) {
+	var problems []error
+	if strings.TrimSpace(req.Title) == "" {
+		problems = append(problems, fmt.Errorf("test title must not be empty"))
+	}
 	if len(req.Questions) != 8 {
-		return nil, fmt.Errorf("a test must have exactly 8 questions, received %d", len(req.Questions))
+		problems = append(problems, fmt.Errorf("a test must have exactly 8 questions, received %d", len(req.Questions)))
 	}
 
 	orderMap := make(map[int]bool)
@@ -34,53 +111,77 @@ func (s *adminTestService) CreateTest(req dto.TestCreateDTO) (*dto.TestResponseD
 
 	for _, qDto := range req.Questions {
 		if _, exists := orderMap[qDto.OrderInTest]; exists {
-			return nil, fmt.Errorf("duplicate OrderInTest %d found in questions", qDto.OrderInTest)
+			problems = append(problems, fmt.Errorf("duplicate OrderInTest %d found in questions", qDto.OrderInTest))
+			continue
 		}
 		orderMap[qDto.OrderInTest] = true
 
 		if qDto.OrderInTest < 1 || qDto.OrderInTest > 8 {
-			return nil, fmt.Errorf("OrderInTest must be between 1 and 8, got %d for question '%s'", qDto.OrderInTest, qDto.Title)
-		}
-
-		// Validate MaxScore based on OrderInTest and Type
-		expectedMaxScore := 0.0
-		switch {
-		case qDto.OrderInTest >= 1 && qDto.OrderInTest <= 5: // Part 1: Q1-5
-			if qDto.Type != "sentence_picture" {
-				return nil, fmt.Errorf("question %d (Order: %d) should be type 'sentence_picture'", qDto.OrderInTest, qDto.OrderInTest)
-			}
-			expectedMaxScore = 3.0
-			if qDto.ImageURL == nil || *qDto.ImageURL == "" || qDto.GivenWord1 == nil || *qDto.GivenWord1 == "" || qDto.GivenWord2 == nil || *qDto.GivenWord2 == "" {
-				return nil, fmt.Errorf("question '%s' (Order: %d) of type 'sentence_picture' requires ImageURL, GivenWord1, and GivenWord2 to be non-empty", qDto.Title, qDto.OrderInTest)
-			}
-		case qDto.OrderInTest >= 6 && qDto.OrderInTest <= 7: // Part 2: Q6-7
-			if qDto.Type != "email_response" {
-				return nil, fmt.Errorf("question %d (Order: %d) should be type 'email_response'", qDto.OrderInTest, qDto.OrderInTest)
-			}
-			expectedMaxScore = 4.0
-		case qDto.OrderInTest == 8: // Part 3: Q8
-			if qDto.Type != "opinion_essay" {
-				return nil, fmt.Errorf("question %d (Order: %d) should be type 'opinion_essay'", qDto.OrderInTest, qDto.OrderInTest)
-			}
-			expectedMaxScore = 5.0
-		default:
-			return nil, fmt.Errorf("invalid OrderInTest: %d", qDto.OrderInTest)
+			problems = append(problems, fmt.Errorf("OrderInTest must be between 1 and 8, got %d for question '%s'", qDto.OrderInTest, qDto.Title))
+			continue
+		}
+		// Validate type, required fields and MaxScore based on OrderInTest
+		expectedType, expectedMaxScore, _ := testQuestionLayout(qDto.OrderInTest)
+		if qDto.Type != expectedType {
+			problems = append(problems, fmt.Errorf("question %d (Order: %d) should be type '%s'", qDto.OrderInTest, qDto.OrderInTest, expectedType))
+			continue
+		}
+		if err := validateQuestionContent(qDto.OrderInTest, qDto.Type, qDto.Title, qDto.Prompt, qDto.ImageURL, qDto.GivenWord1, qDto.GivenWord2); err != nil {
+			problems = append(problems, err)
+			continue
 		}
-
 		if qDto.MaxScore != expectedMaxScore {
-			return nil, fmt.Errorf("MaxScore for question '%s' (Order: %d, Type: %s) should be %.1f, but got %.1f", qDto.Title, qDto.OrderInTest, qDto.Type, expectedMaxScore, qDto.MaxScore)
+			problems = append(problems, fmt.Errorf("MaxScore for question '%s' (Order: %d, Type: %s) should be %.1f, but got %.1f", qDto.Title, qDto.OrderInTest, qDto.Type, expectedMaxScore, qDto.MaxScore))
+			continue
 		}
 
 		var questionModel model.Question
 		copier.Copy(&questionModel, &qDto)
 		questionsToCreateModel = append(questionsToCreateModel, questionModel)
 	}
+	return questionsToCreateModel, problems
+}
+
+// checkQuestionImages checks that the image of every question can be shown and scored: it must be a
+// supported, decodable image within the size limit. External images are copied to storage for scoring.
+func (s *adminTestService) checkQuestionImages(ctx context.Context, questions []model.Question) error {
+	for _, question := range questions {
+		if question.ImageURL == nil || *question.ImageURL == "" {
+			continue
+		}
+		if _, err := s.images.ValidateURL(ctx, *question.ImageURL, true); err != nil {
+			return fmt.Errorf("image of question %d: %w", question.OrderInTest, err)
+		}
+	}
+	return nil
+}
+
+func (s *adminTestService) CreateTest(ctx context.Context, req dto.TestCreateDTO) (*dto.AdminTestDetailDTO, error) {
+	questionsToCreateModel, problems := validateTestCreate(req)
+	if len(problems) > 0 {
+		return nil, problems[0]
+	}
+	if err := s.checkQuestionImages(ctx, questionsToCreateModel); err != nil {
+		return nil, err
+	}
+
+	if taken, err := s.testRepo.ExistsByTitle(req.Title, 0); err != nil {
+		return nil, fmt.Errorf("error checking test title: %w", err)
+	} else if taken {
+		return nil, fmt.Errorf("%w: %q", ErrTestTitleTaken, req.Title)
+	}
 
 	testModel := model.Test{
 		Title:       req.Title,
 		Description: req.Description,
+		Status:      model.TestStatusDraft,
 		Questions:   questionsToCreateModel,
 	}
+	if req.Publish {
+		now := time.Now()
+		testModel.Status = model.TestStatusPublished
+		testModel.PublishedAt = &now
+	}
 
 	if err := s.testRepo.Create(&testModel); err != nil {
 		log.Error().Err(err).Msg("Failed to create test in database")
@@ -90,15 +191,385 @@ func (s *adminTestService) CreateTest(req dto.TestCreateDTO) (*dto.TestResponseD
 	createdTestWithDetails, err := s.testRepo.FindByIDWithQuestions(testModel.ID)
 	if err != nil {
 		log.Error().Err(err).Uint("testID", testModel.ID).Msg("Failed to retrieve newly created test with questions for response")
-		var fallbackResp dto.TestResponseDTO
-		copier.Copy(&fallbackResp, &testModel)
-		return &fallbackResp, nil
+		return toAdminTestDetailDTO(&testModel)
+	}
+	return toAdminTestDetailDTO(createdTestWithDetails)
+}
+
+// CreateTestFromBank builds a test by copying eight bank questions. Each copy records the bank
+// question ID and version, so attempts keep showing exactly the content that was answered.
+func (s *adminTestService) CreateTestFromBank(ctx context.Context, req dto.TestFromBankCreateDTO) (*dto.AdminTestDetailDTO, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("test title must not be empty")
+	}
+	if len(req.BankQuestionIDs) != 8 {
+		return nil, fmt.Errorf("a test must have exactly 8 questions, received %d", len(req.BankQuestionIDs))
+	}
+
+	bankQuestions, err := s.bankRepo.FindByIDs(req.BankQuestionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error loading bank questions: %w", err)
+	}
+	byID := make(map[uint]model.BankQuestion, len(bankQuestions))
+	for _, bq := range bankQuestions {
+		byID[bq.ID] = bq
+	}
+
+	var questions []model.Question
+	used := make(map[uint]bool, len(req.BankQuestionIDs))
+	for i, bankID := range req.BankQuestionIDs {
+		order := i + 1
+		if used[bankID] {
+			return nil, fmt.Errorf("bank question %d is used more than once", bankID)
+		}
+		used[bankID] = true
+
+		bq, ok := byID[bankID]
+		if !ok {
+			return nil, fmt.Errorf("%w with ID %d", ErrBankQuestionNotFound, bankID)
+		}
+		expectedType, maxScore, _ := testQuestionLayout(order)
+		if bq.Type != expectedType {
+			return nil, fmt.Errorf("question %d must be of type '%s', but bank question %d is '%s'", order, expectedType, bankID, bq.Type)
+		}
+
+		bankQuestionID, version := bq.ID, bq.Version
+		questions = append(questions, model.Question{
+			Title:               bq.Title,
+			Prompt:              bq.Prompt,
+			Type:                bq.Type,
+			OrderInTest:         order,
+			ImageURL:            bq.ImageURL,
+			GivenWord1:          bq.GivenWord1,
+			GivenWord2:          bq.GivenWord2,
+			MaxScore:            maxScore,
+			BankQuestionID:      &bankQuestionID,
+			BankQuestionVersion: &version,
+		})
+	}
+	if err := s.checkQuestionImages(ctx, questions); err != nil {
+		return nil, err
+	}
+
+	if taken, err := s.testRepo.ExistsByTitle(title, 0); err != nil {
+		return nil, fmt.Errorf("error checking test title: %w", err)
+	} else if taken {
+		return nil, fmt.Errorf("%w: %q", ErrTestTitleTaken, title)
+	}
+
+	testModel := model.Test{
+		Title:       title,
+		Description: req.Description,
+		Status:      model.TestStatusDraft,
+		Questions:   questions,
+	}
+	if req.Publish {
+		now := time.Now()
+		testModel.Status = model.TestStatusPublished
+		testModel.PublishedAt = &now
+	}
+	if err := s.testRepo.Create(&testModel); err != nil {
+		log.Error().Err(err).Msg("CreateTestFromBank: Failed to create test in database")
+		return nil, fmt.Errorf("database error creating test: %w", err)
+	}
+	log.Info().Uint("testID", testModel.ID).Msg("CreateTestFromBank: Test assembled from question bank.")
+	return s.GetTest(testModel.ID)
+}
+
+func (s *adminTestService) ListTests(status string, includeDeleted bool) ([]dto.AdminTestSummaryDTO, error) {
+	if status != "" && status != model.TestStatusDraft && status != model.TestStatusPublished {
+		return nil, fmt.Errorf("invalid status filter %q, expected %q or %q", status, model.TestStatusDraft, model.TestStatusPublished)
+	}
+	tests, err := s.testRepo.FindAllWithQuestionCount(repository.TestListFilter{Status: status, IncludeDeleted: includeDeleted})
+	if err != nil {
+		log.Error().Err(err).Msg("Admin ListTests: Failed to list tests")
+		return nil, fmt.Errorf("error fetching tests: %w", err)
+	}
+
+	dtos := make([]dto.AdminTestSummaryDTO, 0, len(tests))
+	for _, t := range tests {
+		summary := dto.AdminTestSummaryDTO{
+			ID:            t.ID,
+			Title:         t.Title,
+			Description:   t.Description,
+			Status:        t.Status,
+			QuestionCount: t.QuestionCount,
+			CreatedAt:     t.CreatedAt,
+			UpdatedAt:     t.UpdatedAt,
+			PublishedAt:   t.PublishedAt,
+		}
+		if t.DeletedAt.Valid {
+			summary.DeletedAt = &t.DeletedAt.Time
+		}
+		dtos = append(dtos, summary)
+	}
+	return dtos, nil
+}
+
+func (s *adminTestService) GetTest(testID uint) (*dto.AdminTestDetailDTO, error) {
+	test, err := s.loadTest(testID)
+	if err != nil {
+		return nil, err
+	}
+	return toAdminTestDetailDTO(test)
+}
+
+func (s *adminTestService) UpdateTest(testID uint, req dto.TestUpdateDTO) (*dto.AdminTestDetailDTO, error) {
+	test, err := s.loadTest(testID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, fmt.Errorf("title must not be empty")
+		}
+		if taken, err := s.testRepo.ExistsByTitle(title, test.ID); err != nil {
+			return nil, fmt.Errorf("error checking test title: %w", err)
+		} else if taken {
+			return nil, fmt.Errorf("%w: %q", ErrTestTitleTaken, title)
+		}
+		test.Title = title
+	}
+	if req.Description != nil {
+		test.Description = *req.Description
+	}
+
+	if err := s.testRepo.Update(test); err != nil {
+		log.Error().Err(err).Uint("testID", testID).Msg("Admin UpdateTest: Failed to save test")
+		return nil, fmt.Errorf("database error updating test: %w", err)
+	}
+	return s.GetTest(testID)
+}
+
+// UpdateQuestion edits the content of a question. The question keeps its type, order and max score,
+// so the test always keeps the fixed 8-question layout validated by CreateTest.
+// Each effective edit is stored as a new revision; existing answers stay pinned to the revision they saw.
+func (s *adminTestService) UpdateQuestion(ctx context.Context, testID uint, questionID uint, editedByID uint, req dto.QuestionUpdateDTO) (*dto.AdminTestDetailDTO, error) {
+	question, err := s.findTestQuestion(testID, questionID)
+	if err != nil {
+		return nil, err
 	}
+	before := model.NewQuestionRevision(question, 0, nil)
 
-	var resp dto.TestResponseDTO
-	if err := copier.Copy(&resp, createdTestWithDetails); err != nil {
-		log.Error().Err(err).Msg("Failed to copy created Test model to TestResponseDTO")
+	if req.Title != nil {
+		question.Title = *req.Title
+	}
+	if req.Prompt != nil {
+		question.Prompt = *req.Prompt
+	}
+	if req.ImageURL != nil {
+		question.ImageURL = emptyToNil(*req.ImageURL)
+	}
+	if req.GivenWord1 != nil {
+		question.GivenWord1 = emptyToNil(*req.GivenWord1)
+	}
+	if req.GivenWord2 != nil {
+		question.GivenWord2 = emptyToNil(*req.GivenWord2)
+	}
+
+	if err := validateQuestionContent(question.OrderInTest, question.Type, question.Title, question.Prompt, question.ImageURL, question.GivenWord1, question.GivenWord2); err != nil {
+		return nil, err
+	}
+
+	after := model.NewQuestionRevision(question, 0, nil)
+	if len(diffRevisionFields(&before, &after)) == 0 {
+		return s.GetTest(testID) // Nothing changed, do not create an empty revision
+	}
+	if derefString(before.ImageURL) != derefString(after.ImageURL) {
+		if err := s.checkQuestionImages(ctx, []model.Question{*question}); err != nil {
+			return nil, err
+		}
+	}
+
+	revision, err := s.questionRepo.UpdateWithRevision(question, &editedByID)
+	if err != nil {
+		log.Error().Err(err).Uint("questionID", questionID).Msg("Admin UpdateQuestion: Failed to save question")
+		return nil, fmt.Errorf("database error updating question: %w", err)
+	}
+	log.Info().Uint("questionID", questionID).Int("revision", revision.Revision).Msg("Admin UpdateQuestion: Question revised.")
+	return s.GetTest(testID)
+}
+
+func (s *adminTestService) ListQuestionRevisions(testID uint, questionID uint) ([]dto.QuestionRevisionDTO, error) {
+	question, err := s.findTestQuestion(testID, questionID)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := s.questionRepo.FindRevisions(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading revisions of question %d: %w", questionID, err)
+	}
+
+	dtos := make([]dto.QuestionRevisionDTO, 0, len(revisions))
+	for _, rev := range revisions {
+		var item dto.QuestionRevisionDTO
+		if err := copier.Copy(&item, &rev); err != nil {
+			return nil, fmt.Errorf("error preparing response data: %w", err)
+		}
+		item.IsCurrent = question.CurrentRevisionID != nil && *question.CurrentRevisionID == rev.ID
+		dtos = append(dtos, item)
+	}
+	return dtos, nil
+}
+
+// DiffQuestionRevisions compares two revisions of a question field by field, with a line diff of the prompt.
+// A zero toRevision means the latest revision and a zero fromRevision the one before toRevision.
+func (s *adminTestService) DiffQuestionRevisions(testID uint, questionID uint, fromRevision int, toRevision int) (*dto.QuestionRevisionDiffDTO, error) {
+	if _, err := s.findTestQuestion(testID, questionID); err != nil {
+		return nil, err
+	}
+	if toRevision == 0 {
+		revisions, err := s.questionRepo.FindRevisions(questionID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading revisions of question %d: %w", questionID, err)
+		}
+		if len(revisions) == 0 {
+			return nil, fmt.Errorf("%w: question %d has no revisions", ErrRevisionNotFound, questionID)
+		}
+		toRevision = revisions[len(revisions)-1].Revision
+	}
+	if fromRevision == 0 {
+		fromRevision = max(toRevision-1, 1)
+	}
+
+	from, err := s.findRevision(questionID, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevision(questionID, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.QuestionRevisionDiffDTO{
+		QuestionID:   questionID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Changes:      diffRevisionFields(from, to),
+		PromptDiff:   diffLines(from.Prompt, to.Prompt),
+	}, nil
+}
+
+func (s *adminTestService) findTestQuestion(testID uint, questionID uint) (*model.Question, error) {
+	test, err := s.loadTest(testID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range test.Questions {
+		if test.Questions[i].ID == questionID {
+			return &test.Questions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w with ID %d in test %d", ErrQuestionNotFound, questionID, testID)
+}
+
+func (s *adminTestService) findRevision(questionID uint, revision int) (*model.QuestionRevision, error) {
+	rev, err := s.questionRepo.FindRevision(questionID, revision)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: revision %d of question %d", ErrRevisionNotFound, revision, questionID)
+		}
+		return nil, fmt.Errorf("error loading revision %d of question %d: %w", revision, questionID, err)
+	}
+	return rev, nil
+}
+
+// PublishTest makes a draft visible to learners. Only complete, non-deleted tests can be published.
+func (s *adminTestService) PublishTest(testID uint) (*dto.AdminTestDetailDTO, error) {
+	test, err := s.loadTest(testID)
+	if err != nil {
+		return nil, err
+	}
+	if test.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w: test %d is deleted, restore it first", ErrTestNotPublishable, testID)
+	}
+	if len(test.Questions) != 8 {
+		return nil, fmt.Errorf("%w: test %d has %d questions, expected 8", ErrTestNotPublishable, testID, len(test.Questions))
+	}
+	if test.Status == model.TestStatusPublished {
+		return toAdminTestDetailDTO(test)
+	}
+
+	now := time.Now()
+	if err := s.testRepo.UpdateStatus(testID, model.TestStatusPublished, &now); err != nil {
+		log.Error().Err(err).Uint("testID", testID).Msg("Admin PublishTest: Failed to update status")
+		return nil, fmt.Errorf("database error publishing test: %w", err)
+	}
+	log.Info().Uint("testID", testID).Msg("Admin PublishTest: Test published.")
+	return s.GetTest(testID)
+}
+
+// UnpublishTest moves a test back to draft. Existing attempts are kept and remain visible to their owners.
+func (s *adminTestService) UnpublishTest(testID uint) (*dto.AdminTestDetailDTO, error) {
+	if _, err := s.loadTest(testID); err != nil {
+		return nil, err
+	}
+	if err := s.testRepo.UpdateStatus(testID, model.TestStatusDraft, nil); err != nil {
+		log.Error().Err(err).Uint("testID", testID).Msg("Admin UnpublishTest: Failed to update status")
+		return nil, fmt.Errorf("database error unpublishing test: %w", err)
+	}
+	return s.GetTest(testID)
+}
+
+// DeleteTest soft-deletes a test. Questions, attempts and answers are kept so history still renders.
+func (s *adminTestService) DeleteTest(testID uint) error {
+	if err := s.testRepo.Delete(testID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
+		}
+		log.Error().Err(err).Uint("testID", testID).Msg("Admin DeleteTest: Failed to delete test")
+		return fmt.Errorf("database error deleting test: %w", err)
+	}
+	log.Info().Uint("testID", testID).Msg("Admin DeleteTest: Test soft-deleted.")
+	return nil
+}
+
+func (s *adminTestService) RestoreTest(testID uint) (*dto.AdminTestDetailDTO, error) {
+	if err := s.testRepo.Restore(testID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no deleted test with ID %d", ErrTestNotFound, testID)
+		}
+		log.Error().Err(err).Uint("testID", testID).Msg("Admin RestoreTest: Failed to restore test")
+		return nil, fmt.Errorf("database error restoring test: %w", err)
+	}
+	log.Info().Uint("testID", testID).Msg("Admin RestoreTest: Test restored.")
+	return s.GetTest(testID)
+}
+
+// loadTest finds a test with its questions, including soft-deleted tests.
+func (s *adminTestService) loadTest(testID uint) (*model.Test, error) {
+	test, err := s.testRepo.FindByIDUnscopedWithQuestions(testID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
+		}
+		return nil, fmt.Errorf("error loading test %d: %w", testID, err)
+	}
+	return test, nil
+}
+
+func toAdminTestDetailDTO(test *model.Test) (*dto.AdminTestDetailDTO, error) {
+	var resp dto.AdminTestDetailDTO
+	if err := copier.Copy(&resp.TestResponseDTO, test); err != nil {
 		return nil, fmt.Errorf("error preparing response data: %w", err)
 	}
+	resp.Status = test.Status
+	resp.UpdatedAt = test.UpdatedAt
+	resp.PublishedAt = test.PublishedAt
+	if test.DeletedAt.Valid {
+		resp.DeletedAt = &test.DeletedAt.Time
+	}
 	return &resp, nil
 }
+
+// emptyToNil lets clients clear optional question fields by sending an empty string.
+func emptyToNil(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"gorm.io/gorm"
+)
+
+// scoreBuckets is the number of equal-width buckets in a question's score distribution.
+const scoreBuckets = 5
+
+// AnalyticsService reports how tests and questions perform across all learners.
+type AnalyticsService interface {
+	ListTestAnalytics() ([]dto.TestAnalyticsSummaryDTO, error)
+	GetTestAnalytics(testID uint) (*dto.TestAnalyticsDTO, error)
+}
+
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	testRepo      repository.TestRepository
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, testRepo repository.TestRepository) AnalyticsService {
+	return &analyticsService{analyticsRepo: analyticsRepo, testRepo: testRepo}
+}
+
+func (s *analyticsService) ListTestAnalytics() ([]dto.TestAnalyticsSummaryDTO, error) {
+	rows, err := s.analyticsRepo.TestSummaries(nil)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating test analytics: %w", err)
+	}
+	summaries := make([]dto.TestAnalyticsSummaryDTO, len(rows))
+	for i, row := range rows {
+		summaries[i] = toTestAnalyticsSummaryDTO(row)
+	}
+	return summaries, nil
+}
+
+// GetTestAnalytics reports on a test and each of its questions. Questions without answers are
+// included with zero counts so a report always covers the whole test.
+func (s *analyticsService) GetTestAnalytics(testID uint) (*dto.TestAnalyticsDTO, error) {
+	test, err := s.testRepo.FindByIDUnscopedWithQuestions(testID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
+		}
+		return nil, fmt.Errorf("error loading test %d: %w", testID, err)
+	}
+
+	report := &dto.TestAnalyticsDTO{
+		TestAnalyticsSummaryDTO: dto.TestAnalyticsSummaryDTO{TestID: test.ID, Title: test.Title, Status: test.Status},
+		Questions:               make([]dto.QuestionAnalyticsDTO, 0, len(test.Questions)),
+	}
+	summaryRows, err := s.analyticsRepo.TestSummaries(&testID)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating analytics for test %d: %w", testID, err)
+	}
+	if len(summaryRows) > 0 {
+		report.TestAnalyticsSummaryDTO = toTestAnalyticsSummaryDTO(summaryRows[0])
+	}
+
+	statRows, err := s.analyticsRepo.QuestionStats(testID)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating question analytics for test %d: %w", testID, err)
+	}
+	statsByQuestion := make(map[uint]repository.QuestionAnalyticsRow, len(statRows))
+	for _, row := range statRows {
+		statsByQuestion[row.QuestionID] = row
+	}
+	bucketRows, err := s.analyticsRepo.QuestionScoreBuckets(testID, scoreBuckets)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating score distribution for test %d: %w", testID, err)
+	}
+	bucketsByQuestion := make(map[uint][]repository.QuestionScoreBucketRow)
+	for _, row := range bucketRows {
+		bucketsByQuestion[row.QuestionID] = append(bucketsByQuestion[row.QuestionID], row)
+	}
+
+	for _, q := range test.Questions {
+		stats := statsByQuestion[q.ID]
+		item := dto.QuestionAnalyticsDTO{
+			QuestionID:     q.ID,
+			OrderInTest:    q.OrderInTest,
+			Type:           q.Type,
+			Title:          q.Title,
+			MaxScore:       q.MaxScore,
+			Answers:        stats.Answers,
+			Scored:         stats.Scored,
+			Failed:         stats.Failed,
+			FailureRate:    failureRate(stats.Scored, stats.Failed),
+			MeanFraction:   roundPtr(stats.MeanFraction, 3),
+			MedianFraction: roundPtr(stats.MedianFraction, 3),
+			StdDevFraction: roundPtr(stats.StdDevFraction, 3),
+			AverageWords:   roundPtr(stats.AverageWords, 1),
+			AverageChars:   roundPtr(stats.AverageChars, 1),
+			Distribution:   make([]dto.ScoreBucketDTO, scoreBuckets),
+		}
+		for b := range item.Distribution {
+			item.Distribution[b] = dto.ScoreBucketDTO{From: float64(b) / scoreBuckets, To: float64(b+1) / scoreBuckets}
+		}
+		for _, row := range bucketsByQuestion[q.ID] {
+			if row.Bucket >= 0 && row.Bucket < scoreBuckets {
+				item.Distribution[row.Bucket].Answers = row.Answers
+			}
+		}
+		report.Questions = append(report.Questions, item)
+	}
+	return report, nil
+}
+
+// WriteTestAnalyticsCSV writes one row per test.
+func WriteTestAnalyticsCSV(w io.Writer, summaries []dto.TestAnalyticsSummaryDTO) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"test_id", "title", "status", "attempts", "learners", "completed", "completed_with_errors", "errored",
+		"mean_raw_score", "median_raw_score", "answer_failure_rate"})
+	for _, s := range summaries {
+		out.Write([]string{
+			strconv.FormatUint(uint64(s.TestID), 10), s.Title, s.Status,
+			strconv.Itoa(s.Attempts), strconv.Itoa(s.Learners), strconv.Itoa(s.Completed), strconv.Itoa(s.CompletedWithErrors), strconv.Itoa(s.Errored),
+			csvFloat(s.MeanRawScore), csvFloat(s.MedianRawScore), csvFloat(s.AnswerFailureRate),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteQuestionAnalyticsCSV writes one row per question of the report, with the distribution in bucket columns.
+func WriteQuestionAnalyticsCSV(w io.Writer, report *dto.TestAnalyticsDTO) error {
+	out := csv.NewWriter(w)
+	header := []string{"test_id", "question_id", "order_in_test", "type", "title", "max_score", "answers", "scored", "failed",
+		"failure_rate", "mean_fraction", "median_fraction", "stddev_fraction", "average_words", "average_chars"}
+	for b := 0; b < scoreBuckets; b++ {
+		header = append(header, fmt.Sprintf("answers_%d_%d_pct", b*100/scoreBuckets, (b+1)*100/scoreBuckets))
+	}
+	out.Write(header)
+	for _, q := range report.Questions {
+		row := []string{
+			strconv.FormatUint(uint64(report.TestID), 10), strconv.FormatUint(uint64(q.QuestionID), 10), strconv.Itoa(q.OrderInTest),
+			q.Type, q.Title, strconv.FormatFloat(q.MaxScore, 'f', -1, 64),
+			strconv.Itoa(q.Answers), strconv.Itoa(q.Scored), strconv.Itoa(q.Failed),
+			csvFloat(q.FailureRate), csvFloat(q.MeanFraction), csvFloat(q.MedianFraction), csvFloat(q.StdDevFraction),
+			csvFloat(q.AverageWords), csvFloat(q.AverageChars),
+		}
+		for _, bucket := range q.Distribution {
+			row = append(row, strconv.Itoa(bucket.Answers))
+		}
+		out.Write(row)
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func toTestAnalyticsSummaryDTO(row repository.TestAnalyticsRow) dto.TestAnalyticsSummaryDTO {
+	return dto.TestAnalyticsSummaryDTO{
+		TestID:              row.TestID,
+		Title:               row.Title,
+		Status:              row.Status,
+		Attempts:            row.Attempts,
+		Learners:            row.Learners,
+		Completed:           row.Completed,
+		CompletedWithErrors: row.CompletedWithErrors,
+		Errored:             row.Errored,
+		MeanRawScore:        roundPtr(row.MeanRawScore, 2),
+		MedianRawScore:      roundPtr(row.MedianRawScore, 2),
+		AnswerFailureRate:   failureRate(row.ScoredAnswers, row.FailedAnswers),
+	}
+}
+
+// failureRate is the share of finished scorings that failed, or nil if nothing was scored yet.
+func failureRate(scored, failed int) *float64 {
+	if scored+failed == 0 {
+		return nil
+	}
+	rate := float64(failed) / float64(scored+failed)
+	return roundPtr(&rate, 3)
+}
+
+func roundPtr(value *float64, decimals int) *float64 {
+	if value == nil {
+		return nil
+	}
+	p := math.Pow(10, float64(decimals))
+	rounded := math.Round(*value*p) / p
+	return &rounded
+}
+
+func csvFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
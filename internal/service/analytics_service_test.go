@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+)
+
+// stubAnalyticsRepository returns fixed aggregates, as the Postgres queries would.
+type stubAnalyticsRepository struct {
+	summaries []repository.TestAnalyticsRow
+	stats     []repository.QuestionAnalyticsRow
+	buckets   []repository.QuestionScoreBucketRow
+}
+
+func (r *stubAnalyticsRepository) TestSummaries(testID *uint) ([]repository.TestAnalyticsRow, error) {
+	return r.summaries, nil
+}
+
+func (r *stubAnalyticsRepository) QuestionStats(testID uint) ([]repository.QuestionAnalyticsRow, error) {
+	return r.stats, nil
+}
+
+func (r *stubAnalyticsRepository) QuestionScoreBuckets(testID uint, buckets int) ([]repository.QuestionScoreBucketRow, error) {
+	return r.buckets, nil
+}
+
+type stubAnalyticsTestRepository struct {
+	repository.TestRepository
+	test *model.Test
+}
+
+func (r *stubAnalyticsTestRepository) FindByIDUnscopedWithQuestions(id uint) (*model.Test, error) {
+	return r.test, nil
+}
+
+func TestGetTestAnalytics(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	test := &model.Test{ID: 1, Title: "Practice 1", Status: model.TestStatusPublished, Questions: []model.Question{
+		{ID: 10, OrderInTest: 1, Type: "sentence_picture", Title: "Q1", MaxScore: 3},
+		{ID: 11, OrderInTest: 2, Type: "sentence_picture", Title: "Q2", MaxScore: 3},
+	}}
+	repo := &stubAnalyticsRepository{
+		summaries: []repository.TestAnalyticsRow{{TestID: 1, Title: "Practice 1", Status: model.TestStatusPublished, Attempts: 4,
+			MeanRawScore: f(17.456), MedianRawScore: f(18), ScoredAnswers: 5, FailedAnswers: 1}},
+		stats: []repository.QuestionAnalyticsRow{{QuestionID: 10, Answers: 4, Scored: 3, Failed: 1,
+			MeanFraction: f(0.66666), MedianFraction: f(0.5), StdDevFraction: f(0.23571), AverageWords: f(9.25), AverageChars: f(41.04)}},
+		buckets: []repository.QuestionScoreBucketRow{
+			{QuestionID: 10, Bucket: 2, Answers: 1},
+			{QuestionID: 10, Bucket: 4, Answers: 2},
+			{QuestionID: 10, Bucket: 5, Answers: 9}, // Out of range, ignored
+		},
+	}
+	s := NewAnalyticsService(repo, &stubAnalyticsTestRepository{test: test})
+
+	report, err := s.GetTestAnalytics(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *report.MeanRawScore != 17.46 || *report.AnswerFailureRate != 0.167 {
+		t.Errorf("mean raw score = %v, failure rate = %v; want 17.46 and 0.167", *report.MeanRawScore, *report.AnswerFailureRate)
+	}
+	if len(report.Questions) != 2 {
+		t.Fatalf("questions = %d, want both questions of the test", len(report.Questions))
+	}
+
+	answered := report.Questions[0]
+	if *answered.FailureRate != 0.25 || *answered.MeanFraction != 0.667 || *answered.StdDevFraction != 0.236 || *answered.AverageChars != 41 {
+		t.Errorf("question stats = failure %v, mean %v, stddev %v, chars %v; want 0.25, 0.667, 0.236, 41",
+			*answered.FailureRate, *answered.MeanFraction, *answered.StdDevFraction, *answered.AverageChars)
+	}
+	wantCounts := []int{0, 0, 1, 0, 2}
+	for b, bucket := range answered.Distribution {
+		from, to := float64(b)/scoreBuckets, float64(b+1)/scoreBuckets
+		if bucket.From != from || bucket.To != to || bucket.Answers != wantCounts[b] {
+			t.Errorf("bucket %d = %+v, want [%v, %v) with %d answers", b, bucket, from, to, wantCounts[b])
+		}
+	}
+
+	unanswered := report.Questions[1]
+	if unanswered.Answers != 0 || unanswered.FailureRate != nil || unanswered.MeanFraction != nil || len(unanswered.Distribution) != scoreBuckets {
+		t.Errorf("question without answers = %+v, want zero counts, no rates and empty buckets", unanswered)
+	}
+
+	var csv bytes.Buffer
+	if err := WriteQuestionAnalyticsCSV(&csv, report); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if !strings.HasSuffix(lines[0], "answers_0_20_pct,answers_20_40_pct,answers_40_60_pct,answers_60_80_pct,answers_80_100_pct") {
+		t.Errorf("CSV header = %q, want the five bucket columns", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ",0.25,0.667,0.5,0.236,9.3,41,0,0,1,0,2") {
+		t.Errorf("CSV row = %q", lines[1])
+	}
+}
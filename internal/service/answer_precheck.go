@@ -0,0 +1,369 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+// PrecheckResult holds the findings of the rule-based checks run on an answer before AI scoring.
+type PrecheckResult struct {
+	Findings []model.PrecheckFinding
+	ScoreCap *float64 // Lowest cap of the hard findings; nil when no hard requirement was missed
+}
+
+// wordCountRule is a length expectation of a question type. Below MinWords, a rule with a cap
+// limits the score and a rule without one is only advisory.
+type wordCountRule struct {
+	MinWords int
+	ScoreCap *float64
+}
+
+func scoreCap(value float64) *float64 { return &value }
+
+// wordCountRules lists the length expectations per question type, strictest first.
+// Part 1 is checked by sentence count instead.
+var wordCountRules = map[string][]wordCountRule{
+	"email_response": {
+		{MinWords: 25, ScoreCap: scoreCap(1)},
+		{MinWords: 50},
+	},
+	"opinion_essay": {
+		{MinWords: 50, ScoreCap: scoreCap(1)},
+		{MinWords: 150, ScoreCap: scoreCap(2)},
+		{MinWords: 300}, // The length ETS recommends
+	},
+}
+
+const (
+	sentencePictureMultipleCap = 2 // One or more sentences using both words, per the ETS rubric
+	sentencePictureMissingCap  = 1 // Omits one or both words
+	minEnglishLetterShare      = 0.8
+	minEnglishWordShare        = 0.1
+	minWordsForWordShare       = 8 // Short answers have too few words to judge the language by them
+)
+
+var (
+	wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+(?:['’][\p{L}]+)*`)
+	// A sentence ends with . ! or ? followed by whitespace or the end of the text.
+	sentenceEndPattern = regexp.MustCompile(`[.!?]+(?:["'”’)\]]*)(?:\s+|$)`)
+)
+
+// abbreviations end with a period without ending the sentence.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "st": true, "jr": true, "sr": true,
+	"etc": true, "e.g": true, "i.e": true, "vs": true, "no": true, "approx": true, "dept": true, "co": true, "inc": true, "ltd": true,
+}
+
+// englishFunctionWords are frequent in any English text longer than a few words.
+var englishFunctionWords = map[string]bool{
+	"the": true, "a": true, "an": true, "and": true, "or": true, "but": true, "to": true, "of": true, "in": true,
+	"on": true, "at": true, "for": true, "with": true, "from": true, "by": true, "about": true, "as": true,
+	"is": true, "are": true, "was": true, "were": true, "be": true, "been": true, "am": true, "do": true,
+	"does": true, "did": true, "have": true, "has": true, "had": true, "will": true, "would": true, "can": true,
+	"could": true, "should": true, "not": true, "i": true, "you": true, "he": true, "she": true, "it": true,
+	"we": true, "they": true, "my": true, "your": true, "his": true, "her": true, "our": true, "their": true,
+	"this": true, "that": true, "these": true, "those": true, "there": true, "because": true, "if": true,
+	"so": true, "me": true, "us": true, "them": true, "which": true, "who": true, "what": true, "when": true,
+}
+
+// PrecheckAnswer runs the rule-based checks of the question's type on an answer. It needs
+// no network and no model, so its findings are the same every time the answer is scored.
+func PrecheckAnswer(question *model.Question, userAnswer string) *PrecheckResult {
+	result := &PrecheckResult{}
+	text := strings.TrimSpace(userAnswer)
+	words := wordPattern.FindAllString(text, -1)
+	if len(words) == 0 {
+		result.add("empty", "The answer is empty.", scoreCap(0))
+		return result
+	}
+	if reason := offLanguageReason(text, words); reason != "" {
+		result.add("language", reason, scoreCap(0))
+		return result
+	}
+
+	switch question.Type {
+	case "sentence_picture":
+		if count := countSentences(text); count > 1 {
+			result.add("sentence_count", fmt.Sprintf("The task asks for one sentence, but the answer has %d.", count), scoreCap(sentencePictureMultipleCap))
+		}
+		var missing []string
+		for _, given := range []*string{question.GivenWord1, question.GivenWord2} {
+			if given != nil && strings.TrimSpace(*given) != "" && !containsGivenWords(words, *given) {
+				missing = append(missing, fmt.Sprintf("%q", strings.TrimSpace(*given)))
+			}
+		}
+		if len(missing) > 0 {
+			result.add("given_words", fmt.Sprintf("The answer does not use the given word(s) %s in any form.", strings.Join(missing, " and ")), scoreCap(sentencePictureMissingCap))
+		}
+	default:
+		for _, rule := range wordCountRules[question.Type] {
+			if len(words) >= rule.MinWords {
+				continue
+			}
+			message := fmt.Sprintf("The answer has %d words; at least %d are expected.", len(words), rule.MinWords)
+			result.add("word_count", message, rule.ScoreCap)
+			break // Only the strictest rule missed is reported
+		}
+	}
+	return result
+}
+
+func (r *PrecheckResult) add(rule string, message string, limit *float64) {
+	finding := model.PrecheckFinding{Rule: rule, Severity: model.PrecheckAdvisory, Message: message}
+	if limit != nil {
+		finding.Severity = model.PrecheckHard
+		finding.ScoreCap = limit
+		if r.ScoreCap == nil || *limit < *r.ScoreCap {
+			r.ScoreCap = limit
+		}
+	}
+	r.Findings = append(r.Findings, finding)
+}
+
+// Blocking reports whether a finding caps the score at zero, so the answer is not worth sending to the AI.
+func (r *PrecheckResult) Blocking() bool {
+	return r.ScoreCap != nil && *r.ScoreCap == 0
+}
+
+// PromptSection describes the findings for the scoring prompt, or returns "" when there are none.
+func (r *PrecheckResult) PromptSection() string {
+	if len(r.Findings) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Automated checks of the answer found the following. They are reliable; take them into account in your evaluation:\n")
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "- %s", f.Message)
+		if f.ScoreCap != nil {
+			fmt.Fprintf(&b, " (the overall score cannot exceed %.1f)", *f.ScoreCap)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Apply records the findings on the evaluation and caps its score. The AI's own score is kept in
+// UncappedScore when the cap lowered it.
+func (r *PrecheckResult) Apply(eval *LLMEvaluation) {
+	eval.Prechecks = r.Findings
+	if r.ScoreCap == nil || eval.Score == nil || *eval.Score <= *r.ScoreCap {
+		return
+	}
+	uncapped := *eval.Score
+	capped := *r.ScoreCap
+	eval.UncappedScore = &uncapped
+	eval.Score = &capped
+	eval.Summary = fmt.Sprintf("%s\n\nThe score is capped at %.1f because a requirement of the task was missed: %s",
+		strings.TrimSpace(eval.Summary), capped, r.hardMessages())
+}
+
+// BlockedEvaluation is the evaluation of an answer with a blocking finding: every criterion scores zero.
+func (r *PrecheckResult) BlockedEvaluation(questionType string, maxScore float64) *LLMEvaluation {
+	zero := 0.0
+	eval := &LLMEvaluation{
+		Score:     &zero,
+		Summary:   "This answer was not scored by the AI: " + r.hardMessages(),
+		Prechecks: r.Findings,
+	}
+	for _, name := range rubricCriteria[questionType] {
+		eval.Criteria = append(eval.Criteria, CriterionEvaluation{Criterion: name, MaxScore: maxScore, Comment: "Not scored."})
+	}
+	return eval
+}
+
+func (r *PrecheckResult) hardMessages() string {
+	var messages []string
+	for _, f := range r.Findings {
+		if f.Severity == model.PrecheckHard {
+			messages = append(messages, f.Message)
+		}
+	}
+	return strings.Join(messages, " ")
+}
+
+// offLanguageReason returns why an answer does not look like English, or "" when it does.
+func offLanguageReason(text string, words []string) string {
+	letters, latin := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if r < unicode.MaxASCII {
+				latin++
+			}
+		}
+	}
+	if letters > 0 && float64(latin)/float64(letters) < minEnglishLetterShare {
+		return "The answer is not written in English."
+	}
+	if len(words) < minWordsForWordShare {
+		return ""
+	}
+	common := 0
+	for _, w := range words {
+		if englishFunctionWords[strings.ToLower(w)] {
+			common++
+		}
+	}
+	if float64(common)/float64(len(words)) < minEnglishWordShare {
+		return "The answer does not appear to be written in English."
+	}
+	return ""
+}
+
+// countSentences counts the sentences of a text that contain at least one word.
+func countSentences(text string) int {
+	count := 0
+	start := 0
+	for _, loc := range sentenceEndPattern.FindAllStringIndex(text, -1) {
+		segment := text[start:loc[0]]
+		if lastWord := lastWordOf(segment); abbreviations[lastWord] && loc[1] < len(text) {
+			continue // "Mr. Kim" does not end a sentence
+		}
+		if wordPattern.MatchString(segment) {
+			count++
+		}
+		start = loc[1]
+	}
+	if start < len(text) && wordPattern.MatchString(text[start:]) {
+		count++ // Last sentence without final punctuation
+	}
+	return count
+}
+
+func lastWordOf(segment string) string {
+	fields := strings.Fields(segment)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimLeft(fields[len(fields)-1], `("'“‘`))
+}
+
+// maxGivenWordGap is the number of words allowed between the words of a given phrase,
+// so separable phrasal verbs ("pick the box up") still count.
+const maxGivenWordGap = 2
+
+// containsGivenWords reports whether the answer uses every word of a given word or phrase,
+// in order and in any inflected form.
+func containsGivenWords(answerWords []string, given string) bool {
+	givenWords := wordPattern.FindAllString(given, -1)
+	if len(givenWords) == 0 {
+		return true
+	}
+	for start := range answerWords {
+		if !sameLemma(answerWords[start], givenWords[0]) {
+			continue
+		}
+		pos, matched := start, 1
+		for matched < len(givenWords) {
+			found := false
+			for next := pos + 1; next < len(answerWords) && next <= pos+1+maxGivenWordGap; next++ {
+				if sameLemma(answerWords[next], givenWords[matched]) {
+					pos, found = next, true
+					break
+				}
+			}
+			if !found {
+				break
+			}
+			matched++
+		}
+		if matched == len(givenWords) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameLemma reports whether two words can be forms of the same word, e.g. "sit" and "sitting".
+func sameLemma(a, b string) bool {
+	aStems := lemmaCandidates(a)
+	for stem := range lemmaCandidates(b) {
+		if aStems[stem] {
+			return true
+		}
+	}
+	return false
+}
+
+// lemmaCandidates returns the base forms a word may be an inflection of. Regular suffixes
+// are undone in every plausible way; over-matching only makes the check more lenient.
+func lemmaCandidates(word string) map[string]bool {
+	w := strings.ToLower(strings.NewReplacer("’", "'").Replace(word))
+	w = strings.TrimSuffix(strings.TrimSuffix(w, "'s"), "'")
+	candidates := map[string]bool{w: true}
+	if base, ok := irregularForms[w]; ok {
+		candidates[base] = true
+	}
+	add := func(stem string) {
+		if len(stem) >= 2 {
+			candidates[stem] = true
+		}
+	}
+	undoDoubling := func(stem string) {
+		if n := len(stem); n >= 3 && stem[n-1] == stem[n-2] && !strings.ContainsRune("aeiou", rune(stem[n-1])) {
+			add(stem[:n-1]) // "sitting" -> "sit"
+		}
+	}
+	for _, suffix := range []string{"ies", "ied", "ier", "iest"} {
+		if strings.HasSuffix(w, suffix) {
+			add(strings.TrimSuffix(w, suffix) + "y")
+		}
+	}
+	for _, suffix := range []string{"ing", "ed", "er", "est"} {
+		if stem, ok := strings.CutSuffix(w, suffix); ok {
+			add(stem)
+			add(stem + "e") // "making" -> "make", "used" -> "use"
+			undoDoubling(stem)
+		}
+	}
+	if stem, ok := strings.CutSuffix(w, "es"); ok {
+		add(stem)
+	}
+	if stem, ok := strings.CutSuffix(w, "s"); ok && !strings.HasSuffix(w, "ss") {
+		add(stem)
+	}
+	if stem, ok := strings.CutSuffix(w, "ves"); ok {
+		add(stem + "f")
+		add(stem + "fe")
+	}
+	if stem, ok := strings.CutSuffix(w, "ly"); ok {
+		add(stem) // "quickly" -> "quick"
+	}
+	return candidates
+}
+
+// irregularForms maps common irregular inflections to their base form.
+var irregularForms = func() map[string]string {
+	forms := map[string][]string{
+		"be": {"am", "is", "are", "was", "were", "been", "being"}, "have": {"has", "had", "having"},
+		"do": {"does", "did", "done"}, "go": {"goes", "went", "gone"}, "make": {"made"}, "take": {"took", "taken"},
+		"give": {"gave", "given"}, "see": {"saw", "seen"}, "come": {"came"}, "get": {"got", "gotten"},
+		"write": {"wrote", "written"}, "sit": {"sat"}, "stand": {"stood"}, "hold": {"held"}, "buy": {"bought"},
+		"bring": {"brought"}, "think": {"thought"}, "speak": {"spoke", "spoken"}, "drive": {"drove", "driven"},
+		"ride": {"rode", "ridden"}, "eat": {"ate", "eaten"}, "drink": {"drank", "drunk"}, "run": {"ran"},
+		"leave": {"left"}, "meet": {"met"}, "wear": {"wore", "worn"}, "hang": {"hung"}, "lie": {"lay", "lain", "lying"},
+		"fly": {"flew", "flown"}, "grow": {"grew", "grown"}, "know": {"knew", "known"}, "draw": {"drew", "drawn"},
+		"throw": {"threw", "thrown"}, "fall": {"fell", "fallen"}, "feel": {"felt"}, "keep": {"kept"}, "sleep": {"slept"},
+		"send": {"sent"}, "spend": {"spent"}, "build": {"built"}, "teach": {"taught"}, "catch": {"caught"},
+		"sell": {"sold"}, "tell": {"told"}, "find": {"found"}, "pay": {"paid"}, "say": {"said"}, "lay": {"laid"},
+		"begin": {"began", "begun"}, "swim": {"swam", "swum"}, "sing": {"sang", "sung"}, "choose": {"chose", "chosen"},
+		"break": {"broke", "broken"}, "forget": {"forgot", "forgotten"}, "lend": {"lent"}, "lose": {"lost"},
+		"shake": {"shook", "shaken"}, "wake": {"woke", "woken"}, "win": {"won"}, "bend": {"bent"}, "lead": {"led"},
+		"feed": {"fed"}, "fight": {"fought"}, "seek": {"sought"}, "understand": {"understood"}, "wind": {"wound"},
+		"child": {"children"}, "man": {"men"}, "woman": {"women"}, "person": {"people"}, "foot": {"feet"},
+		"tooth": {"teeth"}, "mouse": {"mice"}, "good": {"better", "best"}, "bad": {"worse", "worst"},
+		"far": {"farther", "further", "farthest", "furthest"},
+	}
+	byForm := make(map[string]string)
+	for base, inflections := range forms {
+		for _, form := range inflections {
+			byForm[form] = base
+		}
+	}
+	return byForm
+}()
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+func TestCountSentences(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "", want: 0},
+		{text: "...", want: 0},
+		{text: "A man is sitting on a bench.", want: 1},
+		{text: "A man is sitting on a bench", want: 1},
+		{text: "A man sits. He reads a newspaper.", want: 2},
+		{text: "A man sits.He reads.", want: 1}, // No space after the period: not a sentence end
+		{text: "He works hard!!! Really?", want: 2},
+		{text: "Wait... what happened?", want: 2},
+		{text: `She said "Hello." Then she left.`, want: 2},
+		{text: "He (the manager) left early.) Then it rained.", want: 2},
+		{text: "Mr. Kim is reading a book.", want: 1},
+		{text: "Dr. Lee and Ms. Park talk, e.g. about work, etc. at the office.", want: 1},
+		{text: "The ticket costs approx. 3.50 dollars.", want: 1},
+		{text: "The sign says No. 5 is closed.", want: 1},
+		{text: "He works for Smith Co.", want: 1}, // An abbreviation at the very end still ends the sentence
+		{text: "He works for Smith Co. It is big.", want: 1},
+		{text: "First line\nSecond line", want: 1},
+		{text: "One. Two. Three.", want: 3},
+	}
+	for _, tt := range tests {
+		if got := countSentences(tt.text); got != tt.want {
+			t.Errorf("countSentences(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestContainsGivenWords(t *testing.T) {
+	tests := []struct {
+		answer string
+		given  string
+		want   bool
+	}{
+		{answer: "The woman is sitting on a bench.", given: "sit", want: true},
+		{answer: "The woman sits on a bench.", given: "sit", want: true},
+		{answer: "The woman sat on a bench.", given: "sit", want: true},
+		{answer: "The woman is standing.", given: "sit", want: false},
+		{answer: "He stopped the car.", given: "stop", want: true},
+		{answer: "She is making coffee.", given: "make", want: true},
+		{answer: "She made coffee.", given: "make", want: true},
+		{answer: "She carries two bags.", given: "carry", want: true},
+		{answer: "She carried two bags.", given: "carry", want: true},
+		{answer: "The leaves are falling.", given: "leaf", want: true},
+		{answer: "The knives are sharp.", given: "knife", want: true},
+		{answer: "The boxes are heavy.", given: "box", want: true},
+		{answer: "He is happier today.", given: "happy", want: true},
+		{answer: "She walks quickly.", given: "quick", want: true},
+		{answer: "The children are playing.", given: "child", want: true},
+		{answer: "Many people are waiting.", given: "person", want: true},
+		{answer: "The woman's bag is red.", given: "woman", want: true},
+		{answer: "The woman’s bag is red.", given: "woman", want: true},
+		{answer: "He wears glasses.", given: "glasses", want: true},
+		{answer: "He holds a glass.", given: "glasses", want: true}, // Lenient: both are forms of "glass"
+		{answer: "He is reading.", given: "write", want: false},
+		{answer: "The WOMAN is SITTING.", given: "Sit", want: true},
+		{answer: "They are in front of the store.", given: "in front of", want: true},
+		{answer: "They are in the front of the store.", given: "in front of", want: true},
+		{answer: "He picked up the box.", given: "pick up", want: true},
+		{answer: "He picked the box up.", given: "pick up", want: true},
+		{answer: "He picked the heavy box up.", given: "pick up", want: false},    // Gap of 3 words
+		{answer: "Up the stairs he picked a box.", given: "pick up", want: false}, // Wrong order
+		{answer: "He picked flowers and looked up.", given: "pick up", want: false},
+		{answer: "He picked a box. He looked up.", given: "pick up", want: false},
+		{answer: "Anything.", given: "  ", want: true},
+	}
+	for _, tt := range tests {
+		words := wordPattern.FindAllString(tt.answer, -1)
+		if got := containsGivenWords(words, tt.given); got != tt.want {
+			t.Errorf("containsGivenWords(%q, %q) = %v, want %v", tt.answer, tt.given, got, tt.want)
+		}
+	}
+}
+
+func TestLemmaCandidates(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{word: "sitting", want: []string{"sitting", "sit"}},
+		{word: "went", want: []string{"go"}},
+		{word: "was", want: []string{"be"}},
+		{word: "studies", want: []string{"study"}},
+		{word: "studied", want: []string{"study"}},
+		{word: "used", want: []string{"use"}},
+		{word: "wolves", want: []string{"wolf"}},
+		{word: "wives", want: []string{"wife"}},
+		{word: "feet", want: []string{"foot"}},
+		{word: "Manager's", want: []string{"manager"}},
+		{word: "bosses'", want: []string{"boss"}},
+	}
+	for _, tt := range tests {
+		got := lemmaCandidates(tt.word)
+		for _, want := range tt.want {
+			if !got[want] {
+				t.Errorf("lemmaCandidates(%q) = %v, want it to include %q", tt.word, got, want)
+			}
+		}
+	}
+	if got := lemmaCandidates("glass"); got["glas"] {
+		t.Errorf("lemmaCandidates(%q) stripped the s of a double s: %v", "glass", got)
+	}
+}
+
+// englishWords returns n words of plain English with a normal share of function words.
+func englishWords(n int) string {
+	source := strings.Fields("we will meet the new client at the office on friday and discuss the plan for next year")
+	words := make([]string, n)
+	for i := range words {
+		words[i] = source[i%len(source)]
+	}
+	return strings.Join(words, " ")
+}
+
+func TestOffLanguageReason(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string // "" for English
+	}{
+		{name: "English", text: "The man is reading a newspaper on the train.", want: ""},
+		{name: "Japanese", text: "男の人がベンチに座って新聞を読んでいます", want: "The answer is not written in English."},
+		{name: "mostly Cyrillic", text: "Мужчина сидит на скамейке and reads", want: "The answer is not written in English."},
+		{name: "English with names", text: "I met Zoë and José at the café in München.", want: ""},
+		{name: "short Spanish is not judged", text: "El hombre lee un periódico", want: ""},
+		{name: "long Spanish", text: "El hombre está sentado en un banco leyendo un periódico por la mañana", want: "The answer does not appear to be written in English."},
+		// minWordsForWordShare words with a single function word is exactly at the threshold share or above.
+		{name: "eight words, one function word", text: "the xyzzy plugh quux frob blarg wibble wobble", want: ""},
+		{name: "eight words, no function word", text: "xyzzy plugh quux frob blarg wibble wobble zorp", want: "The answer does not appear to be written in English."},
+		{name: "ten words, one function word", text: "the a1 b1 c1 d1 e1 f1 g1 h1 i1", want: ""},
+		{name: "eleven words, one function word", text: "the a1 b1 c1 d1 e1 f1 g1 h1 i1 j1", want: "The answer does not appear to be written in English."},
+		{name: "seven unknown words", text: "xyzzy plugh quux frob blarg wibble wobble", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words := wordPattern.FindAllString(tt.text, -1)
+			if got := offLanguageReason(tt.text, words); got != tt.want {
+				t.Errorf("offLanguageReason(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrecheckAnswer(t *testing.T) {
+	word := func(s string) *string { return &s }
+	picture := &model.Question{Type: "sentence_picture", GivenWord1: word("woman"), GivenWord2: word("sit")}
+	email := &model.Question{Type: "email_response"}
+	essay := &model.Question{Type: "opinion_essay"}
+
+	type finding struct {
+		rule string
+		cap  float64 // -1 for an advisory finding
+	}
+	tests := []struct {
+		name     string
+		question *model.Question
+		answer   string
+		want     []finding
+		wantCap  float64 // -1 when nothing caps the score
+	}{
+		{name: "empty", question: email, answer: "  \n ", want: []finding{{"empty", 0}}, wantCap: 0},
+		{name: "punctuation only", question: picture, answer: "?!", want: []finding{{"empty", 0}}, wantCap: 0},
+		{name: "not English", question: picture, answer: "女性がベンチに座っています", want: []finding{{"language", 0}}, wantCap: 0},
+		{name: "picture, fine", question: picture, answer: "The woman is sitting on a bench.", wantCap: -1},
+		{name: "picture, two sentences", question: picture, answer: "The woman sits. She reads.", want: []finding{{"sentence_count", 2}}, wantCap: 2},
+		{name: "picture, missing a word", question: picture, answer: "The woman is standing.", want: []finding{{"given_words", 1}}, wantCap: 1},
+		{name: "picture, strictest cap wins", question: picture, answer: "A man stands. He waits.", want: []finding{{"sentence_count", 2}, {"given_words", 1}}, wantCap: 1},
+		{name: "email, very short", question: email, answer: englishWords(10), want: []finding{{"word_count", 1}}, wantCap: 1},
+		{name: "email, just below the cap", question: email, answer: englishWords(24), want: []finding{{"word_count", 1}}, wantCap: 1},
+		{name: "email, at the cap", question: email, answer: englishWords(25), want: []finding{{"word_count", -1}}, wantCap: -1},
+		{name: "email, long enough", question: email, answer: englishWords(50), wantCap: -1},
+		{name: "essay, very short", question: essay, answer: englishWords(40), want: []finding{{"word_count", 1}}, wantCap: 1},
+		{name: "essay, short", question: essay, answer: englishWords(100), want: []finding{{"word_count", 2}}, wantCap: 2},
+		{name: "essay, below the recommendation", question: essay, answer: englishWords(200), want: []finding{{"word_count", -1}}, wantCap: -1},
+		{name: "essay, long enough", question: essay, answer: englishWords(300), wantCap: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PrecheckAnswer(tt.question, tt.answer)
+			var got []finding
+			for _, f := range result.Findings {
+				limit := -1.0
+				if f.ScoreCap != nil {
+					limit = *f.ScoreCap
+					if f.Severity != model.PrecheckHard {
+						t.Errorf("finding %s has a cap but severity %q", f.Rule, f.Severity)
+					}
+				} else if f.Severity != model.PrecheckAdvisory {
+					t.Errorf("finding %s has no cap but severity %q", f.Rule, f.Severity)
+				}
+				got = append(got, finding{f.Rule, limit})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("findings = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("findings = %v, want %v", got, tt.want)
+				}
+			}
+			gotCap := -1.0
+			if result.ScoreCap != nil {
+				gotCap = *result.ScoreCap
+			}
+			if gotCap != tt.wantCap {
+				t.Errorf("cap = %v, want %v", gotCap, tt.wantCap)
+			}
+			if result.Blocking() != (tt.wantCap == 0) {
+				t.Errorf("Blocking() = %v with cap %v", result.Blocking(), gotCap)
+			}
+		})
+	}
+}
+
+func TestPrecheckResultApply(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+	result := &PrecheckResult{}
+	result.add("given_words", "The answer does not use the given word(s) \"sit\" in any form.", score(1))
+	result.add("word_count", "Advisory only.", nil)
+
+	eval := &LLMEvaluation{Score: score(2.5), Summary: "Good sentence. "}
+	result.Apply(eval)
+	if *eval.Score != 1 || eval.UncappedScore == nil || *eval.UncappedScore != 2.5 {
+		t.Errorf("capped evaluation = score %v, uncapped %v; want 1 and 2.5", *eval.Score, eval.UncappedScore)
+	}
+	if !strings.Contains(eval.Summary, "capped at 1.0") || !strings.Contains(eval.Summary, `"sit"`) || strings.Contains(eval.Summary, "Advisory only.") {
+		t.Errorf("summary does not explain the cap with the hard findings only: %q", eval.Summary)
+	}
+	if len(eval.Prechecks) != 2 {
+		t.Errorf("evaluation records %d findings, want 2", len(eval.Prechecks))
+	}
+
+	low := &LLMEvaluation{Score: score(0.5), Summary: "Weak."}
+	result.Apply(low)
+	if *low.Score != 0.5 || low.UncappedScore != nil || low.Summary != "Weak." {
+		t.Errorf("score under the cap was changed: %v, uncapped %v, summary %q", *low.Score, low.UncappedScore, low.Summary)
+	}
+}
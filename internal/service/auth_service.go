@@ -0,0 +1,191 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmailAlreadyRegistered = errors.New("email is already registered")
+	ErrInvalidCredentials     = errors.New("invalid email or password")
+	ErrInvalidToken           = errors.New("invalid or expired token")
+	ErrUserNotFound           = errors.New("user not found")
+)
+
+// AuthClaims is the JWT payload issued to authenticated users. The role in the token is only
+// informative for clients: Authenticate replaces it with the stored role, so a role change
+// takes effect on the user's next request.
+type AuthClaims struct {
+	UserID uint   `json:"uid"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+type AuthService interface {
+	Register(req dto.RegisterDTO) (*dto.AuthResponseDTO, error)
+	Login(req dto.LoginDTO) (*dto.AuthResponseDTO, error)
+	ParseToken(tokenString string) (*AuthClaims, error)
+	// Authenticate validates the token and loads the user's current role. Tokens of deleted users are rejected.
+	Authenticate(tokenString string) (*AuthClaims, error)
+	GetProfile(userID uint) (*dto.UserResponseDTO, error)
+}
+
+type authService struct {
+	userRepo repository.UserRepository
+	cfg      *config.Config
+}
+
+func NewAuthService(userRepo repository.UserRepository, cfg *config.Config) (AuthService, error) {
+	if cfg.JWT.Secret == "" {
+		return nil, fmt.Errorf("JWT_SECRET must be set to issue and verify access tokens")
+	}
+	if cfg.JWT.ExpiresIn <= 0 {
+		return nil, fmt.Errorf("JWT_EXPIRES_IN must be a positive duration, got %s", cfg.JWT.ExpiresIn)
+	}
+	return &authService{userRepo: userRepo, cfg: cfg}, nil
+}
+
+func (s *authService) Register(req dto.RegisterDTO) (*dto.AuthResponseDTO, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	if _, err := s.userRepo.FindByEmail(email); err == nil {
+		return nil, ErrEmailAlreadyRegistered
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error().Err(err).Msg("Register: Failed to check for existing user")
+		return nil, fmt.Errorf("database error checking email: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := model.User{
+		Email:        email,
+		PasswordHash: string(hash),
+		FullName:     strings.TrimSpace(req.FullName),
+		Role:         model.RoleLearner, // Self-registration never grants elevated roles
+	}
+	if err := s.userRepo.Create(&user); err != nil {
+		if repository.IsUniqueViolation(err) {
+			return nil, ErrEmailAlreadyRegistered // A concurrent signup with the same email won
+		}
+		log.Error().Err(err).Msg("Register: Failed to create user in database")
+		return nil, fmt.Errorf("database error creating user: %w", err)
+	}
+	log.Info().Uint("userID", user.ID).Msg("Register: New user account created")
+
+	return s.issueToken(&user)
+}
+
+func (s *authService) Login(req dto.LoginDTO) (*dto.AuthResponseDTO, error) {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		log.Error().Err(err).Msg("Login: Failed to look up user")
+		return nil, fmt.Errorf("database error looking up user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.issueToken(user)
+}
+
+// ParseToken verifies the signature, issuer and expiry of an access token and returns its claims.
+func (s *authService) ParseToken(tokenString string) (*AuthClaims, error) {
+	claims := &AuthClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.cfg.JWT.Secret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.cfg.JWT.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !token.Valid || claims.UserID == 0 || !model.IsValidRole(claims.Role) {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (s *authService) Authenticate(tokenString string) (*AuthClaims, error) {
+	claims, err := s.ParseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, fmt.Errorf("database error loading user %d: %w", claims.UserID, err)
+	}
+	claims.Role = user.Role
+	claims.Email = user.Email
+	return claims, nil
+}
+
+func (s *authService) GetProfile(userID uint) (*dto.UserResponseDTO, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("database error fetching user %d: %w", userID, err)
+	}
+
+	var resp dto.UserResponseDTO
+	if err := copier.Copy(&resp, user); err != nil {
+		return nil, fmt.Errorf("error preparing user response: %w", err)
+	}
+	return &resp, nil
+}
+
+func (s *authService) issueToken(user *model.User) (*dto.AuthResponseDTO, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.JWT.ExpiresIn)
+
+	claims := AuthClaims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			Issuer:    s.cfg.JWT.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.JWT.Secret))
+	if err != nil {
+		log.Error().Err(err).Uint("userID", user.ID).Msg("Failed to sign access token")
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	resp := &dto.AuthResponseDTO{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresAt:   expiresAt,
+	}
+	if err := copier.Copy(&resp.User, user); err != nil {
+		return nil, fmt.Errorf("error preparing user response: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// DashboardService builds a learner's progress overview across all tests.
+type DashboardService interface {
+	GetDashboard(userID uint) (*dto.DashboardDTO, error)
+}
+
+type dashboardService struct {
+	dashboardRepo  repository.DashboardRepository
+	scoreConverter ScoreConverterService
+}
+
+func NewDashboardService(dashboardRepo repository.DashboardRepository, scoreConverter ScoreConverterService) DashboardService {
+	return &dashboardService{dashboardRepo: dashboardRepo, scoreConverter: scoreConverter}
+}
+
+func (s *dashboardService) GetDashboard(userID uint) (*dto.DashboardDTO, error) {
+	resp := &dto.DashboardDTO{
+		ScoreHistory:  []dto.ScoreHistoryPointDTO{},
+		QuestionTypes: []dto.QuestionTypeAverageDTO{},
+		Criteria:      []dto.CriterionAverageDTO{},
+	}
+
+	if err := s.fillAttemptCounts(userID, resp); err != nil {
+		return nil, err
+	}
+	if err := s.fillScoreHistory(userID, resp); err != nil {
+		return nil, err
+	}
+
+	typeRows, err := s.dashboardRepo.AverageByQuestionType(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error averaging scores by question type: %w", err)
+	}
+	for _, row := range typeRows {
+		average := dto.QuestionTypeAverageDTO{
+			Type:           row.Type,
+			Answers:        row.Answers,
+			AverageScore:   roundTo2(row.AverageScore),
+			MaxScore:       row.AverageMax,
+			AveragePercent: roundTo2(row.AveragePercent),
+		}
+		resp.QuestionTypes = append(resp.QuestionTypes, average)
+		if resp.WeakestQuestionType == nil || average.AveragePercent < resp.WeakestQuestionType.AveragePercent {
+			weakest := average
+			resp.WeakestQuestionType = &weakest
+		}
+	}
+
+	criterionRows, err := s.dashboardRepo.AverageByCriterion(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error averaging scores by criterion: %w", err)
+	}
+	for _, row := range criterionRows {
+		resp.Criteria = append(resp.Criteria, dto.CriterionAverageDTO{
+			Criterion:      row.Criterion,
+			Scores:         row.Scores,
+			AverageScore:   roundTo2(row.AverageScore),
+			AveragePercent: roundTo2(row.AveragePercent),
+		})
+	}
+
+	streaks, err := s.dashboardRepo.ActivityStreaks(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error computing activity streaks: %w", err)
+	}
+	resp.Streak = streakFromRuns(streaks, time.Now().UTC())
+
+	return resp, nil
+}
+
+func (s *dashboardService) fillAttemptCounts(userID uint, resp *dto.DashboardDTO) error {
+	counts, err := s.dashboardRepo.CountAttemptsByStatus(userID)
+	if err != nil {
+		return fmt.Errorf("error counting attempts: %w", err)
+	}
+	for _, c := range counts {
+		switch c.Status {
+		case "in_progress":
+			resp.Attempts.InProgress += c.Count
+			continue
+		case "expired":
+			continue // Timed out without a single answer
+		case "completed", "completed_with_errors":
+			resp.Attempts.Completed += c.Count
+		case "pending", "scoring":
+			resp.Attempts.BeingScored += c.Count
+		case "error":
+			resp.Attempts.Failed += c.Count
+		}
+		resp.Attempts.Submitted += c.Count
+	}
+
+	distinctTests, err := s.dashboardRepo.CountDistinctTests(userID)
+	if err != nil {
+		return fmt.Errorf("error counting tests taken: %w", err)
+	}
+	resp.Attempts.DistinctTests = int(distinctTests)
+	return nil
+}
+
+func (s *dashboardService) fillScoreHistory(userID uint, resp *dto.DashboardDTO) error {
+	rows, err := s.dashboardRepo.ScoreTimeline(userID)
+	if err != nil {
+		return fmt.Errorf("error loading score history: %w", err)
+	}
+
+	var scaledSum float64
+	var scaledCount int
+	for _, row := range rows {
+		point := dto.ScoreHistoryPointDTO{
+			AttemptID:     row.AttemptID,
+			TestID:        row.TestID,
+			TestTitle:     row.TestTitle,
+			SubmittedAt:   row.SubmittedAt,
+			TotalRawScore: row.TotalScore,
+		}
+		// Attempts scored before conversion tables were versioned have no stored scaled score.
+		attempt := model.TestAttempt{ID: row.AttemptID, TotalScore: row.TotalScore, ScaledScore: row.ScaledScore, ScoreTableVersion: row.ScoreTableVersion}
+		scaled, errScale := s.scoreConverter.ScaledScoreOf(&attempt)
+		if errScale != nil {
+			log.Warn().Err(errScale).Uint("attemptID", row.AttemptID).Msg("GetDashboard: Failed to scale score.")
+		} else if scaled != nil {
+			point.ScaledScore = &scaled.Score
+			point.ScoreTableVersion = &scaled.TableVersion
+			scaledSum += scaled.Score
+			scaledCount++
+			if resp.BestScaledScore == nil || scaled.Score > *resp.BestScaledScore {
+				best := scaled.Score
+				resp.BestScaledScore = &best
+			}
+		}
+		resp.ScoreHistory = append(resp.ScoreHistory, point)
+	}
+	if scaledCount > 0 {
+		average := roundTo2(scaledSum / float64(scaledCount))
+		resp.AverageScaledScore = &average
+	}
+	return nil
+}
+
+// streakFromRuns derives the streaks from runs of consecutive active days, most recent run first.
+// The current streak survives until the end of the day after the last active day.
+func streakFromRuns(runs []repository.ActivityStreakRow, now time.Time) dto.StreakDTO {
+	var streak dto.StreakDTO
+	if len(runs) == 0 {
+		return streak
+	}
+	for _, run := range runs {
+		if run.Days > streak.LongestDays {
+			streak.LongestDays = run.Days
+		}
+	}
+
+	latest := runs[0]
+	streak.LastActiveOn = latest.EndDay.Format("2006-01-02")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	lastDay := time.Date(latest.EndDay.Year(), latest.EndDay.Month(), latest.EndDay.Day(), 0, 0, 0, 0, time.UTC)
+	if !lastDay.Before(today.AddDate(0, 0, -1)) {
+		streak.CurrentDays = latest.Days
+	}
+	return streak
+}
+
+func roundTo2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/repository"
+)
+
+func TestStreakFromRuns(t *testing.T) {
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	run := func(start, end string, days int) repository.ActivityStreakRow {
+		return repository.ActivityStreakRow{StartDay: day(start), EndDay: day(end), Days: days}
+	}
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		runs        []repository.ActivityStreakRow
+		now         time.Time
+		wantCurrent int
+		wantLongest int
+		wantLast    string
+	}{
+		{name: "no activity", now: now},
+		{name: "active today", runs: []repository.ActivityStreakRow{run("2025-03-08", "2025-03-10", 3)}, now: now, wantCurrent: 3, wantLongest: 3, wantLast: "2025-03-10"},
+		{name: "active yesterday", runs: []repository.ActivityStreakRow{run("2025-03-09", "2025-03-09", 1)}, now: now, wantCurrent: 1, wantLongest: 1, wantLast: "2025-03-09"},
+		{name: "broken two days ago", runs: []repository.ActivityStreakRow{run("2025-03-01", "2025-03-08", 8)}, now: now, wantCurrent: 0, wantLongest: 8, wantLast: "2025-03-08"},
+		{
+			name:        "longest is an older run",
+			runs:        []repository.ActivityStreakRow{run("2025-03-09", "2025-03-10", 2), run("2025-02-01", "2025-02-10", 10), run("2025-01-01", "2025-01-03", 3)},
+			now:         now,
+			wantCurrent: 2, wantLongest: 10, wantLast: "2025-03-10",
+		},
+		{
+			name:        "first minute of the day keeps yesterday's streak",
+			runs:        []repository.ActivityStreakRow{run("2025-03-05", "2025-03-09", 5)},
+			now:         time.Date(2025, 3, 10, 0, 0, 1, 0, time.UTC),
+			wantCurrent: 5, wantLongest: 5, wantLast: "2025-03-09",
+		},
+		{
+			name:        "last minute of the next day still counts",
+			runs:        []repository.ActivityStreakRow{run("2025-03-05", "2025-03-09", 5)},
+			now:         time.Date(2025, 3, 10, 23, 59, 59, 0, time.UTC),
+			wantCurrent: 5, wantLongest: 5, wantLast: "2025-03-09",
+		},
+		{
+			name:        "across a month boundary",
+			runs:        []repository.ActivityStreakRow{run("2025-02-27", "2025-02-28", 2)},
+			now:         time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC),
+			wantCurrent: 2, wantLongest: 2, wantLast: "2025-02-28",
+		},
+		{
+			name:        "end day with a time of day",
+			runs:        []repository.ActivityStreakRow{{StartDay: day("2025-03-09"), EndDay: time.Date(2025, 3, 9, 22, 0, 0, 0, time.UTC), Days: 1}},
+			now:         now,
+			wantCurrent: 1, wantLongest: 1, wantLast: "2025-03-09",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := streakFromRuns(tt.runs, tt.now)
+			if got.CurrentDays != tt.wantCurrent || got.LongestDays != tt.wantLongest || got.LastActiveOn != tt.wantLast {
+				t.Errorf("streak = current %d, longest %d, last %q; want %d, %d, %q",
+					got.CurrentDays, got.LongestDays, got.LastActiveOn, tt.wantCurrent, tt.wantLongest, tt.wantLast)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	LatePolicyReject = "reject"
+	LatePolicyFlag   = "flag"
+)
+
+var (
+	ErrAttemptNotInProgress = errors.New("test attempt is not in progress")
+	ErrPartDeadlinePassed   = errors.New("the deadline for this part has passed")
+	ErrNoAnswersSaved       = errors.New("no answers have been saved for this attempt")
+	ErrStaleAnswer          = errors.New("answer was saved from another session")
+)
+
+// ExamService runs attempts that are answered over time instead of posted in one request.
+// Answers are saved one at a time (autosave) with a revision counter, so a stale tab or device
+// cannot overwrite newer text, and the attempt can be resumed anywhere until it is submitted.
+// Timed attempts additionally get per-part deadlines and are submitted automatically when the
+// last deadline passes.
+type ExamService interface {
+	StartAttempt(testID uint, userID uint, timed bool) (*dto.TestAttemptDetailDTO, error)
+	GetInProgressAttempt(testID uint, userID uint) (*dto.TestAttemptDetailDTO, error)
+	SaveAnswer(attemptID uint, questionID uint, userID uint, req dto.AnswerSaveDTO) (*dto.AnswerResponseDTO, error)
+	SubmitAttempt(attemptID uint, userID uint) (*dto.TestAttemptDetailDTO, error)
+	// AutoSubmitExpired submits every in-progress attempt whose time is up and returns how many it processed.
+	AutoSubmitExpired(ctx context.Context) (int, error)
+}
+
+type examService struct {
+	testRepo          repository.TestRepository
+	testAttemptRepo   repository.TestAttemptRepository
+	answerRepo        repository.AnswerRepository
+	submissionService TestSubmissionService
+	cfg               config.Exam
+	db                *gorm.DB
+}
+
+func NewExamService(
+	testRepo repository.TestRepository,
+	testAttemptRepo repository.TestAttemptRepository,
+	answerRepo repository.AnswerRepository,
+	submissionService TestSubmissionService,
+	cfg *config.Config,
+	db *gorm.DB,
+) ExamService {
+	examCfg := cfg.Exam
+	if examCfg.LatePolicy != LatePolicyFlag {
+		examCfg.LatePolicy = LatePolicyReject
+	}
+	return &examService{
+		testRepo:          testRepo,
+		testAttemptRepo:   testAttemptRepo,
+		answerRepo:        answerRepo,
+		submissionService: submissionService,
+		cfg:               examCfg,
+		db:                db,
+	}
+}
+
+// StartAttempt starts an attempt, with deadlines when timed. If the user already has one in progress
+// for this test, that attempt is returned instead so that reloading the page does not restart the clock.
+func (s *examService) StartAttempt(testID uint, userID uint, timed bool) (*dto.TestAttemptDetailDTO, error) {
+	test, err := s.testRepo.FindByIDWithQuestions(testID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
+		}
+		return nil, fmt.Errorf("error loading test %d: %w", testID, err)
+	}
+	if test.Status != model.TestStatusPublished {
+		return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
+	}
+	if len(test.Questions) == 0 {
+		return nil, fmt.Errorf("test ID %d has no questions, an attempt cannot be started", testID)
+	}
+
+	existing, err := s.testAttemptRepo.FindInProgressByTestAndUser(testID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for an attempt in progress: %w", err)
+	}
+	if existing != nil {
+		return s.submissionService.GetTestAttemptDetails(existing.ID, userID)
+	}
+
+	now := time.Now()
+	attempt := model.TestAttempt{
+		TestID:    testID,
+		UserID:    &userID,
+		Status:    "in_progress",
+		StartedAt: &now,
+	}
+	if timed {
+		part1 := now.Add(s.cfg.Part1Duration)
+		part2 := part1.Add(s.cfg.Part2Duration)
+		part3 := part2.Add(s.cfg.Part3Duration)
+		attempt.Part1DeadlineAt = &part1
+		attempt.Part2DeadlineAt = &part2
+		attempt.Part3DeadlineAt = &part3
+	}
+	if err := s.testAttemptRepo.Create(&attempt); err != nil {
+		if repository.IsUniqueViolation(err) {
+			// A concurrent request started the attempt first; the unique index keeps it the only one.
+			if existing, errFind := s.testAttemptRepo.FindInProgressByTestAndUser(testID, userID); errFind == nil && existing != nil {
+				return s.submissionService.GetTestAttemptDetails(existing.ID, userID)
+			}
+		}
+		log.Error().Err(err).Uint("testID", testID).Msg("StartAttempt: Failed to create attempt")
+		return nil, fmt.Errorf("failed to start test attempt: %w", err)
+	}
+	log.Info().Uint("attemptID", attempt.ID).Uint("userID", userID).Bool("timed", timed).Msg("StartAttempt: Attempt started.")
+	return s.submissionService.GetTestAttemptDetails(attempt.ID, userID)
+}
+
+// GetInProgressAttempt returns the user's unsubmitted attempt for a test with all saved answers,
+// so that it can be resumed on any device.
+func (s *examService) GetInProgressAttempt(testID uint, userID uint) (*dto.TestAttemptDetailDTO, error) {
+	attempt, err := s.testAttemptRepo.FindInProgressByTestAndUser(testID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading attempt in progress: %w", err)
+	}
+	if attempt == nil {
+		return nil, fmt.Errorf("%w: no attempt in progress for test %d", ErrAttemptNotFound, testID)
+	}
+	return s.submissionService.GetTestAttemptDetails(attempt.ID, userID)
+}
+
+// SaveAnswer creates or overwrites the answer to one question of an in-progress attempt.
+// req.Revision must be the revision the client last received (0 for a new answer); otherwise
+// ErrStaleAnswer is returned and nothing is written.
+// Answers saved after their part's deadline are rejected or flagged, depending on EXAM_LATE_POLICY.
+func (s *examService) SaveAnswer(attemptID uint, questionID uint, userID uint, req dto.AnswerSaveDTO) (*dto.AnswerResponseDTO, error) {
+	attempt, err := s.findOwnAttempt(attemptID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != "in_progress" {
+		return nil, fmt.Errorf("%w (status %q)", ErrAttemptNotInProgress, attempt.Status)
+	}
+
+	var question *model.Question
+	for i := range attempt.Test.Questions {
+		if attempt.Test.Questions[i].ID == questionID {
+			question = &attempt.Test.Questions[i]
+			break
+		}
+	}
+	if question == nil {
+		return nil, fmt.Errorf("%w with ID %d in test %d", ErrQuestionNotFound, questionID, attempt.TestID)
+	}
+
+	now := time.Now()
+	late, err := s.checkPartDeadline(attempt, question.OrderInTest, now)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := &model.Answer{
+		TestAttemptID:      attempt.ID,
+		QuestionID:         question.ID,
+		QuestionRevisionID: question.CurrentRevisionID, // The content on screen when this text was written
+		UserAnswer:         *req.UserAnswer,
+		ScoringStatus:      "pending",
+		SavedAt:            &now,
+		Late:               late,
+	}
+	if err := s.answerRepo.SaveDraft(answer, req.Revision); err != nil {
+		if errors.Is(err, repository.ErrStaleRevision) {
+			return nil, fmt.Errorf("%w: question %d is at revision %d, not %d; reload the attempt", ErrStaleAnswer, question.ID, answer.Revision, req.Revision)
+		}
+		if errors.Is(err, repository.ErrAttemptNotInProgress) {
+			return nil, fmt.Errorf("%w: it was submitted or expired while the answer was being saved", ErrAttemptNotInProgress)
+		}
+		log.Error().Err(err).Uint("attemptID", attemptID).Uint("questionID", questionID).Msg("SaveAnswer: Failed to save answer")
+		return nil, fmt.Errorf("database error saving answer: %w", err)
+	}
+
+	var resp dto.AnswerResponseDTO
+	if err := copier.Copy(&resp, answer); err != nil {
+		return nil, fmt.Errorf("error preparing response data: %w", err)
+	}
+	return &resp, nil
+}
+
+func (s *examService) SubmitAttempt(attemptID uint, userID uint) (*dto.TestAttemptDetailDTO, error) {
+	attempt, err := s.findOwnAttempt(attemptID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != "in_progress" {
+		return nil, fmt.Errorf("%w (status %q)", ErrAttemptNotInProgress, attempt.Status)
+	}
+	if len(attempt.Answers) == 0 {
+		return nil, ErrNoAnswersSaved
+	}
+	if err := s.submit(attempt.ID, false); err != nil {
+		return nil, err
+	}
+	return s.submissionService.GetTestAttemptDetails(attempt.ID, userID)
+}
+
+func (s *examService) AutoSubmitExpired(ctx context.Context) (int, error) {
+	attempts, err := s.testAttemptRepo.FindExpiredInProgress(time.Now().Add(-s.cfg.GracePeriod), 50)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired attempts: %w", err)
+	}
+
+	processed := 0
+	for _, attempt := range attempts {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.submit(attempt.ID, true); err != nil {
+			if errors.Is(err, ErrAttemptNotInProgress) {
+				continue // Submitted by the learner, or by another instance, in the meantime
+			}
+			log.Error().Err(err).Uint("attemptID", attempt.ID).Msg("AutoSubmitExpired: Failed to auto-submit attempt")
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// submit moves an in-progress attempt to "pending" and queues its scoring job, in one transaction.
+// The status check in the UPDATE makes concurrent submits (learner and sweeper) safe.
+// An attempt that expires without a single saved answer is marked "expired" instead.
+func (s *examService) submit(attemptID uint, auto bool) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the attempt before counting its answers, so a save cannot slip in between (see SaveDraft).
+		var attempt model.TestAttempt
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&attempt, attemptID).Error; err != nil {
+			return err
+		}
+		if attempt.Status != "in_progress" {
+			return ErrAttemptNotInProgress
+		}
+
+		var answerCount int64
+		if err := tx.Model(&model.Answer{}).Where("test_attempt_id = ?", attemptID).Count(&answerCount).Error; err != nil {
+			return err
+		}
+
+		status := "pending"
+		if answerCount == 0 {
+			status = "expired"
+		}
+		result := tx.Model(&model.TestAttempt{}).
+			Where("id = ? AND status = ?", attemptID, "in_progress").
+			Updates(map[string]interface{}{
+				"status":         status,
+				"submitted_at":   time.Now(),
+				"auto_submitted": auto,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAttemptNotInProgress
+		}
+		if answerCount == 0 {
+			log.Info().Uint("attemptID", attemptID).Msg("Exam: Attempt expired without answers.")
+			return nil
+		}
+
+		job := model.ScoringJob{
+			TestAttemptID: attemptID,
+			Status:        model.ScoringJobQueued,
+			RunAfter:      time.Now(),
+		}
+		if err := tx.Create(&job).Error; err != nil {
+			return fmt.Errorf("failed to queue scoring job: %w", err)
+		}
+		log.Info().Uint("attemptID", attemptID).Bool("auto", auto).Int64("answerCount", answerCount).Msg("Exam: Attempt submitted and queued for scoring.")
+		return nil
+	})
+}
+
+func (s *examService) findOwnAttempt(attemptID uint, userID uint) (*model.TestAttempt, error) {
+	attempt, err := s.testAttemptRepo.FindByIDWithDetails(attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrAttemptNotFound, attemptID)
+		}
+		return nil, fmt.Errorf("error loading test attempt %d: %w", attemptID, err)
+	}
+	if attempt.UserID == nil || *attempt.UserID != userID {
+		return nil, ErrAttemptAccessDenied
+	}
+	return attempt, nil
+}
+
+// checkPartDeadline reports whether an answer to the question at orderInTest saved at now is late,
+// that is after its part's deadline plus the grace period. Under the reject policy a late answer is an error.
+func (s *examService) checkPartDeadline(attempt *model.TestAttempt, orderInTest int, now time.Time) (bool, error) {
+	deadline := partDeadline(attempt, orderInTest)
+	if deadline == nil || !now.After(deadline.Add(s.cfg.GracePeriod)) {
+		return false, nil
+	}
+	if s.cfg.LatePolicy == LatePolicyReject {
+		return false, fmt.Errorf("%w (question %d, deadline %s)", ErrPartDeadlinePassed, orderInTest, deadline.Format(time.RFC3339))
+	}
+	return true, nil
+}
+
+// partDeadline returns the deadline of the part a question belongs to, or nil for untimed attempts.
+func partDeadline(attempt *model.TestAttempt, orderInTest int) *time.Time {
+	switch {
+	case orderInTest <= 5:
+		return attempt.Part1DeadlineAt
+	case orderInTest <= 7:
+		return attempt.Part2DeadlineAt
+	default:
+		return attempt.Part3DeadlineAt
+	}
+}
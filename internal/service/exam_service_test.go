@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+func TestPartDeadline(t *testing.T) {
+	start := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	part1, part2, part3 := start.Add(10*time.Minute), start.Add(30*time.Minute), start.Add(60*time.Minute)
+	timed := &model.TestAttempt{Part1DeadlineAt: &part1, Part2DeadlineAt: &part2, Part3DeadlineAt: &part3}
+
+	tests := []struct {
+		order int
+		want  time.Time
+	}{
+		{order: 1, want: part1},
+		{order: 5, want: part1},
+		{order: 6, want: part2},
+		{order: 7, want: part2},
+		{order: 8, want: part3},
+	}
+	for _, tt := range tests {
+		if got := partDeadline(timed, tt.order); got == nil || !got.Equal(tt.want) {
+			t.Errorf("partDeadline(question %d) = %v, want %v", tt.order, got, tt.want)
+		}
+		if got := partDeadline(&model.TestAttempt{}, tt.order); got != nil {
+			t.Errorf("partDeadline(question %d) of an untimed attempt = %v, want nil", tt.order, got)
+		}
+	}
+}
+
+func TestCheckPartDeadline(t *testing.T) {
+	const grace = 5 * time.Second
+	start := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+	part1, part2, part3 := start.Add(10*time.Minute), start.Add(30*time.Minute), start.Add(60*time.Minute)
+	attempt := &model.TestAttempt{Part1DeadlineAt: &part1, Part2DeadlineAt: &part2, Part3DeadlineAt: &part3}
+
+	tests := []struct {
+		name     string
+		order    int
+		savedAt  time.Time
+		wantLate bool
+	}{
+		{name: "part 1 before its deadline", order: 5, savedAt: part1.Add(-time.Second)},
+		{name: "part 1 at its deadline", order: 5, savedAt: part1},
+		{name: "part 1 at the end of the grace period", order: 5, savedAt: part1.Add(grace)},
+		{name: "part 1 after the grace period", order: 5, savedAt: part1.Add(grace + time.Nanosecond), wantLate: true},
+		{name: "part 2 while part 1 is over", order: 6, savedAt: part1.Add(time.Minute)},
+		{name: "part 2 after its deadline", order: 7, savedAt: part2.Add(grace + time.Second), wantLate: true},
+		{name: "part 3 while part 2 is over", order: 8, savedAt: part2.Add(time.Minute)},
+		{name: "part 3 after the end of the exam", order: 8, savedAt: part3.Add(grace + time.Second), wantLate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := &examService{cfg: config.Exam{LatePolicy: LatePolicyFlag, GracePeriod: grace}}
+			late, err := flag.checkPartDeadline(attempt, tt.order, tt.savedAt)
+			if err != nil || late != tt.wantLate {
+				t.Errorf("flag policy: late = %v, %v; want %v", late, err, tt.wantLate)
+			}
+
+			reject := &examService{cfg: config.Exam{LatePolicy: LatePolicyReject, GracePeriod: grace}}
+			late, err = reject.checkPartDeadline(attempt, tt.order, tt.savedAt)
+			if late || errors.Is(err, ErrPartDeadlinePassed) != tt.wantLate {
+				t.Errorf("reject policy: late = %v, error = %v; want an error %v", late, err, tt.wantLate)
+			}
+		})
+	}
+
+	untimed := &examService{cfg: config.Exam{LatePolicy: LatePolicyReject, GracePeriod: grace}}
+	if late, err := untimed.checkPartDeadline(&model.TestAttempt{}, 8, part3.Add(time.Hour)); late || err != nil {
+		t.Errorf("untimed attempt: late = %v, %v; want neither", late, err)
+	}
+}
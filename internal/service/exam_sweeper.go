@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/rs/zerolog/log"
+)
+
+// ExamDeadlineSweeper periodically auto-submits timed attempts whose last deadline has passed,
+// so answers saved before the clock ran out are scored even if the learner never submits.
+type ExamDeadlineSweeper struct {
+	examService ExamService
+	interval    time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewExamDeadlineSweeper(examService ExamService, cfg *config.Config) *ExamDeadlineSweeper {
+	interval := cfg.Exam.SweepInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &ExamDeadlineSweeper{examService: examService, interval: interval}
+}
+
+// Start launches the sweeper loop. It returns immediately.
+func (s *ExamDeadlineSweeper) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := s.examService.AutoSubmitExpired(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("Exam sweeper: Failed to auto-submit expired attempts")
+				} else if count > 0 {
+					log.Info().Int("count", count).Msg("Exam sweeper: Auto-submitted expired attempts")
+				}
+			}
+		}
+	}()
+	log.Info().Dur("interval", s.interval).Msg("Exam deadline sweeper started")
+}
+
+// Stop ends the sweeper loop and waits for the current sweep until ctx expires.
+func (s *ExamDeadlineSweeper) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
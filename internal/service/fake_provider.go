@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+// fakeProvider returns a deterministic evaluation derived only from the answer text,
+// so the whole scoring pipeline can run offline (local development, demos, CI).
+type fakeProvider struct{}
+
+func newFakeProvider() LLMProvider {
+	return &fakeProvider{}
+}
+
+func (p *fakeProvider) Name() string  { return "fake" }
+func (p *fakeProvider) Model() string { return "fake-deterministic" }
+
+// Expected word counts for a full-marks answer, per question type.
+var fakeTargetWordCount = map[string]int{
+	"sentence_picture": 8,
+	"email_response":   100,
+	"opinion_essay":    300,
+}
+
+func (p *fakeProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	words := len(strings.Fields(req.Task.UserAnswer))
+	target := fakeTargetWordCount[req.Task.QuestionType]
+	if target == 0 {
+		target = 50
+	}
+
+	// Score grows linearly with length up to the target, rounded to the nearest 0.5.
+	ratio := math.Min(float64(words)/float64(target), 1.0)
+	score := math.Round(ratio*req.Task.MaxScore*2) / 2
+
+	eval := LLMEvaluation{
+		Score:     &score,
+		Summary:   fmt.Sprintf("This evaluation was produced by the offline fake scorer, not a language model. Your answer has %d words; about %d words are expected for this task.", words, target),
+		Strengths: []string{},
+		Errors:    []model.AnswerError{},
+	}
+	for _, criterion := range rubricCriteria[req.Task.QuestionType] {
+		eval.Criteria = append(eval.Criteria, CriterionEvaluation{
+			Criterion: criterion,
+			Score:     score,
+			MaxScore:  req.Task.MaxScore,
+			Comment:   "Derived from answer length only.",
+		})
+	}
+	if words > 0 {
+		eval.Strengths = append(eval.Strengths, "An answer was provided.")
+	}
+
+	text, err := json.Marshal(eval)
+	if err != nil {
+		return nil, fmt.Errorf("fake provider failed to encode evaluation: %w", err)
+	}
+	return &LLMResponse{Text: string(text)}, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/lshigami/Ringtails/config"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/option"
+)
+
+const defaultGeminiModel = "gemini-2.0-flash-lite"
+
+type geminiProvider struct {
+	client    *genai.Client
+	modelName string
+}
+
+func newGeminiProvider(cfg *config.Config) (LLMProvider, error) {
+	modelName := cfg.LLM.Model
+	if modelName == "" {
+		modelName = defaultGeminiModel
+	}
+	if cfg.GeminiApiKey == "" {
+		log.Warn().Msg("GEMINI_API_KEY is not set. Gemini provider will be non-functional.")
+		return &geminiProvider{client: nil, modelName: modelName}, nil
+	}
+	ctx := context.Background()
+	client, err := genai.NewClient(ctx, option.WithAPIKey(cfg.GeminiApiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Gemini client: %w", err)
+	}
+	return &geminiProvider{client: client, modelName: modelName}, nil
+}
+
+func (p *geminiProvider) Name() string  { return "gemini" }
+func (p *geminiProvider) Model() string { return p.modelName }
+
+func (p *geminiProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("gemini client not initialized")
+	}
+
+	var parts []genai.Part
+	for _, img := range req.Images {
+		parts = append(parts, genai.ImageData(img.MIMEType, img.Data))
+	}
+	parts = append(parts, genai.Text(req.Prompt))
+
+	// GenerativeModel carries per-request settings, so build one per call instead of sharing it across goroutines.
+	model := p.client.GenerativeModel(p.modelName)
+	if req.JSON {
+		model.ResponseMIMEType = "application/json"
+	}
+
+	resp, err := model.GenerateContent(ctx, parts...)
+	if err != nil {
+		// The REST transport reports API errors with their HTTP status; keep it so retries can tell 429/503 from 400.
+		var httpErr interface{ HTTPCode() int }
+		if errors.As(err, &httpErr) && httpErr.HTTPCode() > 0 {
+			return nil, &LLMStatusError{Provider: "gemini", StatusCode: httpErr.HTTPCode(), Message: err.Error()}
+		}
+		return nil, fmt.Errorf("gemini API error: %w", err)
+	}
+
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("gemini returned no content")
+	}
+
+	fullResponseText := ""
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if txt, ok := part.(genai.Text); ok {
+			fullResponseText += string(txt)
+		}
+	}
+	if fullResponseText == "" {
+		return nil, fmt.Errorf("gemini returned no text content")
+	}
+	var usage LLMUsage
+	if resp.UsageMetadata != nil {
+		usage = LLMUsage{
+			PromptTokens:     int(resp.UsageMetadata.PromptTokenCount),
+			CompletionTokens: int(resp.UsageMetadata.CandidatesTokenCount),
+			TotalTokens:      int(resp.UsageMetadata.TotalTokenCount),
+		}
+	}
+	return &LLMResponse{Text: fullResponseText, Usage: usage}, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"container/list"
+	"sync"
+)
+
+// imageCache keeps recently used images in memory, keyed by content hash. The total size of
+// the cached data is bounded; the least recently used images are evicted first.
+type imageCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List // Front is the most recently used
+	items    map[string]*list.Element
+}
+
+type imageCacheEntry struct {
+	hash  string
+	image *StoredImage
+}
+
+func newImageCache(maxBytes int64) *imageCache {
+	return &imageCache{maxBytes: maxBytes, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (c *imageCache) get(hash string) (*StoredImage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.items[hash]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*imageCacheEntry).image, true
+}
+
+// add caches an image. Images larger than the whole cache are not cached.
+func (c *imageCache) add(hash string, image *StoredImage) {
+	size := int64(len(image.Data))
+	if size > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[hash]; ok {
+		c.order.MoveToFront(elem)
+		return // Same hash, same data
+	}
+	c.items[hash] = c.order.PushFront(&imageCacheEntry{hash: hash, image: image})
+	c.size += size
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*imageCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.items, entry.hash)
+		c.size -= int64(len(entry.image.Data))
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif" // Register decoders for image.Decode
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// imageInfo is what inspecting image data found out about it.
+type imageInfo struct {
+	MimeType string
+	Width    int // 0 when the format's dimensions are not read (HEIC/HEIF)
+	Height   int
+}
+
+// inspectImage checks that data is a complete image of a supported type. PNG, JPEG and GIF are
+// fully decoded, which catches truncated downloads; WebP and HEIC/HEIF, which the standard library
+// cannot decode, get a check of their container structure.
+func inspectImage(data []byte, name string) (*imageInfo, error) {
+	mimeType, err := detectImageMIMEType(data, name)
+	if err != nil {
+		return nil, err
+	}
+	info := &imageInfo{MimeType: mimeType}
+
+	switch mimeType {
+	case "image/png", "image/jpeg", "image/gif":
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode %s image: %w", mimeType, err)
+		}
+		info.Width, info.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	case "image/webp":
+		if info.Width, info.Height, err = webpDimensions(data); err != nil {
+			return nil, err
+		}
+	case "image/heic", "image/heif":
+		if len(data) < 12 || string(data[4:8]) != "ftyp" {
+			return nil, fmt.Errorf("cannot decode %s image: missing ftyp box", mimeType)
+		}
+	}
+	if info.MimeType != "image/heic" && info.MimeType != "image/heif" && (info.Width == 0 || info.Height == 0) {
+		return nil, fmt.Errorf("image has no pixels (%dx%d)", info.Width, info.Height)
+	}
+	return info, nil
+}
+
+// webpDimensions validates the RIFF container of a WebP image and reads its size from the first chunk.
+func webpDimensions(data []byte) (int, int, error) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, fmt.Errorf("cannot decode image/webp image: invalid RIFF header")
+	}
+	if riffSize := int(binary.LittleEndian.Uint32(data[4:8])) + 8; riffSize > len(data) {
+		return 0, 0, fmt.Errorf("cannot decode image/webp image: truncated (%d of %d bytes)", len(data), riffSize)
+	}
+
+	chunk := data[20:]
+	switch string(data[12:16]) {
+	case "VP8X": // Extended format: 24-bit canvas size minus one
+		width := int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16
+		height := int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16
+		return width + 1, height + 1, nil
+	case "VP8L": // Lossless: signature byte, then 14-bit width and height minus one
+		if chunk[0] != 0x2f {
+			return 0, 0, fmt.Errorf("cannot decode image/webp image: invalid VP8L signature")
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:5])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, nil
+	case "VP8 ": // Lossy: frame tag, start code, then 14-bit width and height
+		if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+			return 0, 0, fmt.Errorf("cannot decode image/webp image: invalid VP8 start code")
+		}
+		return int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3fff), int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3fff), nil
+	}
+	return 0, 0, fmt.Errorf("cannot decode image/webp image: unknown chunk %q", data[12:16])
+}
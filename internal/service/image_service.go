@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrImageNotFound = errors.New("image not found")
+	ErrInvalidImage  = errors.New("invalid image")
+	ErrImageTooLarge = errors.New("image is too large")
+)
+
+// storedImagePath is the API path images are served from. Question image URLs pointing at it
+// are read from storage directly instead of being downloaded.
+const storedImagePath = "/api/v1/images/"
+
+// supportedImageMIMETypes are the image types the AI providers accept, with the file extension used on export.
+var supportedImageMIMETypes = map[string]string{
+	"image/png": ".png", "image/jpeg": ".jpg", "image/webp": ".webp",
+	"image/gif": ".gif", "image/heic": ".heic", "image/heif": ".heif",
+}
+
+// StoredImage is the data of an image together with its type.
+type StoredImage struct {
+	Data     []byte
+	MimeType string
+}
+
+// ImageService stores uploaded images by content hash and reads them back for serving and scoring.
+// External images used by questions are checked when a test is created and copied to storage,
+// so scoring reads them from the cache or storage instead of downloading them for every answer.
+type ImageService interface {
+	// Check validates image data and returns what Upload would store, without storing it.
+	Check(filename string, data []byte) (*dto.ImageDTO, error)
+	Upload(ctx context.Context, uploadedByID *uint, filename string, data []byte) (*dto.ImageDTO, error)
+	Find(hash string) (*dto.ImageDTO, error)
+	Get(ctx context.Context, hash string) (*StoredImage, error)
+
+	// ValidateURL checks the image a question points at: an uploaded image must exist, an external
+	// one is downloaded and checked. With keep set, the result of the check is recorded and an
+	// external image that passes is copied to storage for scoring.
+	ValidateURL(ctx context.Context, imageURL string, keep bool) (*dto.ImageDTO, error)
+	// Load returns the image a question points at, preferring the cache and stored copies to downloading.
+	// An external URL is downloaded only if it was checked before or a question uses it.
+	Load(ctx context.Context, imageURL string) (*StoredImage, error)
+	// BrokenImageReport lists the question images that fail their check. With recheck set, every
+	// external image is downloaded again; otherwise the result of the last check is reported.
+	BrokenImageReport(ctx context.Context, recheck bool) (*dto.BrokenImageReportDTO, error)
+}
+
+// maxConcurrentImageChecks bounds the downloads of a broken image report.
+const maxConcurrentImageChecks = 4
+
+type imageService struct {
+	storage    ImageStorage
+	repo       repository.ImageRepository
+	cache      *imageCache
+	httpClient *http.Client
+	maxBytes   int64
+}
+
+func NewImageService(storage ImageStorage, repo repository.ImageRepository, cfg *config.Config) ImageService {
+	return &imageService{
+		storage:    storage,
+		repo:       repo,
+		cache:      newImageCache(cfg.Storage.CacheMaxBytes),
+		httpClient: newImageFetchClient(cfg.Storage.FetchTimeout),
+		maxBytes:   cfg.Storage.MaxImageBytes,
+	}
+}
+
+func (s *imageService) Check(filename string, data []byte) (*dto.ImageDTO, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImage)
+	}
+	if s.maxBytes > 0 && int64(len(data)) > s.maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, the limit is %d", ErrImageTooLarge, len(data), s.maxBytes)
+	}
+	info, err := inspectImage(data, filename)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	return &dto.ImageDTO{
+		Hash:         hash,
+		URL:          storedImagePath + hash,
+		MimeType:     info.MimeType,
+		Size:         int64(len(data)),
+		Width:        info.Width,
+		Height:       info.Height,
+		OriginalName: path.Base(filename),
+	}, nil
+}
+
+// Upload stores an image. Uploading data that is already stored returns the existing image.
+func (s *imageService) Upload(ctx context.Context, uploadedByID *uint, filename string, data []byte) (*dto.ImageDTO, error) {
+	checked, err := s.Check(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	if existing, err := s.Find(checked.Hash); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, ErrImageNotFound) {
+		return nil, err
+	}
+
+	// Data first, record second: a record always points at stored data, and a failed insert
+	// only leaves an object that the next upload of the same image reuses.
+	if err := s.storage.Put(ctx, checked.Hash, data, checked.MimeType); err != nil {
+		log.Error().Err(err).Str("hash", checked.Hash).Str("backend", s.storage.Name()).Msg("Upload: Failed to store image")
+		return nil, fmt.Errorf("error storing image: %w", err)
+	}
+	image := &model.Image{
+		Hash:         checked.Hash,
+		MimeType:     checked.MimeType,
+		Size:         checked.Size,
+		Width:        checked.Width,
+		Height:       checked.Height,
+		Backend:      s.storage.Name(),
+		OriginalName: checked.OriginalName,
+		UploadedByID: uploadedByID,
+	}
+	if err := s.repo.Create(image); err != nil {
+		return nil, fmt.Errorf("database error recording image: %w", err)
+	}
+	s.cache.add(image.Hash, &StoredImage{Data: data, MimeType: image.MimeType})
+	log.Info().Str("hash", image.Hash).Str("mimeType", image.MimeType).Int64("size", image.Size).Msg("Upload: Image stored.")
+	return toImageDTO(image), nil
+}
+
+func (s *imageService) Find(hash string) (*dto.ImageDTO, error) {
+	image, err := s.repo.FindByHash(hash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with hash %s", ErrImageNotFound, hash)
+		}
+		return nil, fmt.Errorf("error loading image %s: %w", hash, err)
+	}
+	return toImageDTO(image), nil
+}
+
+func (s *imageService) Get(ctx context.Context, hash string) (*StoredImage, error) {
+	if cached, ok := s.cache.get(hash); ok {
+		return cached, nil
+	}
+	image, err := s.Find(hash)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.storage.Get(ctx, hash)
+	if err != nil {
+		if errors.Is(err, ErrStoredObjectNotFound) {
+			return nil, fmt.Errorf("%w: image %s is recorded but missing from storage", ErrImageNotFound, hash)
+		}
+		return nil, fmt.Errorf("error reading image %s: %w", hash, err)
+	}
+	stored := &StoredImage{Data: data, MimeType: image.MimeType}
+	s.cache.add(hash, stored)
+	return stored, nil
+}
+
+func (s *imageService) ValidateURL(ctx context.Context, imageURL string, keep bool) (*dto.ImageDTO, error) {
+	if hash, ok := storedImageHash(imageURL); ok {
+		return s.Find(hash)
+	}
+	if strings.HasPrefix(imageURL, "data:") { // Embedded by imports made before uploads existed
+		data, _, err := parseImageDataURI(imageURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+		}
+		return s.Check("", data)
+	}
+	if !strings.HasPrefix(imageURL, "http://") && !strings.HasPrefix(imageURL, "https://") {
+		return nil, fmt.Errorf("%w: %q is neither an http(s) URL nor an uploaded image", ErrInvalidImage, imageURL)
+	}
+
+	checked, data, err := s.download(ctx, imageURL)
+	if !keep {
+		return checked, err
+	}
+	now := time.Now()
+	if err != nil {
+		if errRecord := s.repo.RecordSourceBroken(imageURL, err.Error(), now); errRecord != nil {
+			log.Error().Err(errRecord).Str("imageURL", imageURL).Msg("ValidateURL: Failed to record broken image")
+		}
+		return nil, err
+	}
+	stored, err := s.Upload(ctx, nil, imageURL, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RecordSourceOK(imageURL, stored.Hash, now); err != nil {
+		return nil, fmt.Errorf("database error recording image check: %w", err)
+	}
+	return stored, nil
+}
+
+func (s *imageService) Load(ctx context.Context, imageURL string) (*StoredImage, error) {
+	if hash, ok := storedImageHash(imageURL); ok {
+		return s.Get(ctx, hash)
+	}
+	if strings.HasPrefix(imageURL, "data:") {
+		data, mimeType, err := parseImageDataURI(imageURL)
+		if err != nil {
+			return nil, err
+		}
+		return &StoredImage{Data: data, MimeType: mimeType}, nil
+	}
+
+	source, err := s.repo.FindSourceByURL(imageURL)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error loading image source %s: %w", imageURL, err)
+	}
+	if err == nil && source.ImageHash != nil {
+		image, errGet := s.Get(ctx, *source.ImageHash)
+		if errGet == nil {
+			return image, nil
+		}
+		log.Warn().Err(errGet).Str("imageURL", imageURL).Msg("Load: Stored copy unavailable, downloading the image again.")
+	}
+
+	// No usable copy, e.g. a question created before images were validated: check and keep it now.
+	// Only URLs entered by an admin for a question qualify; anything else is refused without downloading it.
+	used, err := s.repo.IsQuestionImage(imageURL)
+	if err != nil {
+		return nil, fmt.Errorf("error checking the use of image %s: %w", imageURL, err)
+	}
+	if !used {
+		return nil, fmt.Errorf("%w: %s is not the image of any question", ErrInvalidImage, imageURL)
+	}
+	checked, err := s.ValidateURL(ctx, imageURL, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, checked.Hash)
+}
+
+func (s *imageService) BrokenImageReport(ctx context.Context, recheck bool) (*dto.BrokenImageReportDTO, error) {
+	usages, err := s.repo.FindImageUsages()
+	if err != nil {
+		return nil, fmt.Errorf("error listing question images: %w", err)
+	}
+	var urls []string
+	byURL := make(map[string]*dto.BrokenImageDTO)
+	for _, usage := range usages {
+		entry, ok := byURL[usage.ImageURL]
+		if !ok {
+			entry = &dto.BrokenImageDTO{ImageURL: usage.ImageURL, Questions: []dto.ImageUsageDTO{}}
+			byURL[usage.ImageURL] = entry
+			urls = append(urls, usage.ImageURL)
+		}
+		if usage.BankQuestionID != nil {
+			entry.BankQuestionIDs = append(entry.BankQuestionIDs, *usage.BankQuestionID)
+		} else if usage.TestID != nil && usage.QuestionID != nil {
+			entry.Questions = append(entry.Questions, dto.ImageUsageDTO{
+				TestID: *usage.TestID, TestTitle: usage.TestTitle, QuestionID: *usage.QuestionID, OrderInTest: usage.OrderInTest,
+			})
+		}
+	}
+
+	// Check every URL, downloading external images again only when asked to or never checked before.
+	sources, err := s.repo.FindSourcesByURLs(urls)
+	if err != nil {
+		return nil, fmt.Errorf("error loading image checks: %w", err)
+	}
+	sourceByURL := make(map[string]model.ImageSource, len(sources))
+	for _, source := range sources {
+		sourceByURL[source.URL] = source
+	}
+	failures := make([]error, len(urls))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxConcurrentImageChecks)
+	for i, imageURL := range urls {
+		source, checked := sourceByURL[imageURL]
+		if _, stored := storedImageHash(imageURL); !stored && checked && !recheck && strings.HasPrefix(imageURL, "http") {
+			if source.Status == model.ImageSourceBroken {
+				failures[i] = errors.New(source.Error)
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, imageURL string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			if hash, ok := storedImageHash(imageURL); ok && recheck {
+				_, failures[i] = s.Get(ctx, hash) // Also confirms the data is still in storage
+				return
+			}
+			_, failures[i] = s.ValidateURL(ctx, imageURL, true)
+		}(i, imageURL)
+	}
+	wg.Wait()
+
+	// Reload the checks written above to report their times and stored copies.
+	if sources, err = s.repo.FindSourcesByURLs(urls); err != nil {
+		return nil, fmt.Errorf("error loading image checks: %w", err)
+	}
+	for _, source := range sources {
+		sourceByURL[source.URL] = source
+	}
+	report := &dto.BrokenImageReportDTO{Rechecked: recheck, Checked: len(urls), Broken: []dto.BrokenImageDTO{}}
+	for i, imageURL := range urls {
+		if failures[i] == nil {
+			continue
+		}
+		entry := byURL[imageURL]
+		entry.Error = failures[i].Error()
+		if source, ok := sourceByURL[imageURL]; ok {
+			checkedAt := source.CheckedAt
+			entry.CheckedAt = &checkedAt
+			entry.LastOKAt = source.LastOKAt
+			entry.HasStoredCopy = source.ImageHash != nil
+		}
+		if strings.HasPrefix(entry.ImageURL, "data:") && len(entry.ImageURL) > 64 {
+			entry.ImageURL = entry.ImageURL[:64] + "..." // Embedded data is not useful in a report
+		}
+		report.Broken = append(report.Broken, *entry)
+	}
+	return report, nil
+}
+
+// download fetches an external image and checks it. The data is returned only if the check passed.
+func (s *imageService) download(ctx context.Context, imageURL string) (*dto.ImageDTO, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid URL %s: %v", ErrInvalidImage, imageURL, err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: cannot download %s: %v", ErrInvalidImage, imageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%w: downloading %s returned status %d", ErrInvalidImage, imageURL, resp.StatusCode)
+	}
+
+	limit := s.maxBytes
+	if limit <= 0 {
+		limit = maxBundleFileBytes
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: cannot read %s: %v", ErrInvalidImage, imageURL, err)
+	}
+	checked, err := s.Check(path.Base(req.URL.Path), data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", imageURL, err)
+	}
+	return checked, data, nil
+}
+
+// maxImageRedirects bounds the redirects followed when downloading an image.
+const maxImageRedirects = 5
+
+// nonPublicPrefixes are address ranges that are not reachable on the internet but are not covered by
+// the net/netip predicates used in isPublicAddr.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This network"
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+}
+
+// isPublicAddr reports whether ip is an address on the internet, as opposed to this host, the local
+// network or a cloud metadata service (169.254.169.254 is link-local).
+func isPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// newImageFetchClient returns the client that downloads question images. Image URLs are entered by
+// users, so the client only connects to public addresses: the check runs on the resolved address of
+// every connection, redirects included, so neither a redirect nor a DNS answer can point it inward.
+// Proxies are not used, since the check would then apply to the proxy instead of the image host.
+func newImageFetchClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("unexpected address %q: %w", address, err)
+			}
+			if !isPublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("refusing to connect to non-public address %s", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: checkImageRedirect,
+	}
+}
+
+// checkImageRedirect follows a limited number of redirects to http(s) URLs only. Hosts that are
+// literal non-public addresses are refused here already; the dialer checks resolved names.
+func checkImageRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxImageRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxImageRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("refusing to follow a redirect to %s", req.URL.Redacted())
+	}
+	if ip, err := netip.ParseAddr(req.URL.Hostname()); err == nil && !isPublicAddr(ip) {
+		return fmt.Errorf("refusing to follow a redirect to non-public address %s", ip)
+	}
+	return nil
+}
+
+// IsImageHash reports whether s has the form of an image hash.
+func IsImageHash(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil && strings.ToLower(s) == s
+}
+
+// storedImageHash returns the hash of an image URL served by this API, absolute or relative.
+func storedImageHash(imageURL string) (string, bool) {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return "", false
+	}
+	hash, ok := strings.CutPrefix(u.Path, storedImagePath)
+	if !ok || !IsImageHash(hash) {
+		return "", false
+	}
+	return hash, true
+}
+
+// detectImageMIMEType sniffs the type of image data, falling back to the file extension for
+// formats net/http does not recognise (HEIC/HEIF). The extension is never trusted for other types.
+func detectImageMIMEType(data []byte, name string) (string, error) {
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if _, ok := supportedImageMIMETypes[mimeType]; ok {
+		return mimeType, nil
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".heic":
+		return "image/heic", nil
+	case ".heif":
+		return "image/heif", nil
+	}
+	return "", fmt.Errorf("not a supported image (png, jpeg, webp, gif, heic or heif)")
+}
+
+func toImageDTO(image *model.Image) *dto.ImageDTO {
+	return &dto.ImageDTO{
+		Hash:         image.Hash,
+		URL:          storedImagePath + image.Hash,
+		MimeType:     image.MimeType,
+		Size:         image.Size,
+		Width:        image.Width,
+		Height:       image.Height,
+		OriginalName: image.OriginalName,
+		CreatedAt:    image.CreatedAt,
+	}
+}
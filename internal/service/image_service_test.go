@@ -0,0 +1,205 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"gorm.io/gorm"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":          true,
+		"8.8.8.8":                true,
+		"2606:4700::1111":        true,
+		"127.0.0.1":              false,
+		"127.1.2.3":              false,
+		"::1":                    false,
+		"10.0.0.5":               false,
+		"172.16.0.1":             false,
+		"192.168.1.1":            false,
+		"169.254.169.254":        false, // Cloud metadata service
+		"fe80::1":                false,
+		"fd00::1":                false,
+		"0.0.0.0":                false,
+		"0.1.2.3":                false,
+		"::":                     false,
+		"100.64.0.1":             false,
+		"224.0.0.1":              false,
+		"::ffff:127.0.0.1":       false, // IPv4-mapped loopback
+		"::ffff:169.254.169.254": false,
+	}
+	for addr, want := range tests {
+		if got := isPublicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestImageFetchClientRefusesLocalAddresses(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits.Add(1) }))
+	defer server.Close()
+
+	client := newImageFetchClient(5 * time.Second)
+	for _, target := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)} {
+		resp, err := client.Get(target)
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("GET %s succeeded", target)
+		}
+		if !strings.Contains(err.Error(), "non-public address") {
+			t.Errorf("GET %s: error = %v, want a refused address", target, err)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Errorf("the local server was reached %d time(s)", hits.Load())
+	}
+}
+
+func TestCheckImageRedirect(t *testing.T) {
+	previous := func(n int) []*http.Request {
+		via := make([]*http.Request, n)
+		for i := range via {
+			via[i] = &http.Request{}
+		}
+		return via
+	}
+	tests := []struct {
+		target  string
+		via     int
+		wantErr string
+	}{
+		{target: "https://cdn.example.com/a.png", via: 1},
+		{target: "http://cdn.example.com/a.png", via: 4},
+		{target: "https://cdn.example.com/a.png", via: 5, wantErr: "stopped after 5 redirects"},
+		{target: "file:///etc/passwd", via: 1, wantErr: "refusing to follow a redirect"},
+		{target: "gopher://cdn.example.com/", via: 1, wantErr: "refusing to follow a redirect"},
+		{target: "http://169.254.169.254/latest/meta-data/", via: 1, wantErr: "non-public address 169.254.169.254"},
+		{target: "http://[::1]:8080/", via: 1, wantErr: "non-public address ::1"},
+		{target: "http://10.1.2.3/", via: 1, wantErr: "non-public address 10.1.2.3"},
+	}
+	for _, tt := range tests {
+		target, _ := url.Parse(tt.target)
+		err := checkImageRedirect(&http.Request{URL: target}, previous(tt.via))
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("redirect to %s: unexpected error %v", tt.target, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("redirect to %s: error = %v, want %q", tt.target, err, tt.wantErr)
+		}
+	}
+}
+
+// memoryImageRepository keeps images and checks in memory. questionImages are the URLs used by questions.
+type memoryImageRepository struct {
+	repository.ImageRepository
+	images         map[string]*model.Image
+	sources        map[string]*model.ImageSource
+	questionImages map[string]bool
+}
+
+func newMemoryImageRepository(questionImages ...string) *memoryImageRepository {
+	repo := &memoryImageRepository{images: map[string]*model.Image{}, sources: map[string]*model.ImageSource{}, questionImages: map[string]bool{}}
+	for _, u := range questionImages {
+		repo.questionImages[u] = true
+	}
+	return repo
+}
+
+func (r *memoryImageRepository) Create(image *model.Image) error {
+	if _, ok := r.images[image.Hash]; !ok {
+		r.images[image.Hash] = image
+	}
+	return nil
+}
+
+func (r *memoryImageRepository) FindByHash(hash string) (*model.Image, error) {
+	if image, ok := r.images[hash]; ok {
+		return image, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryImageRepository) FindSourceByURL(url string) (*model.ImageSource, error) {
+	if source, ok := r.sources[url]; ok {
+		return source, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryImageRepository) RecordSourceOK(url string, hash string, checkedAt time.Time) error {
+	r.sources[url] = &model.ImageSource{URL: url, Status: model.ImageSourceOK, ImageHash: &hash, CheckedAt: checkedAt}
+	return nil
+}
+
+func (r *memoryImageRepository) RecordSourceBroken(url string, reason string, checkedAt time.Time) error {
+	r.sources[url] = &model.ImageSource{URL: url, Status: model.ImageSourceBroken, Error: reason, CheckedAt: checkedAt}
+	return nil
+}
+
+func (r *memoryImageRepository) IsQuestionImage(url string) (bool, error) {
+	return r.questionImages[url], nil
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 3))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadDownloadsOnlyQuestionImages(t *testing.T) {
+	data := testPNG(t)
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write(data)
+	}))
+	defer server.Close()
+	questionURL := server.URL + "/question.png"
+
+	storage, err := newLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := newMemoryImageRepository(questionURL)
+	// The loopback test server is only reachable with a plain client.
+	s := &imageService{storage: storage, repo: repo, cache: newImageCache(1 << 20), httpClient: server.Client(), maxBytes: 1 << 20}
+	ctx := context.Background()
+
+	if _, err := s.Load(ctx, server.URL+"/other.png"); !errors.Is(err, ErrInvalidImage) {
+		t.Fatalf("unknown URL: error = %v, want ErrInvalidImage", err)
+	}
+	if hits.Load() != 0 || len(repo.sources) != 0 || len(repo.images) != 0 {
+		t.Fatalf("unknown URL was downloaded (%d) or recorded (%d sources, %d images)", hits.Load(), len(repo.sources), len(repo.images))
+	}
+
+	for range 2 {
+		loaded, err := s.Load(ctx, questionURL)
+		if err != nil {
+			t.Fatalf("question image: %v", err)
+		}
+		if !bytes.Equal(loaded.Data, data) || loaded.MimeType != "image/png" {
+			t.Fatalf("question image loaded as %s with %d bytes", loaded.MimeType, len(loaded.Data))
+		}
+	}
+	if hits.Load() != 1 || repo.sources[questionURL] == nil {
+		t.Errorf("question image downloaded %d time(s), checked %v; want one download, recorded", hits.Load(), repo.sources[questionURL] != nil)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrStoredObjectNotFound means the storage backend has no object under the requested key.
+var ErrStoredObjectNotFound = errors.New("object not found in storage")
+
+// ImageStorage keeps image data under a key. Keys are content hashes, so an object is never
+// overwritten with different data and backends need no locking.
+type ImageStorage interface {
+	Name() string
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// NewImageStorage builds the backend selected by STORAGE_BACKEND.
+func NewImageStorage(cfg *config.Config) (ImageStorage, error) {
+	backend := strings.ToLower(strings.TrimSpace(cfg.Storage.Backend))
+	log.Info().Str("backend", backend).Msg("Initializing image storage")
+	switch backend {
+	case "", "local":
+		return newLocalImageStorage(cfg.Storage.LocalDir)
+	case "s3":
+		return newS3ImageStorage(cfg.Storage)
+	}
+	return nil, fmt.Errorf("unknown STORAGE_BACKEND %q (expected local or s3)", cfg.Storage.Backend)
+}
+
+// localImageStorage keeps each object in a file named after its key, spread over
+// subdirectories by the key's first two characters to keep directories small.
+type localImageStorage struct {
+	dir string
+}
+
+func newLocalImageStorage(dir string) (ImageStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("STORAGE_LOCAL_DIR must be set when STORAGE_BACKEND=local")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create image directory %s: %w", dir, err)
+	}
+	return &localImageStorage{dir: dir}, nil
+}
+
+func (s *localImageStorage) Name() string { return "local" }
+
+func (s *localImageStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so a reader never sees a partly written image.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localImageStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrStoredObjectNotFound, key)
+	}
+	return data, err
+}
+
+func (s *localImageStorage) path(key string) (string, error) {
+	if len(key) < 3 || strings.ContainsAny(key, `/\.`) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(s.dir, key[:2], key), nil
+}
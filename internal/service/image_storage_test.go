@@ -0,0 +1,75 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalImageStoragePath(t *testing.T) {
+	dir := t.TempDir()
+	s := &localImageStorage{dir: dir}
+	hash := strings.Repeat("0f", 32)
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: hash, want: filepath.Join(dir, "0f", hash)},
+		{key: "abc", want: filepath.Join(dir, "ab", "abc")},
+		{key: "", wantErr: true},
+		{key: "ab", wantErr: true},
+		{key: "../../etc/passwd", wantErr: true},
+		{key: "..", wantErr: true},
+		{key: "ab/cd", wantErr: true},
+		{key: `ab\cd`, wantErr: true},
+		{key: "/etc/passwd", wantErr: true},
+		{key: "image.png", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := s.path(tt.key)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("path(%q) = %q, want an error", tt.key, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("path(%q) = %q, %v; want %q", tt.key, got, err, tt.want)
+		}
+		if rel, err := filepath.Rel(dir, got); err != nil || strings.HasPrefix(rel, "..") {
+			t.Errorf("path(%q) = %q is outside %s", tt.key, got, dir)
+		}
+	}
+}
+
+func TestLocalImageStoragePutGet(t *testing.T) {
+	storage, err := newLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	key := strings.Repeat("ab", 32)
+
+	if _, err := storage.Get(ctx, key); !errors.Is(err, ErrStoredObjectNotFound) {
+		t.Fatalf("Get before Put: error = %v, want ErrStoredObjectNotFound", err)
+	}
+	data := []byte("\x89PNG\r\n\x1a\n image data")
+	if err := storage.Put(ctx, key, data, "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := storage.Put(ctx, key, data, "image/png"); err != nil {
+		t.Fatalf("Put of an existing key: %v", err)
+	}
+	got, err := storage.Get(ctx, key)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Get = %q, %v; want the stored data", got, err)
+	}
+	if err := storage.Put(ctx, "../escape", data, "image/png"); err == nil {
+		t.Fatal("Put accepted a key with a path")
+	}
+}
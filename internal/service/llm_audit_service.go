@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var ErrLLMCallNotFound = errors.New("LLM call not found")
+
+const (
+	defaultLLMCallPageSize = 20
+	maxLLMCallPageSize     = 100
+)
+
+// ScoringSubject identifies the answer a scoring call is made for, so its audit records can be
+// found from the answer. At most one of the IDs is set; neither is set for previews.
+type ScoringSubject struct {
+	AnswerID         *uint
+	PracticeAnswerID *uint
+}
+
+// LLMCallQuery holds the filters accepted by ListCalls.
+type LLMCallQuery struct {
+	AnswerID         uint
+	PracticeAnswerID uint
+	ErrorsOnly       bool
+	Page             int // 1-based
+	PageSize         int
+}
+
+// LLMAuditService keeps the audit trail of the calls made to the AI provider while scoring,
+// so a disputed score can be traced back to the exact prompt and response.
+type LLMAuditService interface {
+	// Record stores a call. Failing to store it is logged and never fails scoring.
+	Record(call *model.LLMCallLog)
+	ListCalls(query LLMCallQuery) (*dto.LLMCallListDTO, error)
+	GetCall(callID uint) (*dto.LLMCallDTO, error)
+	// PurgeExpired deletes the calls older than LLM_AUDIT_RETENTION and returns how many were deleted.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type llmAuditService struct {
+	callRepo  repository.LLMCallLogRepository
+	enabled   bool
+	retention time.Duration
+}
+
+func NewLLMAuditService(callRepo repository.LLMCallLogRepository, cfg *config.Config) LLMAuditService {
+	return &llmAuditService{callRepo: callRepo, enabled: cfg.LLM.AuditEnabled, retention: cfg.LLM.AuditRetention}
+}
+
+func (s *llmAuditService) Record(call *model.LLMCallLog) {
+	if !s.enabled {
+		return
+	}
+	if err := s.callRepo.Create(call); err != nil {
+		log.Error().Err(err).Str("questionType", call.QuestionType).Int("attempt", call.Attempt).Msg("LLM audit: Failed to store scoring call")
+	}
+}
+
+func (s *llmAuditService) ListCalls(query LLMCallQuery) (*dto.LLMCallListDTO, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultLLMCallPageSize
+	}
+	if query.PageSize > maxLLMCallPageSize {
+		query.PageSize = maxLLMCallPageSize
+	}
+
+	calls, total, err := s.callRepo.FindAll(repository.LLMCallLogFilter{
+		AnswerID:         query.AnswerID,
+		PracticeAnswerID: query.PracticeAnswerID,
+		ErrorsOnly:       query.ErrorsOnly,
+		Limit:            query.PageSize,
+		Offset:           (query.Page - 1) * query.PageSize,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("ListCalls: Failed to load scoring calls")
+		return nil, fmt.Errorf("error loading scoring calls: %w", err)
+	}
+
+	resp := &dto.LLMCallListDTO{
+		Items:    make([]dto.LLMCallSummaryDTO, 0, len(calls)),
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+	for i := range calls {
+		resp.Items = append(resp.Items, toLLMCallSummaryDTO(&calls[i]))
+	}
+	return resp, nil
+}
+
+func (s *llmAuditService) GetCall(callID uint) (*dto.LLMCallDTO, error) {
+	call, err := s.callRepo.FindByID(callID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrLLMCallNotFound, callID)
+		}
+		return nil, fmt.Errorf("error loading scoring call %d: %w", callID, err)
+	}
+	return &dto.LLMCallDTO{
+		LLMCallSummaryDTO: toLLMCallSummaryDTO(call),
+		Prompt:            call.Prompt,
+		RawResponse:       call.RawResponse,
+	}, nil
+}
+
+func (s *llmAuditService) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	deleted, err := s.callRepo.DeleteCreatedBefore(time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("error deleting expired scoring calls: %w", err)
+	}
+	return deleted, nil
+}
+
+func toLLMCallSummaryDTO(call *model.LLMCallLog) dto.LLMCallSummaryDTO {
+	return dto.LLMCallSummaryDTO{
+		ID:               call.ID,
+		AnswerID:         call.AnswerID,
+		PracticeAnswerID: call.PracticeAnswerID,
+		QuestionType:     call.QuestionType,
+		Attempt:          call.Attempt,
+		Retry:            call.Retry,
+		Provider:         call.Provider,
+		Model:            call.Model,
+		PromptVersion:    call.PromptVersion,
+		ImageCount:       call.ImageCount,
+		Error:            call.Error,
+		LatencyMs:        call.LatencyMs,
+		PromptTokens:     call.PromptTokens,
+		CompletionTokens: call.CompletionTokens,
+		TotalTokens:      call.TotalTokens,
+		CreatedAt:        call.CreatedAt,
+	}
+}
+
+// LLMAuditPurger periodically deletes audited scoring calls older than LLM_AUDIT_RETENTION.
+type LLMAuditPurger struct {
+	auditService LLMAuditService
+	interval     time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewLLMAuditPurger(auditService LLMAuditService, cfg *config.Config) *LLMAuditPurger {
+	interval := cfg.LLM.AuditPurgeInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &LLMAuditPurger{auditService: auditService, interval: interval}
+}
+
+// Start launches the purge loop. It returns immediately.
+func (p *LLMAuditPurger) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				deleted, err := p.auditService.PurgeExpired(ctx)
+				if err != nil {
+					log.Error().Err(err).Msg("LLM audit purger: Failed to delete expired scoring calls")
+				} else if deleted > 0 {
+					log.Info().Int64("count", deleted).Msg("LLM audit purger: Deleted expired scoring calls")
+				}
+			}
+		}
+	}()
+	log.Info().Dur("interval", p.interval).Msg("LLM audit purger started")
+}
+
+// Stop ends the purge loop and waits for the current purge until ctx expires.
+func (p *LLMAuditPurger) Stop(ctx context.Context) error {
+	if p.cancel == nil {
+		return nil
+	}
+	p.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+// CriterionEvaluation is the model's score for one rubric criterion.
+type CriterionEvaluation struct {
+	Criterion string  `json:"criterion"`
+	Score     float64 `json:"score"`
+	MaxScore  float64 `json:"max_score"`
+	Comment   string  `json:"comment"`
+}
+
+// LLMEvaluation is the JSON document the model is asked to return for every answer.
+type LLMEvaluation struct {
+	Score         *float64               `json:"score"` // Pointer so a missing score is distinguishable from 0
+	Criteria      []CriterionEvaluation  `json:"criteria"`
+	Summary       string                 `json:"summary"`
+	Strengths     []string               `json:"strengths"`
+	Errors        []model.AnswerError    `json:"errors"`
+	RevisedAnswer string                 `json:"revised_answer"`
+	Vocabulary    []model.VocabularyItem `json:"vocabulary"`
+
+	Prechecks     []model.PrecheckFinding `json:"-"` // Set by PrecheckResult.Apply, not by the model
+	UncappedScore *float64                `json:"-"`
+	PromptVersion *int                    `json:"-"` // Template version the prompt was rendered with; nil when no prompt was sent
+	Model         string                  `json:"-"`
+}
+
+// rubricCriteria lists the criteria the prompt asks to be scored, per question type.
+var rubricCriteria = map[string][]string{
+	"sentence_picture": {"Grammar", "Vocabulary", "Relevance to Picture", "Task Achievement"},
+	"email_response":   {"Grammar", "Vocabulary", "Coherence and Cohesion", "Task Achievement", "Relevance and Appropriateness"},
+	"opinion_essay":    {"Grammar", "Vocabulary", "Coherence and Cohesion", "Task Achievement", "Relevance and Appropriateness"},
+}
+
+// jsonOutputInstruction describes the response schema. It is appended to every scoring prompt.
+func jsonOutputInstruction(questionType string, maxScore float64, wantsRevision bool) string {
+	criteria := rubricCriteria[questionType]
+	quoted := make([]string, len(criteria))
+	for i, c := range criteria {
+		quoted[i] = fmt.Sprintf("%q", c)
+	}
+
+	revisionRule := `"revised_answer" and "vocabulary" MUST be an empty string and an empty array.`
+	if wantsRevision {
+		revisionRule = `"revised_answer" MUST contain a revised version of the entire essay that incorporates your feedback, aiming for a high score. "vocabulary" MUST list 5-7 relevant English words or phrases that could enhance the essay, each with its Vietnamese definition.`
+	}
+
+	return fmt.Sprintf(`
+Respond with ONLY a single JSON object (no Markdown code fences, no text before or after it) with exactly this structure:
+{
+  "score": <number from 0.0 to %.1f reflecting the overall quality, in steps of 0.5>,
+  "criteria": [
+    {"criterion": <one of %s>, "score": <number from 0.0 to %.1f>, "max_score": %.1f, "comment": <one sentence>}
+  ],
+  "summary": <2-4 sentences of overall feedback in Markdown>,
+  "strengths": [<short strings, the strong points of the response>],
+  "errors": [
+    {"original": <exact erroneous text>, "correction": <corrected text>, "explanation": <brief reason>, "category": <"grammar", "vocabulary", "coherence" or "task">}
+  ],
+  "revised_answer": <string>,
+  "vocabulary": [{"term": <English word or phrase>, "meaning": <Vietnamese definition>}]
+}
+Rules:
+- "criteria" MUST contain exactly one entry for each of: %s.
+- "errors" lists every specific mistake; use an empty array if there are none.
+- %s
+`, maxScore, strings.Join(quoted, ", "), maxScore, maxScore, strings.Join(quoted, ", "), revisionRule)
+}
+
+// parseLLMEvaluation extracts and validates the JSON evaluation from a raw model response.
+// It tolerates Markdown code fences and surrounding prose, which some models add despite instructions.
+func parseLLMEvaluation(raw string, questionType string, maxScore float64) (*LLMEvaluation, error) {
+	jsonText := extractJSONObject(raw)
+	if jsonText == "" {
+		return nil, fmt.Errorf("response does not contain a JSON object")
+	}
+
+	var eval LLMEvaluation
+	if err := json.Unmarshal([]byte(jsonText), &eval); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	if err := eval.validate(questionType, maxScore); err != nil {
+		return nil, err
+	}
+	return &eval, nil
+}
+
+func extractJSONObject(raw string) string {
+	text := strings.TrimSpace(raw)
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(text, "```")
+
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end <= start {
+		return ""
+	}
+	return text[start : end+1]
+}
+
+// validate checks the evaluation against the schema and normalizes harmless deviations
+// (scores slightly out of range, missing max_score) instead of rejecting them.
+func (e *LLMEvaluation) validate(questionType string, maxScore float64) error {
+	if e.Score == nil {
+		return fmt.Errorf(`"score" is missing`)
+	}
+	if math.IsNaN(*e.Score) || *e.Score < -0.5 || *e.Score > maxScore+0.5 {
+		return fmt.Errorf(`"score" %.2f is outside the allowed range 0-%.1f`, *e.Score, maxScore)
+	}
+	clamped := math.Max(0, math.Min(*e.Score, maxScore))
+	e.Score = &clamped
+
+	if strings.TrimSpace(e.Summary) == "" {
+		return fmt.Errorf(`"summary" is empty`)
+	}
+
+	expected := rubricCriteria[questionType]
+	canonical := make(map[string]string, len(expected))
+	for _, name := range expected {
+		canonical[strings.ToLower(name)] = name
+	}
+	seen := make(map[string]bool, len(e.Criteria))
+	for i := range e.Criteria {
+		c := &e.Criteria[i]
+		c.Criterion = strings.TrimSpace(c.Criterion)
+		if c.Criterion == "" {
+			return fmt.Errorf(`criteria[%d] has no "criterion" name`, i)
+		}
+		name, ok := canonical[strings.ToLower(c.Criterion)]
+		if !ok {
+			return fmt.Errorf(`criterion %q is not one of %s`, c.Criterion, strings.Join(expected, ", "))
+		}
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf(`criterion %q is scored more than once`, name)
+		}
+		c.Criterion = name // Stored criterion names must be stable for trend charts
+		seen[strings.ToLower(name)] = true
+
+		c.MaxScore = maxScore
+		if math.IsNaN(c.Score) || c.Score < -0.5 || c.Score > maxScore+0.5 {
+			return fmt.Errorf(`criterion %q score %.2f is outside the allowed range 0-%.1f`, c.Criterion, c.Score, maxScore)
+		}
+		c.Score = math.Max(0, math.Min(c.Score, maxScore))
+	}
+	for _, name := range expected {
+		if !seen[strings.ToLower(name)] {
+			return fmt.Errorf(`criteria is missing %q`, name)
+		}
+	}
+
+	for i, item := range e.Errors {
+		if strings.TrimSpace(item.Original) == "" || strings.TrimSpace(item.Correction) == "" {
+			return fmt.Errorf(`errors[%d] must have both "original" and "correction"`, i)
+		}
+	}
+	for i, item := range e.Vocabulary {
+		if strings.TrimSpace(item.Term) == "" {
+			return fmt.Errorf(`vocabulary[%d] has no "term"`, i)
+		}
+	}
+	if strings.EqualFold(strings.TrimSpace(e.RevisedAnswer), "N/A") {
+		e.RevisedAnswer = ""
+	}
+	return nil
+}
+
+// FeedbackMarkdown renders the evaluation as a single Markdown document for clients
+// that only display Answer.AIFeedback.
+func (e *LLMEvaluation) FeedbackMarkdown() string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(e.Summary))
+	b.WriteString("\n")
+
+	if len(e.Criteria) > 0 {
+		b.WriteString("\n**Scores by criterion**\n")
+		for _, c := range e.Criteria {
+			fmt.Fprintf(&b, "- %s: %.1f/%.1f", c.Criterion, c.Score, c.MaxScore)
+			if c.Comment != "" {
+				fmt.Fprintf(&b, " — %s", c.Comment)
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(e.Strengths) > 0 {
+		b.WriteString("\n**Strengths**\n")
+		for _, s := range e.Strengths {
+			fmt.Fprintf(&b, "- %s\n", s)
+		}
+	}
+	if len(e.Errors) > 0 {
+		b.WriteString("\n**Errors and corrections**\n")
+		for _, item := range e.Errors {
+			fmt.Fprintf(&b, "- \"%s\" → \"%s\"", item.Original, item.Correction)
+			if item.Explanation != "" {
+				fmt.Fprintf(&b, ": %s", item.Explanation)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+// evaluationJSON builds a response for questionType with every criterion scored, then lets
+// edit change the decoded document before it is encoded again.
+func evaluationJSON(t *testing.T, questionType string, score float64, edit func(doc map[string]any)) string {
+	t.Helper()
+	criteria := []any{}
+	for _, name := range rubricCriteria[questionType] {
+		criteria = append(criteria, map[string]any{"criterion": name, "score": score, "max_score": 3.0, "comment": "ok"})
+	}
+	doc := map[string]any{
+		"score":          score,
+		"criteria":       criteria,
+		"summary":        "Good answer.",
+		"strengths":      []string{"Clear"},
+		"errors":         []any{},
+		"revised_answer": "",
+		"vocabulary":     []any{},
+	}
+	if edit != nil {
+		edit(doc)
+	}
+	text, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("encoding test evaluation: %v", err)
+	}
+	return string(text)
+}
+
+func TestParseLLMEvaluation(t *testing.T) {
+	const maxScore = 3.0
+	valid := evaluationJSON(t, "sentence_picture", 2.5, nil)
+
+	tests := []struct {
+		name      string
+		raw       string
+		wantErr   string // Substring of the error; empty when the response is accepted
+		wantScore float64
+	}{
+		{name: "plain JSON", raw: valid, wantScore: 2.5},
+		{name: "code fence", raw: "```json\n" + valid + "\n```", wantScore: 2.5},
+		{name: "surrounding prose", raw: "Here is the evaluation:\n" + valid + "\nThanks!", wantScore: 2.5},
+		{name: "no JSON object", raw: "I cannot score this answer.", wantErr: "does not contain a JSON object"},
+		{name: "truncated JSON", raw: valid[:len(valid)/2] + "}", wantErr: "not valid JSON"},
+		{name: "wrong field type", raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) { doc["score"] = "two" }), wantErr: "not valid JSON"},
+		{name: "missing score", raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) { delete(doc, "score") }), wantErr: `"score" is missing`},
+		{name: "score slightly above max is clamped", raw: evaluationJSON(t, "sentence_picture", 3, func(doc map[string]any) { doc["score"] = 3.4 }), wantScore: 3},
+		{name: "score slightly below zero is clamped", raw: evaluationJSON(t, "sentence_picture", 0, func(doc map[string]any) { doc["score"] = -0.3 }), wantScore: 0},
+		{name: "score far above max", raw: evaluationJSON(t, "sentence_picture", 3, func(doc map[string]any) { doc["score"] = 10 }), wantErr: "outside the allowed range"},
+		{name: "negative score", raw: evaluationJSON(t, "sentence_picture", 0, func(doc map[string]any) { doc["score"] = -1 }), wantErr: "outside the allowed range"},
+		{name: "empty summary", raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) { doc["summary"] = "  " }), wantErr: `"summary" is empty`},
+		{
+			name: "missing criterion",
+			raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) {
+				doc["criteria"] = doc["criteria"].([]any)[1:]
+			}),
+			wantErr: `criteria is missing "Grammar"`,
+		},
+		{
+			name: "unnamed criterion",
+			raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) {
+				doc["criteria"].([]any)[0].(map[string]any)["criterion"] = " "
+			}),
+			wantErr: `criteria[0] has no "criterion" name`,
+		},
+		{
+			name: "criterion score out of range",
+			raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) {
+				doc["criteria"].([]any)[0].(map[string]any)["score"] = 4.0
+			}),
+			wantErr: `criterion "Grammar" score 4.00 is outside`,
+		},
+		{
+			name: "criterion slightly below zero is clamped",
+			raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) {
+				doc["criteria"].([]any)[0].(map[string]any)["score"] = -0.3
+			}),
+			wantScore: 2,
+		},
+		{
+			name: "unknown criterion",
+			raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) {
+				doc["criteria"] = append(doc["criteria"].([]any), map[string]any{"criterion": "Spelling", "score": 2.0})
+			}),
+			wantErr: `criterion "Spelling" is not one of`,
+		},
+		{
+			name: "repeated criterion",
+			raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) {
+				doc["criteria"] = append(doc["criteria"].([]any), map[string]any{"criterion": "grammar", "score": 1.0})
+			}),
+			wantErr: `criterion "Grammar" is scored more than once`,
+		},
+		{
+			name: "error without correction",
+			raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) {
+				doc["errors"] = []any{map[string]any{"original": "he go", "correction": ""}}
+			}),
+			wantErr: `errors[0] must have both`,
+		},
+		{
+			name: "vocabulary without term",
+			raw: evaluationJSON(t, "sentence_picture", 2, func(doc map[string]any) {
+				doc["vocabulary"] = []any{map[string]any{"term": "", "meaning": "x"}}
+			}),
+			wantErr: `vocabulary[0] has no "term"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval, err := parseLLMEvaluation(tt.raw, "sentence_picture", maxScore)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *eval.Score != tt.wantScore {
+				t.Errorf("score = %v, want %v", *eval.Score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestParseLLMEvaluationNormalizes(t *testing.T) {
+	raw := evaluationJSON(t, "email_response", 4, func(doc map[string]any) {
+		first := doc["criteria"].([]any)[0].(map[string]any)
+		first["criterion"] = "  grammar "
+		first["score"] = 4.3
+		first["max_score"] = 10.0
+		doc["revised_answer"] = "N/A"
+	})
+	eval, err := parseLLMEvaluation(raw, "email_response", 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := eval.Criteria[0]
+	if first.Criterion != "Grammar" {
+		t.Errorf("criterion name = %q, want the canonical %q", first.Criterion, "Grammar")
+	}
+	if first.Score != 4 || first.MaxScore != 4 {
+		t.Errorf("criterion score = %v/%v, want 4/4", first.Score, first.MaxScore)
+	}
+	if eval.RevisedAnswer != "" {
+		t.Errorf("revised answer = %q, want it cleared", eval.RevisedAnswer)
+	}
+}
+
+// scriptedProvider returns its responses in order, then fails.
+type scriptedProvider struct {
+	responses []string
+	prompts   []string
+}
+
+func (p *scriptedProvider) Name() string  { return "scripted" }
+func (p *scriptedProvider) Model() string { return "scripted-model" }
+
+func (p *scriptedProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	p.prompts = append(p.prompts, req.Prompt)
+	if len(p.prompts) > len(p.responses) {
+		return nil, errors.New("no scripted response left")
+	}
+	return &LLMResponse{Text: p.responses[len(p.prompts)-1]}, nil
+}
+
+// stubPromptTemplates renders every question with a fixed task text.
+type stubPromptTemplates struct {
+	PromptTemplateService
+}
+
+func (stubPromptTemplates) Render(data PromptData) (*RenderedPrompt, error) {
+	return &RenderedPrompt{Text: "Score this answer: " + data.UserAnswer, Version: 1}, nil
+}
+
+// discardAudit drops every recorded call.
+type discardAudit struct {
+	LLMAuditService
+}
+
+func (discardAudit) Record(*model.LLMCallLog) {}
+
+func TestScoreAndFeedbackAnswerRepairsResponses(t *testing.T) {
+	question := &model.Question{ID: 1, Type: "email_response", OrderInTest: 6, MaxScore: 4}
+	answer := "Dear Ms. Lee, thank you for your email about the meeting next week. I will attend and bring the report you asked for, and I can also present the budget."
+	valid := evaluationJSON(t, "email_response", 3, nil)
+
+	tests := []struct {
+		name       string
+		responses  []string
+		maxRepairs int
+		wantCalls  int
+		wantErr    bool
+	}{
+		{name: "valid first time", responses: []string{valid}, maxRepairs: 2, wantCalls: 1},
+		{name: "repaired on second call", responses: []string{"not json", valid}, maxRepairs: 2, wantCalls: 2},
+		{name: "gives up after max repairs", responses: []string{"not json", "{}", "still bad"}, maxRepairs: 2, wantCalls: 3, wantErr: true},
+		{name: "no repairs allowed", responses: []string{"not json", valid}, maxRepairs: 0, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &scriptedProvider{responses: tt.responses}
+			s := &llmService{provider: provider, prompts: stubPromptTemplates{}, audit: discardAudit{}, maxRepairAttempts: tt.maxRepairs}
+
+			eval, err := s.ScoreAndFeedbackAnswer(context.Background(), question, answer, ScoringSubject{})
+			if len(provider.prompts) != tt.wantCalls {
+				t.Fatalf("provider calls = %d, want %d", len(provider.prompts), tt.wantCalls)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *eval.Score != 3 || eval.Model != "scripted-model" || eval.PromptVersion == nil || *eval.PromptVersion != 1 {
+				t.Errorf("evaluation = score %v, model %q, version %v", *eval.Score, eval.Model, eval.PromptVersion)
+			}
+			for i, prompt := range provider.prompts[1:] {
+				if !strings.HasPrefix(prompt, provider.prompts[0]) || !strings.Contains(prompt, "was rejected because") || !strings.Contains(prompt, tt.responses[i]) {
+					t.Errorf("repair prompt %d does not carry the original prompt, the reason and the rejected response:\n%s", i+1, prompt)
+				}
+			}
+		})
+	}
+}
+
+// recordingAudit keeps every recorded call.
+type recordingAudit struct {
+	LLMAuditService
+	calls []*model.LLMCallLog
+}
+
+func (a *recordingAudit) Record(call *model.LLMCallLog) { a.calls = append(a.calls, call) }
+
+func TestScoreAndFeedbackAnswerAuditsEveryTry(t *testing.T) {
+	question := &model.Question{ID: 1, Type: "email_response", OrderInTest: 6, MaxScore: 4}
+	answer := "Dear Ms. Lee, thank you for your email about the meeting next week. I will attend and bring the report you asked for, and I can also present the budget."
+	const backoff = 50 * time.Millisecond
+
+	next := &flakyProvider{errs: []error{unavailable(), unavailable()}, text: "not json"}
+	provider, _ := newTestResilientProvider(next, config.LLM{MaxRetries: 2}, newFakeClock())
+	provider.after = func(time.Duration) <-chan time.Time { return time.After(backoff) }
+	audit := &recordingAudit{}
+	s := &llmService{provider: provider, prompts: stubPromptTemplates{}, audit: audit, maxRepairAttempts: 1}
+
+	if _, err := s.ScoreAndFeedbackAnswer(context.Background(), question, answer, ScoringSubject{}); err == nil {
+		t.Fatal("expected the response to be rejected")
+	}
+
+	// Two failed tries and the rejected response of the first call, then the repair call.
+	want := []struct {
+		attempt, retry int
+		err            string
+	}{
+		{attempt: 1, retry: 0, err: "overloaded"},
+		{attempt: 1, retry: 1, err: "overloaded"},
+		{attempt: 1, retry: 2, err: "does not contain a JSON object"},
+		{attempt: 2, retry: 0, err: "does not contain a JSON object"},
+	}
+	if len(audit.calls) != len(want) {
+		t.Fatalf("recorded %d calls, want %d", len(audit.calls), len(want))
+	}
+	for i, call := range audit.calls {
+		if call.Attempt != want[i].attempt || call.Retry != want[i].retry || !strings.Contains(call.Error, want[i].err) {
+			t.Errorf("call %d = attempt %d, retry %d, error %q; want %d, %d, %q", i, call.Attempt, call.Retry, call.Error, want[i].attempt, want[i].retry, want[i].err)
+		}
+		if call.LatencyMs >= backoff.Milliseconds() {
+			t.Errorf("call %d latency %dms includes the backoff", i, call.LatencyMs)
+		}
+	}
+	if audit.calls[2].RawResponse != "not json" || audit.calls[0].RawResponse != "" {
+		t.Errorf("raw responses = %q and %q, want only the answered call to carry one", audit.calls[0].RawResponse, audit.calls[2].RawResponse)
+	}
+}
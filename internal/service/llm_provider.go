@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/rs/zerolog/log"
+)
+
+// LLMImage is an inline image attached to a prompt (used for sentence_picture questions).
+type LLMImage struct {
+	MIMEType string
+	Data     []byte
+}
+
+// LLMTask describes what the prompt asks the model to evaluate. Real providers ignore it;
+// the fake provider uses it to produce a deterministic evaluation without reading the prompt.
+type LLMTask struct {
+	QuestionType string
+	MaxScore     float64
+	UserAnswer   string
+}
+
+// LLMRequest is a provider-neutral text generation request.
+type LLMRequest struct {
+	Prompt string
+	Images []LLMImage
+	JSON   bool // Ask the provider to constrain output to a JSON object when it supports it
+	Task   LLMTask
+	// Observe, when set, is told about every request actually sent to the provider, retries
+	// included. Providers built by NewLLMProvider report each one before Generate returns.
+	Observe func(LLMCallResult)
+}
+
+// LLMCallResult is the outcome of one request sent to the provider.
+type LLMCallResult struct {
+	Retry    int // 0 for the first try, higher for retries after a transient error
+	Response *LLMResponse
+	Err      error
+	Latency  time.Duration // The provider call alone, without backoff or waiting for a concurrency slot
+}
+
+// LLMUsage is the token count a provider reported for a call. Zero when it reports none.
+type LLMUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// LLMResponse is the raw text returned by a provider.
+type LLMResponse struct {
+	Text  string
+	Usage LLMUsage
+}
+
+// LLMProvider is a text generation backend. Prompt construction and response parsing
+// live in llmService so every provider is scored against the same rubric.
+type LLMProvider interface {
+	Name() string
+	Model() string
+	Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error)
+}
+
+// NewLLMProvider builds the provider selected by LLM_PROVIDER, wrapped with the timeouts,
+// retries, concurrency cap and circuit breaker configured by the other LLM_* settings.
+func NewLLMProvider(cfg *config.Config) (LLMProvider, error) {
+	provider := strings.ToLower(strings.TrimSpace(cfg.LLM.Provider))
+	log.Info().Str("provider", provider).Str("model", cfg.LLM.Model).Msg("Initializing LLM provider")
+
+	var base LLMProvider
+	var err error
+	switch provider {
+	case "", "gemini":
+		base, err = newGeminiProvider(cfg)
+	case "openai":
+		base, err = newOpenAIProvider(cfg)
+	case "fake":
+		base = newFakeProvider()
+	default:
+		return nil, fmt.Errorf("unknown LLM_PROVIDER %q (expected gemini, openai or fake)", cfg.LLM.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newResilientProvider(base, cfg.LLM), nil
+}
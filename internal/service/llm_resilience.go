@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrLLMUnavailable is returned without calling the provider while the circuit breaker is open.
+// Scoring treats it as "try again later" and defers the answer instead of failing it.
+var ErrLLMUnavailable = errors.New("AI provider is temporarily unavailable")
+
+// LLMStatusError is an HTTP error response from a provider's API.
+type LLMStatusError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration // From the Retry-After header, if the provider sent one
+}
+
+func (e *LLMStatusError) Error() string {
+	return fmt.Sprintf("%s API error (status %d): %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// Transient reports whether the same request may succeed if sent again later.
+func (e *LLMStatusError) Transient() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds. HTTP dates are not used by LLM APIs.
+func parseRetryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// isTransientLLMError reports whether a failed provider call is worth retrying:
+// timeouts, network errors, rate limiting and server-side errors.
+func isTransientLLMError(err error) bool {
+	var statusErr *LLMStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Transient()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// resilientProvider wraps the configured provider with a per-call timeout, retries with
+// exponential backoff and jitter, a concurrency cap and a circuit breaker. There is one
+// instance per process, so the cap and the breaker are shared by every caller (scoring
+// workers and practice requests alike).
+type resilientProvider struct {
+	next    LLMProvider
+	cfg     config.LLM
+	slots   chan struct{}
+	breaker *circuitBreaker
+	after   func(time.Duration) <-chan time.Time // Waits out a backoff; replaced in tests
+}
+
+func newResilientProvider(next LLMProvider, cfg config.LLM) LLMProvider {
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = 1
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.RetryBaseDelay <= 0 {
+		cfg.RetryBaseDelay = time.Second
+	}
+	if cfg.RetryMaxDelay < cfg.RetryBaseDelay {
+		cfg.RetryMaxDelay = cfg.RetryBaseDelay
+	}
+	return &resilientProvider{
+		next:    next,
+		cfg:     cfg,
+		slots:   make(chan struct{}, cfg.MaxConcurrency),
+		breaker: newCircuitBreaker(next.Name(), cfg.BreakerThreshold, cfg.BreakerCooldown),
+		after:   time.After,
+	}
+}
+
+func (p *resilientProvider) Name() string  { return p.next.Name() }
+func (p *resilientProvider) Model() string { return p.next.Model() }
+
+// Generate calls the provider, retrying transient errors. Once the breaker is open, calls fail
+// fast with ErrLLMUnavailable until its cooldown has passed and a probe call succeeds.
+func (p *resilientProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	if !p.breaker.allow() {
+		return nil, fmt.Errorf("%w: %s circuit breaker is open", ErrLLMUnavailable, p.next.Name())
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= p.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			delay := p.backoff(attempt, lastErr)
+			log.Warn().Err(lastErr).Str("provider", p.next.Name()).Int("retry", attempt).Dur("delay", delay).Msg("LLM call failed with a transient error, retrying")
+			select {
+			case <-ctx.Done():
+				p.breaker.release()
+				return nil, ctx.Err()
+			case <-p.after(delay):
+			}
+		}
+
+		resp, err := p.call(ctx, req, attempt)
+		if err == nil || !isTransientLLMError(err) {
+			p.breaker.record(true) // The provider answered, even if it rejected the request
+			return resp, err
+		}
+		if ctx.Err() != nil {
+			p.breaker.release() // The caller gave up; that says nothing about the provider
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	if p.breaker.record(false) {
+		return nil, fmt.Errorf("%w: %v", ErrLLMUnavailable, lastErr)
+	}
+	return nil, fmt.Errorf("giving up after %d attempt(s): %w", p.cfg.MaxRetries+1, lastErr)
+}
+
+// call makes one provider call within a concurrency slot and the per-call timeout, and reports it
+// to req.Observe once the provider has answered.
+func (p *resilientProvider) call(ctx context.Context, req LLMRequest, retry int) (*LLMResponse, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	callCtx := ctx
+	if p.cfg.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, p.cfg.CallTimeout)
+		defer cancel()
+	}
+	started := time.Now()
+	resp, err := p.next.Generate(callCtx, req)
+	latency := time.Since(started)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		resp, err = nil, fmt.Errorf("%s call timed out after %s: %w", p.next.Name(), p.cfg.CallTimeout, context.DeadlineExceeded)
+	}
+	if req.Observe != nil {
+		req.Observe(LLMCallResult{Retry: retry, Response: resp, Err: err, Latency: latency})
+	}
+	return resp, err
+}
+
+// backoff returns the delay before the given retry: the base delay doubled per retry and capped,
+// of which the upper half is randomized so that workers hit by the same outage do not retry in lockstep.
+// A longer Retry-After from the provider wins, within the same cap.
+func (p *resilientProvider) backoff(retry int, lastErr error) time.Duration {
+	delay := p.cfg.RetryBaseDelay << (retry - 1)
+	if delay <= 0 || delay > p.cfg.RetryMaxDelay {
+		delay = p.cfg.RetryMaxDelay
+	}
+	delay = delay/2 + rand.N(delay/2+1)
+
+	var statusErr *LLMStatusError
+	if errors.As(lastErr, &statusErr) && statusErr.RetryAfter > delay {
+		delay = min(statusErr.RetryAfter, p.cfg.RetryMaxDelay)
+	}
+	return delay
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker counts consecutive failed calls. At the threshold it opens and rejects calls;
+// after the cooldown it lets a single probe call through, whose outcome closes or reopens it.
+type circuitBreaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time // Replaced in tests
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(name string, threshold int, cooldown time.Duration) *circuitBreaker {
+	if cooldown <= 0 {
+		cooldown = time.Minute
+	}
+	return &circuitBreaker{name: name, threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow reports whether a call may be made now.
+func (b *circuitBreaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		log.Info().Str("provider", b.name).Msg("LLM circuit breaker half-open, sending a probe call")
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record stores the outcome of an allowed call and reports whether the breaker is now open.
+func (b *circuitBreaker) record(ok bool) bool {
+	if b.threshold <= 0 {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if ok {
+		if b.state != breakerClosed {
+			log.Info().Str("provider", b.name).Msg("LLM circuit breaker closed, provider is responding again")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		return false
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		if b.state != breakerOpen {
+			log.Error().Str("provider", b.name).Int("failures", b.failures).Dur("cooldown", b.cooldown).Msg("LLM circuit breaker opened, deferring scoring")
+		}
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+	return b.state == breakerOpen
+}
+
+// release gives up an allowed call without an outcome, e.g. when the caller's context was cancelled.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		b.probing = false
+	}
+}
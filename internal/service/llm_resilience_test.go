@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+)
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// flakyProvider fails with the scripted errors in order, then succeeds with text ("ok" if unset).
+// It records how many concurrency slots were taken while it ran.
+type flakyProvider struct {
+	errs      []error
+	text      string
+	calls     int
+	slotsUsed []int
+	slots     func() int
+}
+
+func (p *flakyProvider) Name() string  { return "flaky" }
+func (p *flakyProvider) Model() string { return "flaky-model" }
+
+func (p *flakyProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	p.calls++
+	if p.slots != nil {
+		p.slotsUsed = append(p.slotsUsed, p.slots())
+	}
+	if p.calls <= len(p.errs) {
+		return nil, p.errs[p.calls-1]
+	}
+	if p.text == "" {
+		return &LLMResponse{Text: "ok"}, nil
+	}
+	return &LLMResponse{Text: p.text}, nil
+}
+
+func unavailable() error {
+	return &LLMStatusError{Provider: "flaky", StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+}
+
+// newTestResilientProvider wraps next with a fake clock for the breaker and backoffs that return at
+// once; the requested backoff delays are recorded in the returned slice.
+func newTestResilientProvider(next LLMProvider, cfg config.LLM, clock *fakeClock) (*resilientProvider, *[]time.Duration) {
+	p := newResilientProvider(next, cfg).(*resilientProvider)
+	p.breaker.now = clock.Now
+	delays := &[]time.Duration{}
+	p.after = func(d time.Duration) <-chan time.Time {
+		*delays = append(*delays, d)
+		ch := make(chan time.Time, 1)
+		ch <- clock.Now()
+		return ch
+	}
+	return p, delays
+}
+
+func TestCircuitBreakerStates(t *testing.T) {
+	clock := newFakeClock()
+	b := newCircuitBreaker("test", 2, time.Minute)
+	b.now = clock.Now
+
+	// Closed: failures below the threshold keep it closed.
+	if !b.allow() || b.record(false) {
+		t.Fatal("breaker opened before reaching the threshold")
+	}
+	if !b.allow() || !b.record(false) {
+		t.Fatal("breaker did not open at the threshold")
+	}
+
+	// Open: calls are rejected until the cooldown has passed.
+	if b.allow() {
+		t.Fatal("open breaker allowed a call")
+	}
+	clock.Advance(time.Minute - time.Second)
+	if b.allow() {
+		t.Fatal("breaker allowed a call before the cooldown passed")
+	}
+
+	// Half-open: exactly one probe goes through; a failed probe reopens it for a full cooldown.
+	clock.Advance(time.Second)
+	if !b.allow() {
+		t.Fatal("breaker did not let a probe through after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("breaker let a second call through while probing")
+	}
+	if !b.record(false) {
+		t.Fatal("failed probe did not reopen the breaker")
+	}
+	clock.Advance(time.Minute - time.Second)
+	if b.allow() {
+		t.Fatal("reopened breaker allowed a call before a new cooldown passed")
+	}
+
+	// A released probe (caller gave up) frees the slot for another probe.
+	clock.Advance(time.Second)
+	if !b.allow() {
+		t.Fatal("breaker did not let a probe through after the second cooldown")
+	}
+	b.release()
+	if !b.allow() {
+		t.Fatal("released probe did not free the probe slot")
+	}
+
+	// A successful probe closes it and resets the failure count.
+	if b.record(true) {
+		t.Fatal("successful probe left the breaker open")
+	}
+	if !b.allow() || b.record(false) {
+		t.Fatal("failure count was not reset when the breaker closed")
+	}
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	b := newCircuitBreaker("test", 0, time.Minute)
+	for range 10 {
+		if !b.allow() || b.record(false) {
+			t.Fatal("disabled breaker opened")
+		}
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	p := newResilientProvider(&flakyProvider{}, config.LLM{RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second}).(*resilientProvider)
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{retry: 1, min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{retry: 2, min: 100 * time.Millisecond, max: 200 * time.Millisecond},
+		{retry: 4, min: 400 * time.Millisecond, max: 800 * time.Millisecond},
+		{retry: 5, min: 500 * time.Millisecond, max: time.Second},  // 1.6s capped
+		{retry: 70, min: 500 * time.Millisecond, max: time.Second}, // The shift overflows
+	}
+	for _, tt := range tests {
+		seen := make(map[time.Duration]bool)
+		for range 500 {
+			delay := p.backoff(tt.retry, unavailable())
+			if delay < tt.min || delay > tt.max {
+				t.Fatalf("retry %d: delay %s outside [%s, %s]", tt.retry, delay, tt.min, tt.max)
+			}
+			seen[delay] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("retry %d: backoff is not jittered", tt.retry)
+		}
+	}
+}
+
+func TestBackoffRetryAfter(t *testing.T) {
+	p := newResilientProvider(&flakyProvider{}, config.LLM{RetryBaseDelay: 100 * time.Millisecond, RetryMaxDelay: time.Second}).(*resilientProvider)
+	rateLimited := func(retryAfter time.Duration) error {
+		return &LLMStatusError{Provider: "flaky", StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfter}
+	}
+	tests := []struct {
+		name     string
+		err      error
+		min, max time.Duration
+	}{
+		{name: "longer Retry-After wins", err: rateLimited(400 * time.Millisecond), min: 400 * time.Millisecond, max: 400 * time.Millisecond},
+		{name: "Retry-After capped", err: rateLimited(30 * time.Second), min: time.Second, max: time.Second},
+		{name: "shorter Retry-After ignored", err: rateLimited(time.Millisecond), min: 50 * time.Millisecond, max: 100 * time.Millisecond},
+		{name: "wrapped status error", err: errors.Join(errors.New("call failed"), rateLimited(700*time.Millisecond)), min: 700 * time.Millisecond, max: 700 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				if delay := p.backoff(1, tt.err); delay < tt.min || delay > tt.max {
+					t.Fatalf("delay %s outside [%s, %s]", delay, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{"": 0, "5": 5 * time.Second, "0": 0, "-3": 0, "Wed, 21 Oct 2015 07:28:00 GMT": 0}
+	for header, want := range tests {
+		if got := parseRetryAfter(header); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestResilientProviderRetries(t *testing.T) {
+	badRequest := &LLMStatusError{Provider: "flaky", StatusCode: http.StatusBadRequest, Message: "bad prompt"}
+	tests := []struct {
+		name       string
+		errs       []error
+		maxRetries int
+		wantCalls  int
+		wantDelays int
+		wantErr    string
+	}{
+		{name: "success", maxRetries: 2, wantCalls: 1},
+		{name: "transient then success", errs: []error{unavailable(), context.DeadlineExceeded}, maxRetries: 2, wantCalls: 3, wantDelays: 2},
+		{name: "not transient", errs: []error{badRequest}, maxRetries: 2, wantCalls: 1, wantErr: "bad prompt"},
+		{name: "retries exhausted", errs: []error{unavailable(), unavailable(), unavailable()}, maxRetries: 2, wantCalls: 3, wantDelays: 2, wantErr: "giving up after 3 attempt(s)"},
+		{name: "no retries", errs: []error{unavailable()}, maxRetries: 0, wantCalls: 1, wantErr: "giving up after 1 attempt(s)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &flakyProvider{errs: tt.errs}
+			p, delays := newTestResilientProvider(next, config.LLM{MaxRetries: tt.maxRetries, BreakerThreshold: 5}, newFakeClock())
+
+			_, err := p.Generate(context.Background(), LLMRequest{Prompt: "x"})
+			if next.calls != tt.wantCalls || len(*delays) != tt.wantDelays {
+				t.Errorf("calls = %d, backoffs = %d; want %d and %d", next.calls, len(*delays), tt.wantCalls, tt.wantDelays)
+			}
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResilientProviderBreaker(t *testing.T) {
+	clock := newFakeClock()
+	next := &flakyProvider{errs: []error{unavailable(), unavailable(), unavailable()}}
+	p, _ := newTestResilientProvider(next, config.LLM{MaxRetries: 0, BreakerThreshold: 2, BreakerCooldown: time.Minute}, clock)
+	ctx := context.Background()
+
+	if _, err := p.Generate(ctx, LLMRequest{}); err == nil || errors.Is(err, ErrLLMUnavailable) {
+		t.Fatalf("first failure: error = %v, want a plain failure", err)
+	}
+	if _, err := p.Generate(ctx, LLMRequest{}); !errors.Is(err, ErrLLMUnavailable) {
+		t.Fatalf("failure at the threshold: error = %v, want ErrLLMUnavailable", err)
+	}
+	if _, err := p.Generate(ctx, LLMRequest{}); !errors.Is(err, ErrLLMUnavailable) || next.calls != 2 {
+		t.Fatalf("open breaker: error = %v after %d calls, want ErrLLMUnavailable without calling the provider", err, next.calls)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := p.Generate(ctx, LLMRequest{}); !errors.Is(err, ErrLLMUnavailable) || next.calls != 3 {
+		t.Fatalf("failed probe: error = %v after %d calls, want ErrLLMUnavailable after one probe", err, next.calls)
+	}
+	clock.Advance(time.Minute)
+	if _, err := p.Generate(ctx, LLMRequest{}); err != nil || next.calls != 4 {
+		t.Fatalf("successful probe: error = %v after %d calls", err, next.calls)
+	}
+	if _, err := p.Generate(ctx, LLMRequest{}); err != nil || next.calls != 5 {
+		t.Fatalf("closed breaker: error = %v after %d calls", err, next.calls)
+	}
+}
+
+func TestResilientProviderReleasesSlots(t *testing.T) {
+	badRequest := &LLMStatusError{Provider: "flaky", StatusCode: http.StatusBadRequest}
+	next := &flakyProvider{errs: []error{unavailable(), unavailable(), badRequest, errors.New("boom")}}
+	p, _ := newTestResilientProvider(next, config.LLM{MaxRetries: 2, MaxConcurrency: 1}, newFakeClock())
+	next.slots = func() int { return len(p.slots) }
+
+	if _, err := p.Generate(context.Background(), LLMRequest{}); err == nil {
+		t.Fatal("expected the non-transient error")
+	}
+	if _, err := p.Generate(context.Background(), LLMRequest{}); err == nil {
+		t.Fatal("expected the unclassified error")
+	}
+	if len(p.slots) != 0 {
+		t.Fatalf("%d slot(s) still taken after failed calls", len(p.slots))
+	}
+	for i, used := range next.slotsUsed {
+		if used != 1 {
+			t.Errorf("call %d ran with %d slots taken, want 1", i+1, used)
+		}
+	}
+
+	// With the single slot free again, a call that must wait for it is not blocked.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := p.Generate(ctx, LLMRequest{}); err != nil {
+		t.Fatalf("call after failures: %v", err)
+	}
+}
+
+func TestResilientProviderWaitsForSlot(t *testing.T) {
+	p, _ := newTestResilientProvider(&flakyProvider{}, config.LLM{MaxConcurrency: 1}, newFakeClock())
+	p.slots <- struct{}{} // Another call holds the only slot
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Generate(ctx, LLMRequest{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want the caller's cancellation", err)
+	}
+	if len(p.slots) != 1 {
+		t.Fatalf("cancelled wait changed the slots taken to %d", len(p.slots))
+	}
+}
+
+func TestResilientProviderCancelledBackoffReleasesProbe(t *testing.T) {
+	clock := newFakeClock()
+	next := &flakyProvider{errs: []error{unavailable(), unavailable()}}
+	p, _ := newTestResilientProvider(next, config.LLM{MaxRetries: 0, BreakerThreshold: 1, BreakerCooldown: time.Minute}, clock)
+	if _, err := p.Generate(context.Background(), LLMRequest{}); !errors.Is(err, ErrLLMUnavailable) {
+		t.Fatalf("error = %v, want the breaker to open", err)
+	}
+
+	// The probe fails transiently and the caller gives up during the backoff.
+	clock.Advance(time.Minute)
+	p.cfg.MaxRetries = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	p.after = func(time.Duration) <-chan time.Time {
+		cancel()
+		return nil
+	}
+	if _, err := p.Generate(ctx, LLMRequest{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want the caller's cancellation", err)
+	}
+	if !p.breaker.allow() {
+		t.Fatal("abandoned probe was not released")
+	}
+}
+
+func TestResilientProviderCallTimeout(t *testing.T) {
+	next := &blockingProvider{}
+	p, _ := newTestResilientProvider(next, config.LLM{CallTimeout: 10 * time.Millisecond}, newFakeClock())
+	_, err := p.Generate(context.Background(), LLMRequest{})
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out after 10ms") {
+		t.Fatalf("error = %v, want a call timeout", err)
+	}
+}
+
+// blockingProvider answers only when its context ends.
+type blockingProvider struct{}
+
+func (blockingProvider) Name() string  { return "blocking" }
+func (blockingProvider) Model() string { return "blocking-model" }
+
+func (blockingProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/rs/zerolog/log"
+)
+
+// LLMService scores a single answer against its question. It is provider-neutral:
+// the prompt and the response parsing are shared, generation is delegated to an LLMProvider.
+type LLMService interface {
+	// Every call made to the provider is recorded in the audit trail under subject.
+	ScoreAndFeedbackAnswer(ctx context.Context, question *model.Question, userAnswer string, subject ScoringSubject) (*LLMEvaluation, error)
+}
+
+type llmService struct {
+	provider          LLMProvider
+	images            ImageService
+	prompts           PromptTemplateService
+	audit             LLMAuditService
+	maxRepairAttempts int
+}
+
+func NewLLMService(provider LLMProvider, images ImageService, prompts PromptTemplateService, audit LLMAuditService, cfg *config.Config) LLMService {
+	return &llmService{provider: provider, images: images, prompts: prompts, audit: audit, maxRepairAttempts: cfg.LLM.MaxRepairAttempts}
+}
+
+// ScoreAndFeedbackAnswer asks the provider for a JSON evaluation of userAnswer. Responses that do not
+// match the schema are sent back to the model with the validation error, up to LLM_MAX_REPAIR_ATTEMPTS times.
+func (s *llmService) ScoreAndFeedbackAnswer(ctx context.Context, question *model.Question, userAnswer string, subject ScoringSubject) (*LLMEvaluation, error) {
+	var images []LLMImage
+	maxScore := question.MaxScore // Sử dụng MaxScore từ DB
+
+	// Nếu MaxScore từ DB không hợp lệ (ví dụ 0), thì mới fallback
+	if maxScore <= 0 {
+		switch question.OrderInTest {
+		case 1, 2, 3, 4, 5: // Part 1
+			maxScore = 3.0
+		case 6, 7: // Part 2
+			maxScore = 4.0
+		case 8: // Part 3
+			maxScore = 5.0
+		default:
+			log.Error().Uint("questionID", question.ID).Int("orderInTest", question.OrderInTest).Msg("Invalid OrderInTest for question, cannot determine MaxScore.")
+			return nil, fmt.Errorf("invalid orderInTest %d for question ID %d", question.OrderInTest, question.ID)
+		}
+		log.Warn().Uint("questionID", question.ID).Float64("dbMaxScore", question.MaxScore).Float64("fallbackMaxScore", maxScore).Msg("Question MaxScore from DB is invalid, using OrderInTest-based fallback.")
+	}
+
+	if _, ok := rubricCriteria[question.Type]; !ok {
+		return nil, fmt.Errorf("unsupported question type for scoring: %s", question.Type)
+	}
+
+	prechecks := PrecheckAnswer(question, userAnswer)
+	if prechecks.Blocking() {
+		log.Info().Uint("questionID", question.ID).Interface("prechecks", prechecks.Findings).Msg("Answer fails a blocking precheck, not sent to the AI.")
+		return prechecks.BlockedEvaluation(question.Type, maxScore), nil
+	}
+
+	isEssayQuestion8 := (question.Type == "opinion_essay" && question.OrderInTest == 8)
+
+	if question.Type == "sentence_picture" && question.ImageURL != nil && *question.ImageURL != "" {
+		image, errImg := s.images.Load(ctx, *question.ImageURL)
+		if errImg != nil {
+			log.Error().Err(errImg).Str("imageURL", *question.ImageURL).Msg("Failed to fetch image for scoring")
+			return nil, fmt.Errorf("error processing image: %w", errImg)
+		}
+		images = append(images, LLMImage{MIMEType: image.MimeType, Data: image.Data})
+	}
+
+	data := newPromptData(question, maxScore, isEssayQuestion8, userAnswer)
+	data.HasImage = len(images) > 0
+	rendered, err := s.prompts.Render(data)
+	if err != nil {
+		log.Error().Err(err).Str("questionType", question.Type).Msg("Failed to render the scoring prompt")
+		return nil, fmt.Errorf("error rendering the scoring prompt: %w", err)
+	}
+	prompt := scoringPrompt(rendered.Text, prechecks, data)
+
+	req := LLMRequest{
+		Prompt: prompt,
+		Images: images,
+		JSON:   true,
+		Task:   LLMTask{QuestionType: question.Type, MaxScore: maxScore, UserAnswer: userAnswer},
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= s.maxRepairAttempts; attempt++ {
+		// Each request the provider receives, retries included, gets its own audit record with its own latency.
+		var calls []*model.LLMCallLog
+		req.Observe = func(result LLMCallResult) {
+			call := &model.LLMCallLog{
+				AnswerID:         subject.AnswerID,
+				PracticeAnswerID: subject.PracticeAnswerID,
+				QuestionType:     question.Type,
+				Attempt:          attempt + 1,
+				Retry:            result.Retry,
+				Provider:         s.provider.Name(),
+				Model:            s.provider.Model(),
+				PromptVersion:    &rendered.Version,
+				Prompt:           req.Prompt,
+				ImageCount:       len(req.Images),
+				LatencyMs:        result.Latency.Milliseconds(),
+			}
+			if result.Err != nil {
+				call.Error = result.Err.Error()
+			} else if result.Response != nil {
+				call.RawResponse = result.Response.Text
+				call.PromptTokens = result.Response.Usage.PromptTokens
+				call.CompletionTokens = result.Response.Usage.CompletionTokens
+				call.TotalTokens = result.Response.Usage.TotalTokens
+			}
+			calls = append(calls, call)
+		}
+		resp, err := s.provider.Generate(ctx, req)
+		var eval *LLMEvaluation
+		var parseErr error
+		if err == nil {
+			eval, parseErr = parseLLMEvaluation(resp.Text, question.Type, maxScore)
+			if parseErr != nil && len(calls) > 0 {
+				calls[len(calls)-1].Error = parseErr.Error() // The call that answered is the last one
+			}
+		}
+		for _, call := range calls {
+			s.audit.Record(call)
+		}
+		if err != nil {
+			log.Error().Err(err).Str("provider", s.provider.Name()).Str("questionType", question.Type).Msg("LLM provider error during scoring")
+			return nil, fmt.Errorf("AI scoring error (%s): %w", s.provider.Name(), err)
+		}
+		if parseErr == nil {
+			prechecks.Apply(eval)
+			eval.PromptVersion = &rendered.Version
+			eval.Model = s.provider.Model()
+			return eval, nil
+		}
+		lastErr = parseErr
+		log.Warn().Err(parseErr).Int("attempt", attempt+1).Str("rawResponse", resp.Text).Msg("LLM response did not match the evaluation schema")
+
+		// Ask the model to repair its own output; the original prompt stays first so images and task context are kept.
+		req.Prompt = prompt + fmt.Sprintf(
+			"\n\nYour previous response was rejected because: %s\nPrevious response:\n---\n%s\n---\nReturn ONLY the corrected JSON object.\n",
+			parseErr.Error(), resp.Text)
+	}
+	return nil, fmt.Errorf("could not get a valid evaluation from the AI after %d attempt(s): %w", s.maxRepairAttempts+1, lastErr)
+}
+
+// newPromptData collects what a prompt template is rendered with. HasImage is left to the caller.
+func newPromptData(question *model.Question, maxScore float64, wantsRevision bool, userAnswer string) PromptData {
+	word1, word2 := "N/A", "N/A"
+	if question.GivenWord1 != nil {
+		word1 = *question.GivenWord1
+	}
+	if question.GivenWord2 != nil {
+		word2 = *question.GivenWord2
+	}
+	return PromptData{
+		QuestionType:  question.Type,
+		OrderInTest:   question.OrderInTest,
+		Title:         question.Title,
+		Prompt:        question.Prompt,
+		GivenWord1:    word1,
+		GivenWord2:    word2,
+		MaxScore:      maxScore,
+		Criteria:      rubricCriteria[question.Type],
+		WantsRevision: wantsRevision,
+		UserAnswer:    userAnswer,
+	}
+}
+
+// scoringPrompt completes the task rendered from a template with the precheck findings and the
+// response schema. Neither is part of the template, so an edited template cannot break parsing.
+func scoringPrompt(task string, prechecks *PrecheckResult, data PromptData) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(task, "\n"))
+	b.WriteString("\n\n")
+	b.WriteString(prechecks.PromptSection())
+	b.WriteString(jsonOutputInstruction(data.QuestionType, data.MaxScore, data.WantsRevision))
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+)
+
+const defaultOpenAIBaseURL = "http://localhost:11434/v1" // Ollama's OpenAI-compatible endpoint
+
+// openAIProvider talks to any server implementing the OpenAI chat completions API
+// (OpenAI itself, llama.cpp's server, Ollama, vLLM, ...).
+type openAIProvider struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	modelName  string
+}
+
+func newOpenAIProvider(cfg *config.Config) (LLMProvider, error) {
+	if cfg.LLM.Model == "" {
+		return nil, fmt.Errorf("LLM_MODEL must be set when LLM_PROVIDER=openai")
+	}
+	baseURL := strings.TrimRight(cfg.LLM.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	return &openAIProvider{
+		httpClient: &http.Client{Timeout: 5 * time.Minute}, // Local models can be slow; callers bound this further with ctx
+		baseURL:    baseURL,
+		apiKey:     cfg.LLM.APIKey,
+		modelName:  cfg.LLM.Model,
+	}, nil
+}
+
+func (p *openAIProvider) Name() string  { return "openai" }
+func (p *openAIProvider) Model() string { return p.modelName }
+
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+type openAIMessage struct {
+	Role    string              `json:"role"`
+	Content []openAIContentPart `json:"content"`
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type openAIChatRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (p *openAIProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	var content []openAIContentPart
+	for _, img := range req.Images {
+		dataURL := fmt.Sprintf("data:%s;base64,%s", img.MIMEType, base64.StdEncoding.EncodeToString(img.Data))
+		content = append(content, openAIContentPart{Type: "image_url", ImageURL: &openAIImageURL{URL: dataURL}})
+	}
+	content = append(content, openAIContentPart{Type: "text", Text: req.Prompt})
+
+	chatReq := openAIChatRequest{
+		Model:       p.modelName,
+		Messages:    []openAIMessage{{Role: "user", Content: content}},
+		Temperature: 0.2, // Scoring should be as repeatable as the model allows
+	}
+	if req.JSON {
+		chatReq.ResponseFormat = &openAIResponseFormat{Type: "json_object"} // Supported by OpenAI, llama.cpp and Ollama
+	}
+
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build chat request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("openai-compatible API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openai-compatible API response: %w", err)
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		if resp.StatusCode != http.StatusOK { // E.g. an HTML error page from a proxy in front of the model server
+			return nil, &LLMStatusError{Provider: "openai-compatible", StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+		}
+		return nil, fmt.Errorf("openai-compatible API returned invalid JSON (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg := string(respBody)
+		if chatResp.Error != nil && chatResp.Error.Message != "" {
+			msg = chatResp.Error.Message
+		}
+		return nil, &LLMStatusError{
+			Provider:   "openai-compatible",
+			StatusCode: resp.StatusCode,
+			Message:    msg,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if len(chatResp.Choices) == 0 || chatResp.Choices[0].Message.Content == "" {
+		return nil, fmt.Errorf("openai-compatible API returned no content")
+	}
+	var usage LLMUsage
+	if chatResp.Usage != nil {
+		usage = LLMUsage{
+			PromptTokens:     chatResp.Usage.PromptTokens,
+			CompletionTokens: chatResp.Usage.CompletionTokens,
+			TotalTokens:      chatResp.Usage.TotalTokens,
+		}
+	}
+	return &LLMResponse{Text: chatResp.Choices[0].Message.Content, Usage: usage}, nil
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPracticeNotFound     = errors.New("practice answer not found")
+	ErrPracticeAccessDenied = errors.New("practice answer belongs to another user")
+)
+
+// PracticeService scores single answers outside of a full test. Practice answers are scored
+// synchronously through the same LLMService as test answers and kept in their own history.
+type PracticeService interface {
+	SubmitPractice(ctx context.Context, userID uint, req dto.PracticeSubmitDTO) (*dto.PracticeAnswerDTO, error)
+	GetPracticeAnswer(practiceID uint, userID uint) (*dto.PracticeAnswerDTO, error)
+	ListPracticeHistory(userID uint, questionType string) ([]dto.PracticeSummaryDTO, error)
+}
+
+type practiceService struct {
+	practiceRepo repository.PracticeAnswerRepository
+	questionRepo repository.QuestionRepository
+	testRepo     repository.TestRepository
+	llmService   LLMService
+	images       ImageService
+}
+
+func NewPracticeService(
+	practiceRepo repository.PracticeAnswerRepository,
+	questionRepo repository.QuestionRepository,
+	testRepo repository.TestRepository,
+	llmService LLMService,
+	images ImageService,
+) PracticeService {
+	return &practiceService{
+		practiceRepo: practiceRepo,
+		questionRepo: questionRepo,
+		testRepo:     testRepo,
+		llmService:   llmService,
+		images:       images,
+	}
+}
+
+func (s *practiceService) SubmitPractice(ctx context.Context, userID uint, req dto.PracticeSubmitDTO) (*dto.PracticeAnswerDTO, error) {
+	if strings.TrimSpace(req.UserAnswer) == "" {
+		return nil, fmt.Errorf("user_answer must not be empty")
+	}
+
+	practice := model.PracticeAnswer{
+		UserID:        userID,
+		UserAnswer:    req.UserAnswer,
+		ScoringStatus: "pending",
+	}
+	if req.QuestionID != nil {
+		if err := s.fillFromQuestion(&practice, *req.QuestionID); err != nil {
+			return nil, err
+		}
+	} else if err := fillFromAdHocPrompt(&practice, req); err != nil {
+		return nil, err
+	} else if err := s.checkAdHocImage(practice.ImageURL); err != nil {
+		return nil, err
+	}
+
+	if err := s.practiceRepo.Create(&practice); err != nil {
+		log.Error().Err(err).Uint("userID", userID).Msg("SubmitPractice: Failed to store practice answer")
+		return nil, fmt.Errorf("database error storing practice answer: %w", err)
+	}
+
+	question := practice.Question()
+	eval, llmErr := s.llmService.ScoreAndFeedbackAnswer(ctx, &question, practice.UserAnswer, ScoringSubject{PracticeAnswerID: &practice.ID})
+	if errors.Is(llmErr, ErrLLMUnavailable) {
+		// Practice is scored in the request, so there is no job to retry it; the learner resubmits.
+		log.Warn().Err(llmErr).Uint("practiceID", practice.ID).Msg("SubmitPractice: AI provider unavailable.")
+		applyPracticeScoringFailure(&practice, llmErr)
+		practice.AIFeedback = "AI scoring is temporarily unavailable; please submit this answer again in a few minutes."
+		practice.ScoringStatus = "deferred"
+	} else if llmErr != nil {
+		log.Error().Err(llmErr).Uint("practiceID", practice.ID).Msg("SubmitPractice: Error from LLM service.")
+		applyPracticeScoringFailure(&practice, llmErr)
+	} else {
+		applyPracticeEvaluation(&practice, eval)
+	}
+	if err := s.practiceRepo.Update(&practice); err != nil {
+		log.Error().Err(err).Uint("practiceID", practice.ID).Msg("SubmitPractice: Failed to save scoring result")
+		return nil, fmt.Errorf("database error saving practice score: %w", err)
+	}
+	return toPracticeAnswerDTO(&practice)
+}
+
+func (s *practiceService) GetPracticeAnswer(practiceID uint, userID uint) (*dto.PracticeAnswerDTO, error) {
+	practice, err := s.practiceRepo.FindByID(practiceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrPracticeNotFound, practiceID)
+		}
+		return nil, fmt.Errorf("error loading practice answer %d: %w", practiceID, err)
+	}
+	if practice.UserID != userID {
+		return nil, ErrPracticeAccessDenied
+	}
+	return toPracticeAnswerDTO(practice)
+}
+
+func (s *practiceService) ListPracticeHistory(userID uint, questionType string) ([]dto.PracticeSummaryDTO, error) {
+	if questionType != "" && !isValidQuestionType(questionType) {
+		return nil, fmt.Errorf("invalid type filter %q", questionType)
+	}
+	practices, err := s.practiceRepo.FindAllByUser(userID, questionType)
+	if err != nil {
+		log.Error().Err(err).Uint("userID", userID).Msg("ListPracticeHistory: Failed to load practice history")
+		return nil, fmt.Errorf("error fetching practice history: %w", err)
+	}
+
+	dtos := make([]dto.PracticeSummaryDTO, 0, len(practices))
+	for _, p := range practices {
+		var summary dto.PracticeSummaryDTO
+		if err := copier.Copy(&summary, &p); err != nil {
+			return nil, fmt.Errorf("error preparing response data: %w", err)
+		}
+		dtos = append(dtos, summary)
+	}
+	return dtos, nil
+}
+
+// fillFromQuestion copies the current content of a question from a published test.
+func (s *practiceService) fillFromQuestion(practice *model.PracticeAnswer, questionID uint) error {
+	question, err := s.questionRepo.FindByID(questionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w with ID %d", ErrQuestionNotFound, questionID)
+		}
+		return fmt.Errorf("error loading question %d: %w", questionID, err)
+	}
+	test, err := s.testRepo.FindByID(question.TestID)
+	if err != nil || test.Status != model.TestStatusPublished {
+		// Questions of drafts and deleted tests are not visible to learners.
+		return fmt.Errorf("%w with ID %d", ErrQuestionNotFound, questionID)
+	}
+
+	practice.QuestionID = &question.ID
+	practice.QuestionRevisionID = question.CurrentRevisionID
+	practice.Type = question.Type
+	practice.Title = question.Title
+	practice.Prompt = question.Prompt
+	practice.ImageURL = question.ImageURL
+	practice.GivenWord1 = question.GivenWord1
+	practice.GivenWord2 = question.GivenWord2
+	practice.MaxScore = question.MaxScore
+	return nil
+}
+
+func fillFromAdHocPrompt(practice *model.PracticeAnswer, req dto.PracticeSubmitDTO) error {
+	if req.Type == "" || strings.TrimSpace(req.Prompt) == "" {
+		return fmt.Errorf("either question_id or both type and prompt are required")
+	}
+	maxScore, ok := maxScoreForType(req.Type)
+	if !ok {
+		return fmt.Errorf("invalid question type %q", req.Type)
+	}
+
+	practice.Type = req.Type
+	practice.Title = strings.TrimSpace(req.Title)
+	practice.Prompt = req.Prompt
+	practice.ImageURL = emptyToNil(derefString(req.ImageURL))
+	practice.GivenWord1 = emptyToNil(derefString(req.GivenWord1))
+	practice.GivenWord2 = emptyToNil(derefString(req.GivenWord2))
+	practice.MaxScore = maxScore
+	if practice.Type == "sentence_picture" &&
+		(practice.ImageURL == nil || practice.GivenWord1 == nil || practice.GivenWord2 == nil) {
+		return fmt.Errorf("type 'sentence_picture' requires image_url, given_word1 and given_word2")
+	}
+	if practice.ImageURL != nil && !isAdHocImageURL(*practice.ImageURL) {
+		return fmt.Errorf("image_url of an ad-hoc task must be an uploaded image (%s<hash>) or a data: URI; use question_id to practise with a test question's image", storedImagePath)
+	}
+	return nil
+}
+
+// isAdHocImageURL reports whether a learner-supplied image URL is one scoring can read without
+// downloading anything: an uploaded image served by this API, or an image embedded in the request.
+func isAdHocImageURL(imageURL string) bool {
+	if hash, ok := strings.CutPrefix(imageURL, storedImagePath); ok {
+		return IsImageHash(hash)
+	}
+	return strings.HasPrefix(imageURL, "data:")
+}
+
+// checkAdHocImage makes sure scoring can use the image of an ad-hoc task: an uploaded image must
+// exist, and an image embedded as a data: URI must pass the upload checks. A bad image is rejected
+// with the submission instead of failing when it is scored.
+func (s *practiceService) checkAdHocImage(imageURL *string) error {
+	if imageURL == nil {
+		return nil
+	}
+	if hash, ok := strings.CutPrefix(*imageURL, storedImagePath); ok {
+		if _, err := s.images.Find(hash); err != nil {
+			return fmt.Errorf("image_url: %w", err)
+		}
+		return nil
+	}
+	data, _, err := parseImageDataURI(*imageURL)
+	if err != nil {
+		return fmt.Errorf("image_url: %w: %v", ErrInvalidImage, err)
+	}
+	if _, err := s.images.Check("", data); err != nil {
+		return fmt.Errorf("image_url: %w", err)
+	}
+	return nil
+}
+
+func applyPracticeEvaluation(practice *model.PracticeAnswer, eval *LLMEvaluation) {
+	practice.AIScore = eval.Score
+	practice.AIFeedback = eval.FeedbackMarkdown()
+	practice.AIStrengths = eval.Strengths
+	practice.AIErrors = eval.Errors
+	practice.AIRevisedAnswer = eval.RevisedAnswer
+	practice.AIVocabulary = eval.Vocabulary
+	practice.AIUncappedScore = eval.UncappedScore
+	practice.Prechecks = eval.Prechecks
+	practice.PromptVersion = eval.PromptVersion
+	practice.ModelName = eval.Model
+	practice.CriterionScores = make([]model.CriterionResult, 0, len(eval.Criteria))
+	for _, c := range eval.Criteria {
+		practice.CriterionScores = append(practice.CriterionScores, model.CriterionResult{
+			Criterion: c.Criterion,
+			Score:     c.Score,
+			MaxScore:  c.MaxScore,
+			Comment:   c.Comment,
+		})
+	}
+	practice.ScoringStatus = "scored"
+}
+
+func applyPracticeScoringFailure(practice *model.PracticeAnswer, err error) {
+	practice.AIScore = nil
+	practice.AIFeedback = fmt.Sprintf("AI scoring failed: %s", err.Error())
+	practice.ScoringStatus = "failed"
+}
+
+func toPracticeAnswerDTO(practice *model.PracticeAnswer) (*dto.PracticeAnswerDTO, error) {
+	var resp dto.PracticeAnswerDTO
+	if err := copier.Copy(&resp, practice); err != nil {
+		return nil, fmt.Errorf("error preparing response data: %w", err)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+func TestFillFromAdHocPromptImageURL(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	tests := []struct {
+		imageURL string
+		wantErr  bool
+	}{
+		{imageURL: storedImagePath + hash},
+		{imageURL: "data:image/png;base64,iVBORw0KGgo="},
+		{imageURL: "http://169.254.169.254/latest/meta-data/", wantErr: true},
+		{imageURL: "https://example.com/picture.png", wantErr: true},
+		{imageURL: "https://example.com" + storedImagePath + hash, wantErr: true},
+		{imageURL: storedImagePath + "not-a-hash", wantErr: true},
+		{imageURL: storedImagePath + strings.ToUpper(hash), wantErr: true},
+		{imageURL: "file:///etc/passwd", wantErr: true},
+	}
+	for _, tt := range tests {
+		word1, word2 := "desk", "open"
+		req := dto.PracticeSubmitDTO{Type: "sentence_picture", Prompt: "Write one sentence.", ImageURL: &tt.imageURL, GivenWord1: &word1, GivenWord2: &word2}
+		err := fillFromAdHocPrompt(&model.PracticeAnswer{}, req)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("image_url %q: error = %v, want error %v", tt.imageURL, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckAdHocImage(t *testing.T) {
+	repo := newMemoryImageRepository()
+	uploadedHash := strings.Repeat("ab", 32)
+	repo.images[uploadedHash] = &model.Image{Hash: uploadedHash, MimeType: "image/png"}
+	s := &practiceService{images: &imageService{repo: repo, maxBytes: 1 << 10}}
+	dataURI := func(mimeType string, data []byte) *string {
+		uri := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+		return &uri
+	}
+	uploaded := storedImagePath + uploadedHash
+	missing := storedImagePath + strings.Repeat("cd", 32)
+
+	tests := []struct {
+		name     string
+		imageURL *string
+		wantErr  error
+	}{
+		{name: "no image"},
+		{name: "uploaded image", imageURL: &uploaded},
+		{name: "uploaded image that does not exist", imageURL: &missing, wantErr: ErrImageNotFound},
+		{name: "valid PNG", imageURL: dataURI("image/png", testPNG(t))},
+		{name: "not base64", imageURL: func() *string { uri := "data:image/png,raw"; return &uri }(), wantErr: ErrInvalidImage},
+		{name: "unsupported type", imageURL: dataURI("image/svg+xml", []byte("<svg/>")), wantErr: ErrInvalidImage},
+		{name: "undecodable", imageURL: dataURI("image/png", []byte("not an image")), wantErr: ErrInvalidImage},
+		{name: "too large", imageURL: dataURI("image/png", make([]byte, 2<<10)), wantErr: ErrImageTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkAdHocImage(tt.imageURL)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPromptTemplateNotFound = errors.New("prompt template not found")
+	ErrInvalidPromptTemplate  = errors.New("invalid prompt template")
+)
+
+//go:embed prompttemplates/*.tmpl
+var builtinPromptTemplates embed.FS
+
+const activePromptCacheTTL = 30 * time.Second // Other instances see a newly activated version within this delay
+
+// sampleOrderInTest is the position used to render a template without a real question.
+var sampleOrderInTest = map[string]int{"sentence_picture": 1, "email_response": 6, "opinion_essay": 8}
+
+// PromptData is what a prompt template is rendered with.
+type PromptData struct {
+	QuestionType  string
+	OrderInTest   int
+	Title         string
+	Prompt        string
+	GivenWord1    string // "N/A" when the question has none
+	GivenWord2    string
+	HasImage      bool // The question's image is attached to the request
+	MaxScore      float64
+	Criteria      []string // Rubric criteria the response must score
+	WantsRevision bool     // Question 8: a revised answer and vocabulary are requested
+	UserAnswer    string
+}
+
+// RenderedPrompt is the task part of a scoring prompt and the template version that produced it.
+type RenderedPrompt struct {
+	Text    string
+	Version int
+}
+
+// PromptTemplateService renders the scoring prompt of each question type from versioned templates
+// and lets admins write new versions and choose which ones are active.
+type PromptTemplateService interface {
+	// Render renders the prompt for an answer with an active version of its question type.
+	// With two active versions, the version is picked at random according to their weights.
+	Render(data PromptData) (*RenderedPrompt, error)
+	// EnsureDefaultTemplates stores and activates the built-in template of each question type that has none yet.
+	EnsureDefaultTemplates() error
+
+	ListTemplates(questionType string) ([]dto.PromptTemplateDTO, error)
+	GetTemplate(questionType string, version int) (*dto.PromptTemplateDTO, error)
+	CreateTemplate(createdByID uint, questionType string, req dto.PromptTemplateCreateDTO) (*dto.PromptTemplateDTO, error)
+	ActivateTemplates(questionType string, req dto.PromptTemplateActivateDTO) ([]dto.PromptTemplateDTO, error)
+	PreviewTemplate(questionType string, version int, req dto.PromptPreviewDTO) (*dto.PromptPreviewResultDTO, error)
+}
+
+// activePrompts are the active versions of a question type, as last read from the database.
+type activePrompts struct {
+	templates []model.PromptTemplate
+	fetchedAt time.Time
+}
+
+type promptTemplateService struct {
+	templateRepo repository.PromptTemplateRepository
+
+	mu       sync.Mutex
+	compiled map[uint]*template.Template // Templates never change once stored, so they are compiled once
+	active   map[string]activePrompts
+}
+
+func NewPromptTemplateService(templateRepo repository.PromptTemplateRepository) PromptTemplateService {
+	return &promptTemplateService{
+		templateRepo: templateRepo,
+		compiled:     make(map[uint]*template.Template),
+		active:       make(map[string]activePrompts),
+	}
+}
+
+func (s *promptTemplateService) Render(data PromptData) (*RenderedPrompt, error) {
+	active, err := s.activeTemplates(data.QuestionType)
+	if err != nil {
+		return nil, err
+	}
+	chosen := pickWeighted(active, rand.IntN)
+	tmpl, err := s.compile(chosen)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("prompt template %s v%d failed: %w", chosen.QuestionType, chosen.Version, err)
+	}
+	return &RenderedPrompt{Text: b.String(), Version: chosen.Version}, nil
+}
+
+func (s *promptTemplateService) EnsureDefaultTemplates() error {
+	for questionType := range rubricCriteria {
+		count, err := s.templateRepo.Count(questionType)
+		if err != nil {
+			return fmt.Errorf("failed to count prompt templates: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		file := "prompttemplates/" + questionType + ".tmpl"
+		body, err := builtinPromptTemplates.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read built-in prompt template: %w", err)
+		}
+		tmpl := &model.PromptTemplate{
+			QuestionType: questionType,
+			Name:         "Built-in",
+			Description:  "The prompt used before templates could be edited.",
+			Body:         string(body),
+			Source:       model.PromptTemplateSourceFile,
+		}
+		if err := validatePromptTemplate(tmpl); err != nil {
+			return fmt.Errorf("built-in prompt template %s: %w", file, err)
+		}
+		if err := s.templateRepo.Create(tmpl, true); err != nil {
+			return fmt.Errorf("failed to store built-in prompt template: %w", err)
+		}
+		log.Info().Str("questionType", questionType).Int("version", tmpl.Version).Msg("EnsureDefaultTemplates: Built-in prompt template stored and activated.")
+	}
+	return nil
+}
+
+func (s *promptTemplateService) ListTemplates(questionType string) ([]dto.PromptTemplateDTO, error) {
+	if questionType != "" && !isValidQuestionType(questionType) {
+		return nil, fmt.Errorf("%w: unknown question type %q", ErrInvalidPromptTemplate, questionType)
+	}
+	templates, err := s.templateRepo.FindAll(questionType)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching prompt templates: %w", err)
+	}
+	dtos := make([]dto.PromptTemplateDTO, len(templates))
+	for i := range templates {
+		copier.Copy(&dtos[i], &templates[i])
+	}
+	return dtos, nil
+}
+
+func (s *promptTemplateService) GetTemplate(questionType string, version int) (*dto.PromptTemplateDTO, error) {
+	tmpl, err := s.findTemplate(questionType, version)
+	if err != nil {
+		return nil, err
+	}
+	return toPromptTemplateDTO(tmpl), nil
+}
+
+func (s *promptTemplateService) CreateTemplate(createdByID uint, questionType string, req dto.PromptTemplateCreateDTO) (*dto.PromptTemplateDTO, error) {
+	if !isValidQuestionType(questionType) {
+		return nil, fmt.Errorf("%w: unknown question type %q", ErrInvalidPromptTemplate, questionType)
+	}
+	tmpl := &model.PromptTemplate{
+		QuestionType: questionType,
+		Name:         strings.TrimSpace(req.Name),
+		Description:  strings.TrimSpace(req.Description),
+		Body:         req.Body,
+		Source:       model.PromptTemplateSourceAdmin,
+		CreatedByID:  &createdByID,
+	}
+	if tmpl.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidPromptTemplate)
+	}
+	if err := validatePromptTemplate(tmpl); err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Create(tmpl, req.Activate); err != nil {
+		log.Error().Err(err).Msg("CreateTemplate: Failed to store prompt template")
+		return nil, fmt.Errorf("database error storing prompt template: %w", err)
+	}
+	if req.Activate {
+		s.forgetActive(questionType)
+	}
+	log.Info().Str("questionType", questionType).Int("version", tmpl.Version).Bool("active", tmpl.Active).Uint("createdByID", createdByID).Msg("CreateTemplate: Prompt template stored.")
+	return toPromptTemplateDTO(tmpl), nil
+}
+
+// ActivateTemplates replaces the active versions of a question type. Answers already scored keep
+// the version recorded on them; only answers scored from now on use the new split.
+func (s *promptTemplateService) ActivateTemplates(questionType string, req dto.PromptTemplateActivateDTO) ([]dto.PromptTemplateDTO, error) {
+	if !isValidQuestionType(questionType) {
+		return nil, fmt.Errorf("%w: unknown question type %q", ErrInvalidPromptTemplate, questionType)
+	}
+	weights := make(map[int]int, len(req.Versions))
+	total := 0
+	for _, traffic := range req.Versions {
+		if _, dup := weights[traffic.Version]; dup {
+			return nil, fmt.Errorf("%w: version %d is listed twice", ErrInvalidPromptTemplate, traffic.Version)
+		}
+		weights[traffic.Version] = traffic.Weight
+		total += traffic.Weight
+	}
+	if len(weights) == 0 || len(weights) > 2 {
+		return nil, fmt.Errorf("%w: activate one or two versions", ErrInvalidPromptTemplate)
+	}
+	if total != 100 {
+		return nil, fmt.Errorf("%w: weights must sum to 100, got %d", ErrInvalidPromptTemplate, total)
+	}
+
+	if err := s.templateRepo.SetTraffic(questionType, weights); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: a listed version of %s does not exist", ErrPromptTemplateNotFound, questionType)
+		}
+		return nil, fmt.Errorf("database error activating prompt templates: %w", err)
+	}
+	s.forgetActive(questionType)
+	log.Info().Str("questionType", questionType).Interface("weights", weights).Msg("ActivateTemplates: Prompt templates activated.")
+
+	active, err := s.activeTemplates(questionType)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]dto.PromptTemplateDTO, len(active))
+	for i := range active {
+		copier.Copy(&dtos[i], &active[i])
+	}
+	return dtos, nil
+}
+
+func (s *promptTemplateService) PreviewTemplate(questionType string, version int, req dto.PromptPreviewDTO) (*dto.PromptPreviewResultDTO, error) {
+	tmpl, err := s.findTemplate(questionType, version)
+	if err != nil {
+		return nil, err
+	}
+	compiled, err := s.compile(*tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	order := req.OrderInTest
+	if order == 0 {
+		order = sampleOrderInTest[questionType]
+	}
+	layoutType, maxScore, ok := testQuestionLayout(order)
+	if !ok || layoutType != questionType {
+		return nil, fmt.Errorf("%w: question %d of a test is not a %s question", ErrInvalidPromptTemplate, order, questionType)
+	}
+	question := &model.Question{
+		Type:        questionType,
+		OrderInTest: order,
+		Prompt:      req.Prompt,
+		GivenWord1:  emptyToNil(req.GivenWord1),
+		GivenWord2:  emptyToNil(req.GivenWord2),
+		MaxScore:    maxScore,
+	}
+	data := newPromptData(question, maxScore, true, req.UserAnswer)
+	var b strings.Builder
+	if err := compiled.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+	}
+	prompt := scoringPrompt(b.String(), PrecheckAnswer(question, req.UserAnswer), data)
+	return &dto.PromptPreviewResultDTO{QuestionType: questionType, Version: version, Prompt: prompt}, nil
+}
+
+func (s *promptTemplateService) findTemplate(questionType string, version int) (*model.PromptTemplate, error) {
+	tmpl, err := s.templateRepo.FindByVersion(questionType, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s version %d", ErrPromptTemplateNotFound, questionType, version)
+		}
+		return nil, fmt.Errorf("error loading prompt template %s v%d: %w", questionType, version, err)
+	}
+	return tmpl, nil
+}
+
+func (s *promptTemplateService) activeTemplates(questionType string) ([]model.PromptTemplate, error) {
+	s.mu.Lock()
+	cached, ok := s.active[questionType]
+	s.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < activePromptCacheTTL {
+		return cached.templates, nil
+	}
+
+	templates, err := s.templateRepo.FindActive(questionType)
+	if err != nil {
+		return nil, fmt.Errorf("error loading the active prompt templates: %w", err)
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("%w: no version is active for %s", ErrPromptTemplateNotFound, questionType)
+	}
+	s.mu.Lock()
+	s.active[questionType] = activePrompts{templates: templates, fetchedAt: time.Now()}
+	s.mu.Unlock()
+	return templates, nil
+}
+
+func (s *promptTemplateService) forgetActive(questionType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.active, questionType)
+}
+
+func (s *promptTemplateService) compile(tmpl model.PromptTemplate) (*template.Template, error) {
+	s.mu.Lock()
+	compiled, ok := s.compiled[tmpl.ID]
+	s.mu.Unlock()
+	if ok {
+		return compiled, nil
+	}
+	compiled, err := parsePromptTemplate(&tmpl)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.compiled[tmpl.ID] = compiled
+	s.mu.Unlock()
+	return compiled, nil
+}
+
+// pickWeighted chooses one of the active versions with a probability proportional to its weight.
+// intN returns a uniform random number in [0, n); tests pass a deterministic one.
+func pickWeighted(templates []model.PromptTemplate, intN func(n int) int) model.PromptTemplate {
+	total := 0
+	for _, t := range templates {
+		total += t.Weight
+	}
+	if total <= 0 {
+		return templates[0]
+	}
+	n := intN(total)
+	for _, t := range templates {
+		if n < t.Weight {
+			return t
+		}
+		n -= t.Weight
+	}
+	return templates[len(templates)-1]
+}
+
+func parsePromptTemplate(tmpl *model.PromptTemplate) (*template.Template, error) {
+	name := fmt.Sprintf("%s-v%d", tmpl.QuestionType, tmpl.Version)
+	compiled, err := template.New(name).Option("missingkey=error").Parse(tmpl.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+	}
+	return compiled, nil
+}
+
+// validatePromptTemplate parses the template and renders it with sample data, which catches
+// references to fields PromptData does not have before the template can be activated.
+func validatePromptTemplate(tmpl *model.PromptTemplate) error {
+	if strings.TrimSpace(tmpl.Body) == "" {
+		return fmt.Errorf("%w: body is required", ErrInvalidPromptTemplate)
+	}
+	compiled, err := parsePromptTemplate(tmpl)
+	if err != nil {
+		return err
+	}
+	word1, word2 := "sit", "table"
+	order := sampleOrderInTest[tmpl.QuestionType]
+	_, maxScore, _ := testQuestionLayout(order)
+	sample := &model.Question{Type: tmpl.QuestionType, OrderInTest: order, Title: "Sample", Prompt: "Sample task.", GivenWord1: &word1, GivenWord2: &word2, MaxScore: maxScore}
+	var b strings.Builder
+	if err := compiled.Execute(&b, newPromptData(sample, maxScore, true, "Sample answer.")); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+	}
+	if !strings.Contains(b.String(), "Sample answer.") {
+		return fmt.Errorf("%w: the template must include the learner's answer ({{.UserAnswer}})", ErrInvalidPromptTemplate)
+	}
+	return nil
+}
+
+func toPromptTemplateDTO(tmpl *model.PromptTemplate) *dto.PromptTemplateDTO {
+	var resp dto.PromptTemplateDTO
+	copier.Copy(&resp, tmpl)
+	return &resp
+}
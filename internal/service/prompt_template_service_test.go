@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/lshigami/Ringtails/internal/testdb"
+)
+
+const testPromptBody = "Score this {{.QuestionType}} answer out of {{.MaxScore}}:\n{{.UserAnswer}}"
+
+// newPromptTestService returns a service over an empty template table and its repository.
+func newPromptTestService(t *testing.T) (*promptTemplateService, repository.PromptTemplateRepository) {
+	t.Helper()
+	repo := repository.NewPromptTemplateRepository(testdb.Open(t, &model.PromptTemplate{}))
+	return NewPromptTemplateService(repo).(*promptTemplateService), repo
+}
+
+// storeTemplates stores n versions of an email_response template, the first one active.
+func storeTemplates(t *testing.T, repo repository.PromptTemplateRepository, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		tmpl := &model.PromptTemplate{QuestionType: "email_response", Name: "Test", Body: testPromptBody, Source: model.PromptTemplateSourceAdmin}
+		if err := repo.Create(tmpl, i == 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	templates := []model.PromptTemplate{{Version: 3, Weight: 30}, {Version: 4, Weight: 70}}
+	picked := map[int]int{}
+	for roll := 0; roll < 100; roll++ {
+		chosen := pickWeighted(templates, func(n int) int {
+			if n != 100 {
+				t.Fatalf("rolled among %d, want the total weight 100", n)
+			}
+			return roll
+		})
+		picked[chosen.Version]++
+	}
+	if picked[3] != 30 || picked[4] != 70 {
+		t.Errorf("split over every roll = %v, want 30 for v3 and 70 for v4", picked)
+	}
+
+	single := []model.PromptTemplate{{Version: 1, Weight: 100}}
+	if chosen := pickWeighted(single, func(int) int { return 99 }); chosen.Version != 1 {
+		t.Errorf("single version: chose v%d", chosen.Version)
+	}
+	unweighted := []model.PromptTemplate{{Version: 1}, {Version: 2}}
+	if chosen := pickWeighted(unweighted, func(int) int { t.Fatal("rolled without weights"); return 0 }); chosen.Version != 1 {
+		t.Errorf("no weights: chose v%d, want the first version", chosen.Version)
+	}
+}
+
+func TestValidatePromptTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "valid", body: testPromptBody},
+		{name: "every field", body: "{{.QuestionType}} {{.OrderInTest}} {{.Title}} {{.Prompt}} {{.GivenWord1}} {{.GivenWord2}} {{if .HasImage}}image{{end}} " +
+			"{{.MaxScore}} {{range .Criteria}}{{.}} {{end}}{{if .WantsRevision}}revise{{end}} {{.UserAnswer}}"},
+		{name: "empty", body: "  \n", wantErr: true},
+		{name: "syntax error", body: "{{.UserAnswer", wantErr: true},
+		{name: "unknown field", body: "{{.UserAnswer}} {{.Learner}}", wantErr: true},
+		{name: "without the answer", body: "Score the answer out of {{.MaxScore}}.", wantErr: true},
+		{name: "answer only in a branch never taken", body: "{{if .HasImage}}{{.UserAnswer}}{{end}}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePromptTemplate(&model.PromptTemplate{QuestionType: "email_response", Body: tt.body})
+			if tt.wantErr != (err != nil) || (err != nil && !errors.Is(err, ErrInvalidPromptTemplate)) {
+				t.Errorf("error = %v, want an ErrInvalidPromptTemplate: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestActivateTemplatesWeights(t *testing.T) {
+	traffic := func(versionWeights ...int) dto.PromptTemplateActivateDTO {
+		var req dto.PromptTemplateActivateDTO
+		for i := 0; i < len(versionWeights); i += 2 {
+			req.Versions = append(req.Versions, dto.PromptTrafficDTO{Version: versionWeights[i], Weight: versionWeights[i+1]})
+		}
+		return req
+	}
+	tests := []struct {
+		name         string
+		questionType string
+		req          dto.PromptTemplateActivateDTO
+		wantErr      error
+		wantWeights  map[int]int // Active versions afterwards
+	}{
+		{name: "one version", req: traffic(2, 100), wantWeights: map[int]int{2: 100}},
+		{name: "A/B split", req: traffic(1, 80, 3, 20), wantWeights: map[int]int{1: 80, 3: 20}},
+		{name: "no version", req: traffic(), wantErr: ErrInvalidPromptTemplate},
+		{name: "three versions", req: traffic(1, 40, 2, 30, 3, 30), wantErr: ErrInvalidPromptTemplate},
+		{name: "version listed twice", req: traffic(2, 50, 2, 50), wantErr: ErrInvalidPromptTemplate},
+		{name: "weights below 100", req: traffic(1, 50, 2, 40), wantErr: ErrInvalidPromptTemplate},
+		{name: "weights above 100", req: traffic(1, 100, 2, 10), wantErr: ErrInvalidPromptTemplate},
+		{name: "single version below 100", req: traffic(2, 60), wantErr: ErrInvalidPromptTemplate},
+		{name: "unknown version", req: traffic(1, 50, 9, 50), wantErr: ErrPromptTemplateNotFound},
+		{name: "unknown question type", questionType: "essay", req: traffic(1, 100), wantErr: ErrInvalidPromptTemplate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newPromptTestService(t)
+			storeTemplates(t, repo, 3)
+			questionType := tt.questionType
+			if questionType == "" {
+				questionType = "email_response"
+			}
+
+			active, err := s.ActivateTemplates(questionType, tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				tt.wantWeights = map[int]int{1: 100} // Unchanged
+				stored, err := repo.FindActive("email_response")
+				if err != nil {
+					t.Fatal(err)
+				}
+				active = make([]dto.PromptTemplateDTO, len(stored))
+				for i := range stored {
+					active[i] = *toPromptTemplateDTO(&stored[i])
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			weights := map[int]int{}
+			for _, tmpl := range active {
+				weights[tmpl.Version] = tmpl.Weight
+			}
+			if len(weights) != len(tt.wantWeights) {
+				t.Fatalf("active versions = %v, want %v", weights, tt.wantWeights)
+			}
+			for version, weight := range tt.wantWeights {
+				if weights[version] != weight {
+					t.Errorf("active versions = %v, want %v", weights, tt.wantWeights)
+				}
+			}
+		})
+	}
+}
+
+func TestActivePromptCache(t *testing.T) {
+	s, repo := newPromptTestService(t)
+	storeTemplates(t, repo, 2)
+	data := PromptData{QuestionType: "email_response", MaxScore: 4, UserAnswer: "Dear Ms. Lee"}
+	render := func() int {
+		t.Helper()
+		rendered, err := s.Render(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rendered.Version
+	}
+
+	if v := render(); v != 1 {
+		t.Fatalf("rendered with v%d, want the active v1", v)
+	}
+	// Another instance activates v2: this one keeps v1 until its cached list expires.
+	if err := repo.SetTraffic("email_response", map[int]int{2: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if v := render(); v != 1 {
+		t.Errorf("rendered with v%d within the cache delay, want the cached v1", v)
+	}
+	s.mu.Lock()
+	cached := s.active["email_response"]
+	cached.fetchedAt = cached.fetchedAt.Add(-activePromptCacheTTL - time.Second)
+	s.active["email_response"] = cached
+	s.mu.Unlock()
+	if v := render(); v != 2 {
+		t.Errorf("rendered with v%d after the cache delay, want v2", v)
+	}
+
+	// An activation through this instance takes effect at once.
+	if _, err := s.ActivateTemplates("email_response", dto.PromptTemplateActivateDTO{Versions: []dto.PromptTrafficDTO{{Version: 1, Weight: 100}}}); err != nil {
+		t.Fatal(err)
+	}
+	if v := render(); v != 1 {
+		t.Errorf("rendered with v%d right after activating v1", v)
+	}
+}
+
+func TestPreviewTemplateChecksOrderInTest(t *testing.T) {
+	s, repo := newPromptTestService(t)
+	storeTemplates(t, repo, 1)
+	tests := []struct {
+		order   int
+		wantErr bool
+	}{
+		{order: 0},
+		{order: 6},
+		{order: 7},
+		{order: 1, wantErr: true}, // sentence_picture
+		{order: 8, wantErr: true}, // opinion_essay
+		{order: 9, wantErr: true},
+		{order: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		result, err := s.PreviewTemplate("email_response", 1, dto.PromptPreviewDTO{Prompt: "Reply to the email.", OrderInTest: tt.order, UserAnswer: "Dear Ms. Lee"})
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidPromptTemplate) {
+				t.Errorf("order %d: error = %v, want ErrInvalidPromptTemplate", tt.order, err)
+			}
+			continue
+		}
+		if err != nil || result.Version != 1 {
+			t.Errorf("order %d: preview = %+v, %v", tt.order, result, err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var ErrBankQuestionNotFound = errors.New("bank question not found")
+
+const (
+	defaultBankPageSize = 20
+	maxBankPageSize     = 100
+)
+
+// BankQuestionQuery holds the search parameters accepted by ListQuestions.
+type BankQuestionQuery struct {
+	Type       string
+	Difficulty string
+	Tags       []string
+	Search     string
+	Page       int // 1-based
+	PageSize   int
+}
+
+// QuestionBankService manages reusable questions that tests can be assembled from.
+type QuestionBankService interface {
+	CreateQuestion(createdByID uint, req dto.BankQuestionCreateDTO) (*dto.BankQuestionResponseDTO, error)
+	ListQuestions(query BankQuestionQuery) (*dto.BankQuestionListDTO, error)
+	GetQuestion(id uint) (*dto.BankQuestionResponseDTO, error)
+	UpdateQuestion(id uint, req dto.BankQuestionUpdateDTO) (*dto.BankQuestionResponseDTO, error)
+	DeleteQuestion(id uint) error
+}
+
+type questionBankService struct {
+	bankRepo repository.BankQuestionRepository
+}
+
+func NewQuestionBankService(bankRepo repository.BankQuestionRepository) QuestionBankService {
+	return &questionBankService{bankRepo: bankRepo}
+}
+
+func (s *questionBankService) CreateQuestion(createdByID uint, req dto.BankQuestionCreateDTO) (*dto.BankQuestionResponseDTO, error) {
+	question := model.BankQuestion{
+		Title:       strings.TrimSpace(req.Title),
+		Prompt:      req.Prompt,
+		Type:        req.Type,
+		ImageURL:    emptyToNil(derefString(req.ImageURL)),
+		GivenWord1:  emptyToNil(derefString(req.GivenWord1)),
+		GivenWord2:  emptyToNil(derefString(req.GivenWord2)),
+		Difficulty:  req.Difficulty,
+		Tags:        normalizeTags(req.Tags),
+		Version:     1,
+		CreatedByID: &createdByID,
+	}
+	if question.Difficulty == "" {
+		question.Difficulty = model.DifficultyMedium
+	}
+	if err := validateBankQuestion(&question); err != nil {
+		return nil, err
+	}
+
+	if err := s.bankRepo.Create(&question); err != nil {
+		log.Error().Err(err).Msg("CreateQuestion: Failed to store bank question")
+		return nil, fmt.Errorf("database error creating bank question: %w", err)
+	}
+	return toBankQuestionDTO(&question)
+}
+
+func (s *questionBankService) ListQuestions(query BankQuestionQuery) (*dto.BankQuestionListDTO, error) {
+	if query.Difficulty != "" && !model.IsValidDifficulty(query.Difficulty) {
+		return nil, fmt.Errorf("invalid difficulty filter %q", query.Difficulty)
+	}
+	if query.Type != "" && !isValidQuestionType(query.Type) {
+		return nil, fmt.Errorf("invalid type filter %q", query.Type)
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultBankPageSize
+	}
+	if query.PageSize > maxBankPageSize {
+		query.PageSize = maxBankPageSize
+	}
+
+	questions, total, err := s.bankRepo.FindAll(repository.BankQuestionFilter{
+		Type:       query.Type,
+		Difficulty: query.Difficulty,
+		Tags:       normalizeTags(query.Tags),
+		Search:     query.Search,
+		Limit:      query.PageSize,
+		Offset:     (query.Page - 1) * query.PageSize,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("ListQuestions: Failed to search question bank")
+		return nil, fmt.Errorf("error searching question bank: %w", err)
+	}
+
+	resp := &dto.BankQuestionListDTO{
+		Items:    make([]dto.BankQuestionResponseDTO, 0, len(questions)),
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+	for i := range questions {
+		item, err := toBankQuestionDTO(&questions[i])
+		if err != nil {
+			return nil, err
+		}
+		resp.Items = append(resp.Items, *item)
+	}
+	return resp, nil
+}
+
+func (s *questionBankService) GetQuestion(id uint) (*dto.BankQuestionResponseDTO, error) {
+	question, err := s.findQuestion(id)
+	if err != nil {
+		return nil, err
+	}
+	return toBankQuestionDTO(question)
+}
+
+// UpdateQuestion edits a bank question and bumps its version. Tests already built from it
+// keep their copies of the previous version.
+func (s *questionBankService) UpdateQuestion(id uint, req dto.BankQuestionUpdateDTO) (*dto.BankQuestionResponseDTO, error) {
+	question, err := s.findQuestion(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		question.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Prompt != nil {
+		question.Prompt = *req.Prompt
+	}
+	if req.ImageURL != nil {
+		question.ImageURL = emptyToNil(*req.ImageURL)
+	}
+	if req.GivenWord1 != nil {
+		question.GivenWord1 = emptyToNil(*req.GivenWord1)
+	}
+	if req.GivenWord2 != nil {
+		question.GivenWord2 = emptyToNil(*req.GivenWord2)
+	}
+	if req.Difficulty != nil {
+		question.Difficulty = *req.Difficulty
+	}
+	if req.Tags != nil {
+		question.Tags = normalizeTags(*req.Tags)
+	}
+	if err := validateBankQuestion(question); err != nil {
+		return nil, err
+	}
+	question.Version++
+
+	if err := s.bankRepo.Update(question); err != nil {
+		log.Error().Err(err).Uint("bankQuestionID", id).Msg("UpdateQuestion: Failed to save bank question")
+		return nil, fmt.Errorf("database error updating bank question: %w", err)
+	}
+	return toBankQuestionDTO(question)
+}
+
+func (s *questionBankService) DeleteQuestion(id uint) error {
+	if err := s.bankRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w with ID %d", ErrBankQuestionNotFound, id)
+		}
+		log.Error().Err(err).Uint("bankQuestionID", id).Msg("DeleteQuestion: Failed to delete bank question")
+		return fmt.Errorf("database error deleting bank question: %w", err)
+	}
+	return nil
+}
+
+func (s *questionBankService) findQuestion(id uint) (*model.BankQuestion, error) {
+	question, err := s.bankRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrBankQuestionNotFound, id)
+		}
+		return nil, fmt.Errorf("error loading bank question %d: %w", id, err)
+	}
+	return question, nil
+}
+
+func validateBankQuestion(question *model.BankQuestion) error {
+	if question.Title == "" {
+		return fmt.Errorf("title must not be empty")
+	}
+	if strings.TrimSpace(question.Prompt) == "" {
+		return fmt.Errorf("prompt must not be empty")
+	}
+	if !isValidQuestionType(question.Type) {
+		return fmt.Errorf("invalid question type %q", question.Type)
+	}
+	if !model.IsValidDifficulty(question.Difficulty) {
+		return fmt.Errorf("invalid difficulty %q, expected easy, medium or hard", question.Difficulty)
+	}
+	if question.Type == "sentence_picture" &&
+		(question.ImageURL == nil || question.GivenWord1 == nil || question.GivenWord2 == nil) {
+		return fmt.Errorf("question '%s' of type 'sentence_picture' requires ImageURL, GivenWord1, and GivenWord2 to be non-empty", question.Title)
+	}
+	return nil
+}
+
+func isValidQuestionType(questionType string) bool {
+	_, ok := rubricCriteria[questionType]
+	return ok
+}
+
+// normalizeTags lower-cases, trims and de-duplicates tags so that tag filters match reliably.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func toBankQuestionDTO(question *model.BankQuestion) (*dto.BankQuestionResponseDTO, error) {
+	var resp dto.BankQuestionResponseDTO
+	if err := copier.Copy(&resp, question); err != nil {
+		return nil, fmt.Errorf("error preparing response data: %w", err)
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	return &resp, nil
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "nil", tags: nil, want: []string{}},
+		{name: "already normalized", tags: []string{"business", "travel"}, want: []string{"business", "travel"}},
+		{name: "sorted", tags: []string{"travel", "business", "health"}, want: []string{"business", "health", "travel"}},
+		{name: "lower-cased and trimmed", tags: []string{"  Business ", "TRAVEL"}, want: []string{"business", "travel"}},
+		{name: "duplicates after normalizing", tags: []string{"Travel", "travel ", "TRAVEL"}, want: []string{"travel"}},
+		{name: "blank tags dropped", tags: []string{"", "   ", "office"}, want: []string{"office"}},
+		{name: "only blanks", tags: []string{" ", "\t"}, want: []string{}},
+		{name: "inner spaces kept", tags: []string{"Job Interview"}, want: []string{"job interview"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeTags(tt.tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeTags(%q) = %q, want %q", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
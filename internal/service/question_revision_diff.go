@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+// diffRevisionFields lists the content fields that differ between two revisions.
+func diffRevisionFields(from, to *model.QuestionRevision) []dto.FieldChangeDTO {
+	fields := []struct {
+		name     string
+		from, to *string
+	}{
+		{"title", &from.Title, &to.Title},
+		{"prompt", &from.Prompt, &to.Prompt},
+		{"type", &from.Type, &to.Type},
+		{"image_url", from.ImageURL, to.ImageURL},
+		{"given_word1", from.GivenWord1, to.GivenWord1},
+		{"given_word2", from.GivenWord2, to.GivenWord2},
+	}
+
+	changes := []dto.FieldChangeDTO{}
+	for _, f := range fields {
+		if derefString(f.from) != derefString(f.to) {
+			changes = append(changes, dto.FieldChangeDTO{Field: f.name, From: f.from, To: f.to})
+		}
+	}
+	if from.MaxScore != to.MaxScore {
+		fromScore, toScore := fmt.Sprintf("%.1f", from.MaxScore), fmt.Sprintf("%.1f", to.MaxScore)
+		changes = append(changes, dto.FieldChangeDTO{Field: "max_score", From: &fromScore, To: &toScore})
+	}
+	return changes
+}
+
+// diffLines computes a line-based diff (longest common subsequence) between two texts.
+// Prompts are short, so the quadratic table is not a concern.
+func diffLines(from, to string) []dto.DiffLineDTO {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]dto.DiffLineDTO, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, dto.DiffLineDTO{Op: "=", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, dto.DiffLineDTO{Op: "-", Text: a[i]})
+			i++
+		default:
+			lines = append(lines, dto.DiffLineDTO{Op: "+", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, dto.DiffLineDTO{Op: "-", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, dto.DiffLineDTO{Op: "+", Text: b[j]})
+	}
+	return lines
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+// formatDiff renders a diff as "<op><text>" lines, which keeps the expectations readable.
+func formatDiff(lines []dto.DiffLineDTO) []string {
+	formatted := make([]string, len(lines))
+	for i, line := range lines {
+		formatted[i] = line.Op + line.Text
+	}
+	return formatted
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+	}{
+		{name: "identical", from: "a\nb", to: "a\nb", want: []string{"=a", "=b"}},
+		{name: "both empty", from: "", to: "", want: []string{"="}},
+		{name: "from empty", from: "", to: "a", want: []string{"-", "+a"}},
+		{name: "to empty", from: "a", to: "", want: []string{"-a", "+"}},
+		{name: "line added at end", from: "a\nb", to: "a\nb\nc", want: []string{"=a", "=b", "+c"}},
+		{name: "line added at start", from: "b\nc", to: "a\nb\nc", want: []string{"+a", "=b", "=c"}},
+		{name: "line removed in middle", from: "a\nb\nc", to: "a\nc", want: []string{"=a", "-b", "=c"}},
+		{name: "line changed", from: "a\nb\nc", to: "a\nB\nc", want: []string{"=a", "-b", "+B", "=c"}},
+		{name: "all lines changed", from: "a\nb", to: "c\nd", want: []string{"-a", "-b", "+c", "+d"}},
+		{name: "trailing newline added", from: "a", to: "a\n", want: []string{"=a", "+"}},
+		{name: "repeated lines", from: "x\ny\nx", to: "x\nx", want: []string{"=x", "-y", "=x"}},
+		{name: "whitespace is significant", from: "a ", to: "a", want: []string{"-a ", "+a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDiff(diffLines(tt.from, tt.to)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines(%q, %q) = %q, want %q", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDiffLinesReconstructs checks that the kept and removed lines rebuild the old text, the kept and
+// added lines rebuild the new one, and no more lines change than the longest common subsequence allows.
+func TestDiffLinesReconstructs(t *testing.T) {
+	tests := []struct {
+		from, to string
+		wantKept int // Length of the longest common subsequence of the lines
+	}{
+		{from: "Write an email\nto your manager\nasking for leave.", to: "Write a short email\nto your manager\nasking for two days of leave.\nMention the dates.", wantKept: 1},
+		{from: "one\ntwo\nthree\nfour\nfive", to: "zero\ntwo\nfour\nsix", wantKept: 2},
+		{from: "a\nb\na\nb\na", to: "b\na\nb", wantKept: 3},
+	}
+	for _, tt := range tests {
+		var from, to []string
+		kept := 0
+		for _, line := range diffLines(tt.from, tt.to) {
+			switch line.Op {
+			case "=":
+				from, to = append(from, line.Text), append(to, line.Text)
+				kept++
+			case "-":
+				from = append(from, line.Text)
+			case "+":
+				to = append(to, line.Text)
+			default:
+				t.Fatalf("unexpected op %q", line.Op)
+			}
+		}
+		if got := strings.Join(from, "\n"); got != tt.from {
+			t.Errorf("old text rebuilt as %q, want %q", got, tt.from)
+		}
+		if got := strings.Join(to, "\n"); got != tt.to {
+			t.Errorf("new text rebuilt as %q, want %q", got, tt.to)
+		}
+		if kept != tt.wantKept {
+			t.Errorf("diff of %q and %q kept %d lines, want %d", tt.from, tt.to, kept, tt.wantKept)
+		}
+	}
+}
+
+func TestDiffRevisionFields(t *testing.T) {
+	image := "/api/v1/images/abc"
+	word := "desk"
+	from := &model.QuestionRevision{Title: "Q1", Prompt: "Describe the picture.", Type: "sentence_picture", ImageURL: &image, GivenWord1: &word, MaxScore: 3}
+	to := *from
+	to.Prompt = "Describe the photo."
+	to.ImageURL = nil
+	to.MaxScore = 4
+
+	var fields []string
+	for _, change := range diffRevisionFields(from, &to) {
+		fields = append(fields, change.Field)
+	}
+	if want := []string{"prompt", "image_url", "max_score"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("changed fields = %q, want %q", fields, want)
+	}
+	if changes := diffRevisionFields(from, from); len(changes) != 0 {
+		t.Errorf("identical revisions reported %d changes", len(changes))
+	}
+}
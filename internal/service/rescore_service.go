@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAttemptBusy      = errors.New("test attempt is still in progress or being scored")
+	ErrAnswerNotFound   = errors.New("answer not found")
+	ErrAnswerNotFailed  = errors.New("only answers whose scoring failed can be re-scored")
+	ErrNothingToRescore = errors.New("no answers to re-score")
+)
+
+// rescorableAttemptStates are the attempt statuses in which no scoring job is pending or running.
+var rescorableAttemptStates = []string{"completed", "completed_with_errors", "error"}
+
+// RescoreService scores answers of finished attempts again, e.g. after an LLM outage or a rubric change.
+// The current result of every selected answer is archived as AnswerScoreHistory before it is reset,
+// then the attempt goes back through the normal scoring queue, which recomputes TotalScore.
+type RescoreService interface {
+	// RescoreOwnAttempt re-scores failed answers of the caller's attempt; empty answerIDs selects all failed answers.
+	RescoreOwnAttempt(attemptID uint, userID uint, answerIDs []uint) (*dto.AttemptRescoreResultDTO, error)
+	RescoreAttempt(attemptID uint, requestedByID uint, req dto.AttemptRescoreDTO) (*dto.AttemptRescoreResultDTO, error)
+	RescoreTest(testID uint, requestedByID uint, req dto.TestRescoreDTO) (*dto.TestRescoreResultDTO, error)
+	GetOwnAnswerHistory(attemptID uint, answerID uint, userID uint) ([]dto.AnswerScoreHistoryDTO, error)
+	GetAnswerHistory(answerID uint) ([]dto.AnswerScoreHistoryDTO, error)
+}
+
+type rescoreService struct {
+	testRepo        repository.TestRepository
+	testAttemptRepo repository.TestAttemptRepository
+	historyRepo     repository.AnswerScoreHistoryRepository
+	db              *gorm.DB
+}
+
+func NewRescoreService(
+	testRepo repository.TestRepository,
+	testAttemptRepo repository.TestAttemptRepository,
+	historyRepo repository.AnswerScoreHistoryRepository,
+	db *gorm.DB,
+) RescoreService {
+	return &rescoreService{
+		testRepo:        testRepo,
+		testAttemptRepo: testAttemptRepo,
+		historyRepo:     historyRepo,
+		db:              db,
+	}
+}
+
+func (s *rescoreService) RescoreOwnAttempt(attemptID uint, userID uint, answerIDs []uint) (*dto.AttemptRescoreResultDTO, error) {
+	attempt, err := s.loadAttempt(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.UserID == nil || *attempt.UserID != userID {
+		return nil, ErrAttemptAccessDenied
+	}
+	// Learners may retry what failed, but not reroll a score they did not like.
+	if len(answerIDs) > 0 {
+		for _, answer := range attempt.Answers {
+			if containsID(answerIDs, answer.ID) && answer.ScoringStatus != "failed" {
+				return nil, fmt.Errorf("%w: answer %d is %q", ErrAnswerNotFailed, answer.ID, answer.ScoringStatus)
+			}
+		}
+	}
+	return s.rescore(attempt, answerIDs, true, &userID)
+}
+
+func (s *rescoreService) RescoreAttempt(attemptID uint, requestedByID uint, req dto.AttemptRescoreDTO) (*dto.AttemptRescoreResultDTO, error) {
+	attempt, err := s.loadAttempt(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	return s.rescore(attempt, req.AnswerIDs, req.OnlyFailed, &requestedByID)
+}
+
+// RescoreTest queues every finished attempt of the test. Attempts that are in progress or already
+// queued are skipped rather than failing the whole request.
+func (s *rescoreService) RescoreTest(testID uint, requestedByID uint, req dto.TestRescoreDTO) (*dto.TestRescoreResultDTO, error) {
+	if _, err := s.testRepo.FindByID(testID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
+		}
+		return nil, fmt.Errorf("error loading test %d: %w", testID, err)
+	}
+	attemptIDs, err := s.testAttemptRepo.FindIDsByTestAndStatuses(testID, rescorableAttemptStates)
+	if err != nil {
+		return nil, fmt.Errorf("error listing attempts of test %d: %w", testID, err)
+	}
+
+	result := &dto.TestRescoreResultDTO{TestID: testID}
+	for _, attemptID := range attemptIDs {
+		attempt, err := s.loadAttempt(attemptID)
+		if err != nil {
+			return result, err
+		}
+		queued, err := s.rescore(attempt, nil, req.OnlyFailed, &requestedByID)
+		switch {
+		case errors.Is(err, ErrAttemptBusy):
+			result.AttemptsSkipped++ // Picked up by a learner or another re-score since it was listed
+		case errors.Is(err, ErrNothingToRescore):
+			// Nothing failed in this attempt
+		case err != nil:
+			return result, err
+		default:
+			result.AttemptsQueued++
+			result.AnswersQueued += len(queued.AnswerIDs)
+		}
+	}
+	log.Info().Uint("testID", testID).Int("attemptsQueued", result.AttemptsQueued).Int("answersQueued", result.AnswersQueued).Msg("Rescore: Test queued for re-scoring.")
+	return result, nil
+}
+
+func (s *rescoreService) GetOwnAnswerHistory(attemptID uint, answerID uint, userID uint) ([]dto.AnswerScoreHistoryDTO, error) {
+	attempt, err := s.loadAttempt(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.UserID == nil || *attempt.UserID != userID {
+		return nil, ErrAttemptAccessDenied
+	}
+	found := false
+	for _, answer := range attempt.Answers {
+		if answer.ID == answerID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w with ID %d in attempt %d", ErrAnswerNotFound, answerID, attemptID)
+	}
+	return s.GetAnswerHistory(answerID)
+}
+
+func (s *rescoreService) GetAnswerHistory(answerID uint) ([]dto.AnswerScoreHistoryDTO, error) {
+	history, err := s.historyRepo.FindByAnswerID(answerID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading score history of answer %d: %w", answerID, err)
+	}
+	dtos := make([]dto.AnswerScoreHistoryDTO, len(history))
+	for i := range history {
+		if err := copier.Copy(&dtos[i], &history[i]); err != nil {
+			return nil, fmt.Errorf("error preparing response data: %w", err)
+		}
+	}
+	return dtos, nil
+}
+
+// rescore archives and resets the selected answers and queues the attempt, all in one transaction.
+// The status change is conditional, so an attempt can never be queued twice or while it is scored.
+func (s *rescoreService) rescore(attempt *model.TestAttempt, answerIDs []uint, onlyFailed bool, requestedByID *uint) (*dto.AttemptRescoreResultDTO, error) {
+	var selected []model.Answer
+	for _, answer := range attempt.Answers {
+		if len(answerIDs) > 0 && !containsID(answerIDs, answer.ID) {
+			continue
+		}
+		if onlyFailed && answer.ScoringStatus != "failed" {
+			continue
+		}
+		selected = append(selected, answer)
+	}
+	for _, id := range answerIDs {
+		if !containsAnswer(attempt.Answers, id) {
+			return nil, fmt.Errorf("%w with ID %d in attempt %d", ErrAnswerNotFound, id, attempt.ID)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("%w in attempt %d", ErrNothingToRescore, attempt.ID)
+	}
+
+	selectedIDs := make([]uint, len(selected))
+	for i := range selected {
+		selectedIDs[i] = selected[i].ID
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.TestAttempt{}).
+			Where("id = ? AND status IN ?", attempt.ID, rescorableAttemptStates).
+			Updates(map[string]interface{}{"status": "pending", "total_score": nil, "scaled_score": nil, "score_table_version": nil})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w with ID %d", ErrAttemptBusy, attempt.ID)
+		}
+
+		// Archive what is stored now that the attempt row is locked, not what was loaded before:
+		// a review committed in between must not be lost.
+		var current []model.Answer
+		err := tx.Preload("CriterionScores", func(db *gorm.DB) *gorm.DB {
+			return db.Order("answer_criterion_scores.id ASC")
+		}).Where("id IN ?", selectedIDs).Order("id ASC").Find(&current).Error
+		if err != nil {
+			return fmt.Errorf("failed to load answers to archive: %w", err)
+		}
+		history := make([]model.AnswerScoreHistory, 0, len(current))
+		for i := range current {
+			if current[i].ScoringStatus == "scored" || current[i].ScoringStatus == "failed" {
+				history = append(history, model.NewAnswerScoreHistory(&current[i], requestedByID))
+			}
+		}
+		if len(history) > 0 {
+			if err := tx.Create(&history).Error; err != nil {
+				return fmt.Errorf("failed to archive scoring results: %w", err)
+			}
+		}
+		if err := tx.Where("answer_id IN ?", selectedIDs).Delete(&model.AnswerCriterionScore{}).Error; err != nil {
+			return fmt.Errorf("failed to clear criterion scores: %w", err)
+		}
+		err = tx.Model(&model.Answer{}).Where("id IN ?", selectedIDs).Updates(map[string]interface{}{
+			"ai_score":          nil,
+			"ai_feedback":       "",
+			"ai_strengths":      nil,
+			"ai_errors":         nil,
+			"ai_revised_answer": "",
+			"ai_vocabulary":     nil,
+			"ai_uncapped_score": nil,
+			"prechecks":         nil,
+			"prompt_version":    nil,
+			"model_name":        "",
+			"scoring_status":    "pending",
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to reset answers: %w", err)
+		}
+
+		job := model.ScoringJob{
+			TestAttemptID: attempt.ID,
+			Status:        model.ScoringJobQueued,
+			RunAfter:      time.Now(),
+		}
+		if err := tx.Create(&job).Error; err != nil {
+			return fmt.Errorf("failed to queue scoring job: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Uint("attemptID", attempt.ID).Uints("answerIDs", selectedIDs).Msg("Rescore: Answers archived and queued for re-scoring.")
+	return &dto.AttemptRescoreResultDTO{AttemptID: attempt.ID, AnswerIDs: selectedIDs, Status: "pending"}, nil
+}
+
+func (s *rescoreService) loadAttempt(attemptID uint) (*model.TestAttempt, error) {
+	attempt, err := s.testAttemptRepo.FindByIDWithDetails(attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrAttemptNotFound, attemptID)
+		}
+		return nil, fmt.Errorf("error loading test attempt %d: %w", attemptID, err)
+	}
+	return attempt, nil
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnswer(answers []model.Answer, id uint) bool {
+	for _, answer := range answers {
+		if answer.ID == id {
+			return true
+		}
+	}
+	return false
+}
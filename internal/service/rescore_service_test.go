@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/lshigami/Ringtails/internal/testdb"
+)
+
+func TestRescore(t *testing.T) {
+	db := testdb.Open(t, &model.Test{}, &model.Question{}, &model.QuestionRevision{}, &model.TestAttempt{},
+		&model.Answer{}, &model.AnswerCriterionScore{}, &model.AnswerScoreHistory{}, &model.ScoringJob{})
+	s := NewRescoreService(repository.NewTestRepository(db), repository.NewTestAttemptRepository(db), repository.NewAnswerScoreHistoryRepository(db), db)
+
+	learnerID, adminID := uint(5), uint(1)
+	score := 2.5
+	test := model.Test{Title: "Rescore", Questions: []model.Question{
+		{Title: "Q1", Prompt: "Describe the picture.", Type: "sentence_picture", OrderInTest: 1, MaxScore: 3},
+		{Title: "Q2", Prompt: "Describe the picture.", Type: "sentence_picture", OrderInTest: 2, MaxScore: 3},
+	}}
+	if err := db.Create(&test).Error; err != nil {
+		t.Fatal(err)
+	}
+	attempt := model.TestAttempt{TestID: test.ID, UserID: &learnerID, Status: "completed_with_errors", TotalScore: &score, Answers: []model.Answer{
+		{QuestionID: test.Questions[0].ID, UserAnswer: "A man reads a book.", ScoringStatus: "scored", AIScore: &score, AIFeedback: "Good.",
+			CriterionScores: []model.AnswerCriterionScore{{Criterion: "Grammar", Score: 2.5, MaxScore: 3}}},
+		{QuestionID: test.Questions[1].ID, UserAnswer: "A woman drinks tea.", ScoringStatus: "failed", AIFeedback: "provider timeout"},
+	}}
+	if err := db.Create(&attempt).Error; err != nil {
+		t.Fatal(err)
+	}
+	scored, failed := attempt.Answers[0], attempt.Answers[1]
+	finishScoring := func() {
+		t.Helper()
+		if err := db.Model(&model.TestAttempt{}).Where("id = ?", attempt.ID).Update("status", "completed").Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	loadAnswer := func(id uint) model.Answer {
+		t.Helper()
+		var answer model.Answer
+		if err := db.Preload("CriterionScores").First(&answer, id).Error; err != nil {
+			t.Fatal(err)
+		}
+		return answer
+	}
+
+	if _, err := s.RescoreOwnAttempt(attempt.ID, learnerID+1, nil); !errors.Is(err, ErrAttemptAccessDenied) {
+		t.Fatalf("re-score of another learner's attempt: error = %v, want ErrAttemptAccessDenied", err)
+	}
+	if _, err := s.RescoreOwnAttempt(attempt.ID, learnerID, []uint{scored.ID}); !errors.Is(err, ErrAnswerNotFailed) {
+		t.Fatalf("learner re-score of a scored answer: error = %v, want ErrAnswerNotFailed", err)
+	}
+
+	result, err := s.RescoreOwnAttempt(attempt.ID, learnerID, nil)
+	if err != nil || len(result.AnswerIDs) != 1 || result.AnswerIDs[0] != failed.ID {
+		t.Fatalf("learner re-score = %+v, %v; want only the failed answer %d", result, err, failed.ID)
+	}
+	var stored model.TestAttempt
+	if err := db.First(&stored, attempt.ID).Error; err != nil || stored.Status != "pending" || stored.TotalScore != nil {
+		t.Fatalf("attempt after re-score = %q with total %v, %v; want pending without a total", stored.Status, stored.TotalScore, err)
+	}
+	var jobs int64
+	db.Model(&model.ScoringJob{}).Where("test_attempt_id = ?", attempt.ID).Count(&jobs)
+	if jobs != 1 {
+		t.Errorf("scoring jobs = %d, want 1", jobs)
+	}
+	if answer := loadAnswer(scored.ID); answer.ScoringStatus != "scored" || answer.AIScore == nil {
+		t.Errorf("an unselected answer was reset: %+v", answer)
+	}
+	history, err := s.GetOwnAnswerHistory(attempt.ID, failed.ID, learnerID)
+	if err != nil || len(history) != 1 || history[0].ScoringStatus != "failed" || history[0].AIFeedback != "provider timeout" {
+		t.Fatalf("history of the failed answer = %+v, %v; want the archived failure", history, err)
+	}
+	if _, err := s.RescoreAttempt(attempt.ID, adminID, dto.AttemptRescoreDTO{AnswerIDs: []uint{scored.ID}}); !errors.Is(err, ErrAttemptBusy) {
+		t.Fatalf("re-score of a queued attempt: error = %v, want ErrAttemptBusy", err)
+	}
+
+	finishScoring()
+	if _, err := s.RescoreAttempt(attempt.ID, adminID, dto.AttemptRescoreDTO{AnswerIDs: []uint{failed.ID + scored.ID + 100}}); !errors.Is(err, ErrAnswerNotFound) {
+		t.Fatalf("re-score of an answer outside the attempt: error = %v, want ErrAnswerNotFound", err)
+	}
+	result, err = s.RescoreAttempt(attempt.ID, adminID, dto.AttemptRescoreDTO{AnswerIDs: []uint{scored.ID}})
+	if err != nil || len(result.AnswerIDs) != 1 || result.AnswerIDs[0] != scored.ID {
+		t.Fatalf("admin re-score = %+v, %v; want the scored answer %d", result, err, scored.ID)
+	}
+	answer := loadAnswer(scored.ID)
+	if answer.ScoringStatus != "pending" || answer.AIScore != nil || answer.AIFeedback != "" || len(answer.CriterionScores) != 0 {
+		t.Errorf("re-scored answer was not reset: %+v", answer)
+	}
+	history, err = s.GetAnswerHistory(scored.ID)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("history of the scored answer = %+v, %v; want one entry", history, err)
+	}
+	archived := history[0]
+	if archived.ScoringStatus != "scored" || archived.AIScore == nil || *archived.AIScore != score || archived.RequestedByID == nil || *archived.RequestedByID != adminID {
+		t.Errorf("archived result = %+v, want the score %v requested by %d", archived, score, adminID)
+	}
+	if len(archived.CriterionScores) != 1 || archived.CriterionScores[0].Criterion != "Grammar" {
+		t.Errorf("archived criterion scores = %+v, want the Grammar score", archived.CriterionScores)
+	}
+
+	finishScoring()
+	if _, err := s.RescoreAttempt(attempt.ID, adminID, dto.AttemptRescoreDTO{OnlyFailed: true}); !errors.Is(err, ErrNothingToRescore) {
+		t.Fatalf("re-score of failed answers when none failed: error = %v, want ErrNothingToRescore", err)
+	}
+}
@@ -0,0 +1,439 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrDisputeNotFound     = errors.New("dispute not found")
+	ErrDisputeExists       = errors.New("answer already has an open dispute")
+	ErrDisputeClosed       = errors.New("dispute has already been resolved")
+	ErrAnswerNotDisputable = errors.New("only answers that were scored or failed to score can be disputed")
+	ErrInvalidReviewScore  = errors.New("invalid review score")
+	ErrInvalidDispute      = errors.New("invalid dispute")
+)
+
+const (
+	defaultDisputePageSize = 20
+	maxDisputePageSize     = 100
+)
+
+// DisputeQuery holds the filters accepted by ListDisputes.
+type DisputeQuery struct {
+	Status   string // Empty lists every dispute
+	TestID   uint
+	Page     int // 1-based
+	PageSize int
+}
+
+// ReviewService lets learners dispute the score of an answer and teachers review answers.
+// A teacher's score is stored next to the AI's, replaces it in the attempt's TotalScore and scaled
+// score, and survives re-scoring; the AI result stays on the answer so the two can be compared.
+type ReviewService interface {
+	DisputeAnswer(attemptID uint, answerID uint, userID uint, req dto.AnswerDisputeCreateDTO) (*dto.AnswerDisputeDTO, error)
+	ListOwnDisputes(userID uint) ([]dto.AnswerDisputeDTO, error)
+	ListDisputes(query DisputeQuery) (*dto.AnswerDisputeListDTO, error)
+	GetDispute(disputeID uint) (*dto.AnswerDisputeDetailDTO, error)
+	// ResolveDispute overrides the answer's score when req has one and upholds it otherwise.
+	ResolveDispute(disputeID uint, reviewerID uint, req dto.DisputeResolveDTO) (*dto.AnswerDisputeDetailDTO, error)
+	// ReviewAnswer sets a teacher's score on any finished answer, disputed or not, and resolves its open dispute.
+	ReviewAnswer(answerID uint, reviewerID uint, req dto.AnswerReviewDTO) (*dto.AnswerResponseDTO, error)
+	Calibration() ([]dto.ReviewCalibrationDTO, error)
+}
+
+type reviewService struct {
+	testAttemptRepo repository.TestAttemptRepository
+	answerRepo      repository.AnswerRepository
+	disputeRepo     repository.AnswerDisputeRepository
+	scoreConverter  ScoreConverterService
+	db              *gorm.DB
+}
+
+func NewReviewService(
+	testAttemptRepo repository.TestAttemptRepository,
+	answerRepo repository.AnswerRepository,
+	disputeRepo repository.AnswerDisputeRepository,
+	scoreConverter ScoreConverterService,
+	db *gorm.DB,
+) ReviewService {
+	return &reviewService{
+		testAttemptRepo: testAttemptRepo,
+		answerRepo:      answerRepo,
+		disputeRepo:     disputeRepo,
+		scoreConverter:  scoreConverter,
+		db:              db,
+	}
+}
+
+func (s *reviewService) DisputeAnswer(attemptID uint, answerID uint, userID uint, req dto.AnswerDisputeCreateDTO) (*dto.AnswerDisputeDTO, error) {
+	comment := strings.TrimSpace(req.Comment)
+	if comment == "" {
+		return nil, fmt.Errorf("%w: comment must not be empty", ErrInvalidDispute)
+	}
+	attempt, err := s.loadAttempt(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.UserID == nil || *attempt.UserID != userID {
+		return nil, ErrAttemptAccessDenied
+	}
+	answer := findAnswer(attempt.Answers, answerID)
+	if answer == nil {
+		return nil, fmt.Errorf("%w with ID %d in attempt %d", ErrAnswerNotFound, answerID, attemptID)
+	}
+	if err := checkDisputable(answer); err != nil {
+		return nil, err
+	}
+
+	dispute := model.AnswerDispute{
+		AnswerID:      answer.ID,
+		TestAttemptID: attempt.ID,
+		UserID:        userID,
+		Comment:       comment,
+		Status:        model.DisputeOpen,
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the attempt serializes this with re-scoring and with a second dispute of the same answer.
+		var locked model.TestAttempt
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&locked, attempt.ID).Error
+		if err != nil {
+			return err
+		}
+		if !containsStatus(rescorableAttemptStates, locked.Status) {
+			return fmt.Errorf("%w with ID %d", ErrAttemptBusy, attempt.ID)
+		}
+		// A rescore or review may have committed since the attempt was loaded; dispute the score that stands now.
+		var fresh model.Answer
+		if err := tx.First(&fresh, answer.ID).Error; err != nil {
+			return err
+		}
+		if err := checkDisputable(&fresh); err != nil {
+			return err
+		}
+		fresh.Question, fresh.QuestionRevision = answer.Question, answer.QuestionRevision
+		*answer = fresh
+		dispute.DisputedScore = answer.FinalScore()
+
+		// A resolved dispute does not block a new one: the learner may object again, e.g. after a re-score.
+		var existing int64
+		err = tx.Model(&model.AnswerDispute{}).Where("answer_id = ? AND status = ?", answer.ID, model.DisputeOpen).Count(&existing).Error
+		if err != nil {
+			return err
+		}
+		if existing > 0 {
+			return fmt.Errorf("%w: answer %d", ErrDisputeExists, answer.ID)
+		}
+		return tx.Omit(clause.Associations).Create(&dispute).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Uint("disputeID", dispute.ID).Uint("answerID", answer.ID).Uint("userID", userID).Msg("Review: Answer disputed.")
+	dispute.Answer = *answer
+	resp := toAnswerDisputeDTO(&dispute)
+	return &resp, nil
+}
+
+func (s *reviewService) ListOwnDisputes(userID uint) ([]dto.AnswerDisputeDTO, error) {
+	disputes, _, err := s.disputeRepo.FindAll(repository.AnswerDisputeFilter{UserID: userID})
+	if err != nil {
+		return nil, fmt.Errorf("error loading disputes: %w", err)
+	}
+	dtos := make([]dto.AnswerDisputeDTO, len(disputes))
+	for i := range disputes {
+		dtos[i] = toAnswerDisputeDTO(&disputes[i])
+	}
+	return dtos, nil
+}
+
+func (s *reviewService) ListDisputes(query DisputeQuery) (*dto.AnswerDisputeListDTO, error) {
+	switch query.Status {
+	case "", model.DisputeOpen, model.DisputeUpheld, model.DisputeOverridden:
+	default:
+		return nil, fmt.Errorf("%w: unknown status filter %q", ErrInvalidDispute, query.Status)
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = defaultDisputePageSize
+	}
+	if query.PageSize > maxDisputePageSize {
+		query.PageSize = maxDisputePageSize
+	}
+
+	disputes, total, err := s.disputeRepo.FindAll(repository.AnswerDisputeFilter{
+		Status: query.Status,
+		TestID: query.TestID,
+		Limit:  query.PageSize,
+		Offset: (query.Page - 1) * query.PageSize,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("ListDisputes: Failed to load disputes")
+		return nil, fmt.Errorf("error loading disputes: %w", err)
+	}
+
+	resp := &dto.AnswerDisputeListDTO{
+		Items:    make([]dto.AnswerDisputeDTO, 0, len(disputes)),
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+	for i := range disputes {
+		resp.Items = append(resp.Items, toAnswerDisputeDTO(&disputes[i]))
+	}
+	return resp, nil
+}
+
+func (s *reviewService) GetDispute(disputeID uint) (*dto.AnswerDisputeDetailDTO, error) {
+	dispute, err := s.disputeRepo.FindByID(disputeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrDisputeNotFound, disputeID)
+		}
+		return nil, fmt.Errorf("error loading dispute %d: %w", disputeID, err)
+	}
+	return &dto.AnswerDisputeDetailDTO{
+		AnswerDisputeDTO: toAnswerDisputeDTO(dispute),
+		Answer:           toAnswerResponseDTO(&dispute.Answer),
+	}, nil
+}
+
+func (s *reviewService) ResolveDispute(disputeID uint, reviewerID uint, req dto.DisputeResolveDTO) (*dto.AnswerDisputeDetailDTO, error) {
+	dispute, err := s.GetDispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != model.DisputeOpen {
+		return nil, fmt.Errorf("%w with ID %d", ErrDisputeClosed, disputeID)
+	}
+
+	if req.Score != nil {
+		if _, err := s.applyReview(dispute.AnswerID, disputeID, reviewerID, *req.Score, req.Feedback, req.Note); err != nil {
+			return nil, err
+		}
+		return s.GetDispute(disputeID)
+	}
+
+	now := time.Now()
+	result := s.db.Model(&model.AnswerDispute{}).Where("id = ? AND status = ?", disputeID, model.DisputeOpen).Updates(map[string]interface{}{
+		"status":          model.DisputeUpheld,
+		"reviewed_by_id":  reviewerID,
+		"resolution_note": strings.TrimSpace(req.Note),
+		"resolved_at":     now,
+	})
+	if result.Error != nil {
+		return nil, fmt.Errorf("error resolving dispute %d: %w", disputeID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w with ID %d", ErrDisputeClosed, disputeID) // Resolved by another teacher meanwhile
+	}
+	log.Info().Uint("disputeID", disputeID).Uint("reviewerID", reviewerID).Msg("Review: Dispute upheld.")
+	return s.GetDispute(disputeID)
+}
+
+func (s *reviewService) ReviewAnswer(answerID uint, reviewerID uint, req dto.AnswerReviewDTO) (*dto.AnswerResponseDTO, error) {
+	if req.Score == nil {
+		return nil, fmt.Errorf("%w: score is required", ErrInvalidReviewScore)
+	}
+	answer, err := s.applyReview(answerID, 0, reviewerID, *req.Score, req.Feedback, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	resp := toAnswerResponseDTO(answer)
+	return &resp, nil
+}
+
+func (s *reviewService) Calibration() ([]dto.ReviewCalibrationDTO, error) {
+	rows, err := s.disputeRepo.CalibrationByQuestionType()
+	if err != nil {
+		return nil, fmt.Errorf("error comparing teacher and AI scores: %w", err)
+	}
+	dtos := make([]dto.ReviewCalibrationDTO, len(rows))
+	for i := range rows {
+		copier.Copy(&dtos[i], &rows[i])
+	}
+	return dtos, nil
+}
+
+// applyReview stores a teacher's score on the answer, resolves its open dispute as overridden and
+// recomputes the attempt's TotalScore and scaled score, all in one transaction that holds the
+// attempt's row lock, so concurrent reviews and re-scores of the same attempt are applied one at a
+// time and the total is summed from the answers as committed. When disputeID is set, that dispute
+// must still be open.
+func (s *reviewService) applyReview(answerID uint, disputeID uint, reviewerID uint, score float64, feedback string, note string) (*model.Answer, error) {
+	stored, err := s.answerRepo.FindByID(answerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrAnswerNotFound, answerID)
+		}
+		return nil, fmt.Errorf("error loading answer %d: %w", answerID, err)
+	}
+	attempt, err := s.loadAttempt(stored.TestAttemptID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsStatus(rescorableAttemptStates, attempt.Status) {
+		return nil, fmt.Errorf("%w with ID %d", ErrAttemptBusy, attempt.ID)
+	}
+	answer := findAnswer(attempt.Answers, answerID)
+	if answer == nil {
+		return nil, fmt.Errorf("%w with ID %d", ErrAnswerNotFound, answerID)
+	}
+
+	question := answer.QuestionAsAnswered()
+	maxScore := question.MaxScore
+	if maxScore <= 0 {
+		_, maxScore, _ = testQuestionLayout(question.OrderInTest)
+	}
+	if score < 0 || (maxScore > 0 && score > maxScore) {
+		return nil, fmt.Errorf("%w: %.1f is outside 0-%.1f for question %d", ErrInvalidReviewScore, score, maxScore, question.OrderInTest)
+	}
+
+	now := time.Now()
+	answer.HumanScore = &score
+	answer.HumanFeedback = strings.TrimSpace(feedback)
+	answer.ReviewedByID = &reviewerID
+	answer.ReviewedAt = &now
+
+	var total float64
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var locked model.TestAttempt
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&locked, attempt.ID).Error
+		if err != nil {
+			return fmt.Errorf("error locking test attempt %d: %w", attempt.ID, err)
+		}
+		if !containsStatus(rescorableAttemptStates, locked.Status) {
+			return fmt.Errorf("%w with ID %d", ErrAttemptBusy, attempt.ID)
+		}
+
+		err = tx.Model(&model.Answer{}).Where("id = ?", answer.ID).Updates(map[string]interface{}{
+			"human_score":    answer.HumanScore,
+			"human_feedback": answer.HumanFeedback,
+			"reviewed_by_id": answer.ReviewedByID,
+			"reviewed_at":    answer.ReviewedAt,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to save the review: %w", err)
+		}
+		resolved := tx.Model(&model.AnswerDispute{}).Where("answer_id = ? AND status = ?", answer.ID, model.DisputeOpen)
+		if disputeID != 0 {
+			resolved = resolved.Where("id = ?", disputeID)
+		}
+		result := resolved.Updates(map[string]interface{}{
+			"status":          model.DisputeOverridden,
+			"reviewed_by_id":  reviewerID,
+			"resolution_note": strings.TrimSpace(note),
+			"resolved_at":     now,
+		})
+		if result.Error != nil {
+			return fmt.Errorf("failed to resolve the dispute: %w", result.Error)
+		}
+		if disputeID != 0 && result.RowsAffected == 0 {
+			return fmt.Errorf("%w with ID %d", ErrDisputeClosed, disputeID) // Resolved by another teacher meanwhile
+		}
+
+		// Sum the answers as they are now, including reviews and scores committed since the attempt was loaded.
+		var answers []model.Answer
+		err = tx.Select("id", "ai_score", "human_score", "scoring_status").Where("test_attempt_id = ?", attempt.ID).Find(&answers).Error
+		if err != nil {
+			return fmt.Errorf("error loading the answers of test attempt %d: %w", attempt.ID, err)
+		}
+		complete := true
+		for i := range answers {
+			if final := answers[i].FinalScore(); final != nil {
+				total += *final
+			} else {
+				complete = false
+			}
+		}
+		status := locked.Status
+		if complete {
+			status = "completed" // A teacher's score also stands in for an answer the AI failed to score
+		}
+		var scaledScore *float64
+		var tableVersion *int
+		if scaled, errScale := s.scoreConverter.ConvertToScaledScore(total); errScale != nil {
+			log.Warn().Err(errScale).Uint("attemptID", attempt.ID).Float64("rawScore", total).Msg("Review: Failed to scale score; it will be converted when read.")
+		} else {
+			scaledScore, tableVersion = &scaled.Score, &scaled.TableVersion
+		}
+		return tx.Model(&model.TestAttempt{}).Where("id = ?", attempt.ID).
+			Updates(map[string]interface{}{"total_score": total, "scaled_score": scaledScore, "score_table_version": tableVersion, "status": status}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Uint("answerID", answer.ID).Uint("reviewerID", reviewerID).Float64("humanScore", score).Float64("totalRawScore", total).Msg("Review: Answer score overridden.")
+	return answer, nil
+}
+
+func (s *reviewService) loadAttempt(attemptID uint) (*model.TestAttempt, error) {
+	attempt, err := s.testAttemptRepo.FindByIDWithDetails(attemptID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrAttemptNotFound, attemptID)
+		}
+		return nil, fmt.Errorf("error loading test attempt %d: %w", attemptID, err)
+	}
+	return attempt, nil
+}
+
+// checkDisputable reports whether the answer has a final result a learner can dispute.
+func checkDisputable(answer *model.Answer) error {
+	if answer.ScoringStatus != "scored" && answer.ScoringStatus != "failed" {
+		return fmt.Errorf("%w: answer %d is %q", ErrAnswerNotDisputable, answer.ID, answer.ScoringStatus)
+	}
+	return nil
+}
+
+func findAnswer(answers []model.Answer, id uint) *model.Answer {
+	for i := range answers {
+		if answers[i].ID == id {
+			return &answers[i]
+		}
+	}
+	return nil
+}
+
+func containsStatus(statuses []string, status string) bool {
+	for _, candidate := range statuses {
+		if candidate == status {
+			return true
+		}
+	}
+	return false
+}
+
+// toAnswerResponseDTO converts an answer whose question is loaded, showing the revision the learner answered.
+func toAnswerResponseDTO(answer *model.Answer) dto.AnswerResponseDTO {
+	var resp dto.AnswerResponseDTO
+	copier.Copy(&resp, answer)
+	answered := answer.QuestionAsAnswered()
+	copier.Copy(&resp.Question, &answered)
+	return resp
+}
+
+func toAnswerDisputeDTO(dispute *model.AnswerDispute) dto.AnswerDisputeDTO {
+	var resp dto.AnswerDisputeDTO
+	copier.Copy(&resp, dispute)
+	if dispute.Answer.Question.ID != 0 {
+		question := dispute.Answer.QuestionAsAnswered()
+		resp.QuestionType = question.Type
+		resp.OrderInTest = question.OrderInTest
+	}
+	return resp
+}
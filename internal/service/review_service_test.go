@@ -0,0 +1,125 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/lshigami/Ringtails/internal/testdb"
+)
+
+// stubScoreConverter scales a raw score by ten with table version 1.
+type stubScoreConverter struct {
+	ScoreConverterService
+}
+
+func (stubScoreConverter) ConvertToScaledScore(rawScore float64) (*ScaledScore, error) {
+	return &ScaledScore{Score: rawScore * 10, TableVersion: 1}, nil
+}
+
+func TestDisputeAndResolve(t *testing.T) {
+	db := testdb.Open(t, &model.Test{}, &model.Question{}, &model.QuestionRevision{}, &model.TestAttempt{},
+		&model.Answer{}, &model.AnswerCriterionScore{}, &model.AnswerDispute{})
+	s := NewReviewService(repository.NewTestAttemptRepository(db), repository.NewAnswerRepository(db),
+		repository.NewAnswerDisputeRepository(db), stubScoreConverter{}, db)
+
+	learnerID, teacherID := uint(5), uint(2)
+	first, second := 2.0, 1.5
+	total := first + second
+	test := model.Test{Title: "Review", Questions: []model.Question{
+		{Title: "Q1", Prompt: "Describe the picture.", Type: "sentence_picture", OrderInTest: 1, MaxScore: 3},
+		{Title: "Q2", Prompt: "Describe the picture.", Type: "sentence_picture", OrderInTest: 2, MaxScore: 3},
+	}}
+	if err := db.Create(&test).Error; err != nil {
+		t.Fatal(err)
+	}
+	attempt := model.TestAttempt{TestID: test.ID, UserID: &learnerID, Status: "completed", TotalScore: &total, Answers: []model.Answer{
+		{QuestionID: test.Questions[0].ID, UserAnswer: "A man reads a book.", ScoringStatus: "scored", AIScore: &first},
+		{QuestionID: test.Questions[1].ID, UserAnswer: "A woman drinks tea.", ScoringStatus: "scored", AIScore: &second},
+	}}
+	if err := db.Create(&attempt).Error; err != nil {
+		t.Fatal(err)
+	}
+	disputed := attempt.Answers[0]
+	dispute := func(comment string) (*dto.AnswerDisputeDTO, error) {
+		return s.DisputeAnswer(attempt.ID, disputed.ID, learnerID, dto.AnswerDisputeCreateDTO{Comment: comment})
+	}
+
+	if _, err := dispute("  "); !errors.Is(err, ErrInvalidDispute) {
+		t.Fatalf("dispute without a comment: error = %v, want ErrInvalidDispute", err)
+	}
+	if _, err := s.DisputeAnswer(attempt.ID, disputed.ID, learnerID+1, dto.AnswerDisputeCreateDTO{Comment: "Too low."}); !errors.Is(err, ErrAttemptAccessDenied) {
+		t.Fatalf("dispute of another learner's answer: error = %v, want ErrAttemptAccessDenied", err)
+	}
+
+	opened, err := dispute(" Too low. ")
+	if err != nil {
+		t.Fatalf("dispute: %v", err)
+	}
+	if opened.Status != model.DisputeOpen || opened.Comment != "Too low." || opened.DisputedScore == nil || *opened.DisputedScore != first || opened.OrderInTest != 1 {
+		t.Errorf("dispute = %+v, want an open dispute of the score %v", opened, first)
+	}
+	if _, err := dispute("Still too low."); !errors.Is(err, ErrDisputeExists) {
+		t.Fatalf("second open dispute: error = %v, want ErrDisputeExists", err)
+	}
+
+	upheld, err := s.ResolveDispute(opened.ID, teacherID, dto.DisputeResolveDTO{Note: "The score stands."})
+	if err != nil {
+		t.Fatalf("uphold: %v", err)
+	}
+	if upheld.Status != model.DisputeUpheld || upheld.ResolutionNote != "The score stands." || upheld.ReviewedByID == nil || *upheld.ReviewedByID != teacherID || upheld.ResolvedAt == nil {
+		t.Errorf("upheld dispute = %+v, want it resolved by %d with the note", upheld.AnswerDisputeDTO, teacherID)
+	}
+	if upheld.Answer.HumanScore != nil {
+		t.Errorf("upholding set a human score of %v", *upheld.Answer.HumanScore)
+	}
+	if _, err := s.ResolveDispute(opened.ID, teacherID, dto.DisputeResolveDTO{}); !errors.Is(err, ErrDisputeClosed) {
+		t.Fatalf("resolving an upheld dispute: error = %v, want ErrDisputeClosed", err)
+	}
+
+	reopened, err := dispute("Please look again.")
+	if err != nil {
+		t.Fatalf("dispute after the first was resolved: %v", err)
+	}
+	tooHigh := 3.5
+	if _, err := s.ResolveDispute(reopened.ID, teacherID, dto.DisputeResolveDTO{Score: &tooHigh}); !errors.Is(err, ErrInvalidReviewScore) {
+		t.Fatalf("override above the maximum: error = %v, want ErrInvalidReviewScore", err)
+	}
+
+	human := 3.0
+	overridden, err := s.ResolveDispute(reopened.ID, teacherID, dto.DisputeResolveDTO{Score: &human, Feedback: " Clear and correct. ", Note: "Raised."})
+	if err != nil {
+		t.Fatalf("override: %v", err)
+	}
+	if overridden.Status != model.DisputeOverridden || overridden.ResolutionNote != "Raised." {
+		t.Errorf("overridden dispute = %+v, want overridden with the note", overridden.AnswerDisputeDTO)
+	}
+	answer := overridden.Answer
+	if answer.HumanScore == nil || *answer.HumanScore != human || answer.HumanFeedback != "Clear and correct." || answer.AIScore == nil || *answer.AIScore != first {
+		t.Errorf("overridden answer = %+v, want the human score %v next to the AI score %v", answer, human, first)
+	}
+	var stored model.TestAttempt
+	if err := db.First(&stored, attempt.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	wantTotal := human + second
+	if stored.TotalScore == nil || *stored.TotalScore != wantTotal || stored.ScaledScore == nil || *stored.ScaledScore != wantTotal*10 {
+		t.Errorf("attempt total = %v scaled %v, want %v scaled %v", stored.TotalScore, stored.ScaledScore, wantTotal, wantTotal*10)
+	}
+	if _, err := s.ResolveDispute(reopened.ID, teacherID, dto.DisputeResolveDTO{Score: &human}); !errors.Is(err, ErrDisputeClosed) {
+		t.Fatalf("resolving an overridden dispute: error = %v, want ErrDisputeClosed", err)
+	}
+
+	again, err := dispute("Why not full marks?")
+	if err != nil {
+		t.Fatalf("dispute of the teacher's score: %v", err)
+	}
+	if again.DisputedScore == nil || *again.DisputedScore != human {
+		t.Errorf("disputed score = %v, want the teacher's score %v", again.DisputedScore, human)
+	}
+	if _, err := s.ResolveDispute(again.ID+1, teacherID, dto.DisputeResolveDTO{}); !errors.Is(err, ErrDisputeNotFound) {
+		t.Fatalf("resolving a missing dispute: error = %v, want ErrDisputeNotFound", err)
+	}
+}
@@ -1,103 +1,317 @@
 package service
 
 import (
+	"embed"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/rs/zerolog/log" // Thêm log để debug
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
-const MaxRawScorePossibleForTOEICWriting float64 = 28.0
-const MaxScaledScoreForTOEICWriting float64 = 200.0
+var (
+	ErrScoreTableNotFound = errors.New("score conversion table not found")
+	ErrInvalidScoreTable  = errors.New("invalid score conversion table")
+)
+
+//go:embed scoretables/toeic-writing-v1.json
+var builtinScoreTables embed.FS
+
+const (
+	builtinScoreTableFile = "scoretables/toeic-writing-v1.json"
+	defaultScoreRoundTo   = 5.0
+	activeTableCacheTTL   = 30 * time.Second // Other instances see a newly activated table within this delay
+)
+
+// ScaledScore is a converted score together with the version of the table that produced it.
+type ScaledScore struct {
+	Score        float64
+	TableVersion int
+}
 
+// ScoreConverterService converts raw totals to the TOEIC Writing scale with the active conversion table
+// and lets admins upload and activate new tables.
 type ScoreConverterService interface {
-	ConvertToScaledScore(rawScore float64) (float64, error)
-}
-
-type scoreConverterServiceImpl struct{}
-
-func NewScoreConverterService() ScoreConverterService {
-	return &scoreConverterServiceImpl{}
-}
-
-func (s *scoreConverterServiceImpl) ConvertToScaledScore(rawScore float64) (float64, error) {
-	log.Debug().Float64("inputRawScore", rawScore).Msg("ScoreConverterService: Converting raw score") // Log đầu vào
-
-	if rawScore < 0 || rawScore > MaxRawScorePossibleForTOEICWriting {
-		log.Error().Float64("rawScore", rawScore).Msg("ScoreConverterService: Raw score out of valid range")
-		return 0, fmt.Errorf("raw score %.2f is out of valid range (0-%.2f)", rawScore, MaxRawScorePossibleForTOEICWriting)
-	}
-
-	// ---- BẮT ĐẦU LOGIC QUY ĐỔI - ĐÂY LÀ NƠI CẦN XEM XÉT KỸ ----
-	// Logic tuyến tính đơn giản để làm ví dụ debug (NÊN THAY BẰNG BẢNG CHUẨN)
-	if MaxRawScorePossibleForTOEICWriting == 0 { // Tránh chia cho 0
-		log.Error().Msg("ScoreConverterService: MaxRawScorePossibleForTOEICWriting is zero, cannot perform linear scaling.")
-		return 0, fmt.Errorf("max raw score configuration error")
-	}
-	scaledScoreLinear := (rawScore / MaxRawScorePossibleForTOEICWriting) * MaxScaledScoreForTOEICWriting
-	log.Debug().Float64("rawScore", rawScore).Float64("linearlyScaledScore", scaledScoreLinear).Msg("ScoreConverterService: Linearly scaled score (for reference)")
-
-	// Bảng quy đổi ví dụ (CẦN THAY THẾ BẰNG BẢNG QUY ĐỔI CHÍNH XÁC CỦA TOEIC WRITING)
-	// Đây là một ví dụ được cải thiện hơn một chút so với trước, nhưng vẫn cần bảng chuẩn.
-	var scaledScore float64
-	switch {
-	case rawScore == 0:
-		scaledScore = 0
-	case rawScore <= 2:
-		scaledScore = 10 // Mốc điểm tùy ý
-	case rawScore <= 4:
-		scaledScore = 20
-	case rawScore <= 6:
-		scaledScore = 40
-	case rawScore <= 8:
-		scaledScore = 60
-	case rawScore <= 10:
-		scaledScore = 80
-	case rawScore <= 12:
-		scaledScore = 100
-	case rawScore <= 14:
-		scaledScore = 110
-	case rawScore <= 16:
-		scaledScore = 120
-	case rawScore <= 18:
-		scaledScore = 130
-	case rawScore == 18.8:
-		scaledScore = 140 // THÊM TEST CASE CỤ THỂ CHO 18.8 ĐỂ DEBUG
-	case rawScore <= 19:
-		scaledScore = 140
-	case rawScore <= 21:
-		scaledScore = 150
-	case rawScore <= 23:
-		scaledScore = 170
-	case rawScore <= 25:
-		scaledScore = 180
-	case rawScore <= 27:
-		scaledScore = 190
-	case rawScore >= MaxRawScorePossibleForTOEICWriting:
-		scaledScore = MaxScaledScoreForTOEICWriting // Max score 28 -> 200
-	default:
-		// Nếu không có trong các case trên, sử dụng phép nội suy tuyến tính đơn giản như một fallback
-		// Hoặc bạn có thể định nghĩa các khoảng điểm chặt chẽ hơn.
-		log.Warn().Float64("rawScore", rawScore).Msg("ScoreConverterService: Raw score did not match specific case, using linear fallback for this segment.")
-		// Ví dụ: nếu rawScore là 18.5, nó sẽ không khớp case nào ở trên.
-		// Cần một logic nội suy tốt hơn hoặc một bảng map chi tiết.
-		// Tạm thời, nếu không khớp, dùng lại linear scaling cho debug
-		scaledScore = (rawScore / MaxRawScorePossibleForTOEICWriting) * MaxScaledScoreForTOEICWriting
-	}
-	// ---- KẾT THÚC LOGIC QUY ĐỔI ----
-
-	// Đảm bảo điểm không vượt quá 200 và không nhỏ hơn 0
-	if scaledScore > MaxScaledScoreForTOEICWriting {
-		scaledScore = MaxScaledScoreForTOEICWriting
-	}
-	if scaledScore < 0 {
-		scaledScore = 0
-	}
-
-	// Làm tròn điểm theo chuẩn TOEIC (thường là bội số của 5 hoặc 10)
-	// Ví dụ: làm tròn đến bội số của 5 gần nhất
-	finalScaledScore := math.Round(scaledScore/5.0) * 5.0
-	log.Debug().Float64("rawScore", rawScore).Float64("calculatedScaledScore", scaledScore).Float64("finalRoundedScaledScore", finalScaledScore).Msg("ScoreConverterService: Score conversion details")
-
-	return finalScaledScore, nil
+	ConvertToScaledScore(rawScore float64) (*ScaledScore, error)
+	// ScaledScoreOf returns the scaled score recorded on a scored attempt. Attempts scored before
+	// tables were versioned are converted with the active table. Returns nil if the attempt has no total.
+	ScaledScoreOf(attempt *model.TestAttempt) (*ScaledScore, error)
+	// EnsureDefaultTable stores and activates the built-in table when no table exists yet.
+	EnsureDefaultTable() error
+
+	ListTables() ([]dto.ScoreTableDTO, error)
+	GetTable(version int) (*dto.ScoreTableDTO, error)
+	UploadTable(createdByID uint, req dto.ScoreTableUploadDTO) (*dto.ScoreTableDTO, error)
+	ActivateTable(version int) (*dto.ScoreTableDTO, error)
+	PreviewConversion(version int, rawScore float64) (*dto.ScoreConversionDTO, error)
+}
+
+type scoreConverterServiceImpl struct {
+	tableRepo repository.ScoreConversionTableRepository
+
+	mu              sync.Mutex
+	tables          map[int]*model.ScoreConversionTable // Tables never change once stored, so they are cached by version
+	activeVersion   int
+	activeFetchedAt time.Time
+}
+
+func NewScoreConverterService(tableRepo repository.ScoreConversionTableRepository) ScoreConverterService {
+	return &scoreConverterServiceImpl{
+		tableRepo: tableRepo,
+		tables:    make(map[int]*model.ScoreConversionTable),
+	}
+}
+
+func (s *scoreConverterServiceImpl) ConvertToScaledScore(rawScore float64) (*ScaledScore, error) {
+	table, err := s.activeTable()
+	if err != nil {
+		return nil, err
+	}
+	scaled, err := convertWithTable(table, rawScore)
+	if err != nil {
+		return nil, err
+	}
+	return &ScaledScore{Score: scaled, TableVersion: table.Version}, nil
+}
+
+func (s *scoreConverterServiceImpl) ScaledScoreOf(attempt *model.TestAttempt) (*ScaledScore, error) {
+	if attempt.TotalScore == nil {
+		return nil, nil
+	}
+	if attempt.ScaledScore != nil && attempt.ScoreTableVersion != nil {
+		return &ScaledScore{Score: *attempt.ScaledScore, TableVersion: *attempt.ScoreTableVersion}, nil
+	}
+	return s.ConvertToScaledScore(*attempt.TotalScore)
+}
+
+func (s *scoreConverterServiceImpl) EnsureDefaultTable() error {
+	count, err := s.tableRepo.Count()
+	if err != nil {
+		return fmt.Errorf("failed to count score conversion tables: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	data, err := builtinScoreTables.ReadFile(builtinScoreTableFile)
+	if err != nil {
+		return fmt.Errorf("failed to read built-in score table: %w", err)
+	}
+	var req dto.ScoreTableUploadDTO
+	if err := json.Unmarshal(data, &req); err != nil {
+		return fmt.Errorf("built-in score table %s is not valid JSON: %w", builtinScoreTableFile, err)
+	}
+	table, err := newScoreTable(req)
+	if err != nil {
+		return fmt.Errorf("built-in score table %s: %w", builtinScoreTableFile, err)
+	}
+	table.Source = "file"
+	if err := s.tableRepo.Create(table, true); err != nil {
+		return fmt.Errorf("failed to store built-in score table: %w", err)
+	}
+	log.Info().Str("name", table.Name).Int("version", table.Version).Msg("EnsureDefaultTable: Built-in score conversion table stored and activated.")
+	return nil
+}
+
+func (s *scoreConverterServiceImpl) ListTables() ([]dto.ScoreTableDTO, error) {
+	tables, err := s.tableRepo.FindAll()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching score conversion tables: %w", err)
+	}
+	dtos := make([]dto.ScoreTableDTO, len(tables))
+	for i := range tables {
+		copier.Copy(&dtos[i], &tables[i])
+	}
+	return dtos, nil
+}
+
+func (s *scoreConverterServiceImpl) GetTable(version int) (*dto.ScoreTableDTO, error) {
+	table, err := s.tableRepo.FindByVersion(version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with version %d", ErrScoreTableNotFound, version)
+		}
+		return nil, fmt.Errorf("error loading score conversion table %d: %w", version, err)
+	}
+	return toScoreTableDTO(table), nil
+}
+
+func (s *scoreConverterServiceImpl) UploadTable(createdByID uint, req dto.ScoreTableUploadDTO) (*dto.ScoreTableDTO, error) {
+	table, err := newScoreTable(req)
+	if err != nil {
+		return nil, err
+	}
+	table.Source = "upload"
+	table.CreatedByID = &createdByID
+	if err := s.tableRepo.Create(table, req.Activate); err != nil {
+		log.Error().Err(err).Msg("UploadTable: Failed to store score conversion table")
+		return nil, fmt.Errorf("database error storing score conversion table: %w", err)
+	}
+	if req.Activate {
+		s.setActiveVersion(table.Version)
+	}
+	log.Info().Int("version", table.Version).Bool("active", table.Active).Uint("createdByID", createdByID).Msg("UploadTable: Score conversion table stored.")
+	return toScoreTableDTO(table), nil
+}
+
+// ActivateTable switches conversion to the given version. Attempts that were already scored keep
+// the scaled score of the table recorded on them; only attempts scored from now on use this one.
+func (s *scoreConverterServiceImpl) ActivateTable(version int) (*dto.ScoreTableDTO, error) {
+	if err := s.tableRepo.Activate(version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with version %d", ErrScoreTableNotFound, version)
+		}
+		return nil, fmt.Errorf("database error activating score conversion table %d: %w", version, err)
+	}
+	s.setActiveVersion(version)
+	log.Info().Int("version", version).Msg("ActivateTable: Score conversion table activated.")
+	return s.GetTable(version)
+}
+
+func (s *scoreConverterServiceImpl) PreviewConversion(version int, rawScore float64) (*dto.ScoreConversionDTO, error) {
+	table, err := s.tableByVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	scaled, err := convertWithTable(table, rawScore)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidScoreTable, err)
+	}
+	return &dto.ScoreConversionDTO{RawScore: rawScore, ScaledScore: scaled, TableVersion: table.Version}, nil
+}
+
+func (s *scoreConverterServiceImpl) activeTable() (*model.ScoreConversionTable, error) {
+	s.mu.Lock()
+	version, fresh := s.activeVersion, time.Since(s.activeFetchedAt) < activeTableCacheTTL
+	s.mu.Unlock()
+	if version != 0 && fresh {
+		return s.tableByVersion(version)
+	}
+
+	table, err := s.tableRepo.FindActive()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: no table is active", ErrScoreTableNotFound)
+		}
+		return nil, fmt.Errorf("error loading the active score conversion table: %w", err)
+	}
+	s.mu.Lock()
+	s.tables[table.Version] = table
+	s.mu.Unlock()
+	s.setActiveVersion(table.Version)
+	return table, nil
+}
+
+func (s *scoreConverterServiceImpl) tableByVersion(version int) (*model.ScoreConversionTable, error) {
+	s.mu.Lock()
+	table, ok := s.tables[version]
+	s.mu.Unlock()
+	if ok {
+		return table, nil
+	}
+
+	table, err := s.tableRepo.FindByVersion(version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with version %d", ErrScoreTableNotFound, version)
+		}
+		return nil, fmt.Errorf("error loading score conversion table %d: %w", version, err)
+	}
+	s.mu.Lock()
+	s.tables[version] = table
+	s.mu.Unlock()
+	return table, nil
+}
+
+func (s *scoreConverterServiceImpl) setActiveVersion(version int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activeVersion = version
+	s.activeFetchedAt = time.Now()
+}
+
+// newScoreTable validates an uploaded table and fills in the defaults.
+func newScoreTable(req dto.ScoreTableUploadDTO) (*model.ScoreConversionTable, error) {
+	table := &model.ScoreConversionTable{
+		Name:          strings.TrimSpace(req.Name),
+		Description:   strings.TrimSpace(req.Description),
+		Interpolation: req.Interpolation,
+		RoundTo:       defaultScoreRoundTo,
+	}
+	if table.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidScoreTable)
+	}
+	if table.Interpolation == "" {
+		table.Interpolation = model.ScoreInterpolationStep
+	}
+	if !model.IsValidScoreInterpolation(table.Interpolation) {
+		return nil, fmt.Errorf("%w: interpolation must be %q or %q", ErrInvalidScoreTable, model.ScoreInterpolationStep, model.ScoreInterpolationLinear)
+	}
+	if req.RoundTo != nil {
+		if *req.RoundTo < 0 {
+			return nil, fmt.Errorf("%w: round_to must not be negative", ErrInvalidScoreTable)
+		}
+		table.RoundTo = *req.RoundTo
+	}
+
+	if len(req.Points) < 2 {
+		return nil, fmt.Errorf("%w: at least 2 points are required", ErrInvalidScoreTable)
+	}
+	table.Points = make([]model.ScorePoint, len(req.Points))
+	for i, p := range req.Points {
+		if math.IsNaN(p.Raw) || math.IsNaN(p.Scaled) || p.Scaled < 0 {
+			return nil, fmt.Errorf("%w: point %d has an invalid score", ErrInvalidScoreTable, i)
+		}
+		if i == 0 && p.Raw != 0 {
+			return nil, fmt.Errorf("%w: the first point must be for raw score 0", ErrInvalidScoreTable)
+		}
+		if i > 0 && p.Raw <= req.Points[i-1].Raw {
+			return nil, fmt.Errorf("%w: raw scores must increase (point %d)", ErrInvalidScoreTable, i)
+		}
+		if i > 0 && p.Scaled < req.Points[i-1].Scaled {
+			return nil, fmt.Errorf("%w: scaled scores must not decrease (point %d)", ErrInvalidScoreTable, i)
+		}
+		table.Points[i] = model.ScorePoint{Raw: p.Raw, Scaled: p.Scaled}
+	}
+	last := table.Points[len(table.Points)-1]
+	table.MaxRawScore = last.Raw
+	table.MaxScaledScore = last.Scaled
+	return table, nil
+}
+
+// convertWithTable maps a raw score through the table's points. Points are sorted by raw score
+// and start at 0, both guaranteed by newScoreTable.
+func convertWithTable(table *model.ScoreConversionTable, rawScore float64) (float64, error) {
+	if math.IsNaN(rawScore) || rawScore < 0 || rawScore > table.MaxRawScore {
+		return 0, fmt.Errorf("raw score %.2f is out of valid range (0-%.2f)", rawScore, table.MaxRawScore)
+	}
+
+	points := table.Points
+	i := sort.Search(len(points), func(i int) bool { return points[i].Raw >= rawScore })
+	scaled := points[i].Scaled
+	if table.Interpolation == model.ScoreInterpolationLinear && points[i].Raw != rawScore && i > 0 {
+		lo, hi := points[i-1], points[i]
+		scaled = lo.Scaled + (rawScore-lo.Raw)/(hi.Raw-lo.Raw)*(hi.Scaled-lo.Scaled)
+	}
+
+	if table.RoundTo > 0 {
+		scaled = math.Round(scaled/table.RoundTo) * table.RoundTo
+	}
+	return math.Max(0, math.Min(scaled, table.MaxScaledScore)), nil
+}
+
+func toScoreTableDTO(table *model.ScoreConversionTable) *dto.ScoreTableDTO {
+	var resp dto.ScoreTableDTO
+	copier.Copy(&resp, table)
+	return &resp
 }
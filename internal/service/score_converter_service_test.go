@@ -0,0 +1,185 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+func loadBuiltinScoreTable(t *testing.T) *model.ScoreConversionTable {
+	t.Helper()
+	data, err := builtinScoreTables.ReadFile(builtinScoreTableFile)
+	if err != nil {
+		t.Fatalf("reading the built-in table: %v", err)
+	}
+	var req dto.ScoreTableUploadDTO
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("decoding the built-in table: %v", err)
+	}
+	table, err := newScoreTable(req)
+	if err != nil {
+		t.Fatalf("built-in table is invalid: %v", err)
+	}
+	return table
+}
+
+func scorePoints(pairs ...float64) []dto.ScorePointDTO {
+	points := make([]dto.ScorePointDTO, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		points = append(points, dto.ScorePointDTO{Raw: pairs[i], Scaled: pairs[i+1]})
+	}
+	return points
+}
+
+func TestBuiltinScoreTable(t *testing.T) {
+	table := loadBuiltinScoreTable(t)
+	if table.Interpolation != model.ScoreInterpolationStep || table.RoundTo != 5 || table.MaxRawScore != 28 || table.MaxScaledScore != 200 {
+		t.Fatalf("table = %s, round to %v, max %v -> %v", table.Interpolation, table.RoundTo, table.MaxRawScore, table.MaxScaledScore)
+	}
+
+	// A raw score gets the scaled score of the first point at or above it, as the fixed bands did
+	// before tables existed: each point's raw score is the inclusive upper edge of its band.
+	for i, point := range table.Points {
+		edges := []float64{point.Raw}
+		if i > 0 {
+			edges = append(edges, table.Points[i-1].Raw+0.01, (table.Points[i-1].Raw+point.Raw)/2)
+		}
+		for _, raw := range edges {
+			got, err := convertWithTable(table, raw)
+			if err != nil {
+				t.Fatalf("raw %v: %v", raw, err)
+			}
+			if got != point.Scaled {
+				t.Errorf("raw %v = %v, want %v", raw, got, point.Scaled)
+			}
+		}
+	}
+
+	tests := []struct {
+		raw  float64
+		want float64
+	}{
+		{raw: 0, want: 0},
+		{raw: 0.5, want: 10},
+		{raw: 2, want: 10},
+		{raw: 2.5, want: 20},
+		{raw: 18, want: 130},
+		{raw: 18.8, want: 140},
+		{raw: 19.01, want: 150},
+		{raw: 27, want: 190},
+		{raw: 27.5, want: 200},
+		{raw: 28, want: 200},
+	}
+	for _, tt := range tests {
+		if got, err := convertWithTable(table, tt.raw); err != nil || got != tt.want {
+			t.Errorf("raw %v = %v (error %v), want %v", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestConvertWithTableOutOfRange(t *testing.T) {
+	table := loadBuiltinScoreTable(t)
+	for _, raw := range []float64{-0.01, -1, 28.01, 100, math.NaN(), math.Inf(1)} {
+		if got, err := convertWithTable(table, raw); err == nil {
+			t.Errorf("raw %v = %v, want an out of range error", raw, got)
+		}
+	}
+}
+
+func TestConvertWithTable(t *testing.T) {
+	roundTo := func(v float64) *float64 { return &v }
+	points := scorePoints(0, 0, 10, 100, 20, 150)
+
+	tests := []struct {
+		name          string
+		interpolation string
+		roundTo       *float64
+		points        []dto.ScorePointDTO
+		raw           float64
+		want          float64
+	}{
+		{name: "step at zero", interpolation: "step", roundTo: roundTo(0), points: points, raw: 0, want: 0},
+		{name: "step just above zero", interpolation: "step", roundTo: roundTo(0), points: points, raw: 0.01, want: 100},
+		{name: "step at a point", interpolation: "step", roundTo: roundTo(0), points: points, raw: 10, want: 100},
+		{name: "step just above a point", interpolation: "step", roundTo: roundTo(0), points: points, raw: 10.01, want: 150},
+		{name: "step at the max", interpolation: "step", roundTo: roundTo(0), points: points, raw: 20, want: 150},
+		{name: "linear at zero", interpolation: "linear", roundTo: roundTo(0), points: points, raw: 0, want: 0},
+		{name: "linear within the first band", interpolation: "linear", roundTo: roundTo(0), points: points, raw: 2.5, want: 25},
+		{name: "linear at a point", interpolation: "linear", roundTo: roundTo(0), points: points, raw: 10, want: 100},
+		{name: "linear within the last band", interpolation: "linear", roundTo: roundTo(0), points: points, raw: 13, want: 115},
+		{name: "linear at the max", interpolation: "linear", roundTo: roundTo(0), points: points, raw: 20, want: 150},
+		{name: "linear over a flat band", interpolation: "linear", roundTo: roundTo(0), points: scorePoints(0, 0, 5, 50, 10, 50), raw: 7, want: 50},
+		{name: "round to 5 down", interpolation: "linear", roundTo: roundTo(5), points: points, raw: 1.2, want: 10},
+		{name: "round to 5 half up", interpolation: "linear", roundTo: roundTo(5), points: points, raw: 1.25, want: 15},
+		{name: "round to 10", interpolation: "linear", roundTo: roundTo(10), points: points, raw: 13, want: 120},
+		{name: "round to 2.5", interpolation: "linear", roundTo: roundTo(2.5), points: points, raw: 0.4, want: 5},
+		{name: "default rounding is 5", interpolation: "linear", points: points, raw: 0.7, want: 5},
+		{name: "rounding never exceeds the max", interpolation: "linear", roundTo: roundTo(5), points: scorePoints(0, 0, 10, 198), raw: 10, want: 198},
+		{name: "rounding near the max", interpolation: "linear", roundTo: roundTo(5), points: scorePoints(0, 0, 10, 198), raw: 9.9, want: 195},
+		{name: "default interpolation is step", roundTo: roundTo(0), points: points, raw: 5, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := newScoreTable(dto.ScoreTableUploadDTO{Name: "test", Interpolation: tt.interpolation, RoundTo: tt.roundTo, Points: tt.points})
+			if err != nil {
+				t.Fatalf("newScoreTable: %v", err)
+			}
+			got, err := convertWithTable(table, tt.raw)
+			if err != nil {
+				t.Fatalf("convertWithTable: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("raw %v = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewScoreTableValidation(t *testing.T) {
+	negative := -5.0
+	tests := []struct {
+		name    string
+		req     dto.ScoreTableUploadDTO
+		wantErr string
+	}{
+		{name: "no name", req: dto.ScoreTableUploadDTO{Name: "  ", Points: scorePoints(0, 0, 1, 1)}, wantErr: "name is required"},
+		{name: "unknown interpolation", req: dto.ScoreTableUploadDTO{Name: "t", Interpolation: "cubic", Points: scorePoints(0, 0, 1, 1)}, wantErr: "interpolation must be"},
+		{name: "negative rounding", req: dto.ScoreTableUploadDTO{Name: "t", RoundTo: &negative, Points: scorePoints(0, 0, 1, 1)}, wantErr: "round_to must not be negative"},
+		{name: "one point", req: dto.ScoreTableUploadDTO{Name: "t", Points: scorePoints(0, 0)}, wantErr: "at least 2 points"},
+		{name: "does not start at zero", req: dto.ScoreTableUploadDTO{Name: "t", Points: scorePoints(1, 0, 2, 10)}, wantErr: "first point must be for raw score 0"},
+		{name: "raw scores repeat", req: dto.ScoreTableUploadDTO{Name: "t", Points: scorePoints(0, 0, 5, 10, 5, 20)}, wantErr: "raw scores must increase (point 2)"},
+		{name: "raw scores decrease", req: dto.ScoreTableUploadDTO{Name: "t", Points: scorePoints(0, 0, 5, 10, 4, 20)}, wantErr: "raw scores must increase (point 2)"},
+		{name: "scaled scores decrease", req: dto.ScoreTableUploadDTO{Name: "t", Points: scorePoints(0, 0, 5, 20, 10, 10)}, wantErr: "scaled scores must not decrease (point 2)"},
+		{name: "negative scaled score", req: dto.ScoreTableUploadDTO{Name: "t", Points: scorePoints(0, -1, 5, 20)}, wantErr: "point 0 has an invalid score"},
+		{name: "NaN raw score", req: dto.ScoreTableUploadDTO{Name: "t", Points: scorePoints(0, 0, math.NaN(), 20)}, wantErr: "point 1 has an invalid score"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newScoreTable(tt.req)
+			if !errors.Is(err, ErrInvalidScoreTable) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want ErrInvalidScoreTable containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewScoreTableDefaults(t *testing.T) {
+	table, err := newScoreTable(dto.ScoreTableUploadDTO{Name: " custom ", Description: " d ", Points: scorePoints(0, 0, 8, 90, 12, 140)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if table.Name != "custom" || table.Description != "d" {
+		t.Errorf("name and description not trimmed: %q, %q", table.Name, table.Description)
+	}
+	if table.Interpolation != model.ScoreInterpolationStep || table.RoundTo != defaultScoreRoundTo {
+		t.Errorf("defaults = %s rounded to %v, want step rounded to %v", table.Interpolation, table.RoundTo, defaultScoreRoundTo)
+	}
+	if table.MaxRawScore != 12 || table.MaxScaledScore != 140 || len(table.Points) != 3 {
+		t.Errorf("max = %v -> %v with %d points, want 12 -> 140 with 3", table.MaxRawScore, table.MaxScaledScore, len(table.Points))
+	}
+}
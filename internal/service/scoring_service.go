@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrScoringDeferred means some answers of the attempt were not scored because the AI provider is
+// unavailable. The attempt is back in "pending" and its job should be run again later.
+var ErrScoringDeferred = errors.New("scoring deferred while the AI provider is unavailable")
+
+// ScoringService runs AI scoring for the answers of a persisted TestAttempt.
+// It is driven by the background ScoringWorkerPool, never by an HTTP request.
+type ScoringService interface {
+	// ScoreAttempt scores every answer of the attempt that is not yet scored and finalizes the attempt.
+	// A returned error means the attempt could not be processed (e.g. database failure) and should be retried;
+	// LLM failures on individual answers are recorded on the answers and do not produce an error.
+	// ErrScoringDeferred is returned when answers were deferred because the provider is down.
+	ScoreAttempt(ctx context.Context, attemptID uint) error
+}
+
+type scoringService struct {
+	testAttemptRepo repository.TestAttemptRepository
+	answerRepo      repository.AnswerRepository
+	llmService      LLMService
+	scoreConverter  ScoreConverterService
+}
+
+func NewScoringService(
+	testAttemptRepo repository.TestAttemptRepository,
+	answerRepo repository.AnswerRepository,
+	llmService LLMService,
+	scoreConverter ScoreConverterService,
+) ScoringService {
+	return &scoringService{
+		testAttemptRepo: testAttemptRepo,
+		answerRepo:      answerRepo,
+		llmService:      llmService,
+		scoreConverter:  scoreConverter,
+	}
+}
+
+// answerProcessingResult is used to carry results from goroutines processing answers.
+type answerProcessingResult struct {
+	processedAnswer model.Answer
+	err             error // Error during DB update of this specific answer
+}
+
+func (s *scoringService) ScoreAttempt(ctx context.Context, attemptID uint) error {
+	if err := ctx.Err(); err != nil {
+		return err // Shutting down; leave the job for the next run
+	}
+
+	attempt, err := s.testAttemptRepo.FindByIDWithDetails(attemptID)
+	if err != nil {
+		return fmt.Errorf("failed to load test attempt %d for scoring: %w", attemptID, err)
+	}
+
+	if err := s.testAttemptRepo.UpdateStatus(attempt.ID, "scoring"); err != nil {
+		return fmt.Errorf("failed to mark test attempt %d as scoring: %w", attemptID, err)
+	}
+
+	// Answers already scored by an earlier, interrupted run of this job are kept as they are.
+	var toScore []model.Answer
+	for _, answer := range attempt.Answers {
+		if answer.ScoringStatus != "scored" {
+			toScore = append(toScore, answer)
+		}
+	}
+
+	// Process answers for AI feedback and scoring in parallel
+	var wg sync.WaitGroup
+	resultsChan := make(chan answerProcessingResult, len(toScore))
+
+	for i := range toScore {
+		wg.Add(1)
+		go func(currentAnswer model.Answer) {
+			defer wg.Done()
+
+			questionModel := currentAnswer.QuestionAsAnswered() // Score against the revision the learner saw
+
+			log.Info().Uint("answerID", currentAnswer.ID).Uint("questionID", questionModel.ID).Msg("ScoreAttempt: Goroutine processing answer with AI.")
+			eval, llmErr := s.llmService.ScoreAndFeedbackAnswer(ctx, &questionModel, currentAnswer.UserAnswer, ScoringSubject{AnswerID: &currentAnswer.ID})
+			if errors.Is(llmErr, ErrLLMUnavailable) {
+				log.Warn().Err(llmErr).Uint("answerID", currentAnswer.ID).Msg("ScoreAttempt: AI provider unavailable, deferring answer.")
+				applyScoringDeferral(&currentAnswer, llmErr)
+			} else if llmErr != nil {
+				log.Error().Err(llmErr).Uint("answerID", currentAnswer.ID).Msg("ScoreAttempt: Error from LLM service for answer.")
+				applyScoringFailure(&currentAnswer, llmErr)
+			} else {
+				applyEvaluation(&currentAnswer, eval)
+			}
+
+			// Update the individual Answer record in the database
+			if updateErr := s.answerRepo.SaveScoringResult(&currentAnswer); updateErr != nil {
+				log.Error().Err(updateErr).Uint("answerID", currentAnswer.ID).Msg("ScoreAttempt: Failed to update answer with AI results.")
+				resultsChan <- answerProcessingResult{processedAnswer: currentAnswer, err: updateErr}
+				return
+			}
+			resultsChan <- answerProcessingResult{processedAnswer: currentAnswer}
+		}(toScore[i])
+	}
+	wg.Wait()
+	close(resultsChan)
+
+	processed := make(map[uint]model.Answer, len(toScore))
+	for result := range resultsChan {
+		if result.err != nil {
+			// The answer's result is lost; fail the run so the job is retried.
+			return fmt.Errorf("failed to save scoring result for answer %d: %w", result.processedAnswer.ID, result.err)
+		}
+		processed[result.processedAnswer.ID] = result.processedAnswer
+	}
+
+	// Update TestAttempt with total raw score and final status
+	totalRawScore := 0.0
+	allAnswersScoredSuccessfully := true
+	deferred := 0
+	for _, answer := range attempt.Answers {
+		if p, ok := processed[answer.ID]; ok {
+			answer = p
+		}
+		if answer.ScoringStatus == "deferred" {
+			deferred++
+		}
+		score := answer.FinalScore() // A teacher's review outlasts re-scoring by the AI
+		if score == nil {
+			allAnswersScoredSuccessfully = false
+			continue
+		}
+		totalRawScore += *score
+	}
+
+	if deferred > 0 {
+		// Not finished: keep the scored answers and wait for the provider instead of reporting errors.
+		if err := s.testAttemptRepo.UpdateStatus(attempt.ID, "pending"); err != nil {
+			return fmt.Errorf("failed to mark test attempt %d as pending: %w", attempt.ID, err)
+		}
+		return fmt.Errorf("%w: %d answer(s) of attempt %d", ErrScoringDeferred, deferred, attempt.ID)
+	}
+
+	status := "completed"
+	if !allAnswersScoredSuccessfully {
+		status = "completed_with_errors"
+	}
+	// The scaled score is fixed now, with the table active at this moment, so activating
+	// another table later does not silently change results learners have already seen.
+	var scaledScore *float64
+	var tableVersion *int
+	if scaled, errScale := s.scoreConverter.ConvertToScaledScore(totalRawScore); errScale != nil {
+		log.Warn().Err(errScale).Uint("attemptID", attempt.ID).Float64("rawScore", totalRawScore).Msg("ScoreAttempt: Failed to scale score; it will be converted when read.")
+	} else {
+		scaledScore, tableVersion = &scaled.Score, &scaled.TableVersion
+	}
+	if err := s.testAttemptRepo.UpdateScoresAndStatus(attempt.ID, &totalRawScore, scaledScore, tableVersion, status); err != nil {
+		return fmt.Errorf("failed to save total score and final status for attempt %d: %w", attempt.ID, err)
+	}
+
+	log.Info().Uint("attemptID", attempt.ID).Float64("totalRawScore", totalRawScore).Str("status", status).Msg("ScoreAttempt: Attempt scored.")
+	return nil
+}
+
+// applyEvaluation copies a validated LLM evaluation onto the answer's structured columns.
+func applyEvaluation(answer *model.Answer, eval *LLMEvaluation) {
+	answer.AIScore = eval.Score
+	answer.AIFeedback = eval.FeedbackMarkdown()
+	answer.AIStrengths = eval.Strengths
+	answer.AIErrors = eval.Errors
+	answer.AIRevisedAnswer = eval.RevisedAnswer
+	answer.AIVocabulary = eval.Vocabulary
+	answer.AIUncappedScore = eval.UncappedScore
+	answer.Prechecks = eval.Prechecks
+	answer.PromptVersion = eval.PromptVersion
+	answer.ModelName = eval.Model
+	answer.CriterionScores = make([]model.AnswerCriterionScore, 0, len(eval.Criteria))
+	for _, c := range eval.Criteria {
+		answer.CriterionScores = append(answer.CriterionScores, model.AnswerCriterionScore{
+			Criterion: c.Criterion,
+			Score:     c.Score,
+			MaxScore:  c.MaxScore,
+			Comment:   c.Comment,
+		})
+	}
+	answer.ScoringStatus = "scored"
+}
+
+// applyScoringDeferral clears any previous AI result and marks the answer to be scored on the job's next run.
+func applyScoringDeferral(answer *model.Answer, err error) {
+	applyScoringFailure(answer, err)
+	answer.AIFeedback = "AI scoring is temporarily unavailable; this answer will be scored automatically once the service recovers."
+	answer.ScoringStatus = "deferred"
+}
+
+// applyScoringFailure clears any previous AI result and records why scoring failed.
+func applyScoringFailure(answer *model.Answer, err error) {
+	answer.AIScore = nil // Explicitly set AIScore to nil on error
+	answer.AIFeedback = fmt.Sprintf("AI scoring failed: %s", err.Error())
+	answer.AIStrengths = nil
+	answer.AIErrors = nil
+	answer.AIRevisedAnswer = ""
+	answer.AIVocabulary = nil
+	answer.AIUncappedScore = nil
+	answer.Prechecks = nil
+	answer.PromptVersion = nil
+	answer.ModelName = ""
+	answer.CriterionScores = nil
+	answer.ScoringStatus = "failed"
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// ScoringWorkerPool polls the scoring_jobs table and runs ScoringService for each claimed job.
+type ScoringWorkerPool struct {
+	jobRepo         repository.ScoringJobRepository
+	testAttemptRepo repository.TestAttemptRepository
+	scoringService  ScoringService
+	cfg             config.Scoring
+	deferDelay      time.Duration // Wait before re-running a job deferred by the LLM circuit breaker
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScoringWorkerPool(
+	jobRepo repository.ScoringJobRepository,
+	testAttemptRepo repository.TestAttemptRepository,
+	scoringService ScoringService,
+	cfg *config.Config,
+) *ScoringWorkerPool {
+	scoringCfg := cfg.Scoring
+	if scoringCfg.Workers <= 0 {
+		scoringCfg.Workers = 1
+	}
+	if scoringCfg.PollInterval <= 0 {
+		scoringCfg.PollInterval = 2 * time.Second
+	}
+	if scoringCfg.MaxAttempts <= 0 {
+		scoringCfg.MaxAttempts = 1
+	}
+	deferDelay := cfg.LLM.BreakerCooldown
+	if deferDelay <= 0 {
+		deferDelay = time.Minute
+	}
+	return &ScoringWorkerPool{
+		jobRepo:         jobRepo,
+		testAttemptRepo: testAttemptRepo,
+		scoringService:  scoringService,
+		cfg:             scoringCfg,
+		deferDelay:      deferDelay,
+	}
+}
+
+// Start launches the workers and the stale-job reaper. It returns immediately.
+func (p *ScoringWorkerPool) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	hostname, _ := os.Hostname()
+	for i := 0; i < p.cfg.Workers; i++ {
+		workerID := fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), i)
+		p.wg.Add(1)
+		go p.runWorker(ctx, workerID)
+	}
+
+	if p.cfg.StaleAfter > 0 {
+		p.wg.Add(1)
+		go p.runReaper(ctx)
+	}
+	log.Info().Int("workers", p.cfg.Workers).Dur("pollInterval", p.cfg.PollInterval).Msg("Scoring worker pool started")
+}
+
+// Stop signals workers to finish their current job and waits for them until ctx expires.
+// Jobs still running when ctx expires are picked up again by the reaper after a restart.
+func (p *ScoringWorkerPool) Stop(ctx context.Context) error {
+	if p.cancel == nil {
+		return nil
+	}
+	p.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Info().Msg("Scoring worker pool stopped")
+		return nil
+	case <-ctx.Done():
+		log.Warn().Msg("Scoring worker pool did not stop in time; unfinished jobs will be requeued on next start")
+		return ctx.Err()
+	}
+}
+
+func (p *ScoringWorkerPool) runWorker(ctx context.Context, workerID string) {
+	defer p.wg.Done()
+	for {
+		// Drain the queue before sleeping so a burst of submissions is not throttled by the poll interval.
+		worked := p.processNext(ctx, workerID)
+		if worked {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.cfg.PollInterval):
+		}
+	}
+}
+
+// processNext claims and runs one job. It reports whether a job was found.
+func (p *ScoringWorkerPool) processNext(ctx context.Context, workerID string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	job, err := p.jobRepo.ClaimNext(workerID)
+	if err != nil {
+		log.Error().Err(err).Str("workerID", workerID).Msg("Scoring worker: Failed to claim job")
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	log.Info().Uint("jobID", job.ID).Uint("attemptID", job.TestAttemptID).Int("attempt", job.Attempts).Str("workerID", workerID).Msg("Scoring worker: Processing job")
+	jobCtx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+	leaseLost := p.startHeartbeat(jobCtx, cancelJob, job.ID, workerID)
+
+	scoreErr := p.scoringService.ScoreAttempt(jobCtx, job.TestAttemptID)
+	cancelJob()
+	if <-leaseLost {
+		log.Warn().Uint("jobID", job.ID).Str("workerID", workerID).Msg("Scoring worker: Lost the job lease mid-run; leaving the job to its new holder")
+		return true
+	}
+	if scoreErr != nil {
+		p.handleJobError(job, workerID, scoreErr)
+		return true
+	}
+
+	if err := p.jobRepo.MarkDone(job.ID, workerID); err != nil {
+		p.logUpdateError(err, job.ID, "Scoring worker: Failed to mark job done")
+	}
+	return true
+}
+
+// startHeartbeat refreshes the job lock every StaleAfter/3 until ctx is done, so a long run is not reaped.
+// If the lease is lost it cancels the run. The returned channel yields once, after ctx is done,
+// and reports whether the lease was lost.
+func (p *ScoringWorkerPool) startHeartbeat(ctx context.Context, cancel context.CancelFunc, jobID uint, workerID string) <-chan bool {
+	leaseLost := make(chan bool, 1)
+	if p.cfg.StaleAfter <= 0 {
+		go func() {
+			<-ctx.Done()
+			leaseLost <- false
+		}()
+		return leaseLost
+	}
+
+	interval := p.cfg.StaleAfter / 3
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				leaseLost <- false
+				return
+			case <-ticker.C:
+				err := p.jobRepo.Heartbeat(jobID, workerID)
+				if errors.Is(err, repository.ErrLeaseLost) {
+					cancel()
+					leaseLost <- true
+					return
+				}
+				if err != nil {
+					// A missed beat is tolerable; the next one has StaleAfter/3 left before the reaper acts.
+					log.Error().Err(err).Uint("jobID", jobID).Msg("Scoring worker: Failed to refresh job lock")
+				}
+			}
+		}
+	}()
+	return leaseLost
+}
+
+func (p *ScoringWorkerPool) handleJobError(job *model.ScoringJob, workerID string, scoreErr error) {
+	if errors.Is(scoreErr, ErrScoringDeferred) {
+		// The provider is down, not this job; waiting must not use up its attempts.
+		log.Warn().Err(scoreErr).Uint("jobID", job.ID).Dur("retryIn", p.deferDelay).Msg("Scoring worker: Job deferred")
+		if err := p.jobRepo.Defer(job.ID, workerID, scoreErr.Error(), time.Now().Add(p.deferDelay)); err != nil {
+			p.logUpdateError(err, job.ID, "Scoring worker: Failed to defer job")
+		}
+		return
+	}
+	if job.Attempts >= p.cfg.MaxAttempts {
+		log.Error().Err(scoreErr).Uint("jobID", job.ID).Int("attempts", job.Attempts).Msg("Scoring worker: Job failed permanently")
+		if err := p.jobRepo.MarkFailed(job.ID, workerID, scoreErr.Error()); err != nil {
+			// Someone else holds the job now; the attempt status is theirs to decide.
+			p.logUpdateError(err, job.ID, "Scoring worker: Failed to mark job failed")
+			return
+		}
+		if err := p.testAttemptRepo.UpdateStatus(job.TestAttemptID, "error"); err != nil {
+			log.Error().Err(err).Uint("attemptID", job.TestAttemptID).Msg("Scoring worker: Failed to mark attempt as error")
+		}
+		return
+	}
+
+	// Exponential backoff: RetryDelay, 2*RetryDelay, 4*RetryDelay, ...
+	delay := p.cfg.RetryDelay << (job.Attempts - 1)
+	log.Warn().Err(scoreErr).Uint("jobID", job.ID).Int("attempts", job.Attempts).Dur("retryIn", delay).Msg("Scoring worker: Job failed, will retry")
+	if err := p.jobRepo.MarkRetry(job.ID, workerID, scoreErr.Error(), time.Now().Add(delay)); err != nil {
+		p.logUpdateError(err, job.ID, "Scoring worker: Failed to requeue job")
+	}
+}
+
+func (p *ScoringWorkerPool) logUpdateError(err error, jobID uint, msg string) {
+	if errors.Is(err, repository.ErrLeaseLost) {
+		log.Warn().Uint("jobID", jobID).Msg("Scoring worker: Job was requeued and claimed elsewhere; dropping this run's result")
+		return
+	}
+	log.Error().Err(err).Uint("jobID", jobID).Msg(msg)
+}
+
+func (p *ScoringWorkerPool) runReaper(ctx context.Context) {
+	defer p.wg.Done()
+	interval := p.cfg.StaleAfter / 2
+	for {
+		requeued, err := p.jobRepo.RequeueStale(time.Now().Add(-p.cfg.StaleAfter))
+		if err != nil {
+			log.Error().Err(err).Msg("Scoring reaper: Failed to requeue stale jobs")
+		} else if requeued > 0 {
+			log.Warn().Int64("requeued", requeued).Msg("Scoring reaper: Requeued jobs abandoned by a previous worker")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+)
+
+// testPart is one section of the TOEIC Writing test. The ranges must match
+// repository.testPartSQL, which groups answers by part in the database.
+type testPart struct {
+	Number        int
+	Name          string
+	FirstQuestion int
+	LastQuestion  int
+}
+
+var testParts = []testPart{
+	{Number: 1, Name: "Write a sentence based on a picture", FirstQuestion: 1, LastQuestion: 5},
+	{Number: 2, Name: "Respond to a written request", FirstQuestion: 6, LastQuestion: 7},
+	{Number: 3, Name: "Write an opinion essay", FirstQuestion: 8, LastQuestion: 8},
+}
+
+// testPartOf returns the part number of a question position, or 0 if the position is not in the test.
+func testPartOf(orderInTest int) int {
+	for _, p := range testParts {
+		if orderInTest >= p.FirstQuestion && orderInTest <= p.LastQuestion {
+			return p.Number
+		}
+	}
+	return 0
+}
+
+// emptyPartScores returns one zeroed subtotal per part, with the part's max score.
+func emptyPartScores() []dto.PartScoreDTO {
+	scores := make([]dto.PartScoreDTO, len(testParts))
+	for i, p := range testParts {
+		scores[i] = dto.PartScoreDTO{Part: p.Number, Name: p.Name, Questions: fmt.Sprintf("%d-%d", p.FirstQuestion, p.LastQuestion)}
+		if p.FirstQuestion == p.LastQuestion {
+			scores[i].Questions = fmt.Sprintf("%d", p.FirstQuestion)
+		}
+		for order := p.FirstQuestion; order <= p.LastQuestion; order++ {
+			_, maxScore, _ := testQuestionLayout(order)
+			scores[i].MaxScore += maxScore
+		}
+	}
+	return scores
+}
+
+// partScoresFromAnswers computes the subtotals of an attempt whose answers and their questions are loaded.
+func partScoresFromAnswers(answers []model.Answer) []dto.PartScoreDTO {
+	scores := emptyPartScores()
+	for _, answer := range answers {
+		part := testPartOf(answer.Question.OrderInTest)
+		if part == 0 {
+			continue
+		}
+		score := &scores[part-1]
+		score.Answered++
+		if final := answer.FinalScore(); final != nil {
+			score.Scored++
+			score.RawScore += *final
+		}
+	}
+	return scores
+}
+
+// partScoresFromRows turns the aggregated rows of one attempt into subtotals.
+func partScoresFromRows(rows []repository.AttemptPartScore) []dto.PartScoreDTO {
+	scores := emptyPartScores()
+	for _, row := range rows {
+		if row.Part < 1 || row.Part > len(scores) {
+			continue
+		}
+		score := &scores[row.Part-1]
+		score.RawScore = row.RawScore
+		score.Answered = row.Answered
+		score.Scored = row.Scored
+	}
+	return scores
+}
+
+// proficiencyBand is an ETS TOEIC Writing proficiency level and the lowest scaled score that reaches it.
+type proficiencyBand struct {
+	Level       int
+	MinScaled   float64
+	Description string
+}
+
+// proficiencyBands are ordered from the highest level down.
+var proficiencyBands = []proficiencyBand{
+	{9, 200, "Communicates straightforward information effectively and supports opinions with reasons, examples or explanations. Writing is well organized and well developed, with appropriate word choice and varied sentence structure; minor errors do not obscure meaning."},
+	{8, 170, "Communicates straightforward information effectively and supports opinions with reasons, examples or explanations. Writing is generally good, with occasional lapses in organization, development or accuracy that rarely obscure meaning."},
+	{7, 140, "Communicates straightforward information and attempts to support opinions, but explanations may be vague or underdeveloped, connections between ideas unclear, and grammatical errors noticeable."},
+	{6, 110, "Partially successful at giving straightforward information and supporting an opinion. Development is limited, connections between ideas are unclear, and errors in grammar and word choice are frequent."},
+	{5, 90, "Partially successful at giving straightforward information, but has serious difficulty supporting an opinion. Responses lack organization, detail and accuracy."},
+	{4, 70, "Gives straightforward information with limited success and sometimes writes grammatically correct sentences, but cannot support an opinion."},
+	{3, 50, "Writes grammatically correct sentences and describes pictures with some success, but cannot give straightforward information or support an opinion."},
+	{2, 40, "Has limited ability to write grammatically correct sentences or describe pictures, and cannot give straightforward information or support an opinion."},
+	{1, 0, "Cannot yet write grammatically correct sentences, give straightforward information or support an opinion in writing."},
+}
+
+// proficiencyLevelFor maps a scaled score (0-200) to its ETS proficiency level.
+func proficiencyLevelFor(scaledScore float64) *dto.ProficiencyLevelDTO {
+	for _, band := range proficiencyBands {
+		if scaledScore >= band.MinScaled {
+			return &dto.ProficiencyLevelDTO{Level: band.Level, Description: band.Description}
+		}
+	}
+	last := proficiencyBands[len(proficiencyBands)-1]
+	return &dto.ProficiencyLevelDTO{Level: last.Level, Description: last.Description}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/lshigami/Ringtails/internal/model"
+)
+
+func TestProficiencyLevelFor(t *testing.T) {
+	tests := []struct {
+		scaled float64
+		want   int
+	}{
+		{scaled: 0, want: 1},
+		{scaled: 39.9, want: 1},
+		{scaled: 40, want: 2},
+		{scaled: 49.9, want: 2},
+		{scaled: 50, want: 3},
+		{scaled: 69.9, want: 3},
+		{scaled: 70, want: 4},
+		{scaled: 90, want: 5},
+		{scaled: 109.9, want: 5},
+		{scaled: 110, want: 6},
+		{scaled: 140, want: 7},
+		{scaled: 169.9, want: 7},
+		{scaled: 170, want: 8},
+		{scaled: 195, want: 8},
+		{scaled: 200, want: 9},
+		{scaled: -10, want: 1}, // Below the scale still gets the lowest level
+		{scaled: 250, want: 9},
+	}
+	for _, tt := range tests {
+		got := proficiencyLevelFor(tt.scaled)
+		if got.Level != tt.want {
+			t.Errorf("proficiencyLevelFor(%v) = level %d, want %d", tt.scaled, got.Level, tt.want)
+		}
+		if got.Description == "" {
+			t.Errorf("proficiencyLevelFor(%v) has no description", tt.scaled)
+		}
+	}
+}
+
+func TestProficiencyBandsOrdered(t *testing.T) {
+	for i := 1; i < len(proficiencyBands); i++ {
+		prev, band := proficiencyBands[i-1], proficiencyBands[i]
+		if band.Level != prev.Level-1 || band.MinScaled >= prev.MinScaled {
+			t.Errorf("band %d (level %d from %v) does not follow level %d from %v", i, band.Level, band.MinScaled, prev.Level, prev.MinScaled)
+		}
+	}
+	if last := proficiencyBands[len(proficiencyBands)-1]; last.MinScaled != 0 {
+		t.Errorf("lowest band starts at %v, want 0", last.MinScaled)
+	}
+}
+
+func TestTestPartOf(t *testing.T) {
+	want := map[int]int{0: 0, 1: 1, 5: 1, 6: 2, 7: 2, 8: 3, 9: 0, -1: 0}
+	for order, part := range want {
+		if got := testPartOf(order); got != part {
+			t.Errorf("testPartOf(%d) = %d, want %d", order, got, part)
+		}
+	}
+}
+
+func TestPartScoresFromAnswers(t *testing.T) {
+	score := func(v float64) *float64 { return &v }
+	answer := func(order int, status string, ai, human *float64) model.Answer {
+		return model.Answer{Question: model.Question{OrderInTest: order}, ScoringStatus: status, AIScore: ai, HumanScore: human}
+	}
+
+	type part struct {
+		raw              float64
+		answered, scored int
+	}
+	tests := []struct {
+		name    string
+		answers []model.Answer
+		want    [3]part
+	}{
+		{name: "no answers", want: [3]part{}},
+		{
+			name: "full attempt",
+			answers: []model.Answer{
+				answer(1, "scored", score(3), nil), answer(2, "scored", score(2), nil), answer(3, "scored", score(2.5), nil),
+				answer(4, "scored", score(1), nil), answer(5, "scored", score(0), nil),
+				answer(6, "scored", score(4), nil), answer(7, "scored", score(3), nil),
+				answer(8, "scored", score(5), nil),
+			},
+			want: [3]part{{raw: 8.5, answered: 5, scored: 5}, {raw: 7, answered: 2, scored: 2}, {raw: 5, answered: 1, scored: 1}},
+		},
+		{
+			name: "unscored answers count as answered only",
+			answers: []model.Answer{
+				answer(1, "pending", nil, nil), answer(2, "failed", nil, nil), answer(3, "scored", score(2), nil),
+				answer(6, "deferred", nil, nil),
+			},
+			want: [3]part{{raw: 2, answered: 3, scored: 1}, {answered: 1}},
+		},
+		{
+			name: "human score overrides the AI score",
+			answers: []model.Answer{
+				answer(6, "scored", score(2), score(4)), answer(8, "failed", nil, score(3)),
+			},
+			want: [3]part{{}, {raw: 4, answered: 1, scored: 1}, {raw: 3, answered: 1, scored: 1}},
+		},
+		{
+			name:    "positions outside the test are ignored",
+			answers: []model.Answer{answer(0, "scored", score(3), nil), answer(9, "scored", score(3), nil)},
+			want:    [3]part{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores := partScoresFromAnswers(tt.answers)
+			if len(scores) != 3 {
+				t.Fatalf("got %d parts, want 3", len(scores))
+			}
+			for i, want := range tt.want {
+				got := scores[i]
+				if got.RawScore != want.raw || got.Answered != want.answered || got.Scored != want.scored {
+					t.Errorf("part %d = raw %v, answered %d, scored %d; want raw %v, answered %d, scored %d",
+						got.Part, got.RawScore, got.Answered, got.Scored, want.raw, want.answered, want.scored)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyPartScores(t *testing.T) {
+	want := []struct {
+		questions string
+		maxScore  float64
+	}{{"1-5", 15}, {"6-7", 8}, {"8", 5}}
+	scores := emptyPartScores()
+	for i, w := range want {
+		if scores[i].Part != i+1 || scores[i].Questions != w.questions || scores[i].MaxScore != w.maxScore {
+			t.Errorf("part %d = questions %q max %v, want %q max %v", scores[i].Part, scores[i].Questions, scores[i].MaxScore, w.questions, w.maxScore)
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidTestContent means an imported file could not be read as a test content file or bundle.
+var ErrInvalidTestContent = errors.New("invalid test content file")
+
+// Formats accepted by EncodeTestContent.
+const (
+	TestContentFormatJSON = "json"
+	TestContentFormatYAML = "yaml"
+	TestContentFormatZip  = "zip" // tests.yaml plus the embedded images as files under images/
+)
+
+const (
+	testContentFormatVersion = 1
+	maxBundleFileBytes       = 32 << 20 // Guards against zip bombs; images are further limited by ImageService
+	bundleContentFile        = "tests.yaml"
+	bundleImageDir           = "images"
+)
+
+// ImportTests validates every test of a JSON, YAML or zip content file with the rules of CreateTest.
+// Unless dryRun is set and if every test is valid, the embedded images are uploaded and all tests
+// are created in one transaction; otherwise nothing is stored and the report lists the problems of each test.
+func (s *adminTestService) ImportTests(ctx context.Context, data []byte, dryRun bool) (*dto.TestImportReportDTO, error) {
+	file, bundle, err := decodeTestContent(data)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.TestImportReportDTO{DryRun: dryRun, Valid: true, Tests: make([]dto.TestImportItemDTO, 0, len(file.Tests))}
+	if file.FormatVersion != testContentFormatVersion {
+		report.Errors = append(report.Errors, fmt.Sprintf("format_version must be %d, got %d", testContentFormatVersion, file.FormatVersion))
+	}
+	if len(file.Tests) == 0 {
+		report.Errors = append(report.Errors, "the file contains no tests")
+	}
+
+	toCreate := make([]*model.Test, 0, len(file.Tests))
+	titles := make(map[string]int, len(file.Tests))
+	images := make(map[string]pendingImage)
+	for i, content := range file.Tests {
+		item := dto.TestImportItemDTO{Index: i, Title: content.Title}
+		req, problems := bundle.toTestCreate(ctx, content, s.images, images)
+		questions, validationProblems := validateTestCreate(req)
+		problems = append(problems, validationProblems...)
+
+		if first, seen := titles[req.Title]; seen {
+			problems = append(problems, fmt.Errorf("%w: %q is also the title of test %d in this file", ErrTestTitleTaken, req.Title, first))
+		} else if req.Title != "" {
+			titles[req.Title] = i
+			if taken, err := s.testRepo.ExistsByTitle(req.Title, 0); err != nil {
+				return nil, fmt.Errorf("error checking test title: %w", err)
+			} else if taken {
+				problems = append(problems, fmt.Errorf("%w: %q", ErrTestTitleTaken, req.Title))
+			}
+		}
+
+		item.Valid = len(problems) == 0
+		for _, problem := range problems {
+			item.Errors = append(item.Errors, problem.Error())
+		}
+		report.Tests = append(report.Tests, item)
+
+		testModel := &model.Test{
+			Title:       req.Title,
+			Description: req.Description,
+			Status:      model.TestStatusDraft,
+			Questions:   questions,
+		}
+		if req.Publish {
+			now := time.Now()
+			testModel.Status = model.TestStatusPublished
+			testModel.PublishedAt = &now
+		}
+		toCreate = append(toCreate, testModel)
+	}
+	for _, item := range report.Tests {
+		report.Valid = report.Valid && item.Valid
+	}
+	report.Valid = report.Valid && len(report.Errors) == 0
+	if !report.Valid || dryRun {
+		return report, nil
+	}
+
+	// Images are stored before the tests, so a created test never points at a missing image. If the import
+	// fails afterwards, the images stored so far are logged: they are unreferenced, and since images are
+	// stored by content hash, importing the file again reuses them instead of storing them twice.
+	stored := make([]string, 0, len(images))
+	for _, image := range images {
+		var storedImage *dto.ImageDTO
+		if image.url != "" {
+			// Checked again to keep a copy for scoring, in case it changed since the check above.
+			storedImage, err = s.images.ValidateURL(ctx, image.url, true)
+		} else {
+			storedImage, err = s.images.Upload(ctx, nil, image.name, image.data)
+			if err != nil {
+				err = fmt.Errorf("error storing image %s: %w", image.name, err)
+			}
+		}
+		if err != nil {
+			logUnreferencedImages(stored, err)
+			return nil, err
+		}
+		stored = append(stored, storedImage.Hash)
+	}
+	if err := s.testRepo.CreateAll(toCreate); err != nil {
+		log.Error().Err(err).Int("tests", len(toCreate)).Msg("ImportTests: Failed to create tests")
+		logUnreferencedImages(stored, err)
+		return nil, fmt.Errorf("database error importing tests: %w", err)
+	}
+	for i, test := range toCreate {
+		id := test.ID
+		report.Tests[i].TestID = &id
+	}
+	report.Imported = len(toCreate)
+	log.Info().Int("tests", report.Imported).Int("images", len(images)).Msg("ImportTests: Tests imported.")
+	return report, nil
+}
+
+// logUnreferencedImages records the images an import stored before it failed.
+func logUnreferencedImages(hashes []string, cause error) {
+	if len(hashes) == 0 {
+		return
+	}
+	log.Warn().Err(cause).Strs("imageHashes", hashes).Msg("ImportTests: Import failed after storing images; they are not used by any test yet.")
+}
+
+// ExportTests converts tests to the content format. No IDs exports every non-deleted test.
+// Images uploaded to this API are embedded as data: URIs so the file works in any environment.
+func (s *adminTestService) ExportTests(ctx context.Context, testIDs []uint) (*dto.TestContentFileDTO, error) {
+	if len(testIDs) == 0 {
+		tests, err := s.testRepo.FindAllWithQuestionCount(repository.TestListFilter{})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching tests: %w", err)
+		}
+		for _, t := range tests {
+			testIDs = append(testIDs, t.ID)
+		}
+	}
+
+	file := &dto.TestContentFileDTO{FormatVersion: testContentFormatVersion, Tests: make([]dto.TestContentDTO, 0, len(testIDs))}
+	for _, testID := range testIDs {
+		test, err := s.loadTest(testID)
+		if err != nil {
+			return nil, err
+		}
+		content := dto.TestContentDTO{
+			Title:       test.Title,
+			Description: test.Description,
+			Publish:     test.Status == model.TestStatusPublished,
+			Questions:   make([]dto.QuestionContentDTO, 0, len(test.Questions)),
+		}
+		for _, q := range test.Questions {
+			question := dto.QuestionContentDTO{
+				Order:    q.OrderInTest,
+				Type:     q.Type,
+				Title:    q.Title,
+				Prompt:   q.Prompt,
+				Image:    derefString(q.ImageURL),
+				MaxScore: q.MaxScore,
+			}
+			if hash, ok := storedImageHash(question.Image); ok {
+				image, err := s.images.Get(ctx, hash)
+				if err != nil {
+					return nil, fmt.Errorf("error exporting the image of question %d of test %d: %w", q.OrderInTest, test.ID, err)
+				}
+				question.Image = "data:" + image.MimeType + ";base64," + base64.StdEncoding.EncodeToString(image.Data)
+			}
+			if q.GivenWord1 != nil || q.GivenWord2 != nil {
+				question.GivenWords = []string{derefString(q.GivenWord1), derefString(q.GivenWord2)}
+			}
+			content.Questions = append(content.Questions, question)
+		}
+		file.Tests = append(file.Tests, content)
+	}
+	return file, nil
+}
+
+// EncodeTestContent writes a content file in the given format. In a zip bundle, embedded images
+// are written as separate files so the YAML stays readable in version control.
+func EncodeTestContent(w io.Writer, file *dto.TestContentFileDTO, format string) error {
+	switch format {
+	case TestContentFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(file)
+	case TestContentFormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(file); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case TestContentFormatZip:
+		return encodeTestContentBundle(w, file)
+	}
+	return fmt.Errorf("unsupported test content format %q", format)
+}
+
+func encodeTestContentBundle(w io.Writer, file *dto.TestContentFileDTO) error {
+	archive := zip.NewWriter(w)
+	bundled := *file
+	bundled.Tests = make([]dto.TestContentDTO, len(file.Tests))
+	for i, test := range file.Tests {
+		test.Questions = append([]dto.QuestionContentDTO(nil), test.Questions...)
+		for j := range test.Questions {
+			q := &test.Questions[j]
+			if !strings.HasPrefix(q.Image, "data:") {
+				continue
+			}
+			data, mimeType, err := parseImageDataURI(q.Image)
+			if err != nil {
+				return fmt.Errorf("test %q question %d: %w", test.Title, q.Order, err)
+			}
+			name := fmt.Sprintf("%s/test-%d-q%d%s", bundleImageDir, i+1, q.Order, supportedImageMIMETypes[mimeType])
+			entry, err := archive.Create(name)
+			if err != nil {
+				return err
+			}
+			if _, err := entry.Write(data); err != nil {
+				return err
+			}
+			q.Image = name
+		}
+		bundled.Tests[i] = test
+	}
+
+	entry, err := archive.Create(bundleContentFile)
+	if err != nil {
+		return err
+	}
+	if err := EncodeTestContent(entry, &bundled, TestContentFormatYAML); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// contentBundle gives access to the files of an imported zip bundle. A nil bundle means a plain
+// JSON or YAML file was imported, which cannot reference image files.
+type contentBundle struct {
+	files   map[string]*zip.File
+	baseDir string // Directory of the content file; image paths are relative to it
+}
+
+// decodeTestContent reads a zip bundle, a JSON document or a YAML document. Unknown fields are
+// rejected so that typos in hand-written files do not silently drop content.
+func decodeTestContent(data []byte) (*dto.TestContentFileDTO, *contentBundle, error) {
+	var bundle *contentBundle
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		var err error
+		if bundle, data, err = openContentBundle(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var file dto.TestContentFileDTO
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("{")) {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&file); err != nil {
+			return nil, nil, fmt.Errorf("%w: not valid JSON: %v", ErrInvalidTestContent, err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil {
+			return nil, nil, fmt.Errorf("%w: not valid YAML: %v", ErrInvalidTestContent, err)
+		}
+	}
+	return &file, bundle, nil
+}
+
+// openContentBundle finds the single .yaml, .yml or .json file of a zip bundle and returns its content.
+func openContentBundle(data []byte) (*contentBundle, []byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: not a valid zip archive: %v", ErrInvalidTestContent, err)
+	}
+
+	bundle := &contentBundle{files: make(map[string]*zip.File, len(archive.File))}
+	var contentFile *zip.File
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		bundle.files[path.Clean(f.Name)] = f
+		switch strings.ToLower(path.Ext(f.Name)) {
+		case ".yaml", ".yml", ".json":
+			if contentFile != nil {
+				return nil, nil, fmt.Errorf("%w: the bundle contains more than one content file (%s, %s)", ErrInvalidTestContent, contentFile.Name, f.Name)
+			}
+			contentFile = f
+		}
+	}
+	if contentFile == nil {
+		return nil, nil, fmt.Errorf("%w: the bundle contains no .yaml, .yml or .json content file", ErrInvalidTestContent)
+	}
+	bundle.baseDir = path.Dir(path.Clean(contentFile.Name))
+
+	content, err := readZipFile(contentFile, maxBundleFileBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTestContent, err)
+	}
+	return bundle, content, nil
+}
+
+// pendingImage is an image of an imported file that is uploaded once the import is known to succeed.
+// External images have only a url; they are downloaded again and kept then.
+type pendingImage struct {
+	name string
+	data []byte
+	url  string
+}
+
+// toTestCreate converts a test from the content format to the request CreateTest validates,
+// filling in type and max score from each question's position when they are omitted.
+// Images to upload are added to pending, by hash.
+func (b *contentBundle) toTestCreate(ctx context.Context, content dto.TestContentDTO, images ImageService, pending map[string]pendingImage) (dto.TestCreateDTO, []error) {
+	var problems []error
+	req := dto.TestCreateDTO{
+		Title:       strings.TrimSpace(content.Title),
+		Description: content.Description,
+		Publish:     content.Publish,
+		Questions:   make([]dto.QuestionCreateDTO, 0, len(content.Questions)),
+	}
+	for _, q := range content.Questions {
+		question := dto.QuestionCreateDTO{
+			Title:       strings.TrimSpace(q.Title),
+			Prompt:      q.Prompt,
+			Type:        q.Type,
+			OrderInTest: q.Order,
+			MaxScore:    q.MaxScore,
+		}
+		if layoutType, layoutMaxScore, ok := testQuestionLayout(q.Order); ok {
+			if question.Type == "" {
+				question.Type = layoutType
+			}
+			if question.MaxScore == 0 {
+				question.MaxScore = layoutMaxScore
+			}
+		}
+
+		switch len(q.GivenWords) {
+		case 0:
+		case 2:
+			question.GivenWord1 = emptyToNil(strings.TrimSpace(q.GivenWords[0]))
+			question.GivenWord2 = emptyToNil(strings.TrimSpace(q.GivenWords[1]))
+		default:
+			problems = append(problems, fmt.Errorf("question %d: given_words must contain exactly 2 words, got %d", q.Order, len(q.GivenWords)))
+		}
+
+		if image := strings.TrimSpace(q.Image); image != "" {
+			imageURL, err := b.resolveImage(ctx, image, images, pending)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("question %d: %w", q.Order, err))
+				imageURL = image // Only report the image problem, not a missing image as well
+			}
+			question.ImageURL = &imageURL
+		}
+		req.Questions = append(req.Questions, question)
+	}
+	return req, problems
+}
+
+// resolveImage returns the value stored as a question's image URL. External URLs are kept as they are
+// once their image is checked; embedded data and files of the bundle are checked and will be served by this API.
+func (b *contentBundle) resolveImage(ctx context.Context, image string, images ImageService, pending map[string]pendingImage) (string, error) {
+	if hash, ok := storedImageHash(image); ok {
+		if _, err := images.Find(hash); err != nil {
+			return "", err
+		}
+		return image, nil
+	}
+	if strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		if _, err := images.ValidateURL(ctx, image, false); err != nil {
+			return "", err
+		}
+		pending[image] = pendingImage{url: image}
+		return image, nil
+	}
+
+	var name string
+	var data []byte
+	if strings.HasPrefix(image, "data:") {
+		var err error
+		if data, _, err = parseImageDataURI(image); err != nil {
+			return "", err
+		}
+	} else {
+		if b == nil {
+			return "", fmt.Errorf("image %q is a file path; upload a zip bundle containing it or embed the image as a data: URI", image)
+		}
+		name = path.Join(b.baseDir, image)
+		if path.IsAbs(image) || name == ".." || strings.HasPrefix(name, "../") {
+			return "", fmt.Errorf("image path %q must be relative to the content file and stay inside the bundle", image)
+		}
+		f, ok := b.files[name]
+		if !ok {
+			return "", fmt.Errorf("image %q is not in the bundle", image)
+		}
+		var err error
+		if data, err = readZipFile(f, maxBundleFileBytes); err != nil {
+			return "", err
+		}
+	}
+
+	checked, err := images.Check(name, data)
+	if err != nil {
+		return "", err
+	}
+	pending[checked.Hash] = pendingImage{name: name, data: data}
+	return checked.URL, nil
+}
+
+// parseImageDataURI decodes a base64 data: URI holding a supported image.
+func parseImageDataURI(uri string) ([]byte, string, error) {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok || !strings.HasSuffix(header, ";base64") {
+		return nil, "", fmt.Errorf("embedded image must be a base64 data: URI")
+	}
+	mimeType, _, err := mime.ParseMediaType(strings.TrimSuffix(header, ";base64"))
+	if err != nil {
+		return nil, "", fmt.Errorf("embedded image has an invalid media type: %w", err)
+	}
+	if _, ok := supportedImageMIMETypes[mimeType]; !ok {
+		return nil, "", fmt.Errorf("embedded image type %q is not supported", mimeType)
+	}
+	if base64.StdEncoding.DecodedLen(len(payload)) > maxBundleFileBytes {
+		return nil, "", fmt.Errorf("embedded image is larger than %d MB", maxBundleFileBytes>>20)
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("embedded image is not valid base64: %w", err)
+	}
+	return data, mimeType, nil
+}
+
+func readZipFile(f *zip.File, limit int64) ([]byte, error) {
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("%s is larger than %d MB", f.Name, limit>>20)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", f.Name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d MB", f.Name, limit>>20)
+	}
+	return data, nil
+}
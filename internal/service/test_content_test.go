@@ -0,0 +1,360 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/lshigami/Ringtails/internal/testdb"
+	"gorm.io/gorm"
+)
+
+// contentTestImageLimit is the image size limit of newContentTestService.
+const contentTestImageLimit = 1 << 10
+
+func newContentTestService(t *testing.T) (*adminTestService, *gorm.DB) {
+	t.Helper()
+	db := testdb.Open(t, &model.Test{}, &model.Question{}, &model.QuestionRevision{})
+	storage, err := newLocalImageStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	images := &imageService{
+		storage:    storage,
+		repo:       newMemoryImageRepository(),
+		cache:      newImageCache(1 << 20),
+		httpClient: &http.Client{Timeout: 5 * time.Second}, // The fetch client refuses the local test servers
+		maxBytes:   contentTestImageLimit,
+	}
+	return &adminTestService{testRepo: repository.NewTestRepository(db), images: images, db: db}, db
+}
+
+// servePNG serves data as /picture.png, as an external image host would.
+func servePNG(t *testing.T, data []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/picture.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// storedImages lists the hashes of the images s has stored.
+func storedImages(s *adminTestService) []string {
+	var hashes []string
+	for hash := range s.images.(*imageService).repo.(*memoryImageRepository).images {
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+// testContent is a complete test whose sentence_picture questions all show image.
+// Types and max scores are left out, so they come from each question's position.
+func testContent(title string, image string) dto.TestContentDTO {
+	content := dto.TestContentDTO{Title: title, Description: "Imported", Publish: true}
+	for order := 1; order <= 8; order++ {
+		q := dto.QuestionContentDTO{Order: order, Title: fmt.Sprintf("Question %d", order), Prompt: "Write your answer."}
+		if order <= 5 {
+			q.Image = image
+			q.GivenWords = []string{"man", "book"}
+		}
+		content.Questions = append(content.Questions, q)
+	}
+	return content
+}
+
+// encodeContent encodes tests as a content file of the given format.
+func encodeContent(t *testing.T, format string, tests ...dto.TestContentDTO) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := EncodeTestContent(&buf, &dto.TestContentFileDTO{FormatVersion: testContentFormatVersion, Tests: tests}, format); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// zipBundle builds a zip archive from file names and contents.
+func zipBundle(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, data := range files {
+		entry, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := entry.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func countTests(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Unscoped().Model(&model.Test{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestImportTestsFileErrors(t *testing.T) {
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG(t))
+	valid := string(encodeContent(t, TestContentFormatJSON, testContent("Practice 1", dataURI)))
+
+	tests := []struct {
+		name       string
+		data       string
+		wantErr    error  // Error of ImportTests, for files that cannot be read at all
+		wantReport string // Substring of a file-level error of the report
+	}{
+		{name: "missing format_version", data: `{"tests": []}`, wantReport: "format_version must be 1, got 0"},
+		{name: "future format_version", data: strings.Replace(valid, `"format_version": 1`, `"format_version": 2`, 1), wantReport: "format_version must be 1, got 2"},
+		{name: "no tests", data: "format_version: 1\ntests: []\n", wantReport: "the file contains no tests"},
+		{name: "unknown JSON field", data: `{"format_version": 1, "tests": [], "author": "me"}`, wantErr: ErrInvalidTestContent},
+		{name: "unknown YAML field", data: "format_version: 1\ntest: []\n", wantErr: ErrInvalidTestContent},
+		{name: "malformed JSON", data: `{"format_version": 1,`, wantErr: ErrInvalidTestContent},
+		{name: "corrupt zip", data: "PK\x03\x04 not really a zip", wantErr: ErrInvalidTestContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db := newContentTestService(t)
+			report, err := s.ImportTests(context.Background(), []byte(tt.data), false)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if report.Valid || !strings.Contains(strings.Join(report.Errors, "\n"), tt.wantReport) {
+				t.Errorf("report = %+v, want it invalid with %q", report, tt.wantReport)
+			}
+			if n := countTests(t, db); n != 0 {
+				t.Errorf("%d tests created from an invalid file", n)
+			}
+		})
+	}
+}
+
+func TestImportTestsIsAllOrNothing(t *testing.T) {
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG(t))
+	incomplete := testContent("Practice 2", dataURI)
+	incomplete.Questions = incomplete.Questions[:7]
+	wrongType := testContent("Practice 3", dataURI)
+	wrongType.Questions[7].Type = "email_response"
+
+	s, db := newContentTestService(t)
+	if err := s.testRepo.Create(&model.Test{Title: "Already there"}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := s.ImportTests(context.Background(), encodeContent(t, TestContentFormatYAML,
+		testContent("Practice 1", dataURI), incomplete, wrongType, testContent("Practice 1", dataURI), testContent("Already there", dataURI)), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantErrors := []string{
+		"",
+		"exactly 8 questions, received 7",
+		"question 8 (Order: 8) should be type 'opinion_essay'",
+		`"Practice 1" is also the title of test 0 in this file`,
+		`already exists: "Already there"`,
+	}
+	if report.Valid || report.Imported != 0 || len(report.Tests) != len(wantErrors) {
+		t.Fatalf("report = %+v, want %d tests reported and none imported", report, len(wantErrors))
+	}
+	for i, want := range wantErrors {
+		item := report.Tests[i]
+		if item.Index != i || item.Valid != (want == "") || item.TestID != nil || !strings.Contains(strings.Join(item.Errors, "\n"), want) {
+			t.Errorf("test %d = %+v, want errors containing %q", i, item, want)
+		}
+	}
+	if n := countTests(t, db); n != 1 {
+		t.Errorf("%d tests stored, want only the existing one", n)
+	}
+
+	file := encodeContent(t, TestContentFormatJSON, testContent("Practice 1", dataURI), testContent("Practice 2", dataURI))
+	report, err = s.ImportTests(context.Background(), file, true)
+	if err != nil || !report.Valid || !report.DryRun || report.Imported != 0 || report.Tests[0].TestID != nil {
+		t.Fatalf("dry run = %+v, %v; want a valid report that imports nothing", report, err)
+	}
+	if n := countTests(t, db); n != 1 {
+		t.Errorf("a dry run stored %d tests", n-1)
+	}
+	if hashes := storedImages(s); len(hashes) != 0 {
+		t.Errorf("images stored before the import succeeded: %v", hashes)
+	}
+
+	report, err = s.ImportTests(context.Background(), file, false)
+	if err != nil || !report.Valid || report.Imported != 2 || report.Tests[0].TestID == nil || report.Tests[1].TestID == nil {
+		t.Fatalf("import = %+v, %v; want both tests imported", report, err)
+	}
+	imported, err := s.testRepo.FindByIDUnscopedWithQuestions(*report.Tests[1].TestID)
+	if err != nil || imported.Status != model.TestStatusPublished || len(imported.Questions) != 8 {
+		t.Fatalf("imported test = %+v, %v; want a published test with 8 questions", imported, err)
+	}
+	if q := imported.Questions[7]; q.Type != "opinion_essay" || q.MaxScore != 5 {
+		t.Errorf("question 8 = %s worth %v, want the layout's opinion_essay worth 5", q.Type, q.MaxScore)
+	}
+	if hashes := storedImages(s); len(hashes) != 1 {
+		t.Errorf("stored images = %v, want the image shared by all questions stored once", hashes)
+	}
+}
+
+func TestImportTestsImages(t *testing.T) {
+	pngData := testPNG(t)
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngData)
+	sum := sha256.Sum256(pngData)
+	storedURL := storedImagePath + hex.EncodeToString(sum[:])
+	server := servePNG(t, pngData)
+	bundle := func(image string, files map[string][]byte) []byte {
+		all := map[string][]byte{"content/tests.yaml": encodeContent(t, TestContentFormatYAML, testContent("Practice 1", image))}
+		for name, data := range files {
+			all[name] = data
+		}
+		return zipBundle(t, all)
+	}
+
+	tests := []struct {
+		name      string
+		data      []byte
+		uploaded  bool   // Whether the image was uploaded before the import
+		wantErr   error  // Error of ImportTests
+		wantImage string // Stored image URL of question 1
+		wantIssue string // Substring of the problems of the test
+	}{
+		{name: "external URL", data: encodeContent(t, TestContentFormatYAML, testContent("Practice 1", server.URL+"/picture.png")), wantImage: server.URL + "/picture.png"},
+		{name: "broken external URL", data: encodeContent(t, TestContentFormatYAML, testContent("Practice 1", server.URL+"/missing.png")), wantIssue: "returned status 404"},
+		{name: "uploaded image", data: encodeContent(t, TestContentFormatYAML, testContent("Practice 1", storedURL)), uploaded: true, wantImage: storedURL},
+		{name: "uploaded image that does not exist", data: encodeContent(t, TestContentFormatYAML, testContent("Practice 1", storedURL)), wantIssue: "image not found"},
+		{name: "data URI", data: encodeContent(t, TestContentFormatJSON, testContent("Practice 1", dataURI)), wantImage: storedURL},
+		{name: "data URI without base64", data: encodeContent(t, TestContentFormatJSON, testContent("Practice 1", "data:image/png,raw")), wantIssue: "must be a base64 data: URI"},
+		{name: "data URI of an unsupported type", data: encodeContent(t, TestContentFormatJSON, testContent("Practice 1", "data:image/svg+xml;base64,PHN2Zz4=")), wantIssue: `type "image/svg+xml" is not supported`},
+		{name: "data URI with invalid base64", data: encodeContent(t, TestContentFormatJSON, testContent("Practice 1", "data:image/png;base64,***")), wantIssue: "not valid base64"},
+		{name: "file path outside a bundle", data: encodeContent(t, TestContentFormatYAML, testContent("Practice 1", "images/q1.png")), wantIssue: "is a file path"},
+		{name: "path relative to the content file", data: bundle("images/q1.png", map[string][]byte{"content/images/q1.png": pngData}), wantImage: storedURL},
+		{name: "path relative to the archive root", data: bundle("images/q1.png", map[string][]byte{"images/q1.png": pngData}), wantIssue: `"images/q1.png" is not in the bundle`},
+		{name: "parent directory inside the bundle", data: bundle("../shared/q1.png", map[string][]byte{"shared/q1.png": pngData}), wantImage: storedURL},
+		{name: "path traversal", data: bundle("../../q1.png", nil), wantIssue: "must be relative to the content file and stay inside the bundle"},
+		{name: "absolute path", data: bundle("/content/images/q1.png", map[string][]byte{"content/images/q1.png": pngData}), wantIssue: "must be relative to the content file"},
+		{name: "not an image", data: bundle("images/q1.png", map[string][]byte{"content/images/q1.png": []byte("plain text")}), wantIssue: "not a supported image"},
+		{name: "image too large", data: bundle("images/q1.png", map[string][]byte{"content/images/q1.png": append(pngData, make([]byte, contentTestImageLimit)...)}), wantIssue: "the limit is 1024"},
+		{name: "two content files", data: zipBundle(t, map[string][]byte{"a.yaml": nil, "b.json": nil}), wantErr: ErrInvalidTestContent},
+		{name: "no content file", data: zipBundle(t, map[string][]byte{"images/q1.png": pngData}), wantErr: ErrInvalidTestContent},
+		{name: "content file too large", data: zipBundle(t, map[string][]byte{"tests.yaml": make([]byte, maxBundleFileBytes+1)}), wantErr: ErrInvalidTestContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newContentTestService(t)
+			if tt.uploaded {
+				if _, err := s.images.Upload(context.Background(), nil, "q1.png", pngData); err != nil {
+					t.Fatal(err)
+				}
+			}
+			report, err := s.ImportTests(context.Background(), tt.data, false)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantIssue != "" {
+				if report.Valid || !strings.Contains(strings.Join(report.Tests[0].Errors, "\n"), tt.wantIssue) {
+					t.Errorf("report = %+v, want a problem containing %q", report.Tests[0], tt.wantIssue)
+				}
+				return
+			}
+			if !report.Valid {
+				t.Fatalf("report = %+v, want it valid", report.Tests)
+			}
+			test, err := s.testRepo.FindByIDUnscopedWithQuestions(*report.Tests[0].TestID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := derefString(test.Questions[0].ImageURL); got != tt.wantImage {
+				t.Errorf("stored image = %q, want %q", got, tt.wantImage)
+			}
+			if _, err := s.images.Get(context.Background(), strings.TrimPrefix(storedURL, storedImagePath)); err != nil {
+				t.Errorf("image of the question is not stored: %v", err)
+			}
+		})
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG(t))
+	withURL := testContent("Practice 2", servePNG(t, testPNG(t)).URL+"/picture.png")
+	withURL.Publish = false
+	withURL.Questions[5].MaxScore = 4
+	source, _ := newContentTestService(t)
+	report, err := source.ImportTests(context.Background(), encodeContent(t, TestContentFormatJSON, testContent("Practice 1", dataURI), withURL), false)
+	if err != nil || !report.Valid {
+		t.Fatalf("import = %+v, %v", report, err)
+	}
+	exported, err := source.ExportTests(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Tests) != 2 || exported.FormatVersion != testContentFormatVersion {
+		t.Fatalf("export = %+v, want both tests in format version %d", exported, testContentFormatVersion)
+	}
+
+	for _, format := range []string{TestContentFormatJSON, TestContentFormatYAML, TestContentFormatZip} {
+		t.Run(format, func(t *testing.T) {
+			var file bytes.Buffer
+			if err := EncodeTestContent(&file, exported, format); err != nil {
+				t.Fatal(err)
+			}
+			target, _ := newContentTestService(t)
+			report, err := target.ImportTests(context.Background(), file.Bytes(), false)
+			if err != nil || !report.Valid || report.Imported != 2 {
+				t.Fatalf("import of the export = %+v, %v", report, err)
+			}
+			again, err := target.ExportTests(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sortedByTitle(again), sortedByTitle(exported)) {
+				t.Errorf("round trip changed the content:\ngot  %+v\nwant %+v", again, exported)
+			}
+		})
+	}
+}
+
+// sortedByTitle orders the tests of a file by title, since exports list the newest test first.
+func sortedByTitle(file *dto.TestContentFileDTO) *dto.TestContentFileDTO {
+	sorted := *file
+	sorted.Tests = append([]dto.TestContentDTO(nil), file.Tests...)
+	sort.Slice(sorted.Tests, func(i, j int) bool { return sorted.Tests[i].Title < sorted.Tests[j].Title })
+	return &sorted
+}
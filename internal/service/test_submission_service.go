@@ -1,9 +1,9 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"sort"
-	"sync"
 	"time"
 
 	"github.com/jinzhu/copier"
@@ -14,19 +14,24 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrTestNotFound        = errors.New("test not found")
+	ErrAttemptNotFound     = errors.New("test attempt not found")
+	ErrAttemptAccessDenied = errors.New("test attempt belongs to another user")
+)
+
 // TestSubmissionService defines the interface for managing test submissions.
+// All methods are scoped to the authenticated user so learners can only see their own attempts.
 type TestSubmissionService interface {
-	SubmitTest(testID uint, req dto.TestAttemptSubmitDTO) (*dto.TestAttemptDetailDTO, error) // Removed db from interface
-	GetTestAttemptDetails(attemptID uint) (*dto.TestAttemptDetailDTO, error)
-	GetUserAttemptsForTest(testID uint, userID *uint) ([]dto.TestAttemptSummaryDTO, error)
+	SubmitTest(testID uint, userID uint, req dto.TestAttemptSubmitDTO) (*dto.TestAttemptDetailDTO, error)
+	GetTestAttemptDetails(attemptID uint, userID uint) (*dto.TestAttemptDetailDTO, error)
+	GetUserAttemptsForTest(testID uint, userID uint) ([]dto.TestAttemptSummaryDTO, error)
 }
 
 type testSubmissionService struct {
 	testRepo        repository.TestRepository
 	questionRepo    repository.QuestionRepository
 	testAttemptRepo repository.TestAttemptRepository
-	answerRepo      repository.AnswerRepository
-	geminiService   GeminiLLMService
 	scoreConverter  ScoreConverterService
 	db              *gorm.DB // Used for transactions within service methods
 }
@@ -36,8 +41,6 @@ func NewTestSubmissionService(
 	testRepo repository.TestRepository,
 	questionRepo repository.QuestionRepository,
 	testAttemptRepo repository.TestAttemptRepository,
-	answerRepo repository.AnswerRepository,
-	geminiService GeminiLLMService,
 	scoreConverter ScoreConverterService,
 	db *gorm.DB,
 ) TestSubmissionService {
@@ -45,27 +48,27 @@ func NewTestSubmissionService(
 		testRepo:        testRepo,
 		questionRepo:    questionRepo,
 		testAttemptRepo: testAttemptRepo,
-		answerRepo:      answerRepo,
-		geminiService:   geminiService,
 		scoreConverter:  scoreConverter,
 		db:              db,
 	}
 }
 
-// answerProcessingResult is used to carry results from goroutines processing answers.
-type answerProcessingResult struct {
-	processedAnswer model.Answer
-	originalIndex   int   // To map back to the original answer order if needed
-	err             error // Error during AI processing or DB update of this specific answer
-}
-
-// SubmitTest handles the submission of answers for an entire test.
-func (s *testSubmissionService) SubmitTest(testID uint, req dto.TestAttemptSubmitDTO) (*dto.TestAttemptDetailDTO, error) {
+// SubmitTest persists the answers for an entire test and queues them for AI scoring.
+// It returns as soon as the attempt is stored; the ScoringWorkerPool moves it from "pending"
+// to "completed" or "completed_with_errors" in the background.
+func (s *testSubmissionService) SubmitTest(testID uint, userID uint, req dto.TestAttemptSubmitDTO) (*dto.TestAttemptDetailDTO, error) {
 	// 1. Validate Test and prepare question map
 	test, err := s.testRepo.FindByIDWithQuestions(testID)
 	if err != nil {
 		log.Error().Err(err).Uint("testID", testID).Msg("SubmitTest: Test not found")
-		return nil, fmt.Errorf("test not found with ID %d: %w", testID, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
+		}
+		return nil, fmt.Errorf("error loading test %d: %w", testID, err)
+	}
+	if test.Status != model.TestStatusPublished {
+		log.Warn().Uint("testID", testID).Msg("SubmitTest: Test is not published")
+		return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
 	}
 	if len(test.Questions) == 0 {
 		return nil, fmt.Errorf("test ID %d has no questions, submission is not possible", testID)
@@ -78,9 +81,9 @@ func (s *testSubmissionService) SubmitTest(testID uint, req dto.TestAttemptSubmi
 	// 2. Create initial TestAttempt and Answer records
 	testAttempt := model.TestAttempt{
 		TestID:      testID,
-		UserID:      req.UserID,
+		UserID:      &userID,
 		SubmittedAt: time.Now(),
-		Status:      "pending", // Initial status before AI scoring
+		Status:      "pending", // Waiting for a scoring worker
 	}
 
 	validAnswersToProcess := 0
@@ -91,8 +94,10 @@ func (s *testSubmissionService) SubmitTest(testID uint, req dto.TestAttemptSubmi
 			continue
 		}
 		testAttempt.Answers = append(testAttempt.Answers, model.Answer{
-			QuestionID: question.ID,
-			UserAnswer: userAnswerDto.UserAnswer,
+			QuestionID:         question.ID,
+			QuestionRevisionID: question.CurrentRevisionID, // Pin the content the learner saw
+			UserAnswer:         userAnswerDto.UserAnswer,
+			ScoringStatus:      "pending",
 		})
 		validAnswersToProcess++
 	}
@@ -101,195 +106,46 @@ func (s *testSubmissionService) SubmitTest(testID uint, req dto.TestAttemptSubmi
 		return nil, fmt.Errorf("no valid answers provided for the questions in test %d", testID)
 	}
 
-	// Transaction for creating TestAttempt and its initial (unscored) Answers
+	// 3. Store the attempt, its answers and the scoring job atomically so a submission is never
+	// saved without work queued for it (or vice versa).
 	err = s.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(&testAttempt).Error; err != nil { // GORM creates associated answers
 			return fmt.Errorf("failed to create test attempt record: %w", err)
 		}
-		// After Create, testAttempt.ID and testAttempt.Answers[i].ID are populated.
+		job := model.ScoringJob{
+			TestAttemptID: testAttempt.ID,
+			Status:        model.ScoringJobQueued,
+			RunAfter:      time.Now(),
+		}
+		if err := tx.Create(&job).Error; err != nil {
+			return fmt.Errorf("failed to queue scoring job: %w", err)
+		}
 		return nil
 	})
 	if err != nil {
-		log.Error().Err(err).Msg("SubmitTest: Transaction failed for creating test attempt and initial answers.")
+		log.Error().Err(err).Msg("SubmitTest: Transaction failed for creating test attempt and scoring job.")
 		return nil, err
 	}
+	log.Info().Uint("attemptID", testAttempt.ID).Int("answerCount", validAnswersToProcess).Msg("SubmitTest: Attempt stored and queued for scoring.")
 
-	// Update status to "scoring" after successful creation of records
-	testAttempt.Status = "scoring"
-	if errStatusUpdate := s.testAttemptRepo.Update(&testAttempt); errStatusUpdate != nil {
-		log.Error().Err(errStatusUpdate).Uint("attemptID", testAttempt.ID).Msg("SubmitTest: Failed to update test attempt status to 'scoring'. Scoring will proceed.")
-		// This is not fatal for the scoring process itself but indicates a state update issue.
-	}
-
-	// 3. Process answers for AI feedback and scoring in parallel
-	var wg sync.WaitGroup
-	// Use a fresh copy of answers from the created testAttempt which now have IDs
-	persistedAnswers := make([]model.Answer, len(testAttempt.Answers))
-	copy(persistedAnswers, testAttempt.Answers)
-
-	resultsChan := make(chan answerProcessingResult, len(persistedAnswers))
-
-	for i := 0; i < len(persistedAnswers); i++ {
-		wg.Add(1)
-		go func(answerIdx int) {
-			defer wg.Done()
-
-			currentAnswer := persistedAnswers[answerIdx] // Each goroutine works on its copy
-			questionModel := questionMap[currentAnswer.QuestionID]
-
-			log.Info().Uint("answerID", currentAnswer.ID).Uint("questionID", questionModel.ID).Msg("SubmitTest: Goroutine processing answer with AI.")
-			feedback, score, geminiErr := s.geminiService.ScoreAndFeedbackAnswer(&questionModel, currentAnswer.UserAnswer)
-
-			currentAnswer.AIFeedback = feedback
-			if geminiErr != nil {
-				log.Error().Err(geminiErr).Uint("answerID", currentAnswer.ID).Msg("SubmitTest: Error from Gemini service for answer.")
-				currentAnswer.AIScore = nil // Explicitly set AIScore to nil on error
-			} else {
-				currentAnswer.AIScore = &score
-			}
-
-			// Update the individual Answer record in the database
-			if updateErr := s.answerRepo.Update(&currentAnswer); updateErr != nil {
-				log.Error().Err(updateErr).Uint("answerID", currentAnswer.ID).Msg("SubmitTest: Failed to update answer with AI results.")
-				resultsChan <- answerProcessingResult{processedAnswer: currentAnswer, originalIndex: answerIdx, err: updateErr}
-				return
-			}
-			resultsChan <- answerProcessingResult{processedAnswer: currentAnswer, originalIndex: answerIdx, err: nil}
-		}(i)
-	}
-
-	// Collect results from goroutines
-	var processingErrors []string
-	totalRawScore := 0.0
-	allAnswersScoredSuccessfully := true // Assume success initially
-
-	// Use a temporary slice to store processed answers in their original order
-	finalOrderedAnswers := make([]model.Answer, len(persistedAnswers))
-
-	for i := 0; i < len(persistedAnswers); i++ {
-		result := <-resultsChan
-		finalOrderedAnswers[result.originalIndex] = result.processedAnswer // Place in correct order
-		if result.err != nil {
-			allAnswersScoredSuccessfully = false
-			errMsg := fmt.Sprintf("Error processing answer for question ID %d (Answer ID: %d): %s", result.processedAnswer.QuestionID, result.processedAnswer.ID, result.err.Error())
-			processingErrors = append(processingErrors, errMsg)
-			log.Warn().Err(result.err).Uint("answerID", result.processedAnswer.ID).Msg("An error occurred while an answer was being processed by AI/DB.")
-		} else if result.processedAnswer.AIScore != nil {
-			totalRawScore += *result.processedAnswer.AIScore
-		}
-	}
-	close(resultsChan) // Close channel after all results are received.
-
-	// 4. Update TestAttempt with total raw score and final status
-	testAttempt.TotalScore = &totalRawScore // This is the Total Raw Score
-	if !allAnswersScoredSuccessfully || len(processingErrors) > 0 {
-		testAttempt.Status = "completed_with_errors"
-	} else {
-		testAttempt.Status = "completed"
-	}
-	// Assign the accurately ordered and processed answers back
-	testAttempt.Answers = finalOrderedAnswers
-
-	if err := s.testAttemptRepo.Update(&testAttempt); err != nil {
-		log.Error().Err(err).Uint("attemptID", testAttempt.ID).Msg("SubmitTest: Failed to update test attempt with total score and final status.")
-		processingErrors = append(processingErrors, fmt.Sprintf("Critical: Failed to save final attempt status and total score: %s", err.Error()))
-		// Even if this fails, try to return the best possible DTO
-	}
-
-	// 5. Prepare and return response DTO
-	// We reload the TestAttempt to get all associations correctly populated by GORM for the DTO.
-	// This also ensures we return the most up-to-date state from the DB.
-	detailedAttempt, reloadErr := s.testAttemptRepo.FindByIDWithDetails(testAttempt.ID)
-	if reloadErr != nil {
-		log.Error().Err(reloadErr).Uint("attemptID", testAttempt.ID).Msg("SubmitTest: Failed to reload detailed test attempt for response. Constructing DTO from current state.")
-		// Fallback: Construct DTO from the `testAttempt` variable if reload fails
-		// This `testAttempt` already has the updated answers, total score, and status.
-		var fallbackResp dto.TestAttemptDetailDTO
-		if errCopy := copier.Copy(&fallbackResp, &testAttempt); errCopy != nil {
-			log.Error().Err(errCopy).Msg("SubmitTest: Error copying fallback TestAttempt to DTO")
-			return nil, fmt.Errorf("error preparing fallback response: %w", errCopy)
-		}
-		fallbackResp.TestTitle = test.Title // Add test title from initially fetched test
-		// Manually populate Question details within answers if copier misses them
-		answerDTOs := make([]dto.AnswerResponseDTO, len(testAttempt.Answers))
-		for i, ansModel := range testAttempt.Answers {
-			copier.Copy(&answerDTOs[i], &ansModel)
-			qModel := questionMap[ansModel.QuestionID]
-			copier.Copy(&answerDTOs[i].Question, &qModel)
-		}
-		fallbackResp.Answers = answerDTOs
-
-		if testAttempt.TotalScore != nil {
-			scaledScore, errScale := s.scoreConverter.ConvertToScaledScore(*testAttempt.TotalScore)
-			if errScale == nil {
-				fallbackResp.ScaledScore = &scaledScore
-			}
-		}
-		fallbackResp.TotalRawScore = testAttempt.TotalScore
-		return &fallbackResp, nil
-	}
-
-	// Sort answers in the reloaded detailedAttempt for consistent DTO response
-	if len(detailedAttempt.Answers) > 0 && len(questionMap) > 0 {
-		sort.SliceStable(detailedAttempt.Answers, func(i, j int) bool {
-			q_i, ok_i := questionMap[detailedAttempt.Answers[i].QuestionID]
-			q_j, ok_j := questionMap[detailedAttempt.Answers[j].QuestionID]
-			if !ok_i || !ok_j {
-				return false
-			}
-			return q_i.OrderInTest < q_j.OrderInTest
-		})
-	}
-
-	var resp dto.TestAttemptDetailDTO
-	if err := copier.Copy(&resp, detailedAttempt); err != nil {
-		log.Error().Err(err).Msg("SubmitTest: Error copying reloaded TestAttempt to DTO.")
-		return nil, fmt.Errorf("error preparing final response: %w", err)
-	}
-	// Ensure TestTitle is correctly set from the preloaded Test within detailedAttempt
-	if detailedAttempt.Test.ID != 0 {
-		resp.TestTitle = detailedAttempt.Test.Title
-	} else {
-		resp.TestTitle = test.Title // Fallback, though detailedAttempt.Test should be loaded
-	}
-
-	resp.TotalRawScore = detailedAttempt.TotalScore // Set raw score
-	if detailedAttempt.TotalScore != nil {
-		scaledScore, errScale := s.scoreConverter.ConvertToScaledScore(*detailedAttempt.TotalScore)
-		if errScale != nil {
-			log.Warn().Err(errScale).Float64("rawScore", *detailedAttempt.TotalScore).Msg("SubmitTest: Failed to scale score for final response DTO.")
-		} else {
-			resp.ScaledScore = &scaledScore
-		}
-	}
-
-	// Ensure Question details within AnswerResponseDTO are fully populated
-	resp.Answers = make([]dto.AnswerResponseDTO, len(detailedAttempt.Answers))
-	for i, ansModel := range detailedAttempt.Answers {
-		var ansDTO dto.AnswerResponseDTO
-		copier.Copy(&ansDTO, &ansModel)
-		if ansModel.Question.ID != 0 { // ansModel.Question is preloaded by FindByIDWithDetails
-			var qDTO dto.QuestionResponseDTO
-			copier.Copy(&qDTO, &ansModel.Question)
-			ansDTO.Question = qDTO
-		} else { // Fallback if Question somehow wasn't preloaded in Answer
-			qModelFromMap := questionMap[ansModel.QuestionID]
-			var qDTO dto.QuestionResponseDTO
-			copier.Copy(&qDTO, &qModelFromMap)
-			ansDTO.Question = qDTO
-		}
-		resp.Answers[i] = ansDTO
-	}
-
-	return &resp, nil
+	// 4. Return the stored attempt; scores are filled in once the worker is done.
+	return s.GetTestAttemptDetails(testAttempt.ID, userID)
 }
 
-// GetTestAttemptDetails retrieves full details for a specific test attempt.
-func (s *testSubmissionService) GetTestAttemptDetails(attemptID uint) (*dto.TestAttemptDetailDTO, error) {
+// GetTestAttemptDetails retrieves full details for a specific test attempt owned by userID.
+func (s *testSubmissionService) GetTestAttemptDetails(attemptID uint, userID uint) (*dto.TestAttemptDetailDTO, error) {
 	attempt, err := s.testAttemptRepo.FindByIDWithDetails(attemptID)
 	if err != nil {
 		log.Error().Err(err).Uint("attemptID", attemptID).Msg("GetTestAttemptDetails: Failed to find test attempt by ID.")
-		return nil, fmt.Errorf("test attempt not found with ID %d: %w", attemptID, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrAttemptNotFound, attemptID)
+		}
+		return nil, fmt.Errorf("error loading test attempt %d: %w", attemptID, err)
+	}
+	// Attempts created before authentication existed have no owner and are not visible to anyone.
+	if attempt.UserID == nil || *attempt.UserID != userID {
+		log.Warn().Uint("attemptID", attemptID).Uint("userID", userID).Msg("GetTestAttemptDetails: User tried to read an attempt they do not own.")
+		return nil, ErrAttemptAccessDenied
 	}
 
 	// Prepare questionMap for sorting answers if test questions are available
@@ -336,13 +192,16 @@ func (s *testSubmissionService) GetTestAttemptDetails(attemptID uint) (*dto.Test
 	}
 
 	resp.TotalRawScore = attempt.TotalScore
+	scaled, errScale := s.scoreConverter.ScaledScoreOf(attempt)
+	if errScale != nil {
+		log.Warn().Err(errScale).Uint("attemptID", attempt.ID).Msg("GetTestAttemptDetails: Failed to scale score for DTO.")
+	} else if scaled != nil {
+		resp.ScaledScore = &scaled.Score
+		resp.ScoreTableVersion = &scaled.TableVersion
+		resp.ProficiencyLevel = proficiencyLevelFor(scaled.Score)
+	}
 	if attempt.TotalScore != nil {
-		scaledScore, errScale := s.scoreConverter.ConvertToScaledScore(*attempt.TotalScore)
-		if errScale != nil {
-			log.Warn().Err(errScale).Float64("rawScore", *attempt.TotalScore).Msg("GetTestAttemptDetails: Failed to scale score for DTO.")
-		} else {
-			resp.ScaledScore = &scaledScore
-		}
+		resp.PartScores = partScoresFromAnswers(attempt.Answers)
 	}
 
 	// Ensure Question details within AnswerResponseDTO are fully populated
@@ -353,7 +212,8 @@ func (s *testSubmissionService) GetTestAttemptDetails(attemptID uint) (*dto.Test
 		// ansModel.Question should be preloaded by FindByIDWithDetails
 		if ansModel.Question.ID != 0 {
 			var qDTO dto.QuestionResponseDTO
-			copier.Copy(&qDTO, &ansModel.Question)
+			answered := ansModel.QuestionAsAnswered() // Show the revision the learner answered, not later edits
+			copier.Copy(&qDTO, &answered)
 			ansDTO.Question = qDTO
 		} else { // Fallback if Question wasn't preloaded with Answer
 			qModelFromMap := questionMap[ansModel.QuestionID] // Use map built from testQuestionsToSortBy
@@ -372,13 +232,30 @@ func (s *testSubmissionService) GetTestAttemptDetails(attemptID uint) (*dto.Test
 }
 
 // GetUserAttemptsForTest retrieves a summary list of a user's attempts for a specific test.
-func (s *testSubmissionService) GetUserAttemptsForTest(testID uint, userID *uint) ([]dto.TestAttemptSummaryDTO, error) {
-	attempts, err := s.testAttemptRepo.FindAllByTestAndUser(testID, userID)
+func (s *testSubmissionService) GetUserAttemptsForTest(testID uint, userID uint) ([]dto.TestAttemptSummaryDTO, error) {
+	attempts, err := s.testAttemptRepo.FindAllByTestAndUser(testID, &userID)
 	if err != nil {
-		log.Error().Err(err).Uint("testID", testID).Interface("userID", userID).Msg("GetUserAttemptsForTest: Failed to find attempts from repository.")
+		log.Error().Err(err).Uint("testID", testID).Uint("userID", userID).Msg("GetUserAttemptsForTest: Failed to find attempts from repository.")
 		return nil, fmt.Errorf("error fetching attempts for test %d: %w", testID, err)
 	}
 
+	// Subtotals of all scored attempts in one query rather than loading every attempt's answers.
+	var scoredIDs []uint
+	for _, attempt := range attempts {
+		if attempt.TotalScore != nil {
+			scoredIDs = append(scoredIDs, attempt.ID)
+		}
+	}
+	partRows, err := s.testAttemptRepo.SumScoresByPart(scoredIDs)
+	if err != nil {
+		log.Error().Err(err).Uint("testID", testID).Msg("GetUserAttemptsForTest: Failed to sum part scores.")
+		return nil, fmt.Errorf("error fetching part scores for test %d: %w", testID, err)
+	}
+	rowsByAttempt := make(map[uint][]repository.AttemptPartScore)
+	for _, row := range partRows {
+		rowsByAttempt[row.TestAttemptID] = append(rowsByAttempt[row.TestAttemptID], row)
+	}
+
 	var dtos []dto.TestAttemptSummaryDTO
 	for _, attempt := range attempts {
 		var summary dto.TestAttemptSummaryDTO
@@ -389,13 +266,16 @@ func (s *testSubmissionService) GetUserAttemptsForTest(testID uint, userID *uint
 		}
 
 		summary.TotalRawScore = attempt.TotalScore // Assign raw score
+		scaled, errScale := s.scoreConverter.ScaledScoreOf(&attempt)
+		if errScale != nil {
+			log.Warn().Err(errScale).Uint("attemptID", attempt.ID).Msg("GetUserAttemptsForTest: Failed to scale score for summary.")
+		} else if scaled != nil {
+			summary.ScaledScore = &scaled.Score
+			summary.ScoreTableVersion = &scaled.TableVersion
+			summary.ProficiencyLevel = proficiencyLevelFor(scaled.Score)
+		}
 		if attempt.TotalScore != nil {
-			scaledScore, errScale := s.scoreConverter.ConvertToScaledScore(*attempt.TotalScore)
-			if errScale != nil {
-				log.Warn().Err(errScale).Float64("rawScore", *attempt.TotalScore).Msg("GetUserAttemptsForTest: Failed to scale score for summary.")
-			} else {
-				summary.ScaledScore = &scaledScore
-			}
+			summary.PartScores = partScoresFromRows(rowsByAttempt[attempt.ID])
 		}
 		dtos = append(dtos, summary)
 	}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/copier"
+	"github.com/lshigami/Ringtails/config"
+	"github.com/lshigami/Ringtails/internal/dto"
+	"github.com/lshigami/Ringtails/internal/model"
+	"github.com/lshigami/Ringtails/internal/repository"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRole = errors.New("invalid role")
+	ErrLastAdmin   = errors.New("at least one other admin must remain")
+)
+
+type UserManagementService interface {
+	ListUsers(role string) ([]dto.UserResponseDTO, error)
+	UpdateUserRole(userID uint, role string) (*dto.UserResponseDTO, error)
+	BootstrapAdmin() error
+}
+
+type userManagementService struct {
+	userRepo repository.UserRepository
+	cfg      *config.Config
+}
+
+func NewUserManagementService(userRepo repository.UserRepository, cfg *config.Config) UserManagementService {
+	return &userManagementService{userRepo: userRepo, cfg: cfg}
+}
+
+func (s *userManagementService) ListUsers(role string) ([]dto.UserResponseDTO, error) {
+	if role != "" && !model.IsValidRole(role) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+	}
+	users, err := s.userRepo.FindAll(role)
+	if err != nil {
+		log.Error().Err(err).Str("role", role).Msg("ListUsers: Failed to fetch users")
+		return nil, fmt.Errorf("error fetching users: %w", err)
+	}
+
+	dtos := make([]dto.UserResponseDTO, 0, len(users))
+	for _, u := range users {
+		var userDTO dto.UserResponseDTO
+		copier.Copy(&userDTO, &u)
+		dtos = append(dtos, userDTO)
+	}
+	return dtos, nil
+}
+
+func (s *userManagementService) UpdateUserRole(userID uint, role string) (*dto.UserResponseDTO, error) {
+	if !model.IsValidRole(role) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+	}
+	if err := s.userRepo.UpdateRole(userID, role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		if errors.Is(err, repository.ErrLastAdmin) {
+			return nil, fmt.Errorf("%w: user %d is the only admin", ErrLastAdmin, userID)
+		}
+		log.Error().Err(err).Uint("userID", userID).Msg("UpdateUserRole: Failed to update role")
+		return nil, fmt.Errorf("database error updating role: %w", err)
+	}
+	log.Info().Uint("userID", userID).Str("role", role).Msg("UpdateUserRole: Role changed")
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("error reloading user %d: %w", userID, err)
+	}
+	var resp dto.UserResponseDTO
+	copier.Copy(&resp, user)
+	return &resp, nil
+}
+
+// BootstrapAdmin makes sure at least one admin exists. When ADMIN_EMAIL is set and there is no admin yet,
+// the matching account is promoted, or created with ADMIN_PASSWORD if it does not exist.
+func (s *userManagementService) BootstrapAdmin() error {
+	email := strings.ToLower(strings.TrimSpace(s.cfg.Admin.Email))
+	if email == "" {
+		log.Info().Msg("BootstrapAdmin: ADMIN_EMAIL not set, skipping admin bootstrap.")
+		return nil
+	}
+
+	adminCount, err := s.userRepo.CountByRole(model.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to count admin users: %w", err)
+	}
+	if adminCount > 0 {
+		log.Info().Int64("adminCount", adminCount).Msg("BootstrapAdmin: Admin already exists, nothing to do.")
+		return nil
+	}
+
+	existing, err := s.userRepo.FindByEmail(email)
+	if err == nil {
+		if err := s.userRepo.UpdateRole(existing.ID, model.RoleAdmin); err != nil {
+			return fmt.Errorf("failed to promote %s to admin: %w", email, err)
+		}
+		log.Info().Uint("userID", existing.ID).Msg("BootstrapAdmin: Promoted existing user to admin.")
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to look up bootstrap admin: %w", err)
+	}
+
+	if len(s.cfg.Admin.Password) < 8 {
+		return fmt.Errorf("ADMIN_PASSWORD must be at least 8 characters to create the bootstrap admin")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(s.cfg.Admin.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash bootstrap admin password: %w", err)
+	}
+	admin := model.User{
+		Email:        email,
+		PasswordHash: string(hash),
+		FullName:     "Administrator",
+		Role:         model.RoleAdmin,
+	}
+	if err := s.userRepo.Create(&admin); err != nil {
+		return fmt.Errorf("failed to create bootstrap admin: %w", err)
+	}
+	log.Info().Uint("userID", admin.ID).Msg("BootstrapAdmin: Created bootstrap admin account.")
+	return nil
+}
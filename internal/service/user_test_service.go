@@ -1,12 +1,15 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/jinzhu/copier"
 	"github.com/lshigami/Ringtails/internal/dto" // Corrected DTO path
+	"github.com/lshigami/Ringtails/internal/model"
 	"github.com/lshigami/Ringtails/internal/repository"
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 type UserTestService interface {
@@ -33,7 +36,8 @@ func NewUserTestService(
 }
 
 func (s *userTestService) GetAllTests(requestingUserID *uint) ([]dto.TestSummaryDTO, error) {
-	testsWithCount, err := s.testRepo.FindAllWithQuestionCount()
+	// Drafts are still being written and are only visible to teachers and admins.
+	testsWithCount, err := s.testRepo.FindAllWithQuestionCount(repository.TestListFilter{Status: model.TestStatusPublished})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get all tests with question count from repository")
 		return nil, fmt.Errorf("error fetching tests: %w", err)
@@ -59,11 +63,11 @@ func (s *userTestService) GetAllTests(requestingUserID *uint) ([]dto.TestSummary
 				summary.LastAttemptStatus = &latestAttempt.Status
 				if latestAttempt.TotalScore != nil {
 					summary.LastAttemptRawScore = latestAttempt.TotalScore
-					scaledScore, errScale := s.scoreConverter.ConvertToScaledScore(*latestAttempt.TotalScore)
+					scaled, errScale := s.scoreConverter.ScaledScoreOf(latestAttempt)
 					if errScale != nil {
 						log.Warn().Err(errScale).Float64("rawScore", *latestAttempt.TotalScore).Msg("Failed to scale score for test summary")
-					} else {
-						summary.LastAttemptScaledScore = &scaledScore
+					} else if scaled != nil {
+						summary.LastAttemptScaledScore = &scaled.Score
 					}
 				}
 			} else {
@@ -80,8 +84,13 @@ func (s *userTestService) GetTestDetails(testID uint) (*dto.TestResponseDTO, err
 	test, err := s.testRepo.FindByIDWithQuestions(testID)
 	if err != nil {
 		log.Error().Err(err).Uint("testID", testID).Msg("Failed to get test details from repository")
-		// Consider returning a more specific "not found" error if gorm.ErrRecordNotFound
-		return nil, fmt.Errorf("test not found with ID %d: %w", testID, err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
+		}
+		return nil, fmt.Errorf("error loading test %d: %w", testID, err)
+	}
+	if test.Status != model.TestStatusPublished {
+		return nil, fmt.Errorf("%w with ID %d", ErrTestNotFound, testID)
 	}
 
 	var resp dto.TestResponseDTO